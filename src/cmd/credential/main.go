@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/specvital/collector/internal/adapter/repository/postgres"
+	"github.com/specvital/collector/internal/adapter/vcs"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+	"github.com/specvital/collector/internal/infra/githuburl"
+	"github.com/specvital/collector/internal/infra/keyring"
+)
+
+const defaultHost = "github.com"
+
+func main() {
+	databaseURL := flag.String("database", os.Getenv("DATABASE_URL"), "Database URL")
+	deleteCredential := flag.Bool("delete", false, "Remove the codebase's credential")
+	sshKeyFile := flag.String("ssh-key", "", "File with an SSH deploy key, or - to read it from stdin")
+	tokenFile := flag.String("token", "", "File with an HTTPS deploy token, or - to read it from stdin")
+	flag.Usage = printUsage
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	if *databaseURL == "" {
+		fmt.Fprintln(os.Stderr, "Error: Database URL is required (use -database flag or set DATABASE_URL)")
+		os.Exit(1)
+	}
+
+	selected := 0
+	for _, set := range []bool{*deleteCredential, *sshKeyFile != "", *tokenFile != ""} {
+		if set {
+			selected++
+		}
+	}
+	if selected != 1 {
+		fmt.Fprintln(os.Stderr, "Error: exactly one of -token, -ssh-key or -delete is required")
+		os.Exit(1)
+	}
+
+	owner, repo, err := githuburl.Parse(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var credential *analysis.DeployCredential
+	switch {
+	case *sshKeyFile != "":
+		credential, err = readCredential(analysis.DeployCredentialSSHKey, *sshKeyFile)
+	case *tokenFile != "":
+		credential, err = readCredential(analysis.DeployCredentialToken, *tokenFile)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	keys, err := keyring.New(os.Getenv("ENCRYPTION_KEY"), os.Getenv("ENCRYPTION_KEYS"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid encryption keys: %v\n", err)
+		os.Exit(1)
+	}
+	defer keys.Close()
+
+	if err := run(*databaseURL, keys, owner, repo, credential); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: credential [flags] <github-url>")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Stores a codebase's own credential, used instead of user tokens to clone it.")
+	fmt.Fprintln(os.Stderr, "Secrets are encrypted with ENCRYPTION_KEYS (or ENCRYPTION_KEY).")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Arguments:")
+	fmt.Fprintln(os.Stderr, "  <github-url>  GitHub repository URL (e.g., github.com/owner/repo)")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Flags:")
+	flag.PrintDefaults()
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Examples:")
+	fmt.Fprintln(os.Stderr, "  credential -token token.txt github.com/owner/repo")
+	fmt.Fprintln(os.Stderr, "  credential -ssh-key - github.com/owner/repo < id_ed25519")
+	fmt.Fprintln(os.Stderr, "  credential -delete github.com/owner/repo")
+}
+
+func readCredential(kind analysis.DeployCredentialKind, path string) (*analysis.DeployCredential, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", kind, err)
+	}
+
+	credential := NewDeployCredential(kind, string(data))
+	if err := credential.Validate(); err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// NewDeployCredential trims the surrounding whitespace of secret. SSH keys keep a
+// trailing newline, without which ssh refuses to load them.
+func NewDeployCredential(kind analysis.DeployCredentialKind, secret string) analysis.DeployCredential {
+	secret = strings.TrimSpace(secret)
+	if kind == analysis.DeployCredentialSSHKey && secret != "" {
+		secret += "\n"
+	}
+	return analysis.DeployCredential{Kind: kind, Secret: secret}
+}
+
+// CodebaseStore is the part of analysis.CodebaseRepository needed to register a codebase.
+type CodebaseStore interface {
+	FindByExternalID(ctx context.Context, host, externalRepoID string) (*analysis.Codebase, error)
+	FindByOwnerName(ctx context.Context, host, owner, name string) (*analysis.Codebase, error)
+	UnmarkStale(ctx context.Context, id analysis.UUID, owner, name string) (*analysis.Codebase, error)
+	UpdateOwnerName(ctx context.Context, id analysis.UUID, owner, name string) (*analysis.Codebase, error)
+	Upsert(ctx context.Context, params analysis.UpsertCodebaseParams) (*analysis.Codebase, error)
+}
+
+// ResolveCodebase finds the codebase named owner/repo. A codebase that was never analyzed
+// is looked up through the API with the credential's token and registered, so the first
+// analysis can already clone with it. SSH keys cannot call the API, so they return
+// analysis.ErrCodebaseNotFound for such codebases.
+func ResolveCodebase(ctx context.Context, codebases CodebaseStore, api analysis.VCSAPIClient, owner, repo string, credential analysis.DeployCredential) (*analysis.Codebase, error) {
+	codebase, err := codebases.FindByOwnerName(ctx, defaultHost, owner, repo)
+	if err == nil {
+		return codebase, nil
+	}
+	if !errors.Is(err, analysis.ErrCodebaseNotFound) {
+		return nil, fmt.Errorf("find codebase: %w", err)
+	}
+
+	token := credential.APIToken()
+	if token == nil {
+		return nil, fmt.Errorf("%w: analyze %s/%s once or store a token first", analysis.ErrCodebaseNotFound, owner, repo)
+	}
+
+	info, err := api.GetRepoInfo(ctx, defaultHost, owner, repo, token)
+	if err != nil {
+		return nil, fmt.Errorf("get repo info: %w", err)
+	}
+
+	existing, err := codebases.FindByExternalID(ctx, defaultHost, info.ExternalRepoID)
+	switch {
+	case err == nil && existing.IsStale:
+		return codebases.UnmarkStale(ctx, existing.ID, owner, repo)
+	case err == nil:
+		return codebases.UpdateOwnerName(ctx, existing.ID, owner, repo)
+	case !errors.Is(err, analysis.ErrCodebaseNotFound):
+		return nil, fmt.Errorf("find codebase by external ID: %w", err)
+	}
+
+	// A repository that needs its own credential is assumed private until its first
+	// analysis reports the actual visibility.
+	return codebases.Upsert(ctx, analysis.UpsertCodebaseParams{
+		DefaultBranch:  info.DefaultBranch,
+		ExternalRepoID: info.ExternalRepoID,
+		Host:           defaultHost,
+		IsPrivate:      true,
+		Name:           repo,
+		Owner:          owner,
+	})
+}
+
+// run stores credential for owner/repo, or deletes its credential when credential is nil.
+func run(databaseURL string, keys *keyring.Keyring, owner, repo string, credential *analysis.DeployCredential) error {
+	ctx := context.Background()
+
+	pool, err := db.NewPool(ctx, databaseURL)
+	if err != nil {
+		return fmt.Errorf("database connection: %w", err)
+	}
+	defer pool.Close()
+
+	codebases := postgres.NewCodebaseRepository(pool)
+	credentials := postgres.NewDeployCredentialRepository(pool, keys)
+
+	if credential == nil {
+		codebase, err := codebases.FindByOwnerName(ctx, defaultHost, owner, repo)
+		if err != nil {
+			return fmt.Errorf("find codebase: %w", err)
+		}
+		if err := credentials.DeleteDeployCredential(ctx, codebase.ID); err != nil {
+			return err
+		}
+		slog.Info("deploy credential deleted", "owner", owner, "repo", repo, "codebase_id", codebase.ID)
+		return nil
+	}
+
+	codebase, err := ResolveCodebase(ctx, codebases, vcs.NewGitHubAPIClient(nil), owner, repo, *credential)
+	if err != nil {
+		return err
+	}
+	if err := credentials.SaveDeployCredential(ctx, codebase.ID, *credential); err != nil {
+		return fmt.Errorf("save deploy credential: %w", err)
+	}
+
+	slog.Info("deploy credential saved",
+		"owner", owner,
+		"repo", repo,
+		"codebase_id", codebase.ID,
+		"kind", credential.Kind,
+	)
+	return nil
+}
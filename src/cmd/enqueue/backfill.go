@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	adapterqueue "github.com/specvital/collector/internal/adapter/queue"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+	"github.com/specvital/collector/internal/infra/queue"
+)
+
+const sinceLayout = "2006-01-02"
+
+// ParseBackfillArgs builds and validates backfill job args from CLI flag values.
+func ParseBackfillArgs(owner, repo, strategy string, every, maxCommits int, since string) (adapterqueue.BackfillArgs, error) {
+	args := adapterqueue.BackfillArgs{
+		Every:      every,
+		MaxCommits: maxCommits,
+		Owner:      owner,
+		Repo:       repo,
+		Strategy:   strategy,
+	}
+
+	if since != "" {
+		t, err := time.Parse(sinceLayout, since)
+		if err != nil {
+			return adapterqueue.BackfillArgs{}, fmt.Errorf("invalid since date %q (expected YYYY-MM-DD)", since)
+		}
+		args.Since = t
+	}
+
+	plan := analysis.BackfillPlan{
+		Every:      args.Every,
+		MaxCommits: args.MaxCommits,
+		Since:      args.Since,
+		Strategy:   analysis.BackfillStrategy(args.Strategy),
+	}
+	if err := plan.Validate(); err != nil {
+		return adapterqueue.BackfillArgs{}, err
+	}
+
+	return args, nil
+}
+
+func enqueueBackfill(databaseURL string, args adapterqueue.BackfillArgs) error {
+	ctx := context.Background()
+
+	pool, err := db.NewPool(ctx, databaseURL)
+	if err != nil {
+		return fmt.Errorf("database connection: %w", err)
+	}
+	defer pool.Close()
+
+	client, err := queue.NewClient(ctx, pool)
+	if err != nil {
+		return fmt.Errorf("create queue client: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.EnqueueBackfill(ctx, args); err != nil {
+		return fmt.Errorf("enqueue backfill: %w", err)
+	}
+
+	slog.Info("backfill enqueued",
+		"owner", args.Owner,
+		"repo", args.Repo,
+		"strategy", args.Strategy,
+	)
+	return nil
+}
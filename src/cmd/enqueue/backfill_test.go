@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseBackfillArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  string
+		every     int
+		max       int
+		since     string
+		wantErr   bool
+		wantSince time.Time
+	}{
+		{name: "weekly", strategy: "weekly"},
+		{name: "tags with max", strategy: "tags", max: 10},
+		{name: "every n", strategy: "every_n", every: 20},
+		{name: "since date", strategy: "weekly", since: "2024-06-01", wantSince: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "every n without every", strategy: "every_n", wantErr: true},
+		{name: "unknown strategy", strategy: "daily", wantErr: true},
+		{name: "invalid since", strategy: "weekly", since: "06/01/2024", wantErr: true},
+		{name: "max too large", strategy: "weekly", max: 10000, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := ParseBackfillArgs("owner", "repo", tt.strategy, tt.every, tt.max, tt.since)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if args.Owner != "owner" || args.Repo != "repo" || args.Strategy != tt.strategy {
+				t.Errorf("unexpected args: %+v", args)
+			}
+			if !args.Since.Equal(tt.wantSince) {
+				t.Errorf("expected since %v, got %v", tt.wantSince, args.Since)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	adapterqueue "github.com/specvital/collector/internal/adapter/queue"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+	"github.com/specvital/collector/internal/infra/queue"
+)
+
+// ParseIngestArgs reads a test report file and builds validated ingest job args from CLI flag values.
+// The format defaults to junit and the report name to the file name without extension.
+// The report is returned next to the args, which only refer to it once it is uploaded.
+func ParseIngestArgs(owner, repo, reportPath, format, commitSHA, reportName string) (adapterqueue.IngestResultsArgs, []byte, error) {
+	if format == "" {
+		format = string(analysis.ReportFormatJUnit)
+	}
+	report, reportName, err := readReport(reportPath, reportName)
+	if err != nil {
+		return adapterqueue.IngestResultsArgs{}, nil, err
+	}
+
+	args := adapterqueue.IngestResultsArgs{
+		CommitSHA:  commitSHA,
+		Format:     format,
+		Owner:      owner,
+		Repo:       repo,
+		ReportName: reportName,
+	}
+	req := analysis.IngestResultsRequest{
+		CommitSHA:  args.CommitSHA,
+		Format:     analysis.ReportFormat(args.Format),
+		Owner:      args.Owner,
+		Repo:       args.Repo,
+		Report:     report,
+		ReportName: args.ReportName,
+	}
+	if err := req.Validate(); err != nil {
+		return adapterqueue.IngestResultsArgs{}, nil, err
+	}
+
+	return args, report, nil
+}
+
+// ParseCoverageArgs reads a coverage report file and builds validated coverage job args from CLI flag values.
+// The format defaults to lcov and the report name to the file name without extension.
+// Like ParseIngestArgs, it returns the report next to the args.
+func ParseCoverageArgs(owner, repo, reportPath, format, commitSHA, reportName string) (adapterqueue.IngestCoverageArgs, []byte, error) {
+	if format == "" {
+		format = string(analysis.ReportFormatLcov)
+	}
+	report, reportName, err := readReport(reportPath, reportName)
+	if err != nil {
+		return adapterqueue.IngestCoverageArgs{}, nil, err
+	}
+
+	args := adapterqueue.IngestCoverageArgs{
+		CommitSHA:  commitSHA,
+		Format:     format,
+		Owner:      owner,
+		Repo:       repo,
+		ReportName: reportName,
+	}
+	req := analysis.IngestCoverageRequest{
+		CommitSHA:  args.CommitSHA,
+		Format:     analysis.ReportFormat(args.Format),
+		Owner:      args.Owner,
+		Repo:       args.Repo,
+		Report:     report,
+		ReportName: args.ReportName,
+	}
+	if err := req.Validate(); err != nil {
+		return adapterqueue.IngestCoverageArgs{}, nil, err
+	}
+
+	return args, report, nil
+}
+
+// readReport reads at most one byte past analysis.MaxReportSize so oversized reports fail validation.
+func readReport(reportPath, reportName string) ([]byte, string, error) {
+	if reportName == "" {
+		reportName = strings.TrimSuffix(filepath.Base(reportPath), filepath.Ext(reportPath))
+	}
+
+	f, err := os.Open(reportPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("open report: %w", err)
+	}
+	defer f.Close()
+
+	report, err := io.ReadAll(io.LimitReader(f, analysis.MaxReportSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("read report: %w", err)
+	}
+	return report, reportName, nil
+}
+
+func enqueueIngestResults(databaseURL string, args adapterqueue.IngestResultsArgs, report []byte) error {
+	ctx := context.Background()
+
+	pool, err := db.NewPool(ctx, databaseURL)
+	if err != nil {
+		return fmt.Errorf("database connection: %w", err)
+	}
+	defer pool.Close()
+
+	client, err := queue.NewClient(ctx, pool)
+	if err != nil {
+		return fmt.Errorf("create queue client: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.EnqueueIngestResults(ctx, report, args); err != nil {
+		return fmt.Errorf("enqueue ingest results: %w", err)
+	}
+
+	slog.Info("test results enqueued",
+		"owner", args.Owner,
+		"repo", args.Repo,
+		"commit", args.CommitSHA,
+		"report", args.ReportName,
+		"bytes", len(report),
+	)
+	return nil
+}
+
+func enqueueIngestCoverage(databaseURL string, args adapterqueue.IngestCoverageArgs, report []byte) error {
+	ctx := context.Background()
+
+	pool, err := db.NewPool(ctx, databaseURL)
+	if err != nil {
+		return fmt.Errorf("database connection: %w", err)
+	}
+	defer pool.Close()
+
+	client, err := queue.NewClient(ctx, pool)
+	if err != nil {
+		return fmt.Errorf("create queue client: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.EnqueueIngestCoverage(ctx, report, args); err != nil {
+		return fmt.Errorf("enqueue ingest coverage: %w", err)
+	}
+
+	slog.Info("coverage report enqueued",
+		"owner", args.Owner,
+		"repo", args.Repo,
+		"commit", args.CommitSHA,
+		"report", args.ReportName,
+		"bytes", len(report),
+	)
+	return nil
+}
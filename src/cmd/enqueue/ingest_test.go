@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseIngestArgs(t *testing.T) {
+	dir := t.TempDir()
+	reportPath := filepath.Join(dir, "unit-tests.xml")
+	if err := os.WriteFile(reportPath, []byte("<testsuites/>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	emptyPath := filepath.Join(dir, "empty.xml")
+	if err := os.WriteFile(emptyPath, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		format     string
+		commit     string
+		reportName string
+		wantErr    bool
+		wantName   string
+	}{
+		{name: "default report name", path: reportPath, format: "junit", commit: "abc", wantName: "unit-tests"},
+		{name: "default format", path: reportPath, commit: "abc", wantName: "unit-tests"},
+		{name: "explicit report name", path: reportPath, format: "junit", commit: "abc", reportName: "e2e", wantName: "e2e"},
+		{name: "missing commit", path: reportPath, format: "junit", wantErr: true},
+		{name: "unsupported format", path: reportPath, format: "trx", commit: "abc", wantErr: true},
+		{name: "empty report", path: emptyPath, format: "junit", commit: "abc", wantErr: true},
+		{name: "missing file", path: filepath.Join(dir, "missing.xml"), format: "junit", commit: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, report, err := ParseIngestArgs("owner", "repo", tt.path, tt.format, tt.commit, tt.reportName)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if args.ReportName != tt.wantName || string(report) != "<testsuites/>" || args.CommitSHA != tt.commit {
+				t.Errorf("unexpected args: %+v", args)
+			}
+		})
+	}
+}
+
+func TestParseCoverageArgs(t *testing.T) {
+	reportPath := filepath.Join(t.TempDir(), "lcov.info")
+	if err := os.WriteFile(reportPath, []byte("SF:a.ts\nend_of_record\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	args, report, err := ParseCoverageArgs("owner", "repo", reportPath, "", "abc", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if args.Format != "lcov" || args.ReportName != "lcov" || args.CommitSHA != "abc" || len(report) == 0 {
+		t.Errorf("unexpected args: %+v", args)
+	}
+
+	if _, _, err := ParseCoverageArgs("owner", "repo", reportPath, "junit", "abc", ""); err == nil {
+		t.Error("expected error for a test report format")
+	}
+}
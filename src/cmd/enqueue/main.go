@@ -9,11 +9,21 @@ import (
 
 	"github.com/specvital/collector/internal/adapter/vcs"
 	"github.com/specvital/collector/internal/infra/db"
+	"github.com/specvital/collector/internal/infra/githuburl"
 	"github.com/specvital/collector/internal/infra/queue"
 )
 
 func main() {
 	databaseURL := flag.String("database", os.Getenv("DATABASE_URL"), "Database URL")
+	backfillStrategy := flag.String("backfill", "", "Backfill history instead of analyzing HEAD (every_n|weekly|tags)")
+	every := flag.Int("every", 0, "Analyze every Nth commit (with -backfill every_n)")
+	maxCommits := flag.Int("max", 0, "Maximum number of historical commits to analyze (default 52)")
+	since := flag.String("since", "", "Only backfill commits after this date (YYYY-MM-DD)")
+	results := flag.String("results", "", "Ingest a CI test report for -commit instead of analyzing HEAD")
+	coverage := flag.String("coverage", "", "Ingest a coverage report for -commit instead of analyzing HEAD")
+	commit := flag.String("commit", "", "Commit SHA the report was produced for (with -results or -coverage)")
+	format := flag.String("format", "", "Report format: junit (default for -results), lcov (default for -coverage) or cobertura")
+	reportName := flag.String("report-name", "", "Report name, e.g. the CI job (with -results or -coverage, default: file name)")
 	flag.Parse()
 
 	if flag.NArg() < 1 {
@@ -26,12 +36,51 @@ func main() {
 		os.Exit(1)
 	}
 
-	owner, repo, err := ParseGitHubURL(flag.Arg(0))
+	owner, repo, err := githuburl.Parse(flag.Arg(0))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
+	if *coverage != "" {
+		args, report, err := ParseCoverageArgs(owner, repo, *coverage, *format, *commit, *reportName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := enqueueIngestCoverage(*databaseURL, args, report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to enqueue coverage: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *results != "" {
+		args, report, err := ParseIngestArgs(owner, repo, *results, *format, *commit, *reportName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := enqueueIngestResults(*databaseURL, args, report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to enqueue test results: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *backfillStrategy != "" {
+		args, err := ParseBackfillArgs(owner, repo, *backfillStrategy, *every, *maxCommits, *since)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := enqueueBackfill(*databaseURL, args); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to enqueue backfill: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if err := enqueue(*databaseURL, owner, repo); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: failed to enqueue task: %v\n", err)
 		os.Exit(1)
@@ -51,6 +100,11 @@ func printUsage() {
 	fmt.Fprintln(os.Stderr, "  enqueue github.com/octocat/Hello-World")
 	fmt.Fprintln(os.Stderr, "  enqueue -database postgres://localhost/mydb github.com/owner/repo")
 	fmt.Fprintln(os.Stderr, "  enqueue https://github.com/owner/repo.git")
+	fmt.Fprintln(os.Stderr, "  enqueue -backfill weekly -since 2024-01-01 github.com/owner/repo")
+	fmt.Fprintln(os.Stderr, "  enqueue -backfill every_n -every 50 -max 20 github.com/owner/repo")
+	fmt.Fprintln(os.Stderr, "  enqueue -backfill tags github.com/owner/repo")
+	fmt.Fprintln(os.Stderr, "  enqueue -results junit.xml -commit <sha> -report-name unit github.com/owner/repo")
+	fmt.Fprintln(os.Stderr, "  enqueue -coverage coverage.xml -format cobertura -commit <sha> github.com/owner/repo")
 }
 
 func enqueue(databaseURL, owner, repo string) error {
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/specvital/collector/internal/adapter/repository/postgres"
+	"github.com/specvital/collector/internal/infra/db"
+	"github.com/specvital/collector/internal/infra/keyring"
+)
+
+const defaultBatchSize = 500
+
+func main() {
+	databaseURL := flag.String("database", os.Getenv("DATABASE_URL"), "Database URL")
+	batchSize := flag.Int("batch-size", defaultBatchSize, "Number of OAuth accounts or deploy credentials to re-encrypt per batch")
+	flag.Usage = printUsage
+	flag.Parse()
+
+	if *databaseURL == "" {
+		fmt.Fprintln(os.Stderr, "Error: Database URL is required (use -database flag or set DATABASE_URL)")
+		os.Exit(1)
+	}
+	if *batchSize <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: -batch-size must be positive")
+		os.Exit(1)
+	}
+
+	keys, err := keyring.New(os.Getenv("ENCRYPTION_KEY"), os.Getenv("ENCRYPTION_KEYS"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid encryption keys: %v\n", err)
+		os.Exit(1)
+	}
+	defer keys.Close()
+
+	if keys.PrimaryKeyID() == "" {
+		fmt.Fprintln(os.Stderr, "Error: ENCRYPTION_KEYS is required, secrets are re-encrypted with its first key")
+		os.Exit(1)
+	}
+
+	if err := rekey(*databaseURL, keys, *batchSize); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to re-encrypt secrets: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: rekey [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Re-encrypts every stored OAuth access token and deploy credential with the first key of ENCRYPTION_KEYS.")
+	fmt.Fprintln(os.Stderr, "Older keys (and ENCRYPTION_KEY for secrets without a key ID) must still be configured.")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Flags:")
+	flag.PrintDefaults()
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Examples:")
+	fmt.Fprintln(os.Stderr, "  ENCRYPTION_KEYS=k2:<new>,k1:<old> rekey")
+	fmt.Fprintln(os.Stderr, "  rekey -batch-size 100 -database postgres://...")
+}
+
+func rekey(databaseURL string, keys *keyring.Keyring, batchSize int) error {
+	ctx := context.Background()
+
+	pool, err := db.NewPool(ctx, databaseURL)
+	if err != nil {
+		return fmt.Errorf("database connection: %w", err)
+	}
+	defer pool.Close()
+
+	rotator := postgres.NewTokenRotator(pool, keys)
+
+	tokens, err := rotator.RotateOAuthTokens(ctx, batchSize)
+	if err != nil {
+		return err
+	}
+	logRotation("oauth tokens re-encrypted", keys, tokens)
+
+	credentials, err := rotator.RotateDeployCredentials(ctx, batchSize)
+	if err != nil {
+		return err
+	}
+	logRotation("deploy credentials re-encrypted", keys, credentials)
+
+	if failed := tokens.Failed + credentials.Failed; failed > 0 {
+		return fmt.Errorf("%d secrets could not be decrypted with the configured keys", failed)
+	}
+	return nil
+}
+
+func logRotation(msg string, keys *keyring.Keyring, result postgres.TokenRotationResult) {
+	slog.Info(msg,
+		"key_id", keys.PrimaryKeyID(),
+		"scanned", result.Scanned,
+		"rotated", result.Rotated,
+		"skipped", result.Skipped,
+		"failed", result.Failed,
+	)
+}
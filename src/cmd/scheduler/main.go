@@ -5,15 +5,26 @@ import (
 	"os"
 
 	"github.com/specvital/collector/internal/app/bootstrap"
+	"github.com/specvital/collector/internal/infra/config"
 )
 
 func main() {
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 	slog.SetDefault(logger)
 
+	appID, appPrivateKey, err := config.LoadGitHubApp()
+	if err != nil {
+		slog.Error("failed to load config", "error", err)
+		os.Exit(1)
+	}
+
 	cfg := bootstrap.SchedulerConfig{
-		ServiceName: "scheduler",
-		DatabaseURL: os.Getenv("DATABASE_URL"),
+		ServiceName:         "scheduler",
+		DatabaseURL:         os.Getenv("DATABASE_URL"),
+		EncryptionKey:       os.Getenv("ENCRYPTION_KEY"),
+		EncryptionKeys:      os.Getenv("ENCRYPTION_KEYS"),
+		GitHubAppID:         appID,
+		GitHubAppPrivateKey: appPrivateKey,
 	}
 
 	if err := bootstrap.StartScheduler(cfg); err != nil {
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/google/uuid"
+
+	"github.com/specvital/collector/internal/adapter/repository/postgres"
+	"github.com/specvital/collector/internal/adapter/specdoc"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+	"github.com/specvital/collector/internal/infra/githuburl"
+)
+
+func main() {
+	databaseURL := flag.String("database", os.Getenv("DATABASE_URL"), "Database URL")
+	analysisID := flag.String("analysis", "", "Analysis ID to export (instead of a repository URL)")
+	commit := flag.String("commit", "", "Commit SHA to export (default: latest completed analysis)")
+	compareUpstream := flag.Bool("compare-upstream", false, "Also compare a fork with the latest analysis of its upstream repository")
+	format := flag.String("format", string(specdoc.FormatMarkdown), "Output format: markdown or html")
+	out := flag.String("out", "spec", "Output directory")
+	flag.Parse()
+
+	if flag.NArg() < 1 && *analysisID == "" {
+		printUsage()
+		os.Exit(1)
+	}
+
+	if *databaseURL == "" {
+		fmt.Fprintln(os.Stderr, "Error: Database URL is required (use -database flag or set DATABASE_URL)")
+		os.Exit(1)
+	}
+
+	outputFormat, err := specdoc.ParseFormat(*format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	target := SpecTarget{CommitSHA: *commit}
+	if *analysisID != "" {
+		id, err := uuid.Parse(*analysisID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid analysis ID: %v\n", err)
+			os.Exit(1)
+		}
+		target.AnalysisID = id
+	} else {
+		target.Owner, target.Repo, err = githuburl.Parse(flag.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := export(*databaseURL, target, outputFormat, *out, *compareUpstream); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to export spec: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: spec [flags] <github-url>")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Arguments:")
+	fmt.Fprintln(os.Stderr, "  <github-url>  GitHub repository URL (e.g., github.com/owner/repo)")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Flags:")
+	flag.PrintDefaults()
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Examples:")
+	fmt.Fprintln(os.Stderr, "  spec github.com/owner/repo")
+	fmt.Fprintln(os.Stderr, "  spec -commit <sha> -format html -out site github.com/owner/repo")
+	fmt.Fprintln(os.Stderr, "  spec -analysis <id> -out docs/spec")
+	fmt.Fprintln(os.Stderr, "  spec -compare-upstream github.com/owner/fork")
+}
+
+// SpecTarget selects the analysis to export: AnalysisID if set, otherwise the
+// analysis of CommitSHA, or the latest completed analysis of Owner/Repo.
+type SpecTarget struct {
+	AnalysisID analysis.UUID
+	CommitSHA  string
+	Owner      string
+	Repo       string
+}
+
+// ResolveAnalysisID returns analysis.ErrAnalysisNotFound if no completed analysis matches.
+func ResolveAnalysisID(ctx context.Context, specs analysis.SpecRepository, target SpecTarget) (analysis.UUID, error) {
+	if target.AnalysisID != analysis.NilUUID {
+		return target.AnalysisID, nil
+	}
+	if target.CommitSHA != "" {
+		return specs.FindCompletedAnalysis(ctx, target.Owner, target.Repo, target.CommitSHA)
+	}
+	return specs.FindLatestCompletedAnalysis(ctx, target.Owner, target.Repo)
+}
+
+// CompareWithUpstream compares doc with the latest completed analysis of the repository it was forked from.
+// Returns analysis.ErrAnalysisNotFound if the repository is not a fork of an analyzed codebase.
+func CompareWithUpstream(ctx context.Context, specs analysis.SpecRepository, doc *analysis.SpecDocument) (*analysis.ForkComparison, error) {
+	upstreamID, err := specs.FindUpstreamLatestCompletedAnalysis(ctx, doc.Owner, doc.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	upstream, err := specs.GetSpecDocument(ctx, upstreamID)
+	if err != nil {
+		return nil, fmt.Errorf("load upstream analysis %s: %w", upstreamID, err)
+	}
+	return analysis.CompareFork(doc, upstream), nil
+}
+
+func export(databaseURL string, target SpecTarget, format specdoc.Format, out string, compareUpstream bool) error {
+	ctx := context.Background()
+
+	pool, err := db.NewPool(ctx, databaseURL)
+	if err != nil {
+		return fmt.Errorf("database connection: %w", err)
+	}
+	defer pool.Close()
+
+	specs := postgres.NewSpecRepository(pool)
+	analysisID, err := ResolveAnalysisID(ctx, specs, target)
+	if err != nil {
+		return fmt.Errorf("resolve analysis: %w", err)
+	}
+
+	doc, err := specs.GetSpecDocument(ctx, analysisID)
+	if err != nil {
+		return fmt.Errorf("load analysis %s: %w", analysisID, err)
+	}
+
+	pages, err := specdoc.Export(out, doc, format)
+	if err != nil {
+		return fmt.Errorf("write spec: %w", err)
+	}
+
+	slog.Info("spec exported",
+		"owner", doc.Owner,
+		"repo", doc.Repo,
+		"commit", doc.CommitSHA,
+		"format", format,
+		"pages", pages,
+		"out", out,
+	)
+
+	if !compareUpstream {
+		return nil
+	}
+
+	comparison, err := CompareWithUpstream(ctx, specs, doc)
+	if err != nil {
+		return fmt.Errorf("compare with upstream: %w", err)
+	}
+	if err := specdoc.ExportComparison(out, comparison, format); err != nil {
+		return fmt.Errorf("write upstream comparison: %w", err)
+	}
+
+	slog.Info("upstream comparison exported",
+		"upstream_owner", comparison.Upstream.Owner,
+		"upstream_repo", comparison.Upstream.Repo,
+		"upstream_commit", comparison.Upstream.CommitSHA,
+		"shared", comparison.Shared,
+		"added", len(comparison.Added),
+		"removed", len(comparison.Removed),
+	)
+	return nil
+}
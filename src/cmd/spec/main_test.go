@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+type mockSpecRepository struct {
+	findCompletedFn func(ctx context.Context, owner, repo, commitSHA string) (analysis.UUID, error)
+	findLatestFn    func(ctx context.Context, owner, repo string) (analysis.UUID, error)
+	findUpstreamFn  func(ctx context.Context, owner, repo string) (analysis.UUID, error)
+	getSpecDocFn    func(ctx context.Context, analysisID analysis.UUID) (*analysis.SpecDocument, error)
+}
+
+func (m *mockSpecRepository) FindCompletedAnalysis(ctx context.Context, owner, repo, commitSHA string) (analysis.UUID, error) {
+	return m.findCompletedFn(ctx, owner, repo, commitSHA)
+}
+
+func (m *mockSpecRepository) FindLatestCompletedAnalysis(ctx context.Context, owner, repo string) (analysis.UUID, error) {
+	return m.findLatestFn(ctx, owner, repo)
+}
+
+func (m *mockSpecRepository) FindUpstreamLatestCompletedAnalysis(ctx context.Context, owner, repo string) (analysis.UUID, error) {
+	if m.findUpstreamFn != nil {
+		return m.findUpstreamFn(ctx, owner, repo)
+	}
+	return analysis.NilUUID, analysis.ErrAnalysisNotFound
+}
+
+func (m *mockSpecRepository) GetSpecDocument(ctx context.Context, analysisID analysis.UUID) (*analysis.SpecDocument, error) {
+	if m.getSpecDocFn != nil {
+		return m.getSpecDocFn(ctx, analysisID)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func TestResolveAnalysisID(t *testing.T) {
+	byCommit := uuid.New()
+	latest := uuid.New()
+	explicit := uuid.New()
+	specs := &mockSpecRepository{
+		findCompletedFn: func(ctx context.Context, owner, repo, commitSHA string) (analysis.UUID, error) {
+			if owner != "owner" || repo != "repo" || commitSHA != "abc" {
+				return analysis.NilUUID, analysis.ErrAnalysisNotFound
+			}
+			return byCommit, nil
+		},
+		findLatestFn: func(ctx context.Context, owner, repo string) (analysis.UUID, error) {
+			return latest, nil
+		},
+	}
+
+	tests := []struct {
+		name    string
+		target  SpecTarget
+		want    analysis.UUID
+		wantErr error
+	}{
+		{name: "explicit analysis", target: SpecTarget{AnalysisID: explicit, CommitSHA: "abc"}, want: explicit},
+		{name: "commit", target: SpecTarget{CommitSHA: "abc", Owner: "owner", Repo: "repo"}, want: byCommit},
+		{name: "latest", target: SpecTarget{Owner: "owner", Repo: "repo"}, want: latest},
+		{name: "unknown commit", target: SpecTarget{CommitSHA: "def", Owner: "owner", Repo: "repo"}, wantErr: analysis.ErrAnalysisNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveAnalysisID(context.Background(), specs, tt.target)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCompareWithUpstream(t *testing.T) {
+	upstreamID := uuid.New()
+	upstream := &analysis.SpecDocument{
+		Inventory: &analysis.Inventory{Files: []analysis.TestFile{
+			{Path: "a.test.ts", Tests: []analysis.Test{{Name: "shared"}, {Name: "dropped"}}},
+		}},
+		Owner: "upstream",
+		Repo:  "repo",
+	}
+	fork := &analysis.SpecDocument{
+		Inventory: &analysis.Inventory{Files: []analysis.TestFile{
+			{Path: "a.test.ts", Tests: []analysis.Test{{Name: "shared"}, {Name: "new"}}},
+		}},
+		Owner: "owner",
+		Repo:  "repo",
+	}
+	specs := &mockSpecRepository{
+		findUpstreamFn: func(ctx context.Context, owner, repo string) (analysis.UUID, error) {
+			if owner != "owner" || repo != "repo" {
+				return analysis.NilUUID, analysis.ErrAnalysisNotFound
+			}
+			return upstreamID, nil
+		},
+		getSpecDocFn: func(ctx context.Context, analysisID analysis.UUID) (*analysis.SpecDocument, error) {
+			if analysisID != upstreamID {
+				return nil, analysis.ErrAnalysisNotFound
+			}
+			return upstream, nil
+		},
+	}
+
+	got, err := CompareWithUpstream(context.Background(), specs, fork)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Upstream != upstream || got.Shared != 1 || len(got.Added) != 1 || len(got.Removed) != 1 {
+		t.Errorf("unexpected comparison: %+v", got)
+	}
+
+	_, err = CompareWithUpstream(context.Background(), specs, upstream)
+	if !errors.Is(err, analysis.ErrAnalysisNotFound) {
+		t.Errorf("expected ErrAnalysisNotFound for a non-fork, got %v", err)
+	}
+}
@@ -21,9 +21,13 @@ func main() {
 	}
 
 	if err := bootstrap.StartWorker(bootstrap.WorkerConfig{
-		ServiceName:   "worker",
-		DatabaseURL:   cfg.DatabaseURL,
-		EncryptionKey: cfg.EncryptionKey,
+		ServiceName:         "worker",
+		DatabaseURL:         cfg.DatabaseURL,
+		EncryptionKey:       cfg.EncryptionKey,
+		EncryptionKeys:      cfg.EncryptionKeys,
+		ChurnWindow:         cfg.ChurnWindow,
+		GitHubAppID:         cfg.GitHubAppID,
+		GitHubAppPrivateKey: cfg.GitHubAppPrivateKey,
 	}); err != nil {
 		slog.Error("worker failed", "error", err)
 		os.Exit(1)
@@ -26,16 +26,23 @@ type coreSourceProvider interface {
 
 // Scan implements analysis.Parser by delegating to the core parser
 // and converting the result to domain types.
-func (p *CoreParser) Scan(ctx context.Context, src analysis.Source) (*analysis.Inventory, error) {
+// Include patterns are pushed down to the core scanner; exclude patterns and
+// framework filters are applied to the converted inventory.
+func (p *CoreParser) Scan(ctx context.Context, src analysis.Source, opts analysis.ScanOptions) (*analysis.Inventory, error) {
 	provider, ok := src.(coreSourceProvider)
 	if !ok {
 		return nil, fmt.Errorf("source does not implement coreSourceProvider interface")
 	}
 
-	result, err := parser.Scan(ctx, provider.CoreSource())
+	var scanOpts []parser.ScanOption
+	if len(opts.Include) > 0 {
+		scanOpts = append(scanOpts, parser.WithPatterns(opts.Include))
+	}
+
+	result, err := parser.Scan(ctx, provider.CoreSource(), scanOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("core parser scan: %w", err)
 	}
 
-	return mapping.ConvertCoreToDomainInventory(result.Inventory), nil
+	return opts.Filter(mapping.ConvertCoreToDomainInventory(result.Inventory)), nil
 }
@@ -2,11 +2,16 @@ package parser
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/core/pkg/source"
+
+	_ "github.com/specvital/core/pkg/parser/strategies/gotesting"
 )
 
 func TestNewCoreParser(t *testing.T) {
@@ -22,7 +27,7 @@ func TestCoreParser_Scan_InvalidSourceType(t *testing.T) {
 	// mockSource doesn't implement coreSourceProvider
 	mockSrc := &mockInvalidSource{}
 
-	_, err := parser.Scan(context.Background(), mockSrc)
+	_, err := parser.Scan(context.Background(), mockSrc, analysis.ScanOptions{})
 	if err == nil {
 		t.Fatal("expected error for source not implementing coreSourceProvider")
 	}
@@ -31,6 +36,48 @@ func TestCoreParser_Scan_InvalidSourceType(t *testing.T) {
 	}
 }
 
+func TestCoreParser_Scan_WithOptions(t *testing.T) {
+	root := t.TempDir()
+	testFile := "package a\n\nimport \"testing\"\n\nfunc TestA(t *testing.T) {}\n"
+	for _, p := range []string{"pkg/a/a_test.go", "pkg/fixtures/f_test.go", "tools/t_test.go"} {
+		full := filepath.Join(root, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(full, []byte(testFile), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	core, err := source.NewLocalSource(root)
+	if err != nil {
+		t.Fatalf("create local source: %v", err)
+	}
+	src := &localTestSource{core: core}
+
+	inventory, err := NewCoreParser().Scan(context.Background(), src, analysis.ScanOptions{
+		Include: []string{"pkg/**"},
+		Exclude: []string{"**/fixtures/**"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(inventory.Files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(inventory.Files))
+	}
+	if inventory.Files[0].Path != "pkg/a/a_test.go" {
+		t.Errorf("unexpected file path: %s", inventory.Files[0].Path)
+	}
+}
+
+// localTestSource wraps a core LocalSource to satisfy analysis.Source and coreSourceProvider.
+type localTestSource struct {
+	mockInvalidSource
+	core *source.LocalSource
+}
+
+func (s *localTestSource) CoreSource() source.Source { return s.core }
+
 // mockInvalidSource implements analysis.Source but not coreSourceProvider.
 type mockInvalidSource struct{}
 
@@ -41,6 +88,10 @@ func (m *mockInvalidSource) CommitSHA() string             { return "" }
 func (m *mockInvalidSource) CommittedAt() time.Time        { return time.Time{} }
 func (m *mockInvalidSource) Close(_ context.Context) error { return nil }
 
+func (m *mockInvalidSource) Checkout(_ context.Context, _ string) error {
+	return nil
+}
+
 func (m *mockInvalidSource) VerifyCommitExists(_ context.Context, _ string) (bool, error) {
 	return true, nil
 }
@@ -11,13 +11,18 @@ import (
 	uc "github.com/specvital/collector/internal/usecase/analysis"
 )
 
-const maxRetryAttempts = 3
+const (
+	maxRetryAttempts = 3
+	// minRateLimitSnooze keeps a reset time already in the past from re-running the job in a tight loop.
+	minRateLimitSnooze = time.Second
+)
 
 type AnalyzeArgs struct {
-	CommitSHA string  `json:"commit_sha" river:"unique"`
-	Owner     string  `json:"owner" river:"unique"`
-	Repo      string  `json:"repo" river:"unique"`
-	UserID    *string `json:"user_id,omitempty"`
+	CommitSHA   string  `json:"commit_sha" river:"unique"`
+	ExactCommit bool    `json:"exact_commit,omitempty"`
+	Owner       string  `json:"owner" river:"unique"`
+	Repo        string  `json:"repo" river:"unique"`
+	UserID      *string `json:"user_id,omitempty"`
 }
 
 func (AnalyzeArgs) Kind() string { return "analysis:analyze" }
@@ -62,10 +67,11 @@ func (w *AnalyzeWorker) Work(ctx context.Context, job *river.Job[AnalyzeArgs]) e
 	)
 
 	req := analysis.AnalyzeRequest{
-		Owner:     args.Owner,
-		Repo:      args.Repo,
-		CommitSHA: args.CommitSHA,
-		UserID:    args.UserID,
+		Owner:       args.Owner,
+		Repo:        args.Repo,
+		CommitSHA:   args.CommitSHA,
+		ExactCommit: args.ExactCommit,
+		UserID:      args.UserID,
 	}
 
 	if err := w.analyzeUC.Execute(ctx, req); err != nil {
@@ -79,6 +85,20 @@ func (w *AnalyzeWorker) Work(ctx context.Context, job *river.Job[AnalyzeArgs]) e
 			return river.JobCancel(err)
 		}
 
+		var rateLimitErr *analysis.RateLimitError
+		if errors.As(err, &rateLimitErr) {
+			snooze := max(time.Until(rateLimitErr.ResetAt), minRateLimitSnooze)
+			slog.WarnContext(ctx, "rate limited, snoozing analyze task",
+				"job_id", job.ID,
+				"owner", args.Owner,
+				"repo", args.Repo,
+				"commit", args.CommitSHA,
+				"reset_at", rateLimitErr.ResetAt,
+				"snooze", snooze,
+			)
+			return river.JobSnooze(snooze)
+		}
+
 		slog.ErrorContext(ctx, "analyze task failed",
 			"job_id", job.ID,
 			"owner", args.Owner,
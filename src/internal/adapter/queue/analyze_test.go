@@ -3,6 +3,7 @@ package queue
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -47,6 +48,10 @@ func (m *mockSource) Branch() string {
 	return "main"
 }
 
+func (m *mockSource) Checkout(ctx context.Context, sha string) error {
+	return nil
+}
+
 func (m *mockSource) CommitSHA() string {
 	if m.commitSHAFn != nil {
 		return m.commitSHAFn()
@@ -73,12 +78,12 @@ func (m *mockSource) VerifyCommitExists(ctx context.Context, sha string) (bool,
 }
 
 type mockParser struct {
-	scanFn func(ctx context.Context, src analysis.Source) (*analysis.Inventory, error)
+	scanFn func(ctx context.Context, src analysis.Source, opts analysis.ScanOptions) (*analysis.Inventory, error)
 }
 
-func (m *mockParser) Scan(ctx context.Context, src analysis.Source) (*analysis.Inventory, error) {
+func (m *mockParser) Scan(ctx context.Context, src analysis.Source, opts analysis.ScanOptions) (*analysis.Inventory, error) {
 	if m.scanFn != nil {
-		return m.scanFn(ctx, src)
+		return m.scanFn(ctx, src, opts)
 	}
 	return &analysis.Inventory{Files: []analysis.TestFile{}}, nil
 }
@@ -124,7 +129,7 @@ func (m *mockCodebaseRepository) FindWithLastCommit(ctx context.Context, host, o
 	return nil, analysis.ErrCodebaseNotFound
 }
 
-func (m *mockCodebaseRepository) MarkStale(ctx context.Context, id analysis.UUID) error {
+func (m *mockCodebaseRepository) MarkStale(ctx context.Context, id analysis.UUID, reason analysis.StaleReason) error {
 	return nil
 }
 
@@ -146,6 +151,10 @@ func (m *mockCodebaseRepository) UpdateOwnerName(ctx context.Context, id analysi
 	return &analysis.Codebase{ID: id, Owner: owner, Name: name}, nil
 }
 
+func (m *mockCodebaseRepository) UpdateMetadata(ctx context.Context, id analysis.UUID, info analysis.RepoInfo) error {
+	return nil
+}
+
 func (m *mockCodebaseRepository) UpdateVisibility(ctx context.Context, id analysis.UUID, isPrivate bool) error {
 	return nil
 }
@@ -195,7 +204,7 @@ func newSuccessfulMocks() (*mockRepository, *mockVCS, *mockParser) {
 	}
 
 	parser := &mockParser{
-		scanFn: func(ctx context.Context, src analysis.Source) (*analysis.Inventory, error) {
+		scanFn: func(ctx context.Context, src analysis.Source, opts analysis.ScanOptions) (*analysis.Inventory, error) {
 			return &analysis.Inventory{Files: []analysis.TestFile{}}, nil
 		},
 	}
@@ -287,7 +296,7 @@ func TestAnalyzeWorker_Work(t *testing.T) {
 				}
 
 				parser := &mockParser{
-					scanFn: func(ctx context.Context, src analysis.Source) (*analysis.Inventory, error) {
+					scanFn: func(ctx context.Context, src analysis.Source, opts analysis.ScanOptions) (*analysis.Inventory, error) {
 						return nil, errors.New("parser error")
 					},
 				}
@@ -477,7 +486,7 @@ func TestAnalyzeWorker_Work_ErrorPropagation(t *testing.T) {
 				}
 
 				parser := &mockParser{
-					scanFn: func(ctx context.Context, src analysis.Source) (*analysis.Inventory, error) {
+					scanFn: func(ctx context.Context, src analysis.Source, opts analysis.ScanOptions) (*analysis.Inventory, error) {
 						return nil, errors.New("scan error")
 					},
 				}
@@ -581,3 +590,42 @@ func TestAnalyzeWorker_Work_AlreadyCompleted(t *testing.T) {
 		}
 	})
 }
+
+type mockRateLimitedVCSAPIClient struct {
+	resetAt time.Time
+}
+
+func (m *mockRateLimitedVCSAPIClient) GetRepoInfo(ctx context.Context, host, owner, repo string, token *string) (analysis.RepoInfo, error) {
+	return analysis.RepoInfo{}, fmt.Errorf("get repository %s/%s: %w", owner, repo, &analysis.RateLimitError{ResetAt: m.resetAt})
+}
+
+func TestAnalyzeWorker_Work_RateLimited(t *testing.T) {
+	tests := []struct {
+		name    string
+		resetAt time.Time
+		minWait time.Duration
+		maxWait time.Duration
+	}{
+		{name: "snoozes until the reset time", resetAt: time.Now().Add(10 * time.Minute), minWait: 9 * time.Minute, maxWait: 10 * time.Minute},
+		{name: "past reset time snoozes briefly", resetAt: time.Now().Add(-time.Minute), minWait: minRateLimitSnooze, maxWait: minRateLimitSnooze},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, vcs, parser := newSuccessfulMocks()
+			vcsAPI := &mockRateLimitedVCSAPIClient{resetAt: tt.resetAt}
+			analyzeUC := uc.NewAnalyzeUseCase(repo, &mockCodebaseRepository{}, vcs, vcsAPI, parser, nil)
+			worker := NewAnalyzeWorker(analyzeUC)
+
+			err := worker.Work(context.Background(), newTestJob(AnalyzeArgs{Owner: "owner", Repo: "repo", CommitSHA: "abc123"}))
+
+			var snoozeErr *rivertype.JobSnoozeError
+			if !errors.As(err, &snoozeErr) {
+				t.Fatalf("expected JobSnoozeError, got %v", err)
+			}
+			if snoozeErr.Duration < tt.minWait || snoozeErr.Duration > tt.maxWait {
+				t.Errorf("expected snooze between %v and %v, got %v", tt.minWait, tt.maxWait, snoozeErr.Duration)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/riverqueue/river"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/usecase/backfill"
+)
+
+type BackfillArgs struct {
+	Every      int       `json:"every,omitempty" river:"unique"`
+	MaxCommits int       `json:"max_commits,omitempty" river:"unique"`
+	Owner      string    `json:"owner" river:"unique"`
+	Repo       string    `json:"repo" river:"unique"`
+	Since      time.Time `json:"since,omitempty" river:"unique"`
+	Strategy   string    `json:"strategy" river:"unique"`
+	UserID     *string   `json:"user_id,omitempty"`
+}
+
+func (BackfillArgs) Kind() string { return "analysis:backfill" }
+
+func (BackfillArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		MaxAttempts: maxRetryAttempts,
+		UniqueOpts: river.UniqueOpts{
+			ByArgs: true,
+		},
+	}
+}
+
+func (a BackfillArgs) request() analysis.BackfillRequest {
+	return analysis.BackfillRequest{
+		Owner: a.Owner,
+		Repo:  a.Repo,
+		Plan: analysis.BackfillPlan{
+			Every:      a.Every,
+			MaxCommits: a.MaxCommits,
+			Since:      a.Since,
+			Strategy:   analysis.BackfillStrategy(a.Strategy),
+		},
+		UserID: a.UserID,
+	}
+}
+
+type BackfillWorker struct {
+	river.WorkerDefaults[BackfillArgs]
+	backfillUC *backfill.BackfillUseCase
+}
+
+func NewBackfillWorker(backfillUC *backfill.BackfillUseCase) *BackfillWorker {
+	return &BackfillWorker{backfillUC: backfillUC}
+}
+
+// Timeout covers a treeless clone of the full history; enqueueing itself is cheap.
+func (w *BackfillWorker) Timeout(job *river.Job[BackfillArgs]) time.Duration {
+	return 10 * time.Minute
+}
+
+func (w *BackfillWorker) Work(ctx context.Context, job *river.Job[BackfillArgs]) error {
+	args := job.Args
+
+	slog.InfoContext(ctx, "processing backfill task",
+		"job_id", job.ID,
+		"owner", args.Owner,
+		"repo", args.Repo,
+		"strategy", args.Strategy,
+	)
+
+	enqueued, err := w.backfillUC.Execute(ctx, args.request())
+	if err != nil {
+		if errors.Is(err, analysis.ErrInvalidInput) {
+			slog.ErrorContext(ctx, "invalid backfill request, cancelling job",
+				"job_id", job.ID,
+				"owner", args.Owner,
+				"repo", args.Repo,
+				"error", err,
+			)
+			return river.JobCancel(err)
+		}
+
+		slog.ErrorContext(ctx, "backfill task failed",
+			"job_id", job.ID,
+			"owner", args.Owner,
+			"repo", args.Repo,
+			"enqueued", enqueued,
+			"error", err,
+		)
+		return err
+	}
+
+	slog.InfoContext(ctx, "backfill task completed",
+		"job_id", job.ID,
+		"owner", args.Owner,
+		"repo", args.Repo,
+		"enqueued", enqueued,
+	)
+
+	return nil
+}
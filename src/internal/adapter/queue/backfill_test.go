@@ -0,0 +1,107 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/usecase/access"
+	"github.com/specvital/collector/internal/usecase/backfill"
+)
+
+type mockHistoryReader struct {
+	listCommitsFn func(ctx context.Context, url string, token *string, since time.Time) ([]analysis.HistoryCommit, error)
+}
+
+func (m *mockHistoryReader) ListCommits(ctx context.Context, url string, token *string, since time.Time) ([]analysis.HistoryCommit, error) {
+	if m.listCommitsFn != nil {
+		return m.listCommitsFn(ctx, url, token, since)
+	}
+	return nil, nil
+}
+
+func (m *mockHistoryReader) ListTags(ctx context.Context, url string, token *string, since time.Time) ([]analysis.HistoryCommit, error) {
+	return nil, nil
+}
+
+type mockHistoricalTaskQueue struct {
+	enqueued []string
+}
+
+func (m *mockHistoricalTaskQueue) EnqueueHistoricalAnalysis(ctx context.Context, owner, repo, commitSHA string, userID *string) error {
+	m.enqueued = append(m.enqueued, commitSHA)
+	return nil
+}
+
+func newBackfillTestJob(args BackfillArgs) *river.Job[BackfillArgs] {
+	return &river.Job[BackfillArgs]{
+		JobRow: &rivertype.JobRow{
+			ID: 1,
+		},
+		Args: args,
+	}
+}
+
+func TestBackfillWorker_Work(t *testing.T) {
+	history := []analysis.HistoryCommit{
+		{SHA: "c3", CommittedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{SHA: "c2", CommittedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{SHA: "c1", CommittedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	tests := []struct {
+		name       string
+		args       BackfillArgs
+		historyErr error
+		wantCancel bool
+		wantErr    bool
+		wantQueued int
+	}{
+		{
+			name:       "enqueues sampled commits",
+			args:       BackfillArgs{Owner: "owner", Repo: "repo", Strategy: "every_n", Every: 1},
+			wantQueued: 3,
+		},
+		{
+			name:       "invalid strategy cancels job",
+			args:       BackfillArgs{Owner: "owner", Repo: "repo", Strategy: "daily"},
+			wantCancel: true,
+			wantErr:    true,
+		},
+		{
+			name:       "history failure is retried",
+			args:       BackfillArgs{Owner: "owner", Repo: "repo", Strategy: "weekly"},
+			historyErr: errors.New("clone failed"),
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &mockHistoryReader{
+				listCommitsFn: func(ctx context.Context, url string, token *string, since time.Time) ([]analysis.HistoryCommit, error) {
+					return history, tt.historyErr
+				},
+			}
+			taskQueue := &mockHistoricalTaskQueue{}
+			worker := NewBackfillWorker(backfill.NewBackfillUseCase(reader, taskQueue, access.NewResolver(nil)))
+
+			err := worker.Work(context.Background(), newBackfillTestJob(tt.args))
+
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("wantErr %v, got %v", tt.wantErr, err)
+			}
+			var cancelErr *river.JobCancelError
+			if tt.wantCancel != errors.As(err, &cancelErr) {
+				t.Errorf("wantCancel %v, got %v", tt.wantCancel, err)
+			}
+			if len(taskQueue.enqueued) != tt.wantQueued {
+				t.Errorf("expected %d enqueued, got %v", tt.wantQueued, taskQueue.enqueued)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/riverqueue/river"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/usecase/ingest"
+)
+
+// IngestResultsArgs refers to the report by its upload ID, since reports are too large for job arguments.
+type IngestResultsArgs struct {
+	CommitSHA  string `json:"commit_sha"`
+	Format     string `json:"format"`
+	Owner      string `json:"owner"`
+	Repo       string `json:"repo"`
+	ReportName string `json:"report_name"`
+	UploadID   string `json:"upload_id"`
+}
+
+func (IngestResultsArgs) Kind() string { return "analysis:ingest_results" }
+
+func (IngestResultsArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		MaxAttempts: maxRetryAttempts,
+	}
+}
+
+func (a IngestResultsArgs) request(report []byte) analysis.IngestResultsRequest {
+	return analysis.IngestResultsRequest{
+		CommitSHA:  a.CommitSHA,
+		Format:     analysis.ReportFormat(a.Format),
+		Owner:      a.Owner,
+		Repo:       a.Repo,
+		Report:     report,
+		ReportName: a.ReportName,
+	}
+}
+
+type IngestResultsWorker struct {
+	river.WorkerDefaults[IngestResultsArgs]
+	ingestUC *ingest.IngestResultsUseCase
+	uploads  analysis.ReportUploadStore
+}
+
+func NewIngestResultsWorker(ingestUC *ingest.IngestResultsUseCase, uploads analysis.ReportUploadStore) *IngestResultsWorker {
+	return &IngestResultsWorker{ingestUC: ingestUC, uploads: uploads}
+}
+
+// Work retries when the commit has no completed analysis yet, since CI usually
+// uploads results while the analysis of the same push is still running.
+// The upload is released once no later attempt will read it.
+func (w *IngestResultsWorker) Work(ctx context.Context, job *river.Job[IngestResultsArgs]) error {
+	args := job.Args
+
+	slog.InfoContext(ctx, "processing ingest results task",
+		"job_id", job.ID,
+		"owner", args.Owner,
+		"repo", args.Repo,
+		"commit", args.CommitSHA,
+		"report", args.ReportName,
+	)
+
+	summary, err := w.execute(ctx, args)
+	if err != nil {
+		if isInvalidUpload(err) {
+			releaseReportUpload(ctx, w.uploads, args.UploadID)
+			slog.ErrorContext(ctx, "invalid test report, cancelling job",
+				"job_id", job.ID,
+				"owner", args.Owner,
+				"repo", args.Repo,
+				"report", args.ReportName,
+				"error", err,
+			)
+			return river.JobCancel(err)
+		}
+		if job.Attempt >= job.MaxAttempts {
+			releaseReportUpload(ctx, w.uploads, args.UploadID)
+		}
+
+		slog.ErrorContext(ctx, "ingest results task failed",
+			"job_id", job.ID,
+			"owner", args.Owner,
+			"repo", args.Repo,
+			"commit", args.CommitSHA,
+			"error", err,
+		)
+		return err
+	}
+	releaseReportUpload(ctx, w.uploads, args.UploadID)
+
+	slog.InfoContext(ctx, "ingest results task completed",
+		"job_id", job.ID,
+		"owner", args.Owner,
+		"repo", args.Repo,
+		"executions", summary.Executions,
+		"matched", summary.Matched,
+	)
+
+	return nil
+}
+
+func (w *IngestResultsWorker) execute(ctx context.Context, args IngestResultsArgs) (ingest.Summary, error) {
+	report, err := loadReportUpload(ctx, w.uploads, args.UploadID)
+	if err != nil {
+		return ingest.Summary{}, err
+	}
+	return w.ingestUC.Execute(ctx, args.request(report))
+}
+
+// IngestCoverageArgs refers to the report by its upload ID, like IngestResultsArgs.
+type IngestCoverageArgs struct {
+	CommitSHA  string  `json:"commit_sha"`
+	Format     string  `json:"format"`
+	Owner      string  `json:"owner"`
+	Repo       string  `json:"repo"`
+	ReportName string  `json:"report_name"`
+	UploadID   string  `json:"upload_id"`
+	UserID     *string `json:"user_id,omitempty"`
+}
+
+func (IngestCoverageArgs) Kind() string { return "analysis:ingest_coverage" }
+
+func (IngestCoverageArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		MaxAttempts: maxRetryAttempts,
+	}
+}
+
+func (a IngestCoverageArgs) request(report []byte) analysis.IngestCoverageRequest {
+	return analysis.IngestCoverageRequest{
+		CommitSHA:  a.CommitSHA,
+		Format:     analysis.ReportFormat(a.Format),
+		Owner:      a.Owner,
+		Repo:       a.Repo,
+		Report:     report,
+		ReportName: a.ReportName,
+		UserID:     a.UserID,
+	}
+}
+
+type IngestCoverageWorker struct {
+	river.WorkerDefaults[IngestCoverageArgs]
+	coverageUC *ingest.IngestCoverageUseCase
+	uploads    analysis.ReportUploadStore
+}
+
+func NewIngestCoverageWorker(coverageUC *ingest.IngestCoverageUseCase, uploads analysis.ReportUploadStore) *IngestCoverageWorker {
+	return &IngestCoverageWorker{coverageUC: coverageUC, uploads: uploads}
+}
+
+// Timeout covers cloning the repository to normalize report paths.
+func (w *IngestCoverageWorker) Timeout(job *river.Job[IngestCoverageArgs]) time.Duration {
+	return 5 * time.Minute
+}
+
+// Work retries when the commit has no completed analysis yet, like IngestResultsWorker.
+func (w *IngestCoverageWorker) Work(ctx context.Context, job *river.Job[IngestCoverageArgs]) error {
+	args := job.Args
+
+	slog.InfoContext(ctx, "processing ingest coverage task",
+		"job_id", job.ID,
+		"owner", args.Owner,
+		"repo", args.Repo,
+		"commit", args.CommitSHA,
+		"report", args.ReportName,
+	)
+
+	summary, err := w.execute(ctx, args)
+	if err != nil {
+		if isInvalidUpload(err) {
+			releaseReportUpload(ctx, w.uploads, args.UploadID)
+			slog.ErrorContext(ctx, "invalid coverage report, cancelling job",
+				"job_id", job.ID,
+				"owner", args.Owner,
+				"repo", args.Repo,
+				"report", args.ReportName,
+				"error", err,
+			)
+			return river.JobCancel(err)
+		}
+		if job.Attempt >= job.MaxAttempts {
+			releaseReportUpload(ctx, w.uploads, args.UploadID)
+		}
+
+		slog.ErrorContext(ctx, "ingest coverage task failed",
+			"job_id", job.ID,
+			"owner", args.Owner,
+			"repo", args.Repo,
+			"commit", args.CommitSHA,
+			"error", err,
+		)
+		return err
+	}
+	releaseReportUpload(ctx, w.uploads, args.UploadID)
+
+	slog.InfoContext(ctx, "ingest coverage task completed",
+		"job_id", job.ID,
+		"owner", args.Owner,
+		"repo", args.Repo,
+		"files", summary.Files,
+		"unmatched", summary.Unmatched,
+	)
+
+	return nil
+}
+
+func (w *IngestCoverageWorker) execute(ctx context.Context, args IngestCoverageArgs) (ingest.CoverageSummary, error) {
+	report, err := loadReportUpload(ctx, w.uploads, args.UploadID)
+	if err != nil {
+		return ingest.CoverageSummary{}, err
+	}
+	return w.coverageUC.Execute(ctx, args.request(report))
+}
+
+// isInvalidUpload reports whether retrying the job cannot help.
+func isInvalidUpload(err error) bool {
+	return errors.Is(err, analysis.ErrInvalidInput) ||
+		errors.Is(err, analysis.ErrInvalidReport) ||
+		errors.Is(err, analysis.ErrReportUploadNotFound)
+}
+
+func loadReportUpload(ctx context.Context, uploads analysis.ReportUploadStore, uploadID string) ([]byte, error) {
+	id, err := analysis.ParseUUID(uploadID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: upload ID %q: %w", analysis.ErrInvalidInput, uploadID, err)
+	}
+	report, err := uploads.GetReportUpload(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("load report upload %s: %w", uploadID, err)
+	}
+	return report, nil
+}
+
+// releaseReportUpload deletes an upload no attempt will read again. A failure only leaves
+// the report behind, so it is logged rather than failing the job.
+func releaseReportUpload(ctx context.Context, uploads analysis.ReportUploadStore, uploadID string) {
+	id, err := analysis.ParseUUID(uploadID)
+	if err != nil {
+		return
+	}
+	if err := uploads.DeleteReportUpload(ctx, id); err != nil {
+		slog.WarnContext(ctx, "failed to release report upload, ignoring",
+			"upload_id", uploadID,
+			"error", err,
+		)
+	}
+}
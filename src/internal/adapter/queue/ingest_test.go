@@ -0,0 +1,251 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/specvital/collector/internal/adapter/report"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/usecase/access"
+	"github.com/specvital/collector/internal/usecase/ingest"
+)
+
+type mockExecutionRepository struct {
+	findErr error
+	saved   []analysis.ExecutionMatch
+}
+
+func (m *mockExecutionRepository) FindCompletedAnalysis(ctx context.Context, owner, repo, commitSHA string) (analysis.UUID, error) {
+	if m.findErr != nil {
+		return analysis.NilUUID, m.findErr
+	}
+	return analysis.NewUUID(), nil
+}
+
+func (m *mockExecutionRepository) GetStaticTests(ctx context.Context, analysisID analysis.UUID) ([]analysis.StaticTest, error) {
+	return nil, nil
+}
+
+func (m *mockExecutionRepository) SaveTestExecutions(ctx context.Context, analysisID analysis.UUID, reportName string, matches []analysis.ExecutionMatch) error {
+	m.saved = matches
+	return nil
+}
+
+type mockReportUploads struct {
+	reports map[analysis.UUID][]byte
+}
+
+func newMockReportUploads() *mockReportUploads {
+	return &mockReportUploads{reports: map[analysis.UUID][]byte{}}
+}
+
+// upload stores report and returns its upload ID; a nil report stores nothing.
+func (m *mockReportUploads) upload(report []byte) string {
+	id := analysis.NewUUID()
+	if report != nil {
+		m.reports[id] = report
+	}
+	return id.String()
+}
+
+func (m *mockReportUploads) GetReportUpload(ctx context.Context, id analysis.UUID) ([]byte, error) {
+	report, ok := m.reports[id]
+	if !ok {
+		return nil, analysis.ErrReportUploadNotFound
+	}
+	return report, nil
+}
+
+func (m *mockReportUploads) DeleteReportUpload(ctx context.Context, id analysis.UUID) error {
+	delete(m.reports, id)
+	return nil
+}
+
+func newIngestTestJob(args IngestResultsArgs, attempt int) *river.Job[IngestResultsArgs] {
+	return &river.Job[IngestResultsArgs]{
+		JobRow: &rivertype.JobRow{
+			Attempt:     attempt,
+			ID:          1,
+			MaxAttempts: maxRetryAttempts,
+		},
+		Args: args,
+	}
+}
+
+func TestIngestResultsWorker_Work(t *testing.T) {
+	validReport := []byte(`<testsuite name="s"><testcase name="a"/><testcase name="b"/></testsuite>`)
+	validArgs := IngestResultsArgs{CommitSHA: "abc", Format: "junit", Owner: "owner", Repo: "repo", ReportName: "unit"}
+
+	tests := []struct {
+		name         string
+		args         IngestResultsArgs
+		attempt      int
+		findErr      error
+		report       []byte
+		wantCancel   bool
+		wantErr      bool
+		wantReleased bool
+		wantSaved    int
+	}{
+		{
+			name:         "saves executions",
+			args:         validArgs,
+			report:       validReport,
+			wantReleased: true,
+			wantSaved:    2,
+		},
+		{
+			name:         "malformed report cancels job",
+			args:         validArgs,
+			report:       []byte("not xml"),
+			wantCancel:   true,
+			wantErr:      true,
+			wantReleased: true,
+		},
+		{
+			name:         "missing report name cancels job",
+			args:         IngestResultsArgs{CommitSHA: "abc", Format: "junit", Owner: "owner", Repo: "repo"},
+			report:       validReport,
+			wantCancel:   true,
+			wantErr:      true,
+			wantReleased: true,
+		},
+		{
+			name:       "missing upload cancels job",
+			args:       validArgs,
+			wantCancel: true,
+			wantErr:    true,
+		},
+		{
+			name:    "pending analysis is retried",
+			args:    validArgs,
+			findErr: analysis.ErrAnalysisNotFound,
+			report:  validReport,
+			wantErr: true,
+		},
+		{
+			name:         "last attempt releases the upload",
+			args:         validArgs,
+			attempt:      maxRetryAttempts,
+			findErr:      analysis.ErrAnalysisNotFound,
+			report:       validReport,
+			wantErr:      true,
+			wantReleased: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockExecutionRepository{findErr: tt.findErr}
+			uploads := newMockReportUploads()
+			tt.args.UploadID = uploads.upload(tt.report)
+			worker := NewIngestResultsWorker(ingest.NewIngestResultsUseCase(report.NewParser(), repo), uploads)
+
+			err := worker.Work(context.Background(), newIngestTestJob(tt.args, max(tt.attempt, 1)))
+
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("wantErr %v, got %v", tt.wantErr, err)
+			}
+			var cancelErr *river.JobCancelError
+			if tt.wantCancel != errors.As(err, &cancelErr) {
+				t.Errorf("wantCancel %v, got %v", tt.wantCancel, err)
+			}
+			if len(repo.saved) != tt.wantSaved {
+				t.Errorf("expected %d saved, got %d", tt.wantSaved, len(repo.saved))
+			}
+			if released := len(uploads.reports) == 0; tt.report != nil && released != tt.wantReleased {
+				t.Errorf("wantReleased %v, got %v", tt.wantReleased, released)
+			}
+		})
+	}
+}
+
+type mockCoverageRepository struct {
+	saved []analysis.FileCoverage
+}
+
+func (m *mockCoverageRepository) FindCompletedAnalysis(ctx context.Context, owner, repo, commitSHA string) (analysis.UUID, error) {
+	return analysis.NewUUID(), nil
+}
+
+func (m *mockCoverageRepository) SaveFileCoverage(ctx context.Context, analysisID analysis.UUID, reportName string, files []analysis.FileCoverage) error {
+	m.saved = files
+	return nil
+}
+
+type mockFileLister struct{}
+
+func (m *mockFileLister) ListFiles(ctx context.Context, src analysis.Source) ([]string, error) {
+	return []string{"src/a.ts", "src/b.ts"}, nil
+}
+
+func TestIngestCoverageWorker_Work(t *testing.T) {
+	validReport := []byte("SF:/ci/src/a.ts\nDA:1,1\nend_of_record\nSF:/ci/src/b.ts\nDA:1,0\nend_of_record\n")
+
+	tests := []struct {
+		name       string
+		args       IngestCoverageArgs
+		report     []byte
+		wantCancel bool
+		wantErr    bool
+		wantSaved  int
+	}{
+		{
+			name:      "saves normalized coverage",
+			args:      IngestCoverageArgs{CommitSHA: "abc123", Format: "lcov", Owner: "owner", Repo: "repo", ReportName: "frontend"},
+			report:    validReport,
+			wantSaved: 2,
+		},
+		{
+			name:       "malformed report cancels job",
+			args:       IngestCoverageArgs{CommitSHA: "abc123", Format: "cobertura", Owner: "owner", Repo: "repo", ReportName: "frontend"},
+			report:     []byte("not xml"),
+			wantCancel: true,
+			wantErr:    true,
+		},
+		{
+			name:       "unsupported format cancels job",
+			args:       IngestCoverageArgs{CommitSHA: "abc123", Format: "junit", Owner: "owner", Repo: "repo", ReportName: "frontend"},
+			report:     validReport,
+			wantCancel: true,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockCoverageRepository{}
+			vcs := &mockVCS{
+				cloneFn: func(ctx context.Context, url string, token *string) (analysis.Source, error) {
+					return &mockSource{}, nil
+				},
+			}
+			uc := ingest.NewIngestCoverageUseCase(report.NewParser(), repo, access.NewCloner(vcs, nil, access.NewCloneLimiter(1)), &mockFileLister{}, access.NewResolver(nil))
+			uploads := newMockReportUploads()
+			tt.args.UploadID = uploads.upload(tt.report)
+			worker := NewIngestCoverageWorker(uc, uploads)
+
+			err := worker.Work(context.Background(), &river.Job[IngestCoverageArgs]{
+				JobRow: &rivertype.JobRow{Attempt: 1, ID: 1, MaxAttempts: maxRetryAttempts},
+				Args:   tt.args,
+			})
+
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("wantErr %v, got %v", tt.wantErr, err)
+			}
+			var cancelErr *river.JobCancelError
+			if tt.wantCancel != errors.As(err, &cancelErr) {
+				t.Errorf("wantCancel %v, got %v", tt.wantCancel, err)
+			}
+			if len(repo.saved) != tt.wantSaved {
+				t.Errorf("expected %d saved, got %d", tt.wantSaved, len(repo.saved))
+			}
+			if len(uploads.reports) != 0 {
+				t.Error("expected the upload to be released")
+			}
+		})
+	}
+}
@@ -0,0 +1,414 @@
+package repofs
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+// CIConfigPatterns lists the CI configuration files read for test commands.
+var CIConfigPatterns = []string{
+	".circleci/config.yml",
+	".github/workflows/*.yaml",
+	".github/workflows/*.yml",
+	".gitlab-ci.yml",
+	".travis.yml",
+	"azure-pipelines.yml",
+	"bitbucket-pipelines.yml",
+}
+
+// ciCommandKeys are the YAML keys holding shell commands across the supported CI systems.
+var ciCommandKeys = map[string]bool{
+	"bash":          true,
+	"before_script": true,
+	"command":       true,
+	"powershell":    true,
+	"pwsh":          true,
+	"run":           true,
+	"script":        true,
+}
+
+// Flags of package managers and make that take a value, which must not be mistaken for a script or target.
+var ciValueFlags = []string{"--cwd", "--dir", "--filter", "--prefix", "--workspace", "-C", "-F", "-f", "-w"}
+
+var (
+	makeTargetPattern   = regexp.MustCompile(`^([A-Za-z0-9_./-]+(?:\s+[A-Za-z0-9_./-]+)*)\s*::?(?:\s+(.*))?$`)
+	shellCommandPattern = regexp.MustCompile(`\s*(?:&&|\|\||;|\|)\s*`)
+)
+
+// maxCIResolveDepth bounds how many package scripts, make targets and shell scripts a command is followed through.
+const maxCIResolveDepth = 4
+
+var _ analysis.CIConfigLoader = (*CIConfigLoader)(nil)
+
+// CIConfigLoader implements analysis.CIConfigLoader for GitHub Actions, GitLab CI, CircleCI,
+// Azure Pipelines, Bitbucket Pipelines and Travis CI configuration files.
+type CIConfigLoader struct{}
+
+// NewCIConfigLoader creates a new CIConfigLoader.
+func NewCIConfigLoader() *CIConfigLoader {
+	return &CIConfigLoader{}
+}
+
+type ciStep struct {
+	command string
+	job     string
+	uses    bool
+}
+
+// Load keeps only commands that run at least one test framework.
+// Configuration files that fail to parse are skipped.
+func (l *CIConfigLoader) Load(ctx context.Context, src analysis.Source, files []string) (*analysis.CIReport, error) {
+	coreSrc, err := coreSource(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []string
+	for _, f := range files {
+		if analysis.MatchesAnyGlob(CIConfigPatterns, f) {
+			configs = append(configs, f)
+		}
+	}
+	if len(configs) == 0 {
+		return nil, nil
+	}
+
+	tree := newRepoTree(coreSrc, files)
+	resolver := &ciResolver{tree: tree}
+	report := &analysis.CIReport{ConfigPaths: configs}
+	for _, config := range configs {
+		data, readErr := tree.read(ctx, config)
+		if readErr != nil {
+			return nil, readErr
+		}
+		var doc any
+		if yamlErr := yaml.Unmarshal(data, &doc); yamlErr != nil {
+			slog.DebugContext(ctx, "skipping unparseable CI config", "path", config, "error", yamlErr)
+			continue
+		}
+
+		for _, step := range collectCISteps(doc, nil, nil) {
+			var frameworks []string
+			if step.uses {
+				frameworks = analysis.ActionFrameworks(step.command)
+			} else {
+				frameworks = resolver.frameworks(ctx, step.command, 0)
+			}
+			if len(frameworks) == 0 {
+				continue
+			}
+			report.Commands = append(report.Commands, analysis.CICommand{
+				Command:    step.command,
+				ConfigPath: config,
+				Frameworks: frameworks,
+				Job:        step.job,
+			})
+		}
+	}
+	return report, nil
+}
+
+// collectCISteps walks a parsed CI document in key order and returns its shell commands
+// and GitHub Actions references with the job declaring them.
+func collectCISteps(node any, keys []string, steps []ciStep) []ciStep {
+	switch v := node.(type) {
+	case map[string]any:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			child := v[name]
+			childKeys := append(slices.Clone(keys), name)
+			if name == "uses" {
+				if uses, ok := child.(string); ok {
+					steps = append(steps, ciStep{command: uses, job: ciJobName(keys), uses: true})
+				}
+				continue
+			}
+			if ciCommandKeys[name] {
+				for _, script := range ciScripts(child) {
+					for _, command := range splitShellCommands(script) {
+						steps = append(steps, ciStep{command: command, job: ciJobName(keys)})
+					}
+				}
+			}
+			steps = collectCISteps(child, childKeys, steps)
+		}
+	case []any:
+		for _, item := range v {
+			steps = collectCISteps(item, keys, steps)
+		}
+	}
+	return steps
+}
+
+// ciScripts returns the scripts of a command key: a string or a list of strings.
+func ciScripts(node any) []string {
+	switch v := node.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var scripts []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				scripts = append(scripts, s)
+			}
+		}
+		return scripts
+	}
+	return nil
+}
+
+// ciJobName is the key under "jobs" (GitHub Actions, CircleCI) or the top-level key (GitLab CI).
+func ciJobName(keys []string) string {
+	if i := slices.Index(keys, "jobs"); i >= 0 && i+1 < len(keys) {
+		return keys[i+1]
+	}
+	if len(keys) > 0 {
+		return keys[0]
+	}
+	return ""
+}
+
+// splitShellCommands splits a script into simple commands, joining continued lines
+// and splitting on command separators and pipes. Comments and blank lines are dropped
+// and whitespace is collapsed.
+func splitShellCommands(script string) []string {
+	script = strings.ReplaceAll(script, "\\\n", " ")
+
+	var commands []string
+	for _, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, command := range shellCommandPattern.Split(line, -1) {
+			if command = strings.Join(strings.Fields(command), " "); command != "" {
+				commands = append(commands, command)
+			}
+		}
+	}
+	return commands
+}
+
+// ciResolver follows commands through package scripts, make targets and shell scripts.
+type ciResolver struct {
+	makeTargets    map[string]makeTarget
+	packageScripts map[string][]string
+	tree           *repoTree
+}
+
+type makeTarget struct {
+	prerequisites []string
+	recipe        []string
+}
+
+func (r *ciResolver) frameworks(ctx context.Context, command string, depth int) []string {
+	if frameworks := analysis.RunnerFrameworks(command); len(frameworks) > 0 {
+		return frameworks
+	}
+	if depth >= maxCIResolveDepth {
+		return nil
+	}
+
+	var frameworks []string
+	for _, delegated := range r.delegates(ctx, command) {
+		for _, next := range splitShellCommands(delegated) {
+			frameworks = append(frameworks, r.frameworks(ctx, next, depth+1)...)
+		}
+	}
+	slices.Sort(frameworks)
+	return slices.Compact(frameworks)
+}
+
+// delegates returns the scripts a command hands off to, or nil if it runs nothing known.
+func (r *ciResolver) delegates(ctx context.Context, command string) []string {
+	args := analysis.CommandArgs(command)
+	if len(args) == 0 {
+		return nil
+	}
+
+	tool := path.Base(args[0])
+	rest := withoutFlags(args[1:])
+	switch tool {
+	case "npm":
+		if len(rest) == 0 {
+			return nil
+		}
+		switch rest[0] {
+		case "t", "test", "tst":
+			return r.scripts(ctx, "test")
+		case "run", "run-script":
+			if len(rest) > 1 {
+				return r.scripts(ctx, rest[1])
+			}
+		}
+	case "bun", "pnpm", "yarn":
+		if len(rest) == 0 {
+			return nil
+		}
+		if rest[0] == "run" {
+			if len(rest) > 1 {
+				return r.scripts(ctx, rest[1])
+			}
+			return nil
+		}
+		if scripts := r.scripts(ctx, rest[0]); scripts != nil {
+			return scripts
+		}
+		// Unknown names run a binary of node_modules/.bin.
+		return []string{strings.Join(rest, " ")}
+	case "lerna", "nx", "turbo":
+		for _, name := range rest {
+			if scripts := r.scripts(ctx, name); scripts != nil {
+				return scripts
+			}
+		}
+	case "gmake", "make":
+		return r.makeRecipes(ctx, rest)
+	case "bash", "sh", "zsh":
+		if len(rest) > 0 {
+			return r.shellScript(ctx, rest[0])
+		}
+	default:
+		return r.shellScript(ctx, args[0])
+	}
+	return nil
+}
+
+// scripts returns the bodies of a package.json script across all packages of the repository,
+// since CI commands of monorepos often fan out to every workspace.
+func (r *ciResolver) scripts(ctx context.Context, name string) []string {
+	if r.packageScripts == nil {
+		r.packageScripts = make(map[string][]string)
+		for file := range r.tree.files {
+			if path.Base(file) != "package.json" {
+				continue
+			}
+			data, err := r.tree.read(ctx, file)
+			if err != nil {
+				continue
+			}
+			var pkg struct {
+				Scripts map[string]string `json:"scripts"`
+			}
+			if json.Unmarshal(data, &pkg) != nil {
+				continue
+			}
+			for script, body := range pkg.Scripts {
+				r.packageScripts[script] = append(r.packageScripts[script], body)
+			}
+		}
+	}
+	return r.packageScripts[name]
+}
+
+// makeRecipes returns the recipes of the targets and their prerequisites as make invocations.
+// Without targets, the first target of the Makefile is used.
+func (r *ciResolver) makeRecipes(ctx context.Context, targets []string) []string {
+	if r.makeTargets == nil {
+		r.makeTargets = r.parseMakefile(ctx)
+	}
+	if len(targets) == 0 {
+		if first, ok := r.makeTargets[""]; ok {
+			targets = first.prerequisites
+		}
+	}
+
+	var recipes []string
+	for _, name := range targets {
+		target, ok := r.makeTargets[name]
+		if !ok {
+			continue
+		}
+		recipes = append(recipes, target.recipe...)
+		for _, prerequisite := range target.prerequisites {
+			recipes = append(recipes, "make "+prerequisite)
+		}
+	}
+	return recipes
+}
+
+// parseMakefile reads the root Makefile. The entry under "" names the default target.
+func (r *ciResolver) parseMakefile(ctx context.Context) map[string]makeTarget {
+	targets := make(map[string]makeTarget)
+	var data []byte
+	for _, name := range []string{"GNUmakefile", "makefile", "Makefile"} {
+		if content, err := r.tree.read(ctx, name); err == nil {
+			data = content
+			break
+		}
+	}
+	if data == nil {
+		return targets
+	}
+
+	var current []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "\t") {
+			recipe := strings.TrimLeft(strings.TrimSpace(line), "@-+")
+			recipe = strings.NewReplacer("$(MAKE)", "make", "${MAKE}", "make").Replace(recipe)
+			for _, name := range current {
+				target := targets[name]
+				target.recipe = append(target.recipe, recipe)
+				targets[name] = target
+			}
+			continue
+		}
+
+		m := makeTargetPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil || strings.HasPrefix(m[1], ".") {
+			current = nil
+			continue
+		}
+		current = strings.Fields(m[1])
+		prerequisites := strings.Fields(m[2])
+		for _, name := range current {
+			target := targets[name]
+			target.prerequisites = append(target.prerequisites, prerequisites...)
+			targets[name] = target
+		}
+		if _, ok := targets[""]; !ok {
+			targets[""] = makeTarget{prerequisites: current[:1]}
+		}
+	}
+	return targets
+}
+
+// shellScript returns the lines of a repository shell script, or nil if p is not one.
+func (r *ciResolver) shellScript(ctx context.Context, p string) []string {
+	if !strings.HasSuffix(p, ".sh") {
+		return nil
+	}
+	data, err := r.tree.read(ctx, path.Clean(strings.TrimPrefix(p, "./")))
+	if err != nil {
+		return nil
+	}
+	return []string{string(data)}
+}
+
+func withoutFlags(args []string) []string {
+	var result []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			result = append(result, arg)
+			continue
+		}
+		if slices.Contains(ciValueFlags, arg) {
+			i++
+		}
+	}
+	return result
+}
@@ -0,0 +1,119 @@
+package repofs
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+func TestCIConfigLoader_Load(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []analysis.CICommand
+	}{
+		{
+			name: "github actions direct runner",
+			files: map[string]string{
+				".github/workflows/ci.yml": `
+jobs:
+  test:
+    steps:
+      - uses: actions/checkout@v4
+      - run: go vet ./...
+      - run: |
+          go build ./...
+          go test -race ./...
+`,
+			},
+			want: []analysis.CICommand{
+				{Command: "go test -race ./...", ConfigPath: ".github/workflows/ci.yml", Frameworks: []string{"go-testing"}, Job: "test"},
+			},
+		},
+		{
+			name: "npm script across workspaces",
+			files: map[string]string{
+				".github/workflows/test.yaml": "jobs:\n  unit:\n    steps:\n      - run: npm ci && npm test\n",
+				"package.json":                `{"scripts": {"test": "turbo run test"}}`,
+				"apps/web/package.json":       `{"scripts": {"test": "vitest run"}}`,
+				"apps/api/package.json":       `{"scripts": {"test": "jest --ci"}}`,
+			},
+			want: []analysis.CICommand{
+				{Command: "npm test", ConfigPath: ".github/workflows/test.yaml", Frameworks: []string{"jest", "vitest"}, Job: "unit"},
+			},
+		},
+		{
+			name: "gitlab make target with prerequisites",
+			files: map[string]string{
+				".gitlab-ci.yml": "test:\n  script:\n    - make check\n",
+				"Makefile":       ".PHONY: check\ncheck: lint unit\n\nlint:\n\tgolangci-lint run\n\nunit:\n\t@$(MAKE) -C api pytest\n\tgo test ./...\n",
+			},
+			want: []analysis.CICommand{
+				{Command: "make check", ConfigPath: ".gitlab-ci.yml", Frameworks: []string{"go-testing"}, Job: "test"},
+			},
+		},
+		{
+			name: "circleci shell script and action",
+			files: map[string]string{
+				".circleci/config.yml": "jobs:\n  build:\n    steps:\n      - run:\n          name: tests\n          command: ./scripts/ci.sh\n",
+				"scripts/ci.sh":        "#!/bin/sh\nset -e\nbundle exec rspec\n",
+				".github/workflows/e2e.yml": `
+jobs:
+  e2e:
+    steps:
+      - uses: cypress-io/github-action@v6
+`,
+			},
+			want: []analysis.CICommand{
+				{Command: "./scripts/ci.sh", ConfigPath: ".circleci/config.yml", Frameworks: []string{"rspec"}, Job: "build"},
+				{Command: "cypress-io/github-action@v6", ConfigPath: ".github/workflows/e2e.yml", Frameworks: []string{"cypress"}, Job: "e2e"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := newTestSource(t, tt.files)
+
+			report, err := NewCIConfigLoader().Load(context.Background(), src, listTestFiles(t, src))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if report == nil {
+				t.Fatal("expected report, got nil")
+			}
+			if !reflect.DeepEqual(report.Commands, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, report.Commands)
+			}
+		})
+	}
+}
+
+func TestCIConfigLoader_Load_NoConfig(t *testing.T) {
+	src := newTestSource(t, map[string]string{"main.go": "package main"})
+
+	report, err := NewCIConfigLoader().Load(context.Background(), src, listTestFiles(t, src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report != nil {
+		t.Errorf("expected nil report, got %+v", report)
+	}
+}
+
+func TestCIConfigLoader_Load_InvalidSource(t *testing.T) {
+	_, err := NewCIConfigLoader().Load(context.Background(), &mockInvalidSource{}, nil)
+	if err == nil {
+		t.Error("expected error for unsupported source")
+	}
+}
+
+func TestSplitShellCommands(t *testing.T) {
+	got := splitShellCommands("# setup\nnpm ci \\\n  --silent && npm run build; npm test | tee out.log\n")
+	want := []string{"npm ci --silent", "npm run build", "npm test", "tee out.log"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,113 @@
+package repofs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+// CodeownersPaths lists the CODEOWNERS locations in GitHub's lookup order.
+var CodeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// GitHub ignores CODEOWNERS files larger than 3 MB.
+const maxCodeownersFileSize = 3 * 1024 * 1024
+
+var _ analysis.CodeownersLoader = (*CodeownersLoader)(nil)
+
+// CodeownersLoader implements analysis.CodeownersLoader for GitHub-style CODEOWNERS files.
+type CodeownersLoader struct{}
+
+// NewCodeownersLoader creates a new CodeownersLoader.
+func NewCodeownersLoader() *CodeownersLoader {
+	return &CodeownersLoader{}
+}
+
+// Load reads the first existing file of CodeownersPaths.
+// Lines with patterns GitHub does not support (negation, character ranges) are skipped.
+func (l *CodeownersLoader) Load(ctx context.Context, src analysis.Source) (*analysis.Codeowners, error) {
+	coreSrc, err := coreSource(src)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range CodeownersPaths {
+		rc, openErr := coreSrc.Open(ctx, name)
+		if openErr != nil {
+			if errors.Is(openErr, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("open %s: %w", name, openErr)
+		}
+
+		data, readErr := io.ReadAll(io.LimitReader(rc, maxCodeownersFileSize+1))
+		rc.Close()
+		if readErr != nil {
+			return nil, fmt.Errorf("read %s: %w", name, readErr)
+		}
+		if len(data) > maxCodeownersFileSize {
+			return nil, fmt.Errorf("%s exceeds %d bytes", name, maxCodeownersFileSize)
+		}
+
+		return &analysis.Codeowners{Path: name, Rules: parseCodeowners(data)}, nil
+	}
+
+	return nil, nil
+}
+
+func parseCodeowners(data []byte) []analysis.CodeownersRule {
+	var rules []analysis.CodeownersRule
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxCodeownersFileSize)
+	for scanner.Scan() {
+		fields := splitCodeownersLine(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		pattern := fields[0]
+		if strings.HasPrefix(pattern, "!") || strings.ContainsAny(pattern, "[]") || !doublestar.ValidatePattern(pattern) {
+			continue
+		}
+		rules = append(rules, analysis.CodeownersRule{
+			Owners:  fields[1:],
+			Pattern: pattern,
+		})
+	}
+	return rules
+}
+
+// splitCodeownersLine strips comments and splits on whitespace, honoring "\#" and "\ " escapes in the pattern.
+func splitCodeownersLine(line string) []string {
+	var fields []string
+	var current strings.Builder
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line) && (line[i+1] == '#' || line[i+1] == ' '):
+			current.WriteByte(line[i+1])
+			i++
+		case c == '#':
+			i = len(line)
+		case c == ' ' || c == '\t' || c == '\r':
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
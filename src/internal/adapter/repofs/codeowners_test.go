@@ -0,0 +1,89 @@
+package repofs
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+func TestCodeownersLoader_Load(t *testing.T) {
+	t.Run("no codeowners file", func(t *testing.T) {
+		src := newTestSource(t, map[string]string{"README.md": ""})
+
+		got, err := NewCodeownersLoader().Load(context.Background(), src)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != nil {
+			t.Errorf("expected nil codeowners, got %+v", got)
+		}
+	})
+
+	t.Run("github directory takes precedence", func(t *testing.T) {
+		src := newTestSource(t, map[string]string{
+			".github/CODEOWNERS": "* @org/github\n",
+			"CODEOWNERS":         "* @org/root\n",
+			"docs/CODEOWNERS":    "* @org/docs\n",
+		})
+
+		got, err := NewCodeownersLoader().Load(context.Background(), src)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Path != ".github/CODEOWNERS" {
+			t.Errorf("expected .github/CODEOWNERS, got %s", got.Path)
+		}
+	})
+
+	t.Run("falls back to docs directory", func(t *testing.T) {
+		src := newTestSource(t, map[string]string{"docs/CODEOWNERS": "* @org/docs\n"})
+
+		got, err := NewCodeownersLoader().Load(context.Background(), src)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got == nil || got.Path != "docs/CODEOWNERS" {
+			t.Fatalf("expected docs/CODEOWNERS, got %+v", got)
+		}
+	})
+
+	t.Run("invalid source", func(t *testing.T) {
+		_, err := NewCodeownersLoader().Load(context.Background(), &mockInvalidSource{})
+		if err == nil {
+			t.Fatal("expected error for source not implementing coreSourceProvider")
+		}
+	})
+}
+
+func TestParseCodeowners(t *testing.T) {
+	content := `# Default owners
+*       @org/everyone
+
+/api/   @org/backend @alice  # inline comment
+*.md    docs@example.com
+/legacy/
+\#notes.txt @bob
+!keep.txt @nobody
+[Ab]*.go @nobody
+`
+
+	got := parseCodeowners([]byte(content))
+
+	want := []analysis.CodeownersRule{
+		{Pattern: "*", Owners: []string{"@org/everyone"}},
+		{Pattern: "/api/", Owners: []string{"@org/backend", "@alice"}},
+		{Pattern: "*.md", Owners: []string{"docs@example.com"}},
+		{Pattern: "/legacy/", Owners: []string{}},
+		{Pattern: "#notes.txt", Owners: []string{"@bob"}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d rules, got %d: %+v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i].Pattern != want[i].Pattern || !slices.Equal(got[i].Owners, want[i].Owners) {
+			t.Errorf("rule %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
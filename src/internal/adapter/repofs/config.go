@@ -0,0 +1,146 @@
+package repofs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v3"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+// ConfigFileNames lists the repository config file names in lookup order.
+var ConfigFileNames = []string{".specvital.yml", ".specvital.yaml"}
+
+const maxConfigFileSize = 256 * 1024
+
+var _ analysis.RepoConfigLoader = (*ConfigLoader)(nil)
+
+// ConfigLoader implements analysis.RepoConfigLoader by reading
+// the first existing file of ConfigFileNames at the repository root.
+type ConfigLoader struct{}
+
+// NewConfigLoader creates a new ConfigLoader.
+func NewConfigLoader() *ConfigLoader {
+	return &ConfigLoader{}
+}
+
+type fileConfig struct {
+	Frameworks fileFilter   `yaml:"frameworks"`
+	Paths      filePaths    `yaml:"paths"`
+	Policies   []filePolicy `yaml:"policies"`
+	Workspaces []string     `yaml:"workspaces"`
+}
+
+type fileFilter struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+}
+
+type filePaths struct {
+	Exclude []string `yaml:"exclude"`
+	Include []string `yaml:"include"`
+}
+
+type filePolicy struct {
+	Kind      string  `yaml:"kind"`
+	Name      string  `yaml:"name"`
+	Path      string  `yaml:"path"`
+	Status    string  `yaml:"status"`
+	Threshold float64 `yaml:"threshold"`
+}
+
+// Load returns an empty config when no config file exists.
+// Invalid policy rules are skipped and reported in InvalidPolicies.
+//
+// Example .specvital.yml:
+//
+//	paths:
+//	  include: ["packages/**"]
+//	  exclude: ["**/fixtures/**", "third_party/**"]
+//	frameworks:
+//	  deny: [mocha]
+//	workspaces: ["packages/*"]
+//	policies:
+//	  - name: no-focused
+//	    kind: max_status_count
+//	    status: focused
+func (l *ConfigLoader) Load(ctx context.Context, src analysis.Source) (*analysis.RepoConfig, error) {
+	coreSrc, err := coreSource(src)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range ConfigFileNames {
+		rc, openErr := coreSrc.Open(ctx, name)
+		if openErr != nil {
+			if errors.Is(openErr, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("open %s: %w", name, openErr)
+		}
+
+		data, readErr := io.ReadAll(io.LimitReader(rc, maxConfigFileSize+1))
+		rc.Close()
+		if readErr != nil {
+			return nil, fmt.Errorf("read %s: %w", name, readErr)
+		}
+		if len(data) > maxConfigFileSize {
+			return nil, fmt.Errorf("%w: %s exceeds %d bytes", analysis.ErrInvalidRepoConfig, name, maxConfigFileSize)
+		}
+
+		return parseConfig(name, data)
+	}
+
+	return &analysis.RepoConfig{}, nil
+}
+
+func parseConfig(name string, data []byte) (*analysis.RepoConfig, error) {
+	var raw fileConfig
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: parse %s: %w", analysis.ErrInvalidRepoConfig, name, err)
+	}
+
+	for _, pattern := range slices.Concat(raw.Paths.Include, raw.Paths.Exclude, raw.Workspaces) {
+		if !doublestar.ValidatePattern(pattern) {
+			return nil, fmt.Errorf("%w: %s: invalid glob pattern %q", analysis.ErrInvalidRepoConfig, name, pattern)
+		}
+	}
+
+	sum := sha256.Sum256(data)
+	cfg := &analysis.RepoConfig{
+		Exclude:        raw.Paths.Exclude,
+		FrameworkAllow: raw.Frameworks.Allow,
+		FrameworkDeny:  raw.Frameworks.Deny,
+		Hash:           hex.EncodeToString(sum[:]),
+		Include:        raw.Paths.Include,
+		WorkspaceRoots: raw.Workspaces,
+	}
+	for i, p := range raw.Policies {
+		rule := analysis.PolicyRule{
+			Kind:      analysis.PolicyRuleKind(p.Kind),
+			Name:      p.Name,
+			Path:      p.Path,
+			Source:    analysis.PolicySourceRepository,
+			Status:    analysis.TestStatus(p.Status),
+			Threshold: p.Threshold,
+		}
+		if err := rule.Validate(); err != nil {
+			// A nameless rule is still reported, under its position in the file.
+			if rule.Name == "" {
+				rule.Name = fmt.Sprintf("policies[%d]", i)
+			}
+			cfg.InvalidPolicies = append(cfg.InvalidPolicies, analysis.InvalidPolicyResult(rule, err))
+			continue
+		}
+		cfg.Policies = append(cfg.Policies, rule)
+	}
+	return cfg, nil
+}
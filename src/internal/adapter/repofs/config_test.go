@@ -0,0 +1,192 @@
+package repofs
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+func TestConfigLoader_Load(t *testing.T) {
+	t.Run("no config file returns empty config", func(t *testing.T) {
+		src := newTestSource(t, map[string]string{"main.go": ""})
+
+		cfg, err := NewConfigLoader().Load(context.Background(), src)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(cfg.Policies) != 0 {
+			t.Errorf("expected no policies, got %d", len(cfg.Policies))
+		}
+	})
+
+	t.Run("parses policies", func(t *testing.T) {
+		src := newTestSource(t, map[string]string{
+			".specvital.yml": `
+policies:
+  - name: no-focused
+    kind: max_status_count
+    status: focused
+  - name: skipped-ratio
+    kind: max_status_ratio
+    status: skipped
+    threshold: 0.05
+  - name: src-tested
+    kind: require_test_file
+    path: "src/**"
+`,
+		})
+
+		cfg, err := NewConfigLoader().Load(context.Background(), src)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(cfg.Policies) != 3 {
+			t.Fatalf("expected 3 policies, got %d", len(cfg.Policies))
+		}
+		if cfg.Policies[1].Threshold != 0.05 {
+			t.Errorf("expected threshold 0.05, got %v", cfg.Policies[1].Threshold)
+		}
+		if cfg.Policies[2].Path != "src/**" {
+			t.Errorf("expected path src/**, got %q", cfg.Policies[2].Path)
+		}
+		for _, p := range cfg.Policies {
+			if p.Source != analysis.PolicySourceRepository {
+				t.Errorf("expected source repository, got %s", p.Source)
+			}
+		}
+	})
+
+	t.Run("parses scan filters and workspaces", func(t *testing.T) {
+		src := newTestSource(t, map[string]string{
+			".specvital.yml": `
+paths:
+  include: ["packages/**"]
+  exclude: ["**/fixtures/**", "third_party/**"]
+frameworks:
+  allow: [jest, vitest]
+  deny: [mocha]
+workspaces: ["packages/*"]
+`,
+		})
+
+		cfg, err := NewConfigLoader().Load(context.Background(), src)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(cfg.Include, []string{"packages/**"}) {
+			t.Errorf("unexpected include: %v", cfg.Include)
+		}
+		if !slices.Equal(cfg.Exclude, []string{"**/fixtures/**", "third_party/**"}) {
+			t.Errorf("unexpected exclude: %v", cfg.Exclude)
+		}
+		if !slices.Equal(cfg.FrameworkAllow, []string{"jest", "vitest"}) {
+			t.Errorf("unexpected framework allow: %v", cfg.FrameworkAllow)
+		}
+		if !slices.Equal(cfg.FrameworkDeny, []string{"mocha"}) {
+			t.Errorf("unexpected framework deny: %v", cfg.FrameworkDeny)
+		}
+		if !slices.Equal(cfg.WorkspaceRoots, []string{"packages/*"}) {
+			t.Errorf("unexpected workspaces: %v", cfg.WorkspaceRoots)
+		}
+		if len(cfg.Hash) != 64 {
+			t.Errorf("expected sha256 hex hash, got %q", cfg.Hash)
+		}
+	})
+
+	t.Run("hash changes with content", func(t *testing.T) {
+		first, err := NewConfigLoader().Load(context.Background(), newTestSource(t, map[string]string{
+			".specvital.yml": "paths:\n  exclude: [\"a/**\"]\n",
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		second, err := NewConfigLoader().Load(context.Background(), newTestSource(t, map[string]string{
+			".specvital.yml": "paths:\n  exclude: [\"b/**\"]\n",
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if first.Hash == second.Hash {
+			t.Error("expected different hashes for different config content")
+		}
+	})
+
+	t.Run("no config file has empty hash", func(t *testing.T) {
+		cfg, err := NewConfigLoader().Load(context.Background(), newTestSource(t, nil))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Hash != "" {
+			t.Errorf("expected empty hash, got %q", cfg.Hash)
+		}
+	})
+
+	t.Run("invalid glob pattern", func(t *testing.T) {
+		src := newTestSource(t, map[string]string{
+			".specvital.yml": "paths:\n  exclude: [\"src/[a\"]\n",
+		})
+
+		_, err := NewConfigLoader().Load(context.Background(), src)
+		if !errors.Is(err, analysis.ErrInvalidRepoConfig) {
+			t.Errorf("expected ErrInvalidRepoConfig, got %v", err)
+		}
+	})
+
+	t.Run("reads .yaml extension", func(t *testing.T) {
+		src := newTestSource(t, map[string]string{
+			".specvital.yaml": "policies:\n  - name: no-todo\n    kind: max_status_count\n    status: todo\n",
+		})
+
+		cfg, err := NewConfigLoader().Load(context.Background(), src)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(cfg.Policies) != 1 {
+			t.Errorf("expected 1 policy, got %d", len(cfg.Policies))
+		}
+	})
+
+	t.Run("invalid yaml", func(t *testing.T) {
+		src := newTestSource(t, map[string]string{".specvital.yml": "policies: [unclosed"})
+
+		_, err := NewConfigLoader().Load(context.Background(), src)
+		if !errors.Is(err, analysis.ErrInvalidRepoConfig) {
+			t.Errorf("expected ErrInvalidRepoConfig, got %v", err)
+		}
+	})
+
+	t.Run("invalid policy is skipped and reported", func(t *testing.T) {
+		src := newTestSource(t, map[string]string{
+			".specvital.yml": "paths:\n  exclude: [\"vendor/**\"]\n" +
+				"policies:\n  - name: bad\n    kind: unknown\n  - kind: max_status_count\n" +
+				"  - name: no-focused\n    kind: max_status_count\n    status: focused\n",
+		})
+
+		cfg, err := NewConfigLoader().Load(context.Background(), src)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(cfg.Exclude) != 1 || len(cfg.Policies) != 1 || cfg.Policies[0].Name != "no-focused" {
+			t.Errorf("expected the rest of the config to apply, got %+v", cfg)
+		}
+		if len(cfg.InvalidPolicies) != 2 {
+			t.Fatalf("expected 2 invalid policies, got %+v", cfg.InvalidPolicies)
+		}
+		for i, want := range []string{"bad", "policies[1]"} {
+			result := cfg.InvalidPolicies[i]
+			if result.Name != want || result.Passed || result.Source != analysis.PolicySourceRepository {
+				t.Errorf("expected failed result for %s, got %+v", want, result)
+			}
+		}
+	})
+
+	t.Run("invalid source type", func(t *testing.T) {
+		_, err := NewConfigLoader().Load(context.Background(), &mockInvalidSource{})
+		if err == nil {
+			t.Fatal("expected error for invalid source")
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package repofs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+// Files above this size are generated or vendored bundles rather than hand-written code.
+const maxCountedFileSize = 2 * 1024 * 1024
+
+var _ analysis.LineCounter = (*LineCounter)(nil)
+
+// LineCounter implements analysis.LineCounter by reading files from the clone on disk.
+type LineCounter struct{}
+
+// NewLineCounter creates a new LineCounter.
+func NewLineCounter() *LineCounter {
+	return &LineCounter{}
+}
+
+// CountLines counts lines containing at least one non-whitespace byte.
+// Files that cannot be opened or exceed maxCountedFileSize are omitted.
+func (c *LineCounter) CountLines(ctx context.Context, src analysis.Source, files []string) (map[string]int, error) {
+	coreSrc, err := coreSource(src)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(files))
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		rc, openErr := coreSrc.Open(ctx, file)
+		if openErr != nil {
+			slog.DebugContext(ctx, "skipping unreadable source file", "path", file, "error", openErr)
+			continue
+		}
+		lines, countErr := countNonBlankLines(io.LimitReader(rc, maxCountedFileSize+1))
+		rc.Close()
+		if countErr != nil {
+			if !errors.Is(countErr, errFileTooLarge) {
+				slog.DebugContext(ctx, "skipping unreadable source file", "path", file, "error", countErr)
+			}
+			continue
+		}
+		counts[file] = lines
+	}
+	return counts, nil
+}
+
+var errFileTooLarge = errors.New("file too large")
+
+// countNonBlankLines streams r byte by byte so minified single-line files do not need a line buffer.
+func countNonBlankLines(r io.Reader) (int, error) {
+	br := bufio.NewReader(r)
+	var lines, read int
+	blank := true
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return 0, err
+		}
+		read++
+		if read > maxCountedFileSize {
+			return 0, errFileTooLarge
+		}
+		switch b {
+		case '\n':
+			if !blank {
+				lines++
+			}
+			blank = true
+		case ' ', '\t', '\r', '\v', '\f':
+		default:
+			blank = false
+		}
+	}
+	if !blank {
+		lines++
+	}
+	return lines, nil
+}
@@ -0,0 +1,42 @@
+package repofs
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLineCounter_CountLines(t *testing.T) {
+	t.Run("counts non-blank lines", func(t *testing.T) {
+		src := newTestSource(t, map[string]string{
+			"main.go":    "package main\n\nfunc main() {\n  \t\n}\n",
+			"app.js":     "const a = 1;",
+			"empty.py":   "",
+			"blank.rb":   "\n\n  \n",
+			"big.min.js": strings.Repeat("x", maxCountedFileSize+1),
+		})
+
+		counts, err := NewLineCounter().CountLines(context.Background(), src,
+			[]string{"main.go", "app.js", "empty.py", "blank.rb", "big.min.js", "missing.go"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := map[string]int{"main.go": 3, "app.js": 1, "empty.py": 0, "blank.rb": 0}
+		if len(counts) != len(want) {
+			t.Fatalf("expected %v, got %v", want, counts)
+		}
+		for file, lines := range want {
+			if counts[file] != lines {
+				t.Errorf("%s: expected %d lines, got %d", file, lines, counts[file])
+			}
+		}
+	})
+
+	t.Run("invalid source type", func(t *testing.T) {
+		_, err := NewLineCounter().CountLines(context.Background(), &mockInvalidSource{}, []string{"a.go"})
+		if err == nil {
+			t.Fatal("expected error for invalid source")
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package repofs
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/core/pkg/parser"
+)
+
+var _ analysis.FileLister = (*FileLister)(nil)
+
+// FileLister implements analysis.FileLister by walking the clone on disk.
+// Directories in parser.DefaultSkipPatterns are skipped, matching the core scanner.
+type FileLister struct{}
+
+// NewFileLister creates a new FileLister.
+func NewFileLister() *FileLister {
+	return &FileLister{}
+}
+
+// ListFiles returns all regular files of the source, sorted, as slash-separated relative paths.
+func (l *FileLister) ListFiles(ctx context.Context, src analysis.Source) ([]string, error) {
+	coreSrc, err := coreSource(src)
+	if err != nil {
+		return nil, err
+	}
+
+	root := coreSrc.Root()
+	var files []string
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != root && slices.Contains(parser.DefaultSkipPatterns, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, relErr := filepath.Rel(root, p)
+		if relErr != nil {
+			return relErr
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk source files: %w", err)
+	}
+
+	slices.Sort(files)
+	return files, nil
+}
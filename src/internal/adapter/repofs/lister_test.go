@@ -0,0 +1,48 @@
+package repofs
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestFileLister_ListFiles(t *testing.T) {
+	t.Run("lists files sorted and skips default directories", func(t *testing.T) {
+		src := newTestSource(t, map[string]string{
+			"src/b.ts":                      "",
+			"src/a.ts":                      "",
+			"README.md":                     "",
+			"node_modules/lib/index.js":     "",
+			"vendor/github.com/x/x_test.go": "",
+			"pkg/dist/out.js":               "",
+		})
+
+		files, err := NewFileLister().ListFiles(context.Background(), src)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []string{"README.md", "src/a.ts", "src/b.ts"}
+		if !slices.Equal(files, want) {
+			t.Errorf("expected %v, got %v", want, files)
+		}
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		src := newTestSource(t, map[string]string{"a.go": ""})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := NewFileLister().ListFiles(ctx, src)
+		if err == nil {
+			t.Fatal("expected context cancellation error")
+		}
+	})
+
+	t.Run("invalid source type", func(t *testing.T) {
+		_, err := NewFileLister().ListFiles(context.Background(), &mockInvalidSource{})
+		if err == nil {
+			t.Fatal("expected error for invalid source")
+		}
+	})
+}
@@ -0,0 +1,23 @@
+// Package repofs reads repository files from a cloned analysis.Source.
+package repofs
+
+import (
+	"fmt"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/core/pkg/source"
+)
+
+// coreSourceProvider is implemented by sources that expose
+// the underlying source.Source of the clone.
+type coreSourceProvider interface {
+	CoreSource() source.Source
+}
+
+func coreSource(src analysis.Source) (source.Source, error) {
+	provider, ok := src.(coreSourceProvider)
+	if !ok {
+		return nil, fmt.Errorf("source does not implement coreSourceProvider interface")
+	}
+	return provider.CoreSource(), nil
+}
@@ -0,0 +1,95 @@
+package repofs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/core/pkg/source"
+)
+
+func TestCoreSource_InvalidSourceType(t *testing.T) {
+	_, err := coreSource(&mockInvalidSource{})
+	if err == nil {
+		t.Fatal("expected error for source not implementing coreSourceProvider")
+	}
+	if !strings.Contains(err.Error(), "does not implement coreSourceProvider interface") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+// testSource wraps a core LocalSource to satisfy analysis.Source and coreSourceProvider.
+type testSource struct {
+	core *source.LocalSource
+}
+
+var _ analysis.Source = (*testSource)(nil)
+
+func (s *testSource) Branch() string                { return "main" }
+func (s *testSource) CommitSHA() string             { return "abc123" }
+func (s *testSource) CommittedAt() time.Time        { return time.Time{} }
+func (s *testSource) Close(_ context.Context) error { return s.core.Close() }
+func (s *testSource) CoreSource() source.Source     { return s.core }
+
+func (s *testSource) Checkout(_ context.Context, _ string) error {
+	return nil
+}
+
+func (s *testSource) VerifyCommitExists(_ context.Context, _ string) (bool, error) {
+	return true, nil
+}
+
+// newTestSource creates a source rooted at a temp directory populated with files (path -> content).
+func newTestSource(t *testing.T, files map[string]string) *testSource {
+	t.Helper()
+
+	root := t.TempDir()
+	for name, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatalf("create dir for %s: %v", name, err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	core, err := source.NewLocalSource(root)
+	if err != nil {
+		t.Fatalf("create local source: %v", err)
+	}
+	return &testSource{core: core}
+}
+
+// listTestFiles lists src the way the analysis passes files to the adapters.
+func listTestFiles(t *testing.T, src *testSource) []string {
+	t.Helper()
+
+	files, err := NewFileLister().ListFiles(context.Background(), src)
+	if err != nil {
+		t.Fatalf("list files: %v", err)
+	}
+	return files
+}
+
+// mockInvalidSource implements analysis.Source but not coreSourceProvider.
+type mockInvalidSource struct{}
+
+var _ analysis.Source = (*mockInvalidSource)(nil)
+
+func (m *mockInvalidSource) Branch() string                { return "" }
+func (m *mockInvalidSource) CommitSHA() string             { return "" }
+func (m *mockInvalidSource) CommittedAt() time.Time        { return time.Time{} }
+func (m *mockInvalidSource) Close(_ context.Context) error { return nil }
+
+func (m *mockInvalidSource) Checkout(_ context.Context, _ string) error {
+	return nil
+}
+
+func (m *mockInvalidSource) VerifyCommitExists(_ context.Context, _ string) (bool, error) {
+	return true, nil
+}
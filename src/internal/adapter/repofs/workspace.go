@@ -0,0 +1,396 @@
+package repofs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/core/pkg/source"
+)
+
+const maxManifestSize = 1024 * 1024
+
+var (
+	cargoMembersPattern  = regexp.MustCompile(`(?s)members\s*=\s*\[(.*?)\]`)
+	cargoNamePattern     = regexp.MustCompile(`(?m)^\s*name\s*=\s*"([^"]+)"`)
+	goModulePattern      = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+	goWorkUsePattern     = regexp.MustCompile(`(?m)^\s*use\s+(?:\(([^)]*)\)|(\S+))`)
+	gradleIncludePattern = regexp.MustCompile(`(?m)^\s*include\b(.*)$`)
+	quotedStringPattern  = regexp.MustCompile(`["']([^"']+)["']`)
+	tomlHeaderPattern    = regexp.MustCompile(`(?m)^\s*\[`)
+)
+
+var _ analysis.WorkspaceDetector = (*WorkspaceDetector)(nil)
+
+// WorkspaceDetector implements analysis.WorkspaceDetector for pnpm/npm/yarn workspaces,
+// Go multi-module repositories, Cargo workspaces and Gradle multi-project builds.
+type WorkspaceDetector struct{}
+
+// NewWorkspaceDetector creates a new WorkspaceDetector.
+func NewWorkspaceDetector() *WorkspaceDetector {
+	return &WorkspaceDetector{}
+}
+
+// repoTree is the file listing of a source with helpers for manifest lookups.
+type repoTree struct {
+	core  source.Source
+	dirs  []string
+	files map[string]bool
+}
+
+// Detect returns workspaces sorted by path. Tools are combined, so a repository with
+// both npm workspaces and Go modules reports packages of both; the first tool to claim
+// a directory wins.
+func (d *WorkspaceDetector) Detect(ctx context.Context, src analysis.Source, files []string, roots []string) ([]analysis.Workspace, error) {
+	coreSrc, err := coreSource(src)
+	if err != nil {
+		return nil, err
+	}
+	tree := newRepoTree(coreSrc, files)
+
+	var detected []analysis.Workspace
+	if len(roots) > 0 {
+		detected = tree.configWorkspaces(ctx, roots)
+	} else {
+		detectors := []func(context.Context) ([]analysis.Workspace, error){
+			tree.nodeWorkspaces,
+			tree.goWorkspaces,
+			tree.cargoWorkspaces,
+			tree.gradleWorkspaces,
+		}
+		for _, detect := range detectors {
+			ws, detectErr := detect(ctx)
+			if detectErr != nil {
+				return nil, detectErr
+			}
+			detected = append(detected, ws...)
+		}
+	}
+
+	seen := make(map[string]bool, len(detected))
+	result := make([]analysis.Workspace, 0, len(detected))
+	for _, ws := range detected {
+		if seen[ws.Path] {
+			continue
+		}
+		seen[ws.Path] = true
+		result = append(result, ws)
+	}
+	slices.SortFunc(result, func(a, b analysis.Workspace) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return result, nil
+}
+
+func newRepoTree(core source.Source, files []string) *repoTree {
+	fileSet := make(map[string]bool, len(files))
+	dirSet := make(map[string]bool)
+	for _, f := range files {
+		fileSet[f] = true
+		for dir := path.Dir(f); dir != "."; dir = path.Dir(dir) {
+			dirSet[dir] = true
+		}
+	}
+	dirs := make([]string, 0, len(dirSet))
+	for dir := range dirSet {
+		dirs = append(dirs, dir)
+	}
+	slices.Sort(dirs)
+	return &repoTree{core: core, dirs: dirs, files: fileSet}
+}
+
+func (t *repoTree) configWorkspaces(ctx context.Context, roots []string) []analysis.Workspace {
+	var result []analysis.Workspace
+	for _, dir := range t.matchDirs(roots, "") {
+		result = append(result, analysis.Workspace{
+			Kind: analysis.WorkspaceKindConfig,
+			Name: t.manifestName(ctx, dir),
+			Path: dir,
+		})
+	}
+	return result
+}
+
+func (t *repoTree) nodeWorkspaces(ctx context.Context) ([]analysis.Workspace, error) {
+	var patterns []string
+	kind := analysis.WorkspaceKindNPM
+
+	if t.files["pnpm-workspace.yaml"] {
+		data, err := t.read(ctx, "pnpm-workspace.yaml")
+		if err != nil {
+			return nil, err
+		}
+		var cfg struct {
+			Packages []string `yaml:"packages"`
+		}
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, nil
+		}
+		patterns = cfg.Packages
+		kind = analysis.WorkspaceKindPNPM
+	} else if t.files["package.json"] {
+		data, err := t.read(ctx, "package.json")
+		if err != nil {
+			return nil, err
+		}
+		patterns = parsePackageJSONWorkspaces(data)
+		if t.files["yarn.lock"] {
+			kind = analysis.WorkspaceKindYarn
+		}
+	}
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	var result []analysis.Workspace
+	for _, dir := range t.matchDirs(patterns, "package.json") {
+		result = append(result, analysis.Workspace{
+			Kind: kind,
+			Name: t.packageJSONName(ctx, dir),
+			Path: dir,
+		})
+	}
+	return result, nil
+}
+
+func (t *repoTree) goWorkspaces(ctx context.Context) ([]analysis.Workspace, error) {
+	var dirs []string
+	if t.files["go.work"] {
+		data, err := t.read(ctx, "go.work")
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range goWorkUsePattern.FindAllStringSubmatch(string(data), -1) {
+			for _, dir := range strings.Fields(m[1] + " " + m[2]) {
+				dirs = append(dirs, cleanRelDir(dir))
+			}
+		}
+	} else {
+		for f := range t.files {
+			if path.Base(f) == "go.mod" {
+				dirs = append(dirs, path.Dir(f))
+			}
+		}
+		if len(dirs) < 2 {
+			return nil, nil
+		}
+	}
+
+	var result []analysis.Workspace
+	for _, dir := range dirs {
+		name := dir
+		if data, err := t.read(ctx, path.Join(dir, "go.mod")); err == nil {
+			if m := goModulePattern.FindSubmatch(data); m != nil {
+				name = string(m[1])
+			}
+		}
+		result = append(result, analysis.Workspace{
+			Kind: analysis.WorkspaceKindGoModule,
+			Name: name,
+			Path: dir,
+		})
+	}
+	return result, nil
+}
+
+func (t *repoTree) cargoWorkspaces(ctx context.Context) ([]analysis.Workspace, error) {
+	if !t.files["Cargo.toml"] {
+		return nil, nil
+	}
+	data, err := t.read(ctx, "Cargo.toml")
+	if err != nil {
+		return nil, err
+	}
+	section := tomlSection(string(data), "workspace")
+	m := cargoMembersPattern.FindStringSubmatch(section)
+	if m == nil {
+		return nil, nil
+	}
+	var patterns []string
+	for _, q := range quotedStringPattern.FindAllStringSubmatch(m[1], -1) {
+		patterns = append(patterns, cleanRelDir(q[1]))
+	}
+
+	var result []analysis.Workspace
+	for _, dir := range t.matchDirs(patterns, "Cargo.toml") {
+		name := dir
+		if manifest, readErr := t.read(ctx, path.Join(dir, "Cargo.toml")); readErr == nil {
+			if nm := cargoNamePattern.FindStringSubmatch(tomlSection(string(manifest), "package")); nm != nil {
+				name = nm[1]
+			}
+		}
+		result = append(result, analysis.Workspace{
+			Kind: analysis.WorkspaceKindCargo,
+			Name: name,
+			Path: dir,
+		})
+	}
+	return result, nil
+}
+
+func (t *repoTree) gradleWorkspaces(ctx context.Context) ([]analysis.Workspace, error) {
+	var settings string
+	for _, name := range []string{"settings.gradle.kts", "settings.gradle"} {
+		if t.files[name] {
+			settings = name
+			break
+		}
+	}
+	if settings == "" {
+		return nil, nil
+	}
+	data, err := t.read(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	dirSet := make(map[string]bool, len(t.dirs))
+	for _, dir := range t.dirs {
+		dirSet[dir] = true
+	}
+
+	var result []analysis.Workspace
+	for _, line := range gradleIncludePattern.FindAllStringSubmatch(string(data), -1) {
+		for _, q := range quotedStringPattern.FindAllStringSubmatch(line[1], -1) {
+			project := ":" + strings.TrimPrefix(q[1], ":")
+			dir := strings.ReplaceAll(strings.TrimPrefix(project, ":"), ":", "/")
+			if !dirSet[dir] {
+				continue
+			}
+			result = append(result, analysis.Workspace{
+				Kind: analysis.WorkspaceKindGradle,
+				Name: project,
+				Path: dir,
+			})
+		}
+	}
+	return result, nil
+}
+
+// matchDirs returns directories matching the workspace globs. Patterns prefixed with "!"
+// exclude matches. When manifest is non-empty, only directories containing it qualify.
+func (t *repoTree) matchDirs(patterns []string, manifest string) []string {
+	var include, exclude []string
+	for _, p := range patterns {
+		if negated, ok := strings.CutPrefix(p, "!"); ok {
+			exclude = append(exclude, cleanRelDir(negated))
+		} else {
+			include = append(include, cleanRelDir(p))
+		}
+	}
+
+	var result []string
+	for _, dir := range t.dirs {
+		if manifest != "" && !t.files[path.Join(dir, manifest)] {
+			continue
+		}
+		if analysis.MatchesAnyGlob(include, dir) && !analysis.MatchesAnyGlob(exclude, dir) {
+			result = append(result, dir)
+		}
+	}
+	return result
+}
+
+func (t *repoTree) manifestName(ctx context.Context, dir string) string {
+	if t.files[path.Join(dir, "package.json")] {
+		return t.packageJSONName(ctx, dir)
+	}
+	if data, err := t.read(ctx, path.Join(dir, "go.mod")); err == nil {
+		if m := goModulePattern.FindSubmatch(data); m != nil {
+			return string(m[1])
+		}
+	}
+	if data, err := t.read(ctx, path.Join(dir, "Cargo.toml")); err == nil {
+		if m := cargoNamePattern.FindStringSubmatch(tomlSection(string(data), "package")); m != nil {
+			return m[1]
+		}
+	}
+	return dir
+}
+
+func (t *repoTree) packageJSONName(ctx context.Context, dir string) string {
+	data, err := t.read(ctx, path.Join(dir, "package.json"))
+	if err != nil {
+		return dir
+	}
+	var pkg struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil || pkg.Name == "" {
+		return dir
+	}
+	return pkg.Name
+}
+
+func (t *repoTree) read(ctx context.Context, name string) ([]byte, error) {
+	if !t.files[name] {
+		return nil, fs.ErrNotExist
+	}
+	rc, err := t.core.Open(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxManifestSize))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	return data, nil
+}
+
+// parsePackageJSONWorkspaces supports both the array form and the yarn object form
+// ({"packages": [...]}) of the "workspaces" field.
+func parsePackageJSONWorkspaces(data []byte) []string {
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil || len(pkg.Workspaces) == 0 {
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(pkg.Workspaces, &list); err == nil {
+		return list
+	}
+	var obj struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(pkg.Workspaces, &obj); err == nil {
+		return obj.Packages
+	}
+	return nil
+}
+
+// tomlSection returns the body of a top-level [name] table, up to the next table header.
+func tomlSection(content, name string) string {
+	header := "[" + name + "]"
+	idx := strings.Index(content, header)
+	if idx < 0 {
+		return ""
+	}
+	body := content[idx+len(header):]
+	if next := tomlHeaderPattern.FindStringIndex(body); next != nil {
+		body = body[:next[0]]
+	}
+	return body
+}
+
+func cleanRelDir(p string) string {
+	p = strings.TrimSpace(p)
+	p = strings.TrimPrefix(p, "./")
+	p = strings.TrimSuffix(p, "/")
+	if p == "" {
+		return analysis.RootWorkspacePath
+	}
+	return path.Clean(p)
+}
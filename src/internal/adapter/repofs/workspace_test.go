@@ -0,0 +1,142 @@
+package repofs
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+func TestWorkspaceDetector_Detect(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		roots []string
+		want  []analysis.Workspace
+	}{
+		{
+			name: "single package repository",
+			files: map[string]string{
+				"package.json":  `{"name": "app"}`,
+				"src/a.test.ts": "",
+			},
+			want: []analysis.Workspace{},
+		},
+		{
+			name: "pnpm workspace with negation",
+			files: map[string]string{
+				"pnpm-workspace.yaml":            "packages:\n  - 'packages/*'\n  - '!packages/internal'\n",
+				"packages/ui/package.json":       `{"name": "@acme/ui"}`,
+				"packages/api/package.json":      `{"name": "@acme/api"}`,
+				"packages/internal/package.json": `{"name": "@acme/internal"}`,
+				"packages/docs/README.md":        "",
+			},
+			want: []analysis.Workspace{
+				{Kind: analysis.WorkspaceKindPNPM, Name: "@acme/api", Path: "packages/api"},
+				{Kind: analysis.WorkspaceKindPNPM, Name: "@acme/ui", Path: "packages/ui"},
+			},
+		},
+		{
+			name: "yarn workspaces object form",
+			files: map[string]string{
+				"package.json":          `{"workspaces": {"packages": ["apps/*"]}}`,
+				"yarn.lock":             "",
+				"apps/web/package.json": `{"name": "web"}`,
+			},
+			want: []analysis.Workspace{
+				{Kind: analysis.WorkspaceKindYarn, Name: "web", Path: "apps/web"},
+			},
+		},
+		{
+			name: "npm workspaces without package name",
+			files: map[string]string{
+				"package.json":           `{"workspaces": ["libs/**"]}`,
+				"libs/core/package.json": `{}`,
+			},
+			want: []analysis.Workspace{
+				{Kind: analysis.WorkspaceKindNPM, Name: "libs/core", Path: "libs/core"},
+			},
+		},
+		{
+			name: "go work",
+			files: map[string]string{
+				"go.work":         "go 1.22\n\nuse (\n\t./svc/a\n\t./svc/b\n)\n",
+				"svc/a/go.mod":    "module example.com/a\n",
+				"svc/b/go.mod":    "module example.com/b\n",
+				"svc/a/a_test.go": "",
+			},
+			want: []analysis.Workspace{
+				{Kind: analysis.WorkspaceKindGoModule, Name: "example.com/a", Path: "svc/a"},
+				{Kind: analysis.WorkspaceKindGoModule, Name: "example.com/b", Path: "svc/b"},
+			},
+		},
+		{
+			name: "go multi-module without go work",
+			files: map[string]string{
+				"go.mod":       "module example.com/root\n",
+				"tools/go.mod": "module example.com/tools\n",
+			},
+			want: []analysis.Workspace{
+				{Kind: analysis.WorkspaceKindGoModule, Name: "example.com/root", Path: "."},
+				{Kind: analysis.WorkspaceKindGoModule, Name: "example.com/tools", Path: "tools"},
+			},
+		},
+		{
+			name: "cargo workspace",
+			files: map[string]string{
+				"Cargo.toml":             "[workspace]\nmembers = [\n  \"crates/*\",\n]\n\n[profile.release]\nlto = true\n",
+				"crates/core/Cargo.toml": "[package]\nname = \"acme-core\"\nversion = \"0.1.0\"\n",
+			},
+			want: []analysis.Workspace{
+				{Kind: analysis.WorkspaceKindCargo, Name: "acme-core", Path: "crates/core"},
+			},
+		},
+		{
+			name: "gradle subprojects",
+			files: map[string]string{
+				"settings.gradle.kts":           "rootProject.name = \"acme\"\ninclude(\":app\", \":lib:util\")\ninclude(\":missing\")\n",
+				"app/build.gradle.kts":          "",
+				"lib/util/src/main/kotlin/U.kt": "",
+			},
+			want: []analysis.Workspace{
+				{Kind: analysis.WorkspaceKindGradle, Name: ":app", Path: "app"},
+				{Kind: analysis.WorkspaceKindGradle, Name: ":lib:util", Path: "lib/util"},
+			},
+		},
+		{
+			name: "configured roots replace detection",
+			files: map[string]string{
+				"pnpm-workspace.yaml":        "packages:\n  - 'packages/*'\n",
+				"packages/ui/package.json":   `{"name": "@acme/ui"}`,
+				"services/billing/main.py":   "",
+				"services/billing/test_x.py": "",
+			},
+			roots: []string{"services/*"},
+			want: []analysis.Workspace{
+				{Kind: analysis.WorkspaceKindConfig, Name: "services/billing", Path: "services/billing"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := newTestSource(t, tt.files)
+
+			got, err := NewWorkspaceDetector().Detect(context.Background(), src, listTestFiles(t, src), tt.roots)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("invalid source", func(t *testing.T) {
+		_, err := NewWorkspaceDetector().Detect(context.Background(), &mockInvalidSource{}, nil, nil)
+		if err == nil {
+			t.Fatal("expected error for source not implementing coreSourceProvider")
+		}
+	})
+}
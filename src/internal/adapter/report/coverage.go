@@ -0,0 +1,175 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+var _ analysis.CoverageParser = (*Parser)(nil)
+
+// ParseCoverage reads per-file line coverage from a coverage report of the given format.
+// Files reported more than once (e.g. one Cobertura class per inner class) are merged per line.
+func (p *Parser) ParseCoverage(format analysis.ReportFormat, data []byte) ([]analysis.FileCoverage, error) {
+	var (
+		hits *lineHits
+		err  error
+	)
+	switch format {
+	case analysis.ReportFormatLcov:
+		hits, err = parseLcov(data)
+	case analysis.ReportFormatCobertura:
+		hits, err = parseCobertura(data)
+	default:
+		return nil, fmt.Errorf("%w: unsupported coverage format %q", analysis.ErrInvalidReport, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return hits.coverage(), nil
+}
+
+// lineHits accumulates hit counts per line of each file, keeping file order.
+type lineHits struct {
+	files []string
+	hits  map[string]map[int]int
+}
+
+func newLineHits() *lineHits {
+	return &lineHits{hits: make(map[string]map[int]int)}
+}
+
+func (h *lineHits) add(file string, line, count int) {
+	lines, ok := h.hits[file]
+	if !ok {
+		lines = make(map[int]int)
+		h.hits[file] = lines
+		h.files = append(h.files, file)
+	}
+	lines[line] += count
+}
+
+func (h *lineHits) touch(file string) {
+	if _, ok := h.hits[file]; !ok {
+		h.hits[file] = make(map[int]int)
+		h.files = append(h.files, file)
+	}
+}
+
+func (h *lineHits) coverage() []analysis.FileCoverage {
+	files := make([]analysis.FileCoverage, 0, len(h.files))
+	for _, file := range h.files {
+		lines := h.hits[file]
+		coverage := analysis.FileCoverage{Path: file, TotalLines: len(lines)}
+		for line, count := range lines {
+			if count > 0 {
+				coverage.CoveredLines++
+			} else {
+				coverage.UncoveredLines = append(coverage.UncoveredLines, line)
+			}
+		}
+		slices.Sort(coverage.UncoveredLines)
+		files = append(files, coverage)
+	}
+	return files
+}
+
+// parseLcov reads the SF and DA records of an lcov tracefile; other records are ignored.
+func parseLcov(data []byte) (*lineHits, error) {
+	hits := newLineHits()
+	var current string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		record := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(record, "SF:"):
+			current = strings.TrimPrefix(record, "SF:")
+			hits.touch(current)
+		case strings.HasPrefix(record, "DA:"):
+			if current == "" {
+				return nil, fmt.Errorf("%w: line %d: DA record outside of a file", analysis.ErrInvalidReport, lineNo)
+			}
+			fields := strings.Split(strings.TrimPrefix(record, "DA:"), ",")
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("%w: line %d: malformed DA record", analysis.ErrInvalidReport, lineNo)
+			}
+			line, lineErr := strconv.Atoi(fields[0])
+			count, countErr := strconv.ParseFloat(fields[1], 64)
+			if lineErr != nil || countErr != nil || line < 1 {
+				return nil, fmt.Errorf("%w: line %d: malformed DA record", analysis.ErrInvalidReport, lineNo)
+			}
+			hits.add(current, line, int(min(count, 1)))
+		case record == "end_of_record":
+			current = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", analysis.ErrInvalidReport, err)
+	}
+	if len(hits.files) == 0 {
+		return nil, fmt.Errorf("%w: no SF records", analysis.ErrInvalidReport)
+	}
+	return hits, nil
+}
+
+type coberturaReport struct {
+	Classes []coberturaClass `xml:"packages>package>classes>class"`
+	Sources []string         `xml:"sources>source"`
+	XMLName xml.Name         `xml:"coverage"`
+}
+
+type coberturaClass struct {
+	Filename string          `xml:"filename,attr"`
+	Lines    []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Hits   string `xml:"hits,attr"`
+	Number int    `xml:"number,attr"`
+}
+
+// parseCobertura joins class file names with the report's source root when there is exactly one;
+// with several roots the file name is left relative for path normalization to resolve.
+func parseCobertura(data []byte) (*lineHits, error) {
+	var report coberturaReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("%w: %w", analysis.ErrInvalidReport, err)
+	}
+
+	root := ""
+	if len(report.Sources) == 1 {
+		root = strings.TrimSpace(report.Sources[0])
+	}
+
+	hits := newLineHits()
+	for _, class := range report.Classes {
+		if class.Filename == "" {
+			continue
+		}
+		file := class.Filename
+		if root != "" && root != "." && !path.IsAbs(strings.ReplaceAll(file, "\\", "/")) {
+			file = strings.TrimRight(root, "/\\") + "/" + file
+		}
+		hits.touch(file)
+		for _, line := range class.Lines {
+			if line.Number < 1 {
+				continue
+			}
+			count, err := strconv.ParseFloat(line.Hits, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s line %d: invalid hits %q", analysis.ErrInvalidReport, class.Filename, line.Number, line.Hits)
+			}
+			hits.add(file, line.Number, int(min(count, 1)))
+		}
+	}
+	return hits, nil
+}
@@ -0,0 +1,97 @@
+package report
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+func TestParser_ParseCoverage(t *testing.T) {
+	t.Run("lcov", func(t *testing.T) {
+		data := []byte(`TN:
+SF:/home/runner/work/app/app/src/cart.ts
+FN:1,add
+DA:1,4
+DA:2,0
+DA:3,1,abc123
+LF:3
+LH:2
+end_of_record
+SF:src/empty.ts
+end_of_record
+SF:/home/runner/work/app/app/src/cart.ts
+DA:2,2
+end_of_record
+`)
+
+		got, err := NewParser().ParseCoverage(analysis.ReportFormatLcov, data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []analysis.FileCoverage{
+			{Path: "/home/runner/work/app/app/src/cart.ts", CoveredLines: 3, TotalLines: 3},
+			{Path: "src/empty.ts"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("cobertura", func(t *testing.T) {
+		data := []byte(`<?xml version="1.0" ?>
+<coverage line-rate="0.5" version="7.4">
+  <sources><source>/home/runner/work/app/app/src</source></sources>
+  <packages>
+    <package name="shop">
+      <classes>
+        <class name="Cart" filename="shop/cart.py">
+          <lines>
+            <line number="1" hits="1"/>
+            <line number="2" hits="0"/>
+            <line number="5" hits="0"/>
+          </lines>
+        </class>
+        <class name="Cart$Item" filename="shop/cart.py">
+          <lines><line number="5" hits="3"/></lines>
+        </class>
+      </classes>
+    </package>
+  </packages>
+</coverage>`)
+
+		got, err := NewParser().ParseCoverage(analysis.ReportFormatCobertura, data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []analysis.FileCoverage{
+			{Path: "/home/runner/work/app/app/src/shop/cart.py", CoveredLines: 2, TotalLines: 3, UncoveredLines: []int{2}},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("invalid reports", func(t *testing.T) {
+		tests := []struct {
+			format analysis.ReportFormat
+			data   string
+		}{
+			{format: analysis.ReportFormatLcov, data: "no records"},
+			{format: analysis.ReportFormatLcov, data: "DA:1,1\n"},
+			{format: analysis.ReportFormatLcov, data: "SF:a.ts\nDA:x,1\n"},
+			{format: analysis.ReportFormatCobertura, data: "<testsuites/>"},
+			{format: analysis.ReportFormatCobertura, data: `<coverage><packages><package><classes><class filename="a.py"><lines><line number="1" hits="x"/></lines></class></classes></package></packages></coverage>`},
+			{format: analysis.ReportFormatJUnit, data: "<testsuites/>"},
+		}
+
+		for _, tt := range tests {
+			if _, err := NewParser().ParseCoverage(tt.format, []byte(tt.data)); !errors.Is(err, analysis.ErrInvalidReport) {
+				t.Errorf("%s %q: expected ErrInvalidReport, got %v", tt.format, tt.data, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+// Failure bodies often carry full stack traces; only the head is kept.
+const maxMessageLength = 4096
+
+var _ analysis.ReportParser = (*Parser)(nil)
+
+// Parser implements analysis.ReportParser for the report formats emitted by CI test runners.
+type Parser struct{}
+
+// NewParser creates a new Parser.
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Parse reads test executions from a report of the given format.
+func (p *Parser) Parse(format analysis.ReportFormat, data []byte) ([]analysis.TestExecution, error) {
+	switch format {
+	case analysis.ReportFormatJUnit:
+		return parseJUnit(data)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", analysis.ErrInvalidReport, format)
+	}
+}
+
+// junitSuite covers both the <testsuites> and <testsuite> elements,
+// since reporters nest suites arbitrarily and some emit a bare <testsuite> root.
+type junitSuite struct {
+	Cases   []junitCase  `xml:"testcase"`
+	File    string       `xml:"file,attr"`
+	Name    string       `xml:"name,attr"`
+	Suites  []junitSuite `xml:"testsuite"`
+	XMLName xml.Name
+}
+
+type junitCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Errors    []junitResult `xml:"error"`
+	Failures  []junitResult `xml:"failure"`
+	File      string        `xml:"file,attr"`
+	Name      string        `xml:"name,attr"`
+	Skipped   *junitResult  `xml:"skipped"`
+	Time      string        `xml:"time,attr"`
+}
+
+type junitResult struct {
+	Body    string `xml:",chardata"`
+	Message string `xml:"message,attr"`
+}
+
+func parseJUnit(data []byte) ([]analysis.TestExecution, error) {
+	var root junitSuite
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&root); err != nil {
+		return nil, fmt.Errorf("%w: %w", analysis.ErrInvalidReport, err)
+	}
+
+	switch root.XMLName.Local {
+	case "testsuites":
+		var executions []analysis.TestExecution
+		for _, suite := range root.Suites {
+			executions = collectJUnit(executions, suite, nil, "")
+		}
+		return executions, nil
+	case "testsuite":
+		return collectJUnit(nil, root, nil, ""), nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected root element <%s>", analysis.ErrInvalidReport, root.XMLName.Local)
+	}
+}
+
+func collectJUnit(executions []analysis.TestExecution, suite junitSuite, parents []string, file string) []analysis.TestExecution {
+	suitePath := parents
+	if suite.Name != "" {
+		suitePath = append(append([]string(nil), parents...), suite.Name)
+	}
+	if suite.File != "" {
+		file = suite.File
+	}
+
+	for _, tc := range suite.Cases {
+		execution := analysis.TestExecution{
+			ClassName:  tc.ClassName,
+			DurationMs: parseSeconds(tc.Time),
+			File:       file,
+			Name:       tc.Name,
+			Outcome:    analysis.ExecutionOutcomePassed,
+			SuitePath:  suitePath,
+		}
+		if tc.File != "" {
+			execution.File = tc.File
+		}
+
+		switch {
+		case len(tc.Failures) > 0:
+			execution.Outcome = analysis.ExecutionOutcomeFailed
+			execution.Message = resultMessage(tc.Failures[0])
+		case len(tc.Errors) > 0:
+			execution.Outcome = analysis.ExecutionOutcomeFailed
+			execution.Message = resultMessage(tc.Errors[0])
+		case tc.Skipped != nil:
+			execution.Outcome = analysis.ExecutionOutcomeSkipped
+			execution.Message = resultMessage(*tc.Skipped)
+		}
+		executions = append(executions, execution)
+	}
+
+	for _, child := range suite.Suites {
+		executions = collectJUnit(executions, child, suitePath, file)
+	}
+	return executions
+}
+
+// parseSeconds converts a JUnit time attribute to milliseconds.
+// Some reporters format thousands with commas; unparseable values count as zero.
+func parseSeconds(value string) int {
+	seconds, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), ",", ""), 64)
+	if err != nil || seconds < 0 || math.IsInf(seconds, 0) || math.IsNaN(seconds) {
+		return 0
+	}
+	return int(math.Round(seconds * 1000))
+}
+
+func resultMessage(result junitResult) string {
+	message := strings.TrimSpace(result.Message)
+	if message == "" {
+		message = strings.TrimSpace(result.Body)
+	}
+	if len(message) > maxMessageLength {
+		message = strings.ToValidUTF8(message[:maxMessageLength], "")
+	}
+	return message
+}
@@ -0,0 +1,74 @@
+package report
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+func TestParser_ParseJUnit(t *testing.T) {
+	t.Run("nested suites", func(t *testing.T) {
+		data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="jest tests">
+  <testsuite name="Cart" file="src/cart.test.ts" time="0.5">
+    <testcase classname="Cart adds item" name="adds item" time="0.012"/>
+    <testsuite name="checkout">
+      <testcase classname="Cart checkout fails" name="fails" time="1,002.5">
+        <failure message="expected 1 to be 2">stack trace</failure>
+      </testcase>
+    </testsuite>
+  </testsuite>
+  <testsuite name="pytest">
+    <testcase classname="tests.test_cart" name="test_skip" file="tests/test_cart.py" time="0">
+      <skipped message="not on linux"/>
+    </testcase>
+    <testcase classname="tests.test_cart" name="test_boom" time="abc">
+      <error>RuntimeError</error>
+    </testcase>
+  </testsuite>
+</testsuites>`)
+
+		got, err := NewParser().Parse(analysis.ReportFormatJUnit, data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []analysis.TestExecution{
+			{ClassName: "Cart adds item", DurationMs: 12, File: "src/cart.test.ts", Name: "adds item", Outcome: analysis.ExecutionOutcomePassed, SuitePath: []string{"Cart"}},
+			{ClassName: "Cart checkout fails", DurationMs: 1002500, File: "src/cart.test.ts", Message: "expected 1 to be 2", Name: "fails", Outcome: analysis.ExecutionOutcomeFailed, SuitePath: []string{"Cart", "checkout"}},
+			{ClassName: "tests.test_cart", File: "tests/test_cart.py", Message: "not on linux", Name: "test_skip", Outcome: analysis.ExecutionOutcomeSkipped, SuitePath: []string{"pytest"}},
+			{ClassName: "tests.test_cart", Message: "RuntimeError", Name: "test_boom", Outcome: analysis.ExecutionOutcomeFailed, SuitePath: []string{"pytest"}},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("bare testsuite root", func(t *testing.T) {
+		data := []byte(`<testsuite name="com.acme.CartTest"><testcase classname="com.acme.CartTest" name="checkout" time="0.1"/></testsuite>`)
+
+		got, err := NewParser().Parse(analysis.ReportFormatJUnit, data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 1 || got[0].DurationMs != 100 || !reflect.DeepEqual(got[0].SuitePath, []string{"com.acme.CartTest"}) {
+			t.Errorf("unexpected executions: %+v", got)
+		}
+	})
+
+	t.Run("invalid reports", func(t *testing.T) {
+		for _, data := range []string{"not xml", "<coverage/>"} {
+			if _, err := NewParser().Parse(analysis.ReportFormatJUnit, []byte(data)); !errors.Is(err, analysis.ErrInvalidReport) {
+				t.Errorf("%q: expected ErrInvalidReport, got %v", data, err)
+			}
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		if _, err := NewParser().Parse("trx", []byte("<x/>")); !errors.Is(err, analysis.ErrInvalidReport) {
+			t.Errorf("expected ErrInvalidReport, got %v", err)
+		}
+	})
+}
@@ -20,6 +20,7 @@ import (
 )
 
 var _ analysis.AutoRefreshRepository = (*AnalysisRepository)(nil)
+var _ analysis.CodebaseViewerLookup = (*AnalysisRepository)(nil)
 
 const defaultHost = "github.com"
 const maxErrorMessageLength = 1000
@@ -111,12 +112,13 @@ func (r *AnalysisRepository) CreateAnalysisRecord(ctx context.Context, params an
 	}
 
 	dbAnalysis, err := queries.CreateAnalysis(ctx, db.CreateAnalysisParams{
-		ID:         toPgUUID(analysisID),
-		CodebaseID: codebaseID,
-		CommitSha:  params.CommitSHA,
-		BranchName: pgtype.Text{String: params.Branch, Valid: params.Branch != ""},
-		Status:     db.AnalysisStatusRunning,
-		StartedAt:  pgtype.Timestamptz{Time: startedAt, Valid: true},
+		ID:           toPgUUID(analysisID),
+		CodebaseID:   codebaseID,
+		CommitSha:    params.CommitSHA,
+		BranchName:   pgtype.Text{String: params.Branch, Valid: params.Branch != ""},
+		Status:       db.AnalysisStatusRunning,
+		StartedAt:    pgtype.Timestamptz{Time: startedAt, Valid: true},
+		IsHistorical: params.IsHistorical,
 	})
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -207,6 +209,7 @@ func (r *AnalysisRepository) SaveAnalysisInventory(ctx context.Context, params a
 		TotalTests:  int32(totalTests),
 		CompletedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
 		CommittedAt: pgtype.Timestamptz{Time: params.CommittedAt, Valid: !params.CommittedAt.IsZero()},
+		ConfigHash:  pgtype.Text{String: params.ConfigHash, Valid: params.ConfigHash != ""},
 	}); err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -215,6 +218,42 @@ func (r *AnalysisRepository) SaveAnalysisInventory(ctx context.Context, params a
 		return fmt.Errorf("update analysis: %w", err)
 	}
 
+	if err := savePolicyResults(ctx, queries, pgID, params.PolicyResults); err != nil {
+		return fmt.Errorf("save policy results: %w", err)
+	}
+
+	if err := savePackages(ctx, queries, pgID, params.Inventory, params.Workspaces); err != nil {
+		return fmt.Errorf("save packages: %w", err)
+	}
+
+	if err := saveOwnership(ctx, tx, pgID, params.Inventory); err != nil {
+		return fmt.Errorf("save ownership: %w", err)
+	}
+
+	if err := saveSourceCensus(ctx, queries, pgID, params.SourceCensus, len(params.Inventory.Files), totalTests); err != nil {
+		return fmt.Errorf("save source census: %w", err)
+	}
+
+	if err := saveSourceMapping(ctx, tx, pgID, params.SourceMapping); err != nil {
+		return fmt.Errorf("save source mapping: %w", err)
+	}
+
+	if err := saveCIReport(ctx, queries, pgID, params.CIReport); err != nil {
+		return fmt.Errorf("save CI report: %w", err)
+	}
+
+	if err := saveChurn(ctx, tx, pgID, params.Churn); err != nil {
+		return fmt.Errorf("save churn: %w", err)
+	}
+
+	if err := saveAnomalies(ctx, queries, pgID); err != nil {
+		return fmt.Errorf("save anomalies: %w", err)
+	}
+
+	if err := queries.LinkCodebaseTagsToAnalysis(ctx, pgID); err != nil {
+		return fmt.Errorf("link codebase tags: %w", err)
+	}
+
 	if params.UserID != nil {
 		userUUID, parseErr := analysis.ParseUUID(*params.UserID)
 		if parseErr != nil {
@@ -360,6 +399,9 @@ func (r *AnalysisRepository) GetCodebasesForAutoRefresh(ctx context.Context) ([]
 			ConsecutiveFailures: int(row.ConsecutiveFailures),
 			Host:                row.Host,
 			ID:                  fromPgUUID(row.ID),
+			IsArchived:          row.IsArchived,
+			IsDisabled:          row.IsDisabled,
+			IsPrivate:           row.IsPrivate,
 			LastViewedAt:        row.LastViewedAt.Time,
 			Name:                row.Name,
 			Owner:               row.Owner,
@@ -374,12 +416,59 @@ func (r *AnalysisRepository) GetCodebasesForAutoRefresh(ctx context.Context) ([]
 			info.LastCommitSHA = row.LastCommitSha.String
 		}
 
+		if row.LastViewerID.Valid {
+			viewerID := fromPgUUID(row.LastViewerID).String()
+			info.LastViewerID = &viewerID
+		}
+
+		if row.MetadataRefreshedAt.Valid {
+			t := row.MetadataRefreshedAt.Time
+			info.MetadataRefreshedAt = &t
+		}
+
+		if row.EmptyCheckedAt.Valid {
+			t := row.EmptyCheckedAt.Time
+			info.EmptyCheckedAt = &t
+		}
+
 		result = append(result, info)
 	}
 
 	return result, nil
 }
 
+func (r *AnalysisRepository) ListRecentViewers(ctx context.Context, codebaseID analysis.UUID, limit int) ([]string, error) {
+	queries := db.New(r.pool)
+
+	rows, err := queries.ListRecentCodebaseViewers(ctx, db.ListRecentCodebaseViewersParams{
+		CodebaseID: toPgUUID(codebaseID),
+		Limit:      int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list recent codebase viewers: %w", err)
+	}
+
+	viewers := make([]string, 0, len(rows))
+	for _, row := range rows {
+		viewers = append(viewers, fromPgUUID(row).String())
+	}
+	return viewers, nil
+}
+
+func (r *AnalysisRepository) UpdateEmptyState(ctx context.Context, id analysis.UUID, isEmpty bool) error {
+	queries := db.New(r.pool)
+
+	err := queries.UpdateCodebaseEmptyState(ctx, db.UpdateCodebaseEmptyStateParams{
+		ID:      toPgUUID(id),
+		IsEmpty: isEmpty,
+	})
+	if err != nil {
+		return fmt.Errorf("update codebase empty state: %w", err)
+	}
+
+	return nil
+}
+
 type flatSuite struct {
 	tempID     int
 	parentTemp int // -1 if root
@@ -497,8 +586,10 @@ func (r *AnalysisRepository) saveSuitesBatch(
 			truncateString(s.suite.Name, maxTestSuiteNameLength),
 			s.file.Path,
 			pgtype.Int4{Int32: int32(s.suite.Location.StartLine), Valid: true},
+			endLineNumber(s.suite.Location),
 			pgtype.Text{String: s.file.Framework, Valid: s.file.Framework != ""},
 			int32(s.depth),
+			pgtype.Text{String: s.file.Package, Valid: s.file.Package != ""},
 		)
 	}
 
@@ -516,6 +607,11 @@ func (r *AnalysisRepository) saveSuitesBatch(
 	return newIDs, nil
 }
 
+// endLineNumber is NULL when the parser reported no end line.
+func endLineNumber(loc analysis.Location) pgtype.Int4 {
+	return pgtype.Int4{Int32: int32(loc.EndLine), Valid: loc.EndLine >= loc.StartLine && loc.EndLine > 0}
+}
+
 func (r *AnalysisRepository) saveTestsCopyFrom(
 	ctx context.Context,
 	tx pgx.Tx,
@@ -535,14 +631,15 @@ func (r *AnalysisRepository) saveTestsCopyFrom(
 
 	rows := make([][]any, len(tests))
 	for i, t := range tests {
-		rows[i] = []any{
+		rows[i] = append([]any{
 			suiteIDs[t.suiteTempID],
 			truncateString(t.test.Name, maxTestCaseNameLength),
 			pgtype.Int4{Int32: int32(t.test.Location.StartLine), Valid: true},
+			endLineNumber(t.test.Location),
 			mapTestStatus(t.test.Status),
 			[]byte("[]"),
 			pgtype.Text{},
-		}
+		}, blameColumns(t.test.Blame)...)
 	}
 
 	_, err := tx.Conn().CopyFrom(
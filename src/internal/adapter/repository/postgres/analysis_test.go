@@ -507,6 +507,37 @@ func TestAnalysisRepository_SaveAnalysisInventory(t *testing.T) {
 			t.Errorf("expected status 'completed', got '%s'", status)
 		}
 	})
+
+	t.Run("should store applied config hash", func(t *testing.T) {
+		analysisID, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+			Owner:          "config-owner",
+			Repo:           "config-repo",
+			CommitSHA:      "cfg123",
+			Branch:         "main",
+			ExternalRepoID: "config-id",
+		})
+		if err != nil {
+			t.Fatalf("CreateAnalysisRecord failed: %v", err)
+		}
+
+		err = repo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+			AnalysisID: analysisID,
+			ConfigHash: "abc123hash",
+			Inventory:  &analysis.Inventory{},
+		})
+		if err != nil {
+			t.Fatalf("SaveAnalysisInventory failed: %v", err)
+		}
+
+		var configHash *string
+		err = pool.QueryRow(ctx, "SELECT config_hash FROM analyses WHERE id = $1", toPgUUID(analysisID)).Scan(&configHash)
+		if err != nil {
+			t.Fatalf("failed to query config hash: %v", err)
+		}
+		if configHash == nil || *configHash != "abc123hash" {
+			t.Errorf("expected config hash 'abc123hash', got %v", configHash)
+		}
+	})
 }
 
 func Test_truncateErrorMessage(t *testing.T) {
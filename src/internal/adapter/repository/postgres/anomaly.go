@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+)
+
+// saveAnomalies compares the saved inventory with the previous completed analysis of the codebase,
+// ordered by commit time, and stores the anomalies found. The first analysis has nothing to compare.
+func saveAnomalies(ctx context.Context, queries *db.Queries, analysisID pgtype.UUID) error {
+	previousID, err := queries.GetPreviousCompletedAnalysisID(ctx, analysisID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("get previous analysis: %w", err)
+	}
+
+	previous, err := frameworkTotals(ctx, queries, previousID)
+	if err != nil {
+		return fmt.Errorf("load previous totals: %w", err)
+	}
+	current, err := frameworkTotals(ctx, queries, analysisID)
+	if err != nil {
+		return fmt.Errorf("load current totals: %w", err)
+	}
+
+	for _, anomaly := range analysis.DetectAnomalies(previous, current) {
+		if err := queries.InsertAnalysisAnomaly(ctx, db.InsertAnalysisAnomalyParams{
+			AnalysisID:         analysisID,
+			CurrentValue:       int32(min(anomaly.Current, math.MaxInt32)),
+			Framework:          pgtype.Text{String: anomaly.Framework, Valid: anomaly.Framework != ""},
+			Kind:               string(anomaly.Kind),
+			PreviousAnalysisID: previousID,
+			PreviousValue:      int32(min(anomaly.Previous, math.MaxInt32)),
+		}); err != nil {
+			return fmt.Errorf("insert anomaly %s: %w", anomaly.Kind, err)
+		}
+		slog.WarnContext(ctx, "inventory anomaly detected",
+			"analysis_id", fromPgUUID(analysisID),
+			"previous_analysis_id", fromPgUUID(previousID),
+			"kind", anomaly.Kind,
+			"framework", anomaly.Framework,
+			"previous", anomaly.Previous,
+			"current", anomaly.Current,
+		)
+	}
+	return nil
+}
+
+func frameworkTotals(ctx context.Context, queries *db.Queries, analysisID pgtype.UUID) ([]analysis.FrameworkTotals, error) {
+	rows, err := queries.GetAnalysisFrameworkTotals(ctx, analysisID)
+	if err != nil {
+		return nil, err
+	}
+	totals := make([]analysis.FrameworkTotals, 0, len(rows))
+	for _, row := range rows {
+		totals = append(totals, analysis.FrameworkTotals{
+			Framework: row.Framework,
+			Skipped:   int(row.SkippedTests),
+			Tests:     int(row.TotalTests),
+		})
+	}
+	return totals, nil
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	testdb "github.com/specvital/collector/internal/testutil/postgres"
+)
+
+func TestAnalysisRepository_SaveAnomalies(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	repo := NewAnalysisRepository(pool)
+	ctx := context.Background()
+
+	save := func(commitSHA string, committedAt time.Time, inventory *analysis.Inventory) analysis.UUID {
+		t.Helper()
+		analysisID, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+			Owner:          "anomaly-owner",
+			Repo:           "anomaly-repo",
+			CommitSHA:      commitSHA,
+			Branch:         "main",
+			ExternalRepoID: "anomaly-id",
+		})
+		if err != nil {
+			t.Fatalf("CreateAnalysisRecord failed: %v", err)
+		}
+		if err := repo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+			AnalysisID:  analysisID,
+			CommittedAt: committedAt,
+			Inventory:   inventory,
+		}); err != nil {
+			t.Fatalf("SaveAnalysisInventory failed: %v", err)
+		}
+		return analysisID
+	}
+
+	tests := func(n int) []analysis.Test {
+		result := make([]analysis.Test, n)
+		for i := range result {
+			result[i] = analysis.Test{Name: fmt.Sprintf("test %d", i)}
+		}
+		return result
+	}
+
+	first := save("anomaly1", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), &analysis.Inventory{
+		Files: []analysis.TestFile{
+			{Path: "src/a.test.ts", Framework: "jest", Tests: tests(10)},
+			{Path: "e2e/b.spec.ts", Framework: "playwright", Tests: tests(2)},
+		},
+	})
+	second := save("anomaly2", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), &analysis.Inventory{
+		Files: []analysis.TestFile{
+			{Path: "src/a.test.ts", Framework: "jest", Tests: tests(5)},
+		},
+	})
+
+	var count int
+	if err := pool.QueryRow(ctx,
+		"SELECT COUNT(*) FROM analysis_anomalies WHERE analysis_id = $1",
+		toPgUUID(first),
+	).Scan(&count); err != nil {
+		t.Fatalf("count first anomalies: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected no anomalies for the first analysis, got %d", count)
+	}
+
+	rows, err := pool.Query(ctx,
+		"SELECT kind, COALESCE(framework, ''), previous_value, current_value, previous_analysis_id FROM analysis_anomalies WHERE analysis_id = $1 ORDER BY kind",
+		toPgUUID(second),
+	)
+	if err != nil {
+		t.Fatalf("query anomalies: %v", err)
+	}
+	defer rows.Close()
+
+	var got []analysis.Anomaly
+	for rows.Next() {
+		var anomaly analysis.Anomaly
+		var kind string
+		var previousValue, currentValue int32
+		var previousID [16]byte
+		if err := rows.Scan(&kind, &anomaly.Framework, &previousValue, &currentValue, &previousID); err != nil {
+			t.Fatalf("scan anomaly: %v", err)
+		}
+		if analysis.UUID(previousID) != first {
+			t.Errorf("expected previous analysis %v, got %v", first, analysis.UUID(previousID))
+		}
+		anomaly.Kind = analysis.AnomalyKind(kind)
+		anomaly.Previous = int(previousValue)
+		anomaly.Current = int(currentValue)
+		got = append(got, anomaly)
+	}
+
+	want := []analysis.Anomaly{
+		{Framework: "playwright", Kind: analysis.AnomalyKindFrameworkVanished, Previous: 2},
+		{Current: 5, Kind: analysis.AnomalyKindTestDrop, Previous: 12},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
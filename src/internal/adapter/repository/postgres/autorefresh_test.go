@@ -0,0 +1,249 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	testdb "github.com/specvital/collector/internal/testutil/postgres"
+)
+
+func TestAnalysisRepository_GetCodebasesForAutoRefresh_PrivateViewer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	repo := NewAnalysisRepository(pool)
+	ctx := context.Background()
+
+	newUser := func(username string, token *string) string {
+		t.Helper()
+		var userID string
+		if err := pool.QueryRow(ctx,
+			"INSERT INTO users (username) VALUES ($1) RETURNING id::text", username,
+		).Scan(&userID); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		if _, err := pool.Exec(ctx, `
+			INSERT INTO oauth_accounts (user_id, provider, provider_user_id, access_token)
+			VALUES ($1::uuid, 'github', $2, $3)
+		`, userID, username, token); err != nil {
+			t.Fatalf("failed to create oauth account: %v", err)
+		}
+		return userID
+	}
+	token := "encrypted-token"
+	viewer := newUser("viewer", &token)
+	revoked := newUser("revoked", nil)
+
+	analysisID, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+		Owner:          "private-owner",
+		Repo:           "private-repo",
+		CommitSHA:      "private123",
+		Branch:         "main",
+		ExternalRepoID: "private-id",
+	})
+	if err != nil {
+		t.Fatalf("CreateAnalysisRecord failed: %v", err)
+	}
+	if err := repo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+		AnalysisID: analysisID,
+		Inventory:  &analysis.Inventory{},
+		UserID:     &viewer,
+	}); err != nil {
+		t.Fatalf("SaveAnalysisInventory failed: %v", err)
+	}
+	// The revoked user viewed last, but without a token it cannot be used for refresh.
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO user_analysis_history (user_id, analysis_id, updated_at)
+		VALUES ($1::uuid, $2, now() + interval '1 minute')
+	`, revoked, toPgUUID(analysisID)); err != nil {
+		t.Fatalf("failed to record history: %v", err)
+	}
+	if _, err := pool.Exec(ctx,
+		"UPDATE codebases SET is_private = true, last_viewed_at = now() WHERE owner = 'private-owner'",
+	); err != nil {
+		t.Fatalf("failed to update codebase: %v", err)
+	}
+
+	codebases, err := repo.GetCodebasesForAutoRefresh(ctx)
+	if err != nil {
+		t.Fatalf("GetCodebasesForAutoRefresh failed: %v", err)
+	}
+	if len(codebases) != 1 {
+		t.Fatalf("expected private codebase to be eligible, got %d codebases", len(codebases))
+	}
+	got := codebases[0]
+	if !got.IsPrivate {
+		t.Error("expected IsPrivate to be true")
+	}
+	if got.LastViewerID == nil || *got.LastViewerID != viewer {
+		t.Errorf("expected last viewer %s, got %v", viewer, got.LastViewerID)
+	}
+
+	viewers, err := repo.ListRecentViewers(ctx, got.ID, 5)
+	if err != nil {
+		t.Fatalf("ListRecentViewers failed: %v", err)
+	}
+	if len(viewers) != 1 || viewers[0] != viewer {
+		t.Errorf("expected only the viewer with a token, got %v", viewers)
+	}
+}
+
+func TestAnalysisRepository_GetCodebasesForAutoRefresh_Lifecycle(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	repo := NewAnalysisRepository(pool)
+	codebaseRepo := NewCodebaseRepository(pool)
+	ctx := context.Background()
+
+	newCodebase := func(name string, info analysis.RepoInfo) analysis.UUID {
+		t.Helper()
+		codebase, err := codebaseRepo.Upsert(ctx, analysis.UpsertCodebaseParams{
+			ExternalRepoID: name + "-id",
+			Host:           "github.com",
+			Name:           name,
+			Owner:          "lifecycle-owner",
+		})
+		if err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+		if err := codebaseRepo.UpdateMetadata(ctx, codebase.ID, info); err != nil {
+			t.Fatalf("UpdateMetadata failed: %v", err)
+		}
+		return codebase.ID
+	}
+	active := newCodebase("active", analysis.RepoInfo{})
+	newCodebase("archived", analysis.RepoInfo{IsArchived: true})
+	recheck := newCodebase("archived-recheck", analysis.RepoInfo{IsArchived: true})
+	if _, err := pool.Exec(ctx, `
+		UPDATE codebases SET last_viewed_at = now(),
+			metadata_refreshed_at = CASE WHEN id = $1 THEN now() - interval '2 days' ELSE metadata_refreshed_at END
+		WHERE owner = 'lifecycle-owner'
+	`, toPgUUID(recheck)); err != nil {
+		t.Fatalf("failed to update codebases: %v", err)
+	}
+	if err := repo.UpdateEmptyState(ctx, active, true); err != nil {
+		t.Fatalf("UpdateEmptyState failed: %v", err)
+	}
+
+	codebases, err := repo.GetCodebasesForAutoRefresh(ctx)
+	if err != nil {
+		t.Fatalf("GetCodebasesForAutoRefresh failed: %v", err)
+	}
+	byName := make(map[string]analysis.CodebaseRefreshInfo)
+	for _, c := range codebases {
+		byName[c.Name] = c
+	}
+
+	if _, ok := byName["archived"]; ok {
+		t.Error("expected recently checked archived codebase to be excluded")
+	}
+	if c, ok := byName["archived-recheck"]; !ok || !c.IsArchived {
+		t.Errorf("expected archived codebase due for a recheck to be included as archived, got %+v", c)
+	}
+	if c := byName["active"]; c.EmptyCheckedAt == nil {
+		t.Error("expected active codebase to be marked empty")
+	}
+
+	if err := repo.UpdateEmptyState(ctx, active, false); err != nil {
+		t.Fatalf("UpdateEmptyState failed: %v", err)
+	}
+	codebases, err = repo.GetCodebasesForAutoRefresh(ctx)
+	if err != nil {
+		t.Fatalf("GetCodebasesForAutoRefresh failed: %v", err)
+	}
+	for _, c := range codebases {
+		if c.Name == "active" && c.EmptyCheckedAt != nil {
+			t.Error("expected empty mark to be cleared")
+		}
+	}
+}
+
+func TestAnalysisRepository_GetCodebasesForAutoRefresh_Historical(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	repo := NewAnalysisRepository(pool)
+	ctx := context.Background()
+	headCommittedAt := time.Now().Add(-time.Hour)
+
+	complete := func(commitSHA string, isHistorical bool, committedAt time.Time) {
+		t.Helper()
+		analysisID, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+			Owner:          "history-owner",
+			Repo:           "history-repo",
+			CommitSHA:      commitSHA,
+			Branch:         "main",
+			ExternalRepoID: "history-id",
+			IsHistorical:   isHistorical,
+		})
+		if err != nil {
+			t.Fatalf("CreateAnalysisRecord failed: %v", err)
+		}
+		if err := repo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+			AnalysisID:  analysisID,
+			CommittedAt: committedAt,
+			Inventory:   &analysis.Inventory{},
+		}); err != nil {
+			t.Fatalf("SaveAnalysisInventory failed: %v", err)
+		}
+	}
+	complete("head123", false, headCommittedAt)
+	// A backfilled commit finishing after the HEAD analysis must not become the latest.
+	complete("old123", true, headCommittedAt.Add(-30*24*time.Hour))
+
+	failedID, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+		Owner:          "history-owner",
+		Repo:           "history-repo",
+		CommitSHA:      "older123",
+		Branch:         "main",
+		ExternalRepoID: "history-id",
+		IsHistorical:   true,
+	})
+	if err != nil {
+		t.Fatalf("CreateAnalysisRecord failed: %v", err)
+	}
+	if err := repo.RecordFailure(ctx, failedID, "checkout failed"); err != nil {
+		t.Fatalf("RecordFailure failed: %v", err)
+	}
+	if _, err := pool.Exec(ctx, "UPDATE codebases SET last_viewed_at = now() WHERE owner = 'history-owner'"); err != nil {
+		t.Fatalf("failed to update codebase: %v", err)
+	}
+
+	codebases, err := repo.GetCodebasesForAutoRefresh(ctx)
+	if err != nil {
+		t.Fatalf("GetCodebasesForAutoRefresh failed: %v", err)
+	}
+	if len(codebases) != 1 {
+		t.Fatalf("expected 1 codebase, got %d", len(codebases))
+	}
+	got := codebases[0]
+	if got.LastCommitSHA != "head123" {
+		t.Errorf("expected last commit head123, got %q", got.LastCommitSHA)
+	}
+	if got.ConsecutiveFailures != 0 {
+		t.Errorf("expected historical failures to be ignored, got %d", got.ConsecutiveFailures)
+	}
+
+	codebase, err := NewCodebaseRepository(pool).FindWithLastCommit(ctx, "github.com", "history-owner", "history-repo")
+	if err != nil {
+		t.Fatalf("FindWithLastCommit failed: %v", err)
+	}
+	if codebase.LastCommitSHA != "head123" {
+		t.Errorf("expected codebase last commit head123, got %q", codebase.LastCommitSHA)
+	}
+}
@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+const (
+	maxBlameAuthorLength = 255
+	maxCommitSHALength   = 40
+)
+
+// blameColumns returns the introduced_* and last_modified_at values of a test case,
+// in db.TestCaseCopyColumns order. All values are NULL when the test has no blame.
+func blameColumns(blame *analysis.Blame) []any {
+	if blame == nil {
+		return []any{pgtype.Text{}, pgtype.Text{}, pgtype.Text{}, pgtype.Timestamptz{}, pgtype.Timestamptz{}}
+	}
+	return []any{
+		pgtype.Text{String: blame.CommitSHA, Valid: blame.CommitSHA != "" && len(blame.CommitSHA) <= maxCommitSHALength},
+		pgtype.Text{String: truncateString(blame.AuthorName, maxBlameAuthorLength), Valid: blame.AuthorName != ""},
+		pgtype.Text{String: truncateString(blame.AuthorEmail, maxBlameAuthorLength), Valid: blame.AuthorEmail != ""},
+		pgtype.Timestamptz{Time: blame.IntroducedAt, Valid: !blame.IntroducedAt.IsZero()},
+		pgtype.Timestamptz{Time: blame.LastModifiedAt, Valid: !blame.LastModifiedAt.IsZero()},
+	}
+}
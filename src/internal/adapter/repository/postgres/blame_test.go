@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+	testdb "github.com/specvital/collector/internal/testutil/postgres"
+)
+
+func Test_blameColumns(t *testing.T) {
+	t.Run("nil blame is all NULL", func(t *testing.T) {
+		cols := blameColumns(nil)
+		if len(cols) != len(db.TestCaseCopyColumns)-7 {
+			t.Fatalf("expected %d columns, got %d", len(db.TestCaseCopyColumns)-7, len(cols))
+		}
+		if cols[0].(pgtype.Text).Valid || cols[3].(pgtype.Timestamptz).Valid {
+			t.Error("expected NULL values")
+		}
+	})
+
+	t.Run("oversized SHA is dropped", func(t *testing.T) {
+		cols := blameColumns(&analysis.Blame{CommitSHA: string(make([]byte, 64)), AuthorName: "Alice"})
+		if cols[0].(pgtype.Text).Valid {
+			t.Error("expected NULL commit SHA")
+		}
+		if got := cols[1].(pgtype.Text); !got.Valid || got.String != "Alice" {
+			t.Errorf("unexpected author: %+v", got)
+		}
+	})
+}
+
+func TestAnalysisRepository_SaveBlame(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	repo := NewAnalysisRepository(pool)
+	ctx := context.Background()
+
+	analysisID, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+		Owner:          "blame-owner",
+		Repo:           "blame-repo",
+		CommitSHA:      "blame123",
+		Branch:         "main",
+		ExternalRepoID: "blame-id",
+	})
+	if err != nil {
+		t.Fatalf("CreateAnalysisRecord failed: %v", err)
+	}
+
+	introducedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	modifiedAt := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	err = repo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+		AnalysisID: analysisID,
+		Inventory: &analysis.Inventory{
+			Files: []analysis.TestFile{
+				{
+					Path:      "a_test.go",
+					Framework: "go-testing",
+					Tests: []analysis.Test{
+						{
+							Name:   "TestSkipped",
+							Status: analysis.TestStatusSkipped,
+							Blame: &analysis.Blame{
+								AuthorEmail:    "alice@example.com",
+								AuthorName:     "Alice",
+								CommitSHA:      "0123456789abcdef0123456789abcdef01234567",
+								IntroducedAt:   introducedAt,
+								LastModifiedAt: modifiedAt,
+							},
+						},
+						{Name: "TestUnblamed", Status: analysis.TestStatusActive},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SaveAnalysisInventory failed: %v", err)
+	}
+
+	var sha, author, email *string
+	var gotIntroduced, gotModified *time.Time
+	err = pool.QueryRow(ctx, `
+		SELECT tc.introduced_commit_sha, tc.introduced_by_name, tc.introduced_by_email, tc.introduced_at, tc.last_modified_at
+		FROM test_cases tc JOIN test_suites ts ON tc.suite_id = ts.id
+		WHERE ts.analysis_id = $1 AND tc.name = 'TestSkipped'
+	`, toPgUUID(analysisID)).Scan(&sha, &author, &email, &gotIntroduced, &gotModified)
+	if err != nil {
+		t.Fatalf("failed to query test case: %v", err)
+	}
+	if sha == nil || *sha != "0123456789abcdef0123456789abcdef01234567" {
+		t.Errorf("unexpected commit SHA: %v", sha)
+	}
+	if author == nil || *author != "Alice" || email == nil || *email != "alice@example.com" {
+		t.Errorf("unexpected author: %v <%v>", author, email)
+	}
+	if gotIntroduced == nil || !gotIntroduced.Equal(introducedAt) {
+		t.Errorf("unexpected introduced at: %v", gotIntroduced)
+	}
+	if gotModified == nil || !gotModified.Equal(modifiedAt) {
+		t.Errorf("unexpected last modified at: %v", gotModified)
+	}
+
+	var unblamed *string
+	err = pool.QueryRow(ctx, `
+		SELECT tc.introduced_commit_sha
+		FROM test_cases tc JOIN test_suites ts ON tc.suite_id = ts.id
+		WHERE ts.analysis_id = $1 AND tc.name = 'TestUnblamed'
+	`, toPgUUID(analysisID)).Scan(&unblamed)
+	if err != nil {
+		t.Fatalf("failed to query test case: %v", err)
+	}
+	if unblamed != nil {
+		t.Errorf("expected NULL commit SHA, got %q", *unblamed)
+	}
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+)
+
+// saveSourceCensus stores per-language source totals and the test-to-code ratios of an analysis.
+// Ratios stay NULL when the codebase has no source files or lines to divide by.
+func saveSourceCensus(
+	ctx context.Context,
+	queries *db.Queries,
+	analysisID pgtype.UUID,
+	census *analysis.SourceCensus,
+	testFiles, tests int,
+) error {
+	if census == nil {
+		return nil
+	}
+
+	for _, language := range census.Languages {
+		if err := queries.InsertAnalysisLanguageStat(ctx, db.InsertAnalysisLanguageStatParams{
+			AnalysisID:  analysisID,
+			Language:    language.Language,
+			SourceFiles: int32(language.Files),
+			SourceLines: int32(language.Lines),
+		}); err != nil {
+			return fmt.Errorf("insert language stat %q: %w", language.Language, err)
+		}
+	}
+
+	fileRatio, hasFileRatio := census.TestFileRatio(testFiles)
+	perKLOC, hasPerKLOC := census.TestsPerKLOC(tests)
+	if err := queries.UpdateAnalysisSourceCensus(ctx, db.UpdateAnalysisSourceCensusParams{
+		ID:               analysisID,
+		TotalSourceFiles: pgtype.Int4{Int32: int32(census.TotalFiles), Valid: true},
+		TotalSourceLines: pgtype.Int4{Int32: int32(census.TotalLines), Valid: true},
+		TestFileRatio:    pgtype.Float8{Float64: fileRatio, Valid: hasFileRatio},
+		TestsPerKloc:     pgtype.Float8{Float64: perKLOC, Valid: hasPerKLOC},
+	}); err != nil {
+		return fmt.Errorf("update analysis source census: %w", err)
+	}
+	return nil
+}
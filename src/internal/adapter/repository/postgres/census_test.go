@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/specvital/collector/internal/domain/analysis"
+	testdb "github.com/specvital/collector/internal/testutil/postgres"
+)
+
+func TestAnalysisRepository_SaveSourceCensus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	repo := NewAnalysisRepository(pool)
+	ctx := context.Background()
+
+	analysisID, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+		Owner:          "census-owner",
+		Repo:           "census-repo",
+		CommitSHA:      "census123",
+		Branch:         "main",
+		ExternalRepoID: "census-id",
+	})
+	if err != nil {
+		t.Fatalf("CreateAnalysisRecord failed: %v", err)
+	}
+
+	census := analysis.NewSourceCensus(
+		[]string{"pkg/a.go", "pkg/b.go", "web/app.ts", "web/util.ts"},
+		map[string]int{"pkg/a.go": 1500, "pkg/b.go": 500, "web/app.ts": 1000, "web/util.ts": 1000},
+	)
+	err = repo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+		AnalysisID: analysisID,
+		Inventory: &analysis.Inventory{
+			Files: []analysis.TestFile{{
+				Path:      "pkg/a_test.go",
+				Framework: "go-testing",
+				Tests:     []analysis.Test{{Name: "TestA"}, {Name: "TestB"}},
+			}},
+		},
+		SourceCensus: &census,
+	})
+	if err != nil {
+		t.Fatalf("SaveAnalysisInventory failed: %v", err)
+	}
+
+	var sourceFiles, sourceLines pgtype.Int4
+	var fileRatio, perKLOC pgtype.Float8
+	if err := pool.QueryRow(ctx,
+		"SELECT total_source_files, total_source_lines, test_file_ratio, tests_per_kloc FROM analyses WHERE id = $1",
+		toPgUUID(analysisID),
+	).Scan(&sourceFiles, &sourceLines, &fileRatio, &perKLOC); err != nil {
+		t.Fatalf("query analysis: %v", err)
+	}
+	if sourceFiles.Int32 != 4 || sourceLines.Int32 != 4000 {
+		t.Errorf("unexpected source totals: files=%d lines=%d", sourceFiles.Int32, sourceLines.Int32)
+	}
+	if fileRatio.Float64 != 0.25 || perKLOC.Float64 != 0.5 {
+		t.Errorf("unexpected ratios: file=%v kloc=%v", fileRatio.Float64, perKLOC.Float64)
+	}
+
+	var goLines int32
+	if err := pool.QueryRow(ctx,
+		"SELECT source_lines FROM analysis_language_stats WHERE analysis_id = $1 AND language = 'go'",
+		toPgUUID(analysisID),
+	).Scan(&goLines); err != nil {
+		t.Fatalf("query language stats: %v", err)
+	}
+	if goLines != 2000 {
+		t.Errorf("expected 2000 go lines, got %d", goLines)
+	}
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+)
+
+// saveChurn stores the churn of every test file of an analysis along with the window start.
+func saveChurn(ctx context.Context, tx pgx.Tx, analysisID pgtype.UUID, report *analysis.ChurnReport) error {
+	if report == nil {
+		return nil
+	}
+
+	since := pgtype.Timestamptz{Time: report.Since, Valid: true}
+	rows := make([][]any, 0, len(report.Files))
+	for _, file := range report.Files {
+		if len(file.Path) > maxSourcePathLength {
+			continue
+		}
+		rows = append(rows, []any{
+			analysisID,
+			file.Path,
+			int32(min(file.Commits, math.MaxInt32)),
+			int32(min(file.LinesAdded, math.MaxInt32)),
+			int32(min(file.LinesRemoved, math.MaxInt32)),
+			int32(min(file.Authors, math.MaxInt32)),
+			since,
+		})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if _, err := tx.Conn().CopyFrom(
+		ctx,
+		pgx.Identifier{"analysis_test_file_churn"},
+		db.TestFileChurnCopyColumns,
+		pgx.CopyFromRows(rows),
+	); err != nil {
+		return fmt.Errorf("copy test file churn: %w", err)
+	}
+	return nil
+}
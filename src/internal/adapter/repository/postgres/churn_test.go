@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	testdb "github.com/specvital/collector/internal/testutil/postgres"
+)
+
+func TestAnalysisRepository_SaveChurn(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	repo := NewAnalysisRepository(pool)
+	ctx := context.Background()
+
+	analysisID, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+		Owner:          "churn-owner",
+		Repo:           "churn-repo",
+		CommitSHA:      "churn123",
+		Branch:         "main",
+		ExternalRepoID: "churn-id",
+	})
+	if err != nil {
+		t.Fatalf("CreateAnalysisRecord failed: %v", err)
+	}
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	err = repo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+		AnalysisID: analysisID,
+		Churn: &analysis.ChurnReport{
+			Files: []analysis.FileChurn{
+				{Authors: 2, Commits: 5, LinesAdded: 40, LinesRemoved: 12, Path: "a_test.go"},
+				{Path: "b_test.go"},
+			},
+			Since: since,
+		},
+		Inventory: &analysis.Inventory{
+			Files: []analysis.TestFile{
+				{Path: "a_test.go", Framework: "go-testing", Tests: []analysis.Test{{Name: "TestA"}}},
+				{Path: "b_test.go", Framework: "go-testing", Tests: []analysis.Test{{Name: "TestB"}}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SaveAnalysisInventory failed: %v", err)
+	}
+
+	var commits, added, removed, authors int32
+	var windowStart time.Time
+	if err := pool.QueryRow(ctx,
+		"SELECT commits, lines_added, lines_removed, authors, window_start FROM analysis_test_file_churn WHERE analysis_id = $1 AND file_path = 'a_test.go'",
+		toPgUUID(analysisID),
+	).Scan(&commits, &added, &removed, &authors, &windowStart); err != nil {
+		t.Fatalf("query churn: %v", err)
+	}
+	if commits != 5 || added != 40 || removed != 12 || authors != 2 {
+		t.Errorf("unexpected churn: commits=%d added=%d removed=%d authors=%d", commits, added, removed, authors)
+	}
+	if !windowStart.Equal(since) {
+		t.Errorf("expected window start %v, got %v", since, windowStart)
+	}
+
+	var files int
+	if err := pool.QueryRow(ctx,
+		"SELECT COUNT(*) FROM analysis_test_file_churn WHERE analysis_id = $1",
+		toPgUUID(analysisID),
+	).Scan(&files); err != nil {
+		t.Fatalf("count churn: %v", err)
+	}
+	if files != 2 {
+		t.Errorf("expected 2 churn rows, got %d", files)
+	}
+}
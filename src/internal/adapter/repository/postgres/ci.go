@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+)
+
+// saveCIReport stores the CI test commands of an analysis and whether each detected framework is run by CI.
+func saveCIReport(ctx context.Context, queries *db.Queries, analysisID pgtype.UUID, report *analysis.CIReport) error {
+	if report == nil {
+		return nil
+	}
+
+	for _, command := range report.Commands {
+		if err := queries.InsertAnalysisCICommand(ctx, db.InsertAnalysisCICommandParams{
+			AnalysisID: analysisID,
+			Command:    command.Command,
+			ConfigPath: command.ConfigPath,
+			Frameworks: command.Frameworks,
+			JobName:    command.Job,
+		}); err != nil {
+			return fmt.Errorf("insert CI command %q: %w", command.Command, err)
+		}
+	}
+
+	for _, framework := range report.Frameworks {
+		if err := queries.InsertAnalysisCIFramework(ctx, db.InsertAnalysisCIFrameworkParams{
+			AnalysisID: analysisID,
+			Executed:   framework.Executed,
+			Framework:  framework.Framework,
+			TestFiles:  int32(framework.TestFiles),
+		}); err != nil {
+			return fmt.Errorf("insert CI framework %q: %w", framework.Framework, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	testdb "github.com/specvital/collector/internal/testutil/postgres"
+)
+
+func TestAnalysisRepository_SaveCIReport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	repo := NewAnalysisRepository(pool)
+	ctx := context.Background()
+
+	analysisID, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+		Owner:          "ci-owner",
+		Repo:           "ci-repo",
+		CommitSHA:      "ci123",
+		Branch:         "main",
+		ExternalRepoID: "ci-id",
+	})
+	if err != nil {
+		t.Fatalf("CreateAnalysisRecord failed: %v", err)
+	}
+
+	err = repo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+		AnalysisID: analysisID,
+		CIReport: &analysis.CIReport{
+			Commands: []analysis.CICommand{{
+				Command:    "npm test",
+				ConfigPath: ".github/workflows/ci.yml",
+				Frameworks: []string{"jest"},
+				Job:        "test",
+			}},
+			ConfigPaths: []string{".github/workflows/ci.yml"},
+			Frameworks: []analysis.CIFramework{
+				{Executed: true, Framework: "jest", TestFiles: 1},
+				{Executed: false, Framework: "playwright", TestFiles: 1},
+			},
+		},
+		Inventory: &analysis.Inventory{
+			Files: []analysis.TestFile{
+				{Path: "src/a.test.ts", Framework: "jest", Tests: []analysis.Test{{Name: "a"}}},
+				{Path: "e2e/b.spec.ts", Framework: "playwright", Tests: []analysis.Test{{Name: "b"}}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SaveAnalysisInventory failed: %v", err)
+	}
+
+	var job string
+	var frameworks []string
+	if err := pool.QueryRow(ctx,
+		"SELECT job_name, frameworks FROM analysis_ci_commands WHERE analysis_id = $1",
+		toPgUUID(analysisID),
+	).Scan(&job, &frameworks); err != nil {
+		t.Fatalf("query CI commands: %v", err)
+	}
+	if job != "test" || !slices.Equal(frameworks, []string{"jest"}) {
+		t.Errorf("unexpected CI command: job=%s frameworks=%v", job, frameworks)
+	}
+
+	var notExecuted []string
+	rows, err := pool.Query(ctx,
+		"SELECT framework FROM analysis_ci_frameworks WHERE analysis_id = $1 AND NOT executed",
+		toPgUUID(analysisID),
+	)
+	if err != nil {
+		t.Fatalf("query CI frameworks: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var framework string
+		if err := rows.Scan(&framework); err != nil {
+			t.Fatalf("scan CI framework: %v", err)
+		}
+		notExecuted = append(notExecuted, framework)
+	}
+	if !slices.Equal(notExecuted, []string{"playwright"}) {
+		t.Errorf("expected playwright not executed, got %v", notExecuted)
+	}
+}
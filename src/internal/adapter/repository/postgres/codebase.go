@@ -58,10 +58,13 @@ func (r *CodebaseRepository) FindByOwnerName(ctx context.Context, host, owner, n
 	return mapCodebase(row), nil
 }
 
-func (r *CodebaseRepository) MarkStale(ctx context.Context, id analysis.UUID) error {
+func (r *CodebaseRepository) MarkStale(ctx context.Context, id analysis.UUID, reason analysis.StaleReason) error {
 	queries := db.New(r.pool)
 
-	err := queries.MarkCodebaseStale(ctx, toPgUUID(id))
+	err := queries.MarkCodebaseStale(ctx, db.MarkCodebaseStaleParams{
+		ID:          toPgUUID(id),
+		StaleReason: pgtype.Text{String: string(reason), Valid: reason != ""},
+	})
 	if err != nil {
 		return fmt.Errorf("mark codebase stale: %w", err)
 	}
@@ -92,7 +95,10 @@ func (r *CodebaseRepository) MarkStaleAndUpsert(ctx context.Context, staleID ana
 
 	queries := db.New(tx)
 
-	if err := queries.MarkCodebaseStale(ctx, toPgUUID(staleID)); err != nil {
+	if err := queries.MarkCodebaseStale(ctx, db.MarkCodebaseStaleParams{
+		ID:          toPgUUID(staleID),
+		StaleReason: pgtype.Text{String: string(analysis.StaleReasonReplaced), Valid: true},
+	}); err != nil {
 		return nil, fmt.Errorf("mark codebase stale: %w", err)
 	}
 
@@ -133,6 +139,37 @@ func (r *CodebaseRepository) UnmarkStale(ctx context.Context, id analysis.UUID,
 	return mapCodebase(row), nil
 }
 
+func (r *CodebaseRepository) UpdateMetadata(ctx context.Context, id analysis.UUID, info analysis.RepoInfo) error {
+	queries := db.New(r.pool)
+
+	params := db.UpdateCodebaseMetadataParams{
+		DefaultBranch:        pgtype.Text{String: info.DefaultBranch, Valid: info.DefaultBranch != ""},
+		ID:                   toPgUUID(id),
+		IsArchived:           info.IsArchived,
+		IsDisabled:           info.IsDisabled,
+		IsFork:               info.IsFork,
+		LicenseSpdxID:        pgtype.Text{String: info.License, Valid: info.License != ""},
+		ParentExternalRepoID: pgtype.Text{String: info.ParentExternalRepoID, Valid: info.ParentExternalRepoID != ""},
+		ParentFullName:       pgtype.Text{String: info.ParentFullName, Valid: info.ParentFullName != ""},
+		PrimaryLanguage:      pgtype.Text{String: info.Language, Valid: info.Language != ""},
+		SizeKb:               info.SizeKB,
+		StargazersCount:      int32(info.Stars),
+		Topics:               info.Topics,
+	}
+	if params.Topics == nil {
+		params.Topics = []string{}
+	}
+	if info.PushedAt != nil {
+		params.PushedAt = pgtype.Timestamptz{Time: *info.PushedAt, Valid: true}
+	}
+
+	if err := queries.UpdateCodebaseMetadata(ctx, params); err != nil {
+		return fmt.Errorf("update codebase metadata: %w", err)
+	}
+
+	return nil
+}
+
 func (r *CodebaseRepository) UpdateOwnerName(ctx context.Context, id analysis.UUID, owner, name string) (*analysis.Codebase, error) {
 	queries := db.New(r.pool)
 
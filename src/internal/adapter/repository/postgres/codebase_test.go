@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/specvital/collector/internal/domain/analysis"
 	testdb "github.com/specvital/collector/internal/testutil/postgres"
@@ -157,7 +158,7 @@ func TestCodebaseRepository_MarkStale(t *testing.T) {
 			t.Fatalf("FindByOwnerName failed: %v", err)
 		}
 
-		err = codebaseRepo.MarkStale(ctx, codebase.ID)
+		err = codebaseRepo.MarkStale(ctx, codebase.ID, analysis.StaleReasonDeleted)
 		if err != nil {
 			t.Fatalf("MarkStale failed: %v", err)
 		}
@@ -174,6 +175,16 @@ func TestCodebaseRepository_MarkStale(t *testing.T) {
 		if !staleCodebase.IsStale {
 			t.Error("expected codebase to be stale")
 		}
+
+		var reason string
+		var hasStaleAt bool
+		err = pool.QueryRow(ctx, "SELECT stale_reason, stale_at IS NOT NULL FROM codebases WHERE id = $1", toPgUUID(codebase.ID)).Scan(&reason, &hasStaleAt)
+		if err != nil {
+			t.Fatalf("query stale reason: %v", err)
+		}
+		if reason != string(analysis.StaleReasonDeleted) || !hasStaleAt {
+			t.Errorf("expected stale reason %q with timestamp, got %q (stale_at set: %v)", analysis.StaleReasonDeleted, reason, hasStaleAt)
+		}
 	})
 }
 
@@ -290,7 +301,7 @@ func TestCodebaseRepository_UnmarkStale(t *testing.T) {
 		}
 
 		codebase, _ := codebaseRepo.FindByOwnerName(ctx, "github.com", "unmark-old-owner", "unmark-old-repo")
-		_ = codebaseRepo.MarkStale(ctx, codebase.ID)
+		_ = codebaseRepo.MarkStale(ctx, codebase.ID, analysis.StaleReasonAccessRevoked)
 
 		updated, err := codebaseRepo.UnmarkStale(ctx, codebase.ID, "unmark-new-owner", "unmark-new-repo")
 		if err != nil {
@@ -314,6 +325,15 @@ func TestCodebaseRepository_UnmarkStale(t *testing.T) {
 		if found.ID != codebase.ID {
 			t.Error("expected same codebase ID after unmark")
 		}
+
+		var cleared bool
+		err = pool.QueryRow(ctx, "SELECT stale_reason IS NULL AND stale_at IS NULL FROM codebases WHERE id = $1", toPgUUID(codebase.ID)).Scan(&cleared)
+		if err != nil {
+			t.Fatalf("query stale reason: %v", err)
+		}
+		if !cleared {
+			t.Error("expected stale reason to be cleared after unmark")
+		}
 	})
 
 	t.Run("should return ErrCodebaseNotFound for non-existent ID", func(t *testing.T) {
@@ -385,3 +405,82 @@ func TestCodebaseRepository_UpdateOwnerName(t *testing.T) {
 		}
 	})
 }
+
+func TestCodebaseRepository_UpdateMetadata(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	codebaseRepo := NewCodebaseRepository(pool)
+	ctx := context.Background()
+
+	codebase, err := codebaseRepo.Upsert(ctx, analysis.UpsertCodebaseParams{
+		DefaultBranch:  "main",
+		ExternalRepoID: "metadata-ext-id",
+		Host:           "github.com",
+		Name:           "metadata-repo",
+		Owner:          "metadata-owner",
+	})
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	pushedAt := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	err = codebaseRepo.UpdateMetadata(ctx, codebase.ID, analysis.RepoInfo{
+		IsArchived:     true,
+		IsFork:         true,
+		Language:       "Go",
+		License:        "MIT",
+		ParentFullName: "upstream/metadata-repo",
+		PushedAt:       &pushedAt,
+		SizeKB:         2048,
+		Stars:          42,
+		Topics:         []string{"testing", "go"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateMetadata failed: %v", err)
+	}
+
+	var (
+		defaultBranch, language, license, parent string
+		isArchived, isFork                       bool
+		sizeKB                                   int64
+		stars                                    int32
+		topics                                   []string
+		storedPushedAt                           time.Time
+		refreshed                                bool
+	)
+	err = pool.QueryRow(ctx, `
+		SELECT default_branch, primary_language, license_spdx_id, parent_full_name, is_archived, is_fork,
+		       size_kb, stargazers_count, topics, pushed_at, metadata_refreshed_at IS NOT NULL
+		FROM codebases WHERE id = $1`, toPgUUID(codebase.ID),
+	).Scan(&defaultBranch, &language, &license, &parent, &isArchived, &isFork, &sizeKB, &stars, &topics, &storedPushedAt, &refreshed)
+	if err != nil {
+		t.Fatalf("query codebase: %v", err)
+	}
+
+	if defaultBranch != "main" {
+		t.Errorf("expected empty default branch to keep 'main', got %q", defaultBranch)
+	}
+	if language != "Go" || license != "MIT" || parent != "upstream/metadata-repo" {
+		t.Errorf("unexpected language/license/parent: %q %q %q", language, license, parent)
+	}
+	if !isArchived || !isFork {
+		t.Errorf("expected archived fork, got archived=%v fork=%v", isArchived, isFork)
+	}
+	if sizeKB != 2048 || stars != 42 {
+		t.Errorf("unexpected size/stars: %d %d", sizeKB, stars)
+	}
+	if len(topics) != 2 || topics[0] != "testing" || topics[1] != "go" {
+		t.Errorf("unexpected topics: %v", topics)
+	}
+	if !storedPushedAt.Equal(pushedAt) {
+		t.Errorf("expected pushed_at %v, got %v", pushedAt, storedPushedAt)
+	}
+	if !refreshed {
+		t.Error("expected metadata_refreshed_at to be set")
+	}
+}
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+)
+
+var _ analysis.CoverageRepository = (*CoverageRepository)(nil)
+
+type CoverageRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewCoverageRepository(pool *pgxpool.Pool) *CoverageRepository {
+	return &CoverageRepository{pool: pool}
+}
+
+func (r *CoverageRepository) FindCompletedAnalysis(ctx context.Context, owner, repo, commitSHA string) (analysis.UUID, error) {
+	return findCompletedAnalysis(ctx, db.New(r.pool), owner, repo, commitSHA)
+}
+
+// SaveFileCoverage replaces the coverage stored for reportName in a single transaction.
+// Paths longer than the column limit are skipped, as in saveSourceMapping.
+func (r *CoverageRepository) SaveFileCoverage(ctx context.Context, analysisID analysis.UUID, reportName string, files []analysis.FileCoverage) error {
+	if analysisID == analysis.NilUUID {
+		return fmt.Errorf("%w: analysis ID is required", analysis.ErrInvalidInput)
+	}
+	if reportName == "" || len(reportName) > analysis.MaxReportNameLength {
+		return fmt.Errorf("%w: invalid report name", analysis.ErrInvalidInput)
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rollback transaction",
+				"operation", "SaveFileCoverage",
+				"error", rbErr,
+				"analysis_id", analysisID,
+			)
+		}
+	}()
+
+	queries := db.New(tx)
+	pgAnalysisID := toPgUUID(analysisID)
+
+	if err := queries.DeleteFileCoverageByReport(ctx, db.DeleteFileCoverageByReportParams{
+		AnalysisID: pgAnalysisID,
+		ReportName: reportName,
+	}); err != nil {
+		return fmt.Errorf("delete previous coverage: %w", err)
+	}
+
+	rows := make([][]any, 0, len(files))
+	for _, file := range files {
+		if len(file.Path) > maxSourcePathLength {
+			continue
+		}
+		uncovered := make([]int32, 0, len(file.UncoveredLines))
+		for _, line := range file.UncoveredLines {
+			if line <= math.MaxInt32 {
+				uncovered = append(uncovered, int32(line))
+			}
+		}
+		rows = append(rows, []any{
+			pgAnalysisID,
+			reportName,
+			file.Path,
+			int32(min(file.TotalLines, math.MaxInt32)),
+			int32(min(file.CoveredLines, math.MaxInt32)),
+			uncovered,
+		})
+	}
+	if len(rows) > 0 {
+		if _, err := tx.Conn().CopyFrom(
+			ctx,
+			pgx.Identifier{"analysis_file_coverage"},
+			db.FileCoverageCopyColumns,
+			pgx.CopyFromRows(rows),
+		); err != nil {
+			return fmt.Errorf("copy file coverage: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
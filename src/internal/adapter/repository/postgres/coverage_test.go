@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	testdb "github.com/specvital/collector/internal/testutil/postgres"
+)
+
+func TestCoverageRepository_SaveFileCoverage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	analysisRepo := NewAnalysisRepository(pool)
+	coverageRepo := NewCoverageRepository(pool)
+
+	commitSHA := "4444444444444444444444444444444444444444"
+	analysisID, err := analysisRepo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+		Owner:          "cov-owner",
+		Repo:           "cov-repo",
+		CommitSHA:      commitSHA,
+		Branch:         "main",
+		ExternalRepoID: "cov-id",
+	})
+	if err != nil {
+		t.Fatalf("CreateAnalysisRecord failed: %v", err)
+	}
+	if err := analysisRepo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+		AnalysisID: analysisID,
+		Inventory:  &analysis.Inventory{},
+	}); err != nil {
+		t.Fatalf("SaveAnalysisInventory failed: %v", err)
+	}
+
+	foundID, err := coverageRepo.FindCompletedAnalysis(ctx, "cov-owner", "cov-repo", commitSHA)
+	if err != nil {
+		t.Fatalf("FindCompletedAnalysis failed: %v", err)
+	}
+
+	first := []analysis.FileCoverage{
+		{Path: "src/cart.ts", CoveredLines: 1, TotalLines: 3, UncoveredLines: []int{2, 3}},
+		{Path: "src/order.ts", CoveredLines: 2, TotalLines: 2},
+	}
+	second := []analysis.FileCoverage{
+		{Path: "src/cart.ts", CoveredLines: 2, TotalLines: 3, UncoveredLines: []int{3}},
+	}
+	if err := coverageRepo.SaveFileCoverage(ctx, foundID, "frontend", first); err != nil {
+		t.Fatalf("SaveFileCoverage failed: %v", err)
+	}
+	if err := coverageRepo.SaveFileCoverage(ctx, foundID, "frontend", second); err != nil {
+		t.Fatalf("SaveFileCoverage re-ingest failed: %v", err)
+	}
+
+	var count int
+	if err := pool.QueryRow(ctx,
+		"SELECT COUNT(*) FROM analysis_file_coverage WHERE analysis_id = $1",
+		toPgUUID(analysisID),
+	).Scan(&count); err != nil {
+		t.Fatalf("query coverage count: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected re-ingest to replace the report, got %d rows", count)
+	}
+
+	var covered int
+	var uncovered []int32
+	if err := pool.QueryRow(ctx,
+		"SELECT covered_lines, uncovered_lines FROM analysis_file_coverage WHERE analysis_id = $1 AND file_path = 'src/cart.ts'",
+		toPgUUID(analysisID),
+	).Scan(&covered, &uncovered); err != nil {
+		t.Fatalf("query coverage: %v", err)
+	}
+	if covered != 2 || !reflect.DeepEqual(uncovered, []int32{3}) {
+		t.Errorf("expected 2 covered and [3] uncovered, got %d and %v", covered, uncovered)
+	}
+}
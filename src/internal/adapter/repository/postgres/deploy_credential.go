@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/specvital/core/pkg/crypto"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+)
+
+var _ analysis.DeployCredentialLookup = (*DeployCredentialRepository)(nil)
+
+// DeployCredentialRepository stores codebases' deploy credentials with their secret encrypted.
+type DeployCredentialRepository struct {
+	encryptor crypto.Encryptor
+	pool      *pgxpool.Pool
+}
+
+func NewDeployCredentialRepository(pool *pgxpool.Pool, encryptor crypto.Encryptor) *DeployCredentialRepository {
+	return &DeployCredentialRepository{
+		encryptor: encryptor,
+		pool:      pool,
+	}
+}
+
+// GetDeployCredential looks up the credential of the current codebase named owner/repo.
+func (r *DeployCredentialRepository) GetDeployCredential(ctx context.Context, host, owner, repo string) (analysis.DeployCredential, error) {
+	row, err := db.New(r.pool).GetCodebaseCredential(ctx, db.GetCodebaseCredentialParams{
+		Host:  host,
+		Owner: owner,
+		Name:  repo,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return analysis.DeployCredential{}, analysis.ErrDeployCredentialNotFound
+		}
+		return analysis.DeployCredential{}, fmt.Errorf("query deploy credential: %w", err)
+	}
+
+	secret, err := r.encryptor.Decrypt(row.Secret)
+	if err != nil {
+		return analysis.DeployCredential{}, fmt.Errorf("decrypt deploy credential: %w", err)
+	}
+
+	return analysis.DeployCredential{
+		Kind:   analysis.DeployCredentialKind(row.Kind),
+		Secret: secret,
+	}, nil
+}
+
+// SaveDeployCredential replaces any credential the codebase already has.
+func (r *DeployCredentialRepository) SaveDeployCredential(ctx context.Context, codebaseID analysis.UUID, credential analysis.DeployCredential) error {
+	if err := credential.Validate(); err != nil {
+		return err
+	}
+
+	secret, err := r.encryptor.Encrypt(credential.Secret)
+	if err != nil {
+		return fmt.Errorf("encrypt deploy credential: %w", err)
+	}
+
+	err = db.New(r.pool).UpsertCodebaseCredential(ctx, db.UpsertCodebaseCredentialParams{
+		CodebaseID: toPgUUID(codebaseID),
+		Kind:       string(credential.Kind),
+		Secret:     secret,
+	})
+	if err != nil {
+		return fmt.Errorf("upsert deploy credential: %w", err)
+	}
+	return nil
+}
+
+// DeleteDeployCredential returns analysis.ErrDeployCredentialNotFound if the codebase had none.
+func (r *DeployCredentialRepository) DeleteDeployCredential(ctx context.Context, codebaseID analysis.UUID) error {
+	deleted, err := db.New(r.pool).DeleteCodebaseCredential(ctx, toPgUUID(codebaseID))
+	if err != nil {
+		return fmt.Errorf("delete deploy credential: %w", err)
+	}
+	if deleted == 0 {
+		return analysis.ErrDeployCredentialNotFound
+	}
+	return nil
+}
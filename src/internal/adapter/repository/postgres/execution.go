@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+)
+
+var _ analysis.ExecutionRepository = (*ExecutionRepository)(nil)
+
+type ExecutionRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewExecutionRepository(pool *pgxpool.Pool) *ExecutionRepository {
+	return &ExecutionRepository{pool: pool}
+}
+
+func (r *ExecutionRepository) FindCompletedAnalysis(ctx context.Context, owner, repo, commitSHA string) (analysis.UUID, error) {
+	return findCompletedAnalysis(ctx, db.New(r.pool), owner, repo, commitSHA)
+}
+
+// findCompletedAnalysis resolves the completed analysis that CI reports for a commit attach to.
+func findCompletedAnalysis(ctx context.Context, queries *db.Queries, owner, repo, commitSHA string) (analysis.UUID, error) {
+	id, err := queries.FindCompletedAnalysisByCommit(ctx, db.FindCompletedAnalysisByCommitParams{
+		Host:      defaultHost,
+		Owner:     owner,
+		Name:      repo,
+		CommitSha: commitSHA,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return analysis.NilUUID, analysis.ErrAnalysisNotFound
+	}
+	if err != nil {
+		return analysis.NilUUID, fmt.Errorf("find completed analysis: %w", err)
+	}
+	return fromPgUUID(id), nil
+}
+
+// GetStaticTests returns the test cases of an analysis with their suite chains.
+// Implicit file-level suites are omitted from the chains.
+func (r *ExecutionRepository) GetStaticTests(ctx context.Context, analysisID analysis.UUID) ([]analysis.StaticTest, error) {
+	if analysisID == analysis.NilUUID {
+		return nil, fmt.Errorf("%w: analysis ID is required", analysis.ErrInvalidInput)
+	}
+
+	queries := db.New(r.pool)
+
+	rows, err := queries.GetStaticTestsByAnalysisID(ctx, toPgUUID(analysisID))
+	if err != nil {
+		return nil, fmt.Errorf("get static tests: %w", err)
+	}
+
+	tests := make([]analysis.StaticTest, 0, len(rows))
+	for _, row := range rows {
+		tests = append(tests, analysis.StaticTest{
+			FilePath:  row.FilePath,
+			ID:        fromPgUUID(row.ID),
+			Name:      row.Name,
+			SuitePath: row.SuitePath,
+		})
+	}
+	return tests, nil
+}
+
+// SaveTestExecutions replaces the executions stored for reportName in a single transaction.
+// Reported file paths longer than the column limit are stored as NULL; names are truncated.
+func (r *ExecutionRepository) SaveTestExecutions(ctx context.Context, analysisID analysis.UUID, reportName string, matches []analysis.ExecutionMatch) error {
+	if analysisID == analysis.NilUUID {
+		return fmt.Errorf("%w: analysis ID is required", analysis.ErrInvalidInput)
+	}
+	if reportName == "" || len(reportName) > analysis.MaxReportNameLength {
+		return fmt.Errorf("%w: invalid report name", analysis.ErrInvalidInput)
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rollback transaction",
+				"operation", "SaveTestExecutions",
+				"error", rbErr,
+				"analysis_id", analysisID,
+			)
+		}
+	}()
+
+	queries := db.New(tx)
+	pgAnalysisID := toPgUUID(analysisID)
+
+	if err := queries.DeleteTestExecutionsByReport(ctx, db.DeleteTestExecutionsByReportParams{
+		AnalysisID: pgAnalysisID,
+		ReportName: reportName,
+	}); err != nil {
+		return fmt.Errorf("delete previous executions: %w", err)
+	}
+
+	rows := make([][]any, 0, len(matches))
+	for _, match := range matches {
+		execution := match.Execution
+
+		var testCaseID pgtype.UUID
+		if match.TestCaseID != nil {
+			testCaseID = toPgUUID(*match.TestCaseID)
+		}
+		filePath := pgtype.Text{String: execution.File, Valid: execution.File != "" && len(execution.File) <= maxSourcePathLength}
+		message := pgtype.Text{String: execution.Message, Valid: execution.Message != ""}
+		suitePath := execution.SuitePath
+		if suitePath == nil {
+			suitePath = []string{}
+		}
+
+		rows = append(rows, []any{
+			pgAnalysisID,
+			testCaseID,
+			reportName,
+			filePath,
+			suitePath,
+			truncateString(execution.Name, maxTestCaseNameLength),
+			string(execution.Outcome),
+			int32(min(execution.DurationMs, math.MaxInt32)),
+			message,
+		})
+	}
+	if len(rows) > 0 {
+		if _, err := tx.Conn().CopyFrom(
+			ctx,
+			pgx.Identifier{"test_executions"},
+			db.TestExecutionCopyColumns,
+			pgx.CopyFromRows(rows),
+		); err != nil {
+			return fmt.Errorf("copy test executions: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	testdb "github.com/specvital/collector/internal/testutil/postgres"
+)
+
+func TestExecutionRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	analysisRepo := NewAnalysisRepository(pool)
+	executionRepo := NewExecutionRepository(pool)
+
+	commitSHA := "3333333333333333333333333333333333333333"
+	analysisID, err := analysisRepo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+		Owner:          "exec-owner",
+		Repo:           "exec-repo",
+		CommitSHA:      commitSHA,
+		Branch:         "main",
+		ExternalRepoID: "exec-id",
+	})
+	if err != nil {
+		t.Fatalf("CreateAnalysisRecord failed: %v", err)
+	}
+
+	if _, err := executionRepo.FindCompletedAnalysis(ctx, "exec-owner", "exec-repo", commitSHA); !errors.Is(err, analysis.ErrAnalysisNotFound) {
+		t.Fatalf("expected ErrAnalysisNotFound before completion, got %v", err)
+	}
+
+	if err := analysisRepo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+		AnalysisID: analysisID,
+		Inventory: &analysis.Inventory{
+			Files: []analysis.TestFile{{
+				Path:      "src/cart.test.ts",
+				Framework: "jest",
+				Suites: []analysis.TestSuite{{
+					Name: "Cart",
+					Suites: []analysis.TestSuite{{
+						Name:  "checkout",
+						Tests: []analysis.Test{{Name: "fails", Status: analysis.TestStatusActive}},
+					}},
+				}},
+				Tests: []analysis.Test{{Name: "top level", Status: analysis.TestStatusActive}},
+			}},
+		},
+	}); err != nil {
+		t.Fatalf("SaveAnalysisInventory failed: %v", err)
+	}
+
+	foundID, err := executionRepo.FindCompletedAnalysis(ctx, "exec-owner", "exec-repo", commitSHA)
+	if err != nil {
+		t.Fatalf("FindCompletedAnalysis failed: %v", err)
+	}
+	if foundID != analysisID {
+		t.Fatalf("expected analysis %v, got %v", analysisID, foundID)
+	}
+
+	static, err := executionRepo.GetStaticTests(ctx, analysisID)
+	if err != nil {
+		t.Fatalf("GetStaticTests failed: %v", err)
+	}
+	chains := make(map[string][]string)
+	for _, test := range static {
+		if test.FilePath != "src/cart.test.ts" {
+			t.Errorf("unexpected file path %q", test.FilePath)
+		}
+		chains[test.Name] = test.SuitePath
+	}
+	want := map[string][]string{"fails": {"Cart", "checkout"}, "top level": {}}
+	if !reflect.DeepEqual(chains, want) {
+		t.Fatalf("expected chains %v, got %v", want, chains)
+	}
+
+	matches := analysis.MatchExecutions(static, []analysis.TestExecution{
+		{DurationMs: 12, Message: "boom", Name: "fails", Outcome: analysis.ExecutionOutcomeFailed, SuitePath: []string{"Cart", "checkout"}},
+		{Name: "unknown", Outcome: analysis.ExecutionOutcomeSkipped},
+	})
+	for range 2 {
+		if err := executionRepo.SaveTestExecutions(ctx, analysisID, "unit", matches); err != nil {
+			t.Fatalf("SaveTestExecutions failed: %v", err)
+		}
+	}
+
+	var total, linked int
+	if err := pool.QueryRow(ctx,
+		"SELECT COUNT(*), COUNT(test_case_id) FROM test_executions WHERE analysis_id = $1 AND report_name = 'unit'",
+		toPgUUID(analysisID),
+	).Scan(&total, &linked); err != nil {
+		t.Fatalf("query test executions: %v", err)
+	}
+	if total != 2 || linked != 1 {
+		t.Errorf("expected 2 executions with 1 linked after re-ingest, got %d and %d", total, linked)
+	}
+
+	var outcome string
+	var durationMs int
+	if err := pool.QueryRow(ctx,
+		`SELECT e.outcome, e.duration_ms FROM test_executions e
+		JOIN test_cases tc ON tc.id = e.test_case_id
+		WHERE e.analysis_id = $1 AND tc.name = 'fails'`,
+		toPgUUID(analysisID),
+	).Scan(&outcome, &durationMs); err != nil {
+		t.Fatalf("query linked execution: %v", err)
+	}
+	if outcome != "failed" || durationMs != 12 {
+		t.Errorf("expected failed in 12ms, got %s in %dms", outcome, durationMs)
+	}
+}
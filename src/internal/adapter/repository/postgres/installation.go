@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+)
+
+var _ analysis.InstallationRepository = (*InstallationRepository)(nil)
+
+type InstallationRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewInstallationRepository(pool *pgxpool.Pool) *InstallationRepository {
+	return &InstallationRepository{pool: pool}
+}
+
+// FindInstallationID matches the account login case-insensitively, as GitHub logins are.
+func (r *InstallationRepository) FindInstallationID(ctx context.Context, accountLogin string) (int64, error) {
+	if accountLogin == "" {
+		return 0, fmt.Errorf("%w: account login is required", analysis.ErrInvalidInput)
+	}
+
+	installationID, err := db.New(r.pool).GetActiveInstallationIDByAccountLogin(ctx, accountLogin)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, analysis.ErrInstallationNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("query installation for %s: %w", accountLogin, err)
+	}
+	return installationID, nil
+}
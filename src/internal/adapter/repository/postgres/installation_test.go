@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	testdb "github.com/specvital/collector/internal/testutil/postgres"
+)
+
+func TestInstallationRepository_FindInstallationID(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	repo := NewInstallationRepository(pool)
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, `
+		INSERT INTO github_app_installations (installation_id, account_type, account_id, account_login, suspended_at)
+		VALUES (101, 'organization', 1, 'Acme', NULL),
+		       (102, 'user', 2, 'suspended-user', now())
+	`)
+	if err != nil {
+		t.Fatalf("failed to create installations: %v", err)
+	}
+
+	t.Run("should match login case-insensitively", func(t *testing.T) {
+		id, err := repo.FindInstallationID(ctx, "acme")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id != 101 {
+			t.Errorf("expected installation 101, got %d", id)
+		}
+	})
+
+	t.Run("should ignore suspended installations", func(t *testing.T) {
+		_, err := repo.FindInstallationID(ctx, "suspended-user")
+		if !errors.Is(err, analysis.ErrInstallationNotFound) {
+			t.Errorf("expected ErrInstallationNotFound, got %v", err)
+		}
+	})
+
+	t.Run("should return ErrInstallationNotFound for unknown account", func(t *testing.T) {
+		_, err := repo.FindInstallationID(ctx, "unknown")
+		if !errors.Is(err, analysis.ErrInstallationNotFound) {
+			t.Errorf("expected ErrInstallationNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+)
+
+const maxOwnerLength = 255
+
+type ownerTotals struct {
+	files   int
+	skipped int
+	suites  int
+	tests   int
+}
+
+// saveOwnership stores the CODEOWNERS owners of each test file and per-owner totals.
+// A file owned by several owners counts towards each of them.
+func saveOwnership(ctx context.Context, tx pgx.Tx, analysisID pgtype.UUID, inventory *analysis.Inventory) error {
+	if inventory == nil {
+		return nil
+	}
+
+	var rows [][]any
+	totals := make(map[string]ownerTotals)
+	for _, file := range inventory.Files {
+		owners := uniqueOwners(file.Owners)
+		if len(owners) == 0 {
+			continue
+		}
+
+		counts := file.Counts()
+		for _, owner := range owners {
+			rows = append(rows, []any{analysisID, file.Path, owner})
+
+			t := totals[owner]
+			t.files++
+			t.skipped += counts.ByStatus[analysis.TestStatusSkipped]
+			t.suites += counts.Suites
+			t.tests += counts.Tests
+			totals[owner] = t
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if _, err := tx.Conn().CopyFrom(
+		ctx,
+		pgx.Identifier{"analysis_file_owners"},
+		db.FileOwnerCopyColumns,
+		pgx.CopyFromRows(rows),
+	); err != nil {
+		return fmt.Errorf("copy file owners: %w", err)
+	}
+
+	queries := db.New(tx)
+	for _, owner := range slices.Sorted(maps.Keys(totals)) {
+		t := totals[owner]
+		if err := queries.InsertAnalysisOwnerSummary(ctx, db.InsertAnalysisOwnerSummaryParams{
+			AnalysisID:   analysisID,
+			Owner:        owner,
+			TotalFiles:   int32(t.files),
+			TotalSuites:  int32(t.suites),
+			TotalTests:   int32(t.tests),
+			SkippedTests: int32(t.skipped),
+		}); err != nil {
+			return fmt.Errorf("insert owner summary %q: %w", owner, err)
+		}
+	}
+	return nil
+}
+
+// uniqueOwners drops duplicates and owners that do not fit the column.
+func uniqueOwners(owners []string) []string {
+	var result []string
+	for _, o := range owners {
+		if o == "" || len(o) > maxOwnerLength || slices.Contains(result, o) {
+			continue
+		}
+		result = append(result, o)
+	}
+	return result
+}
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	testdb "github.com/specvital/collector/internal/testutil/postgres"
+)
+
+// Owner totals count suites with TestFile.Counts, which must agree with the stored rows.
+func Test_fileCountsMatchFlattenInventory(t *testing.T) {
+	file := analysis.TestFile{
+		Path:  "api/user_test.go",
+		Tests: []analysis.Test{{Name: "TestTop", Status: analysis.TestStatusSkipped}},
+		Suites: []analysis.TestSuite{
+			{
+				Name:   "User",
+				Tests:  []analysis.Test{{Name: "creates", Status: analysis.TestStatusActive}},
+				Suites: []analysis.TestSuite{{Name: "nested", Tests: []analysis.Test{{Name: "x", Status: analysis.TestStatusSkipped}}}},
+			},
+		},
+	}
+
+	got := file.Counts()
+
+	if got.Suites != 3 || got.Tests != 3 || got.ByStatus[analysis.TestStatusSkipped] != 2 {
+		t.Errorf("expected 3 suites, 3 tests and 2 skipped, got %+v", got)
+	}
+
+	suites, tests := flattenInventory(&analysis.Inventory{Files: []analysis.TestFile{file}})
+	if got.Suites != len(suites) || got.Tests != len(tests) {
+		t.Errorf("counts diverge from flattenInventory: suites=%d/%d tests=%d/%d", got.Suites, len(suites), got.Tests, len(tests))
+	}
+}
+
+func Test_uniqueOwners(t *testing.T) {
+	got := uniqueOwners([]string{"@a", "@b", "@a", ""})
+	if !slices.Equal(got, []string{"@a", "@b"}) {
+		t.Errorf("unexpected owners: %v", got)
+	}
+}
+
+func TestAnalysisRepository_SaveOwnership(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	repo := NewAnalysisRepository(pool)
+	ctx := context.Background()
+
+	analysisID, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+		Owner:          "owners-owner",
+		Repo:           "owners-repo",
+		CommitSHA:      "own123",
+		Branch:         "main",
+		ExternalRepoID: "owners-id",
+	})
+	if err != nil {
+		t.Fatalf("CreateAnalysisRecord failed: %v", err)
+	}
+
+	err = repo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+		AnalysisID: analysisID,
+		Inventory: &analysis.Inventory{
+			Files: []analysis.TestFile{
+				{
+					Path:      "api/user_test.go",
+					Framework: "go-testing",
+					Owners:    []string{"@org/backend", "@alice"},
+					Tests: []analysis.Test{
+						{Name: "TestCreate", Status: analysis.TestStatusActive},
+						{Name: "TestDelete", Status: analysis.TestStatusSkipped},
+					},
+				},
+				{
+					Path:      "api/order_test.go",
+					Framework: "go-testing",
+					Owners:    []string{"@org/backend"},
+					Tests:     []analysis.Test{{Name: "TestOrder", Status: analysis.TestStatusActive}},
+				},
+				{
+					Path:      "scripts/build_test.go",
+					Framework: "go-testing",
+					Tests:     []analysis.Test{{Name: "TestBuild", Status: analysis.TestStatusActive}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SaveAnalysisInventory failed: %v", err)
+	}
+
+	t.Run("should store owners per file", func(t *testing.T) {
+		var count int
+		err := pool.QueryRow(ctx,
+			"SELECT COUNT(*) FROM analysis_file_owners WHERE analysis_id = $1",
+			toPgUUID(analysisID),
+		).Scan(&count)
+		if err != nil {
+			t.Fatalf("failed to count file owners: %v", err)
+		}
+		if count != 3 {
+			t.Errorf("expected 3 file owner rows, got %d", count)
+		}
+	})
+
+	t.Run("should store per-owner totals", func(t *testing.T) {
+		var files, suites, tests, skipped int32
+		err := pool.QueryRow(ctx,
+			"SELECT total_files, total_suites, total_tests, skipped_tests FROM analysis_owner_summaries WHERE analysis_id = $1 AND owner = '@org/backend'",
+			toPgUUID(analysisID),
+		).Scan(&files, &suites, &tests, &skipped)
+		if err != nil {
+			t.Fatalf("failed to query owner summary: %v", err)
+		}
+		if files != 2 || suites != 2 || tests != 3 || skipped != 1 {
+			t.Errorf("unexpected totals: files=%d suites=%d tests=%d skipped=%d", files, suites, tests, skipped)
+		}
+
+		var owners int
+		err = pool.QueryRow(ctx,
+			"SELECT COUNT(*) FROM analysis_owner_summaries WHERE analysis_id = $1",
+			toPgUUID(analysisID),
+		).Scan(&owners)
+		if err != nil {
+			t.Fatalf("failed to count owner summaries: %v", err)
+		}
+		if owners != 2 {
+			t.Errorf("expected 2 owner summaries, got %d", owners)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+)
+
+const (
+	maxPackageNameLength = 255
+	maxPackagePathLength = 500
+)
+
+type packageTotals struct {
+	files  int
+	suites int
+	tests  int
+}
+
+// savePackages stores one row per workspace with totals counted the same way as the
+// analysis totals, so package totals sum to the analysis totals minus files outside any package.
+func savePackages(
+	ctx context.Context,
+	queries *db.Queries,
+	analysisID pgtype.UUID,
+	inventory *analysis.Inventory,
+	workspaces []analysis.Workspace,
+) error {
+	if len(workspaces) == 0 {
+		return nil
+	}
+
+	totals := countPackageTotals(inventory)
+	for _, ws := range workspaces {
+		t := totals[ws.Path]
+		if err := queries.InsertAnalysisPackage(ctx, db.InsertAnalysisPackageParams{
+			AnalysisID:  analysisID,
+			Path:        truncateString(ws.Path, maxPackagePathLength),
+			Name:        truncateString(ws.Name, maxPackageNameLength),
+			Kind:        string(ws.Kind),
+			TotalFiles:  int32(t.files),
+			TotalSuites: int32(t.suites),
+			TotalTests:  int32(t.tests),
+		}); err != nil {
+			return fmt.Errorf("insert package %q: %w", ws.Path, err)
+		}
+	}
+	return nil
+}
+
+func countPackageTotals(inventory *analysis.Inventory) map[string]packageTotals {
+	totals := make(map[string]packageTotals)
+	if inventory == nil {
+		return totals
+	}
+
+	for _, file := range inventory.Files {
+		counts := file.Counts()
+		t := totals[file.Package]
+		t.files++
+		t.suites += counts.Suites
+		t.tests += counts.Tests
+		totals[file.Package] = t
+	}
+	return totals
+}
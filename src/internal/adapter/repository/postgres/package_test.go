@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	testdb "github.com/specvital/collector/internal/testutil/postgres"
+)
+
+func Test_countPackageTotals(t *testing.T) {
+	inventory := &analysis.Inventory{
+		Files: []analysis.TestFile{
+			{
+				Path:    "packages/ui/button.test.ts",
+				Package: "packages/ui",
+				Suites: []analysis.TestSuite{
+					{
+						Name:   "Button",
+						Tests:  []analysis.Test{{Name: "renders"}},
+						Suites: []analysis.TestSuite{{Name: "disabled", Tests: []analysis.Test{{Name: "ignores click"}}}},
+					},
+				},
+			},
+			{
+				Path:    "packages/ui/input.test.ts",
+				Package: "packages/ui",
+				Tests:   []analysis.Test{{Name: "accepts text"}},
+			},
+			{
+				Path:  "scripts/build.test.js",
+				Tests: []analysis.Test{{Name: "builds"}},
+			},
+		},
+	}
+
+	totals := countPackageTotals(inventory)
+
+	if got := totals["packages/ui"]; got != (packageTotals{files: 2, suites: 3, tests: 3}) {
+		t.Errorf("unexpected packages/ui totals: %+v", got)
+	}
+	if got := totals[""]; got != (packageTotals{files: 1, suites: 1, tests: 1}) {
+		t.Errorf("unexpected totals outside packages: %+v", got)
+	}
+	if len(countPackageTotals(nil)) != 0 {
+		t.Error("expected empty totals for nil inventory")
+	}
+}
+
+func TestAnalysisRepository_SavePackages(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	repo := NewAnalysisRepository(pool)
+	ctx := context.Background()
+
+	analysisID, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+		Owner:          "mono-owner",
+		Repo:           "mono-repo",
+		CommitSHA:      "mono123",
+		Branch:         "main",
+		ExternalRepoID: "mono-id",
+	})
+	if err != nil {
+		t.Fatalf("CreateAnalysisRecord failed: %v", err)
+	}
+
+	err = repo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+		AnalysisID: analysisID,
+		Inventory: &analysis.Inventory{
+			Files: []analysis.TestFile{
+				{
+					Path:      "apps/web/page.test.tsx",
+					Framework: "vitest",
+					Package:   "apps/web",
+					Suites: []analysis.TestSuite{
+						{Name: "Page", Tests: []analysis.Test{{Name: "renders", Status: analysis.TestStatusActive}}},
+					},
+				},
+				{
+					Path:      "tools/lint.test.js",
+					Framework: "vitest",
+					Tests:     []analysis.Test{{Name: "lints", Status: analysis.TestStatusActive}},
+				},
+			},
+		},
+		Workspaces: []analysis.Workspace{
+			{Kind: analysis.WorkspaceKindPNPM, Name: "@acme/web", Path: "apps/web"},
+			{Kind: analysis.WorkspaceKindPNPM, Name: "@acme/empty", Path: "apps/empty"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SaveAnalysisInventory failed: %v", err)
+	}
+
+	t.Run("should store package path on suites", func(t *testing.T) {
+		var packagePath *string
+		err := pool.QueryRow(ctx,
+			"SELECT package_path FROM test_suites WHERE analysis_id = $1 AND name = 'Page'",
+			toPgUUID(analysisID),
+		).Scan(&packagePath)
+		if err != nil {
+			t.Fatalf("failed to query suite: %v", err)
+		}
+		if packagePath == nil || *packagePath != "apps/web" {
+			t.Errorf("expected package path 'apps/web', got %v", packagePath)
+		}
+
+		var outside *string
+		err = pool.QueryRow(ctx,
+			"SELECT package_path FROM test_suites WHERE analysis_id = $1 AND file_path = 'tools/lint.test.js'",
+			toPgUUID(analysisID),
+		).Scan(&outside)
+		if err != nil {
+			t.Fatalf("failed to query suite: %v", err)
+		}
+		if outside != nil {
+			t.Errorf("expected NULL package path, got %q", *outside)
+		}
+	})
+
+	t.Run("should store per-package totals", func(t *testing.T) {
+		rows, err := pool.Query(ctx,
+			"SELECT path, name, kind, total_files, total_suites, total_tests FROM analysis_packages WHERE analysis_id = $1 ORDER BY path",
+			toPgUUID(analysisID),
+		)
+		if err != nil {
+			t.Fatalf("failed to query packages: %v", err)
+		}
+		defer rows.Close()
+
+		type packageRow struct {
+			path, name, kind          string
+			files, suites, testsCount int32
+		}
+		var got []packageRow
+		for rows.Next() {
+			var r packageRow
+			if err := rows.Scan(&r.path, &r.name, &r.kind, &r.files, &r.suites, &r.testsCount); err != nil {
+				t.Fatalf("scan failed: %v", err)
+			}
+			got = append(got, r)
+		}
+
+		want := []packageRow{
+			{path: "apps/empty", name: "@acme/empty", kind: "pnpm"},
+			{path: "apps/web", name: "@acme/web", kind: "pnpm", files: 1, suites: 1, testsCount: 1},
+		}
+		if len(got) != len(want) {
+			t.Fatalf("expected %d packages, got %d", len(want), len(got))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("package %d: expected %+v, got %+v", i, want[i], got[i])
+			}
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+)
+
+var _ analysis.PolicyRuleRepository = (*PolicyRepository)(nil)
+
+const maxPolicyDetailLength = 2000
+
+type PolicyRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPolicyRepository(pool *pgxpool.Pool) *PolicyRepository {
+	return &PolicyRepository{pool: pool}
+}
+
+func (r *PolicyRepository) GetCodebasePolicyRules(ctx context.Context, codebaseID analysis.UUID) ([]analysis.PolicyRule, error) {
+	if codebaseID == analysis.NilUUID {
+		return nil, fmt.Errorf("%w: codebase ID is required", analysis.ErrInvalidInput)
+	}
+
+	queries := db.New(r.pool)
+
+	rows, err := queries.GetCodebasePolicyRules(ctx, toPgUUID(codebaseID))
+	if err != nil {
+		return nil, fmt.Errorf("get codebase policy rules: %w", err)
+	}
+
+	rules := make([]analysis.PolicyRule, 0, len(rows))
+	for _, row := range rows {
+		rule := analysis.PolicyRule{
+			Kind:   analysis.PolicyRuleKind(row.Kind),
+			Name:   row.Name,
+			Source: analysis.PolicySourceCodebase,
+		}
+		if row.Status.Valid {
+			rule.Status = analysis.TestStatus(row.Status.TestStatus)
+		}
+		if row.Threshold.Valid {
+			rule.Threshold = row.Threshold.Float64
+		}
+		if row.PathPattern.Valid {
+			rule.Path = row.PathPattern.String
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func savePolicyResults(ctx context.Context, queries *db.Queries, analysisID pgtype.UUID, results []analysis.PolicyResult) error {
+	for _, result := range results {
+		if err := queries.InsertAnalysisPolicyResult(ctx, db.InsertAnalysisPolicyResultParams{
+			AnalysisID: analysisID,
+			Name:       result.Name,
+			Kind:       string(result.Kind),
+			Source:     string(result.Source),
+			Passed:     result.Passed,
+			Detail:     pgtype.Text{String: truncateString(result.Detail, maxPolicyDetailLength), Valid: result.Detail != ""},
+		}); err != nil {
+			return fmt.Errorf("insert policy result %q: %w", result.Name, err)
+		}
+	}
+	return nil
+}
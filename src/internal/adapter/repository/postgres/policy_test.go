@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	testdb "github.com/specvital/collector/internal/testutil/postgres"
+)
+
+func TestPolicyRepository_GetCodebasePolicyRules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	analysisRepo := NewAnalysisRepository(pool)
+	codebaseRepo := NewCodebaseRepository(pool)
+	policyRepo := NewPolicyRepository(pool)
+	ctx := context.Background()
+
+	t.Run("should return rules configured for codebase", func(t *testing.T) {
+		codebase, err := codebaseRepo.Upsert(ctx, analysis.UpsertCodebaseParams{
+			Host:           "github.com",
+			Owner:          "policy-owner",
+			Name:           "policy-repo",
+			ExternalRepoID: "policy-ext-id",
+		})
+		if err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+
+		_, err = pool.Exec(ctx, `
+			INSERT INTO codebase_policy_rules (codebase_id, name, kind, status, threshold, path_pattern)
+			VALUES ($1, 'no-focused', 'max_status_count', 'focused', 0, NULL),
+			       ($1, 'src-tested', 'require_test_file', NULL, NULL, 'src/**')
+		`, toPgUUID(codebase.ID))
+		if err != nil {
+			t.Fatalf("failed to insert policy rules: %v", err)
+		}
+
+		rules, err := policyRepo.GetCodebasePolicyRules(ctx, codebase.ID)
+		if err != nil {
+			t.Fatalf("GetCodebasePolicyRules failed: %v", err)
+		}
+		if len(rules) != 2 {
+			t.Fatalf("expected 2 rules, got %d", len(rules))
+		}
+		if rules[0].Name != "no-focused" || rules[0].Status != analysis.TestStatusFocused {
+			t.Errorf("unexpected first rule: %+v", rules[0])
+		}
+		if rules[1].Path != "src/**" || rules[1].Kind != analysis.PolicyRuleKindRequireTestFile {
+			t.Errorf("unexpected second rule: %+v", rules[1])
+		}
+		for _, r := range rules {
+			if r.Source != analysis.PolicySourceCodebase {
+				t.Errorf("expected source codebase, got %s", r.Source)
+			}
+		}
+	})
+
+	t.Run("should return empty slice when no rules configured", func(t *testing.T) {
+		_, err := analysisRepo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+			Owner:          "no-policy-owner",
+			Repo:           "no-policy-repo",
+			CommitSHA:      "no-policy-sha",
+			Branch:         "main",
+			ExternalRepoID: "no-policy-ext-id",
+		})
+		if err != nil {
+			t.Fatalf("CreateAnalysisRecord failed: %v", err)
+		}
+
+		codebase, err := codebaseRepo.FindByExternalID(ctx, "github.com", "no-policy-ext-id")
+		if err != nil {
+			t.Fatalf("FindByExternalID failed: %v", err)
+		}
+
+		rules, err := policyRepo.GetCodebasePolicyRules(ctx, codebase.ID)
+		if err != nil {
+			t.Fatalf("GetCodebasePolicyRules failed: %v", err)
+		}
+		if len(rules) != 0 {
+			t.Errorf("expected no rules, got %d", len(rules))
+		}
+	})
+
+	t.Run("should return error for nil codebase ID", func(t *testing.T) {
+		_, err := policyRepo.GetCodebasePolicyRules(ctx, analysis.NilUUID)
+		if !errors.Is(err, analysis.ErrInvalidInput) {
+			t.Errorf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+}
+
+func TestAnalysisRepository_SavePolicyResults(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	repo := NewAnalysisRepository(pool)
+	ctx := context.Background()
+
+	t.Run("should persist policy results with inventory", func(t *testing.T) {
+		analysisID, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+			Owner:          "policy-result-owner",
+			Repo:           "policy-result-repo",
+			CommitSHA:      "policy-result-sha",
+			Branch:         "main",
+			ExternalRepoID: "policy-result-ext-id",
+		})
+		if err != nil {
+			t.Fatalf("CreateAnalysisRecord failed: %v", err)
+		}
+
+		err = repo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+			AnalysisID: analysisID,
+			Inventory:  &analysis.Inventory{},
+			PolicyResults: []analysis.PolicyResult{
+				{Name: "no-focused", Kind: analysis.PolicyRuleKindMaxStatusCount, Source: analysis.PolicySourceRepository, Passed: true, Detail: "0 focused tests (max 0)"},
+				{Name: "src-tested", Kind: analysis.PolicyRuleKindRequireTestFile, Source: analysis.PolicySourceCodebase, Passed: false, Detail: "1 files without a test file: src/a.go"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("SaveAnalysisInventory failed: %v", err)
+		}
+
+		var total, passed int
+		err = pool.QueryRow(ctx, `
+			SELECT COUNT(*), COUNT(*) FILTER (WHERE passed)
+			FROM analysis_policy_results WHERE analysis_id = $1
+		`, toPgUUID(analysisID)).Scan(&total, &passed)
+		if err != nil {
+			t.Fatalf("failed to query policy results: %v", err)
+		}
+		if total != 2 {
+			t.Errorf("expected 2 policy results, got %d", total)
+		}
+		if passed != 1 {
+			t.Errorf("expected 1 passed policy result, got %d", passed)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+)
+
+var _ analysis.ReleaseTagRepository = (*ReleaseTagRepository)(nil)
+
+const (
+	maxTagNameLength = 255
+	maxTagSHALength  = 40
+)
+
+type ReleaseTagRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewReleaseTagRepository(pool *pgxpool.Pool) *ReleaseTagRepository {
+	return &ReleaseTagRepository{pool: pool}
+}
+
+func (r *ReleaseTagRepository) GetCodebaseTags(ctx context.Context, codebaseID analysis.UUID) ([]analysis.ReleaseTag, error) {
+	if codebaseID == analysis.NilUUID {
+		return nil, fmt.Errorf("%w: codebase ID is required", analysis.ErrInvalidInput)
+	}
+
+	queries := db.New(r.pool)
+
+	rows, err := queries.GetCodebaseTags(ctx, toPgUUID(codebaseID))
+	if err != nil {
+		return nil, fmt.Errorf("get codebase tags: %w", err)
+	}
+
+	tags := make([]analysis.ReleaseTag, 0, len(rows))
+	for _, row := range rows {
+		tags = append(tags, analysis.ReleaseTag{Name: row.Name, SHA: row.CommitSha})
+	}
+	return tags, nil
+}
+
+// SaveCodebaseTags upserts tags and links each to the completed analysis of its commit, if any.
+// Tags analyzed later are linked when their analysis completes.
+func (r *ReleaseTagRepository) SaveCodebaseTags(ctx context.Context, codebaseID analysis.UUID, tags []analysis.ReleaseTag) error {
+	if codebaseID == analysis.NilUUID {
+		return fmt.Errorf("%w: codebase ID is required", analysis.ErrInvalidInput)
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rollback transaction",
+				"operation", "SaveCodebaseTags",
+				"error", rbErr,
+				"codebase_id", codebaseID,
+			)
+		}
+	}()
+
+	queries := db.New(tx)
+	pgCodebaseID := toPgUUID(codebaseID)
+
+	for _, tag := range tags {
+		if len(tag.Name) > maxTagNameLength || len(tag.SHA) > maxTagSHALength {
+			slog.WarnContext(ctx, "skipping tag exceeding column limits",
+				"codebase_id", codebaseID,
+				"tag", truncateString(tag.Name, maxTagNameLength),
+			)
+			continue
+		}
+		if err := queries.UpsertCodebaseTag(ctx, db.UpsertCodebaseTagParams{
+			CodebaseID: pgCodebaseID,
+			Name:       tag.Name,
+			CommitSha:  tag.SHA,
+		}); err != nil {
+			return fmt.Errorf("upsert codebase tag %q: %w", tag.Name, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/specvital/collector/internal/domain/analysis"
+	testdb "github.com/specvital/collector/internal/testutil/postgres"
+)
+
+func TestReleaseTagRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	analysisRepo := NewAnalysisRepository(pool)
+	tagRepo := NewReleaseTagRepository(pool)
+
+	releasedSHA := "1111111111111111111111111111111111111111"
+	pendingSHA := "2222222222222222222222222222222222222222"
+
+	releasedID, err := analysisRepo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+		Owner:          "tag-owner",
+		Repo:           "tag-repo",
+		CommitSHA:      releasedSHA,
+		Branch:         "main",
+		ExternalRepoID: "tag-id",
+	})
+	if err != nil {
+		t.Fatalf("CreateAnalysisRecord failed: %v", err)
+	}
+	if err := analysisRepo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+		AnalysisID: releasedID,
+		Inventory:  &analysis.Inventory{},
+	}); err != nil {
+		t.Fatalf("SaveAnalysisInventory failed: %v", err)
+	}
+
+	var pgCodebaseID pgtype.UUID
+	if err := pool.QueryRow(ctx, "SELECT codebase_id FROM analyses WHERE id = $1", toPgUUID(releasedID)).Scan(&pgCodebaseID); err != nil {
+		t.Fatalf("query codebase: %v", err)
+	}
+	codebaseID := fromPgUUID(pgCodebaseID)
+
+	if err := tagRepo.SaveCodebaseTags(ctx, codebaseID, []analysis.ReleaseTag{
+		{Name: "v1.0.0", SHA: releasedSHA},
+		{Name: "v1.1.0", SHA: pendingSHA},
+	}); err != nil {
+		t.Fatalf("SaveCodebaseTags failed: %v", err)
+	}
+
+	tags, err := tagRepo.GetCodebaseTags(ctx, codebaseID)
+	if err != nil {
+		t.Fatalf("GetCodebaseTags failed: %v", err)
+	}
+	if len(tags) != 2 || tags[0].Name != "v1.0.0" || tags[1].SHA != pendingSHA {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+
+	var linked pgtype.UUID
+	if err := pool.QueryRow(ctx, "SELECT analysis_id FROM codebase_tags WHERE name = 'v1.0.0'").Scan(&linked); err != nil {
+		t.Fatalf("query v1.0.0 link: %v", err)
+	}
+	if fromPgUUID(linked) != releasedID {
+		t.Errorf("expected v1.0.0 linked to %s, got %s", releasedID, fromPgUUID(linked))
+	}
+
+	pendingID, err := analysisRepo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+		Owner:          "tag-owner",
+		Repo:           "tag-repo",
+		CommitSHA:      pendingSHA,
+		Branch:         "main",
+		ExternalRepoID: "tag-id",
+	})
+	if err != nil {
+		t.Fatalf("CreateAnalysisRecord failed: %v", err)
+	}
+	if err := analysisRepo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+		AnalysisID: pendingID,
+		Inventory:  &analysis.Inventory{},
+	}); err != nil {
+		t.Fatalf("SaveAnalysisInventory failed: %v", err)
+	}
+
+	if err := pool.QueryRow(ctx, "SELECT analysis_id FROM codebase_tags WHERE name = 'v1.1.0'").Scan(&linked); err != nil {
+		t.Fatalf("query v1.1.0 link: %v", err)
+	}
+	if fromPgUUID(linked) != pendingID {
+		t.Errorf("expected v1.1.0 linked on completion to %s, got %s", pendingID, fromPgUUID(linked))
+	}
+}
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+)
+
+var _ analysis.ReportUploadStore = (*ReportUploadRepository)(nil)
+
+type ReportUploadRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewReportUploadRepository(pool *pgxpool.Pool) *ReportUploadRepository {
+	return &ReportUploadRepository{pool: pool}
+}
+
+func (r *ReportUploadRepository) GetReportUpload(ctx context.Context, id analysis.UUID) ([]byte, error) {
+	report, err := db.New(r.pool).GetReportUpload(ctx, toPgUUID(id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, analysis.ErrReportUploadNotFound
+		}
+		return nil, fmt.Errorf("get report upload: %w", err)
+	}
+	return report, nil
+}
+
+func (r *ReportUploadRepository) DeleteReportUpload(ctx context.Context, id analysis.UUID) error {
+	if err := db.New(r.pool).DeleteReportUpload(ctx, toPgUUID(id)); err != nil {
+		return fmt.Errorf("delete report upload: %w", err)
+	}
+	return nil
+}
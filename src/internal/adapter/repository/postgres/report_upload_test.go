@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+	testdb "github.com/specvital/collector/internal/testutil/postgres"
+)
+
+func TestReportUploadRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewReportUploadRepository(pool)
+
+	pgID, err := db.New(pool).InsertReportUpload(ctx, []byte("<testsuites/>"))
+	if err != nil {
+		t.Fatalf("InsertReportUpload failed: %v", err)
+	}
+	id := fromPgUUID(pgID)
+
+	report, err := repo.GetReportUpload(ctx, id)
+	if err != nil {
+		t.Fatalf("GetReportUpload failed: %v", err)
+	}
+	if string(report) != "<testsuites/>" {
+		t.Errorf("expected stored report, got %q", report)
+	}
+
+	if err := repo.DeleteReportUpload(ctx, id); err != nil {
+		t.Fatalf("DeleteReportUpload failed: %v", err)
+	}
+	if _, err := repo.GetReportUpload(ctx, id); !errors.Is(err, analysis.ErrReportUploadNotFound) {
+		t.Errorf("expected ErrReportUploadNotFound after delete, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+)
+
+const maxSourcePathLength = 1000
+
+// saveSourceMapping stores test-to-source links and untested source files.
+// Paths longer than the column limit are skipped rather than truncated, since a truncated path names no file.
+func saveSourceMapping(ctx context.Context, tx pgx.Tx, analysisID pgtype.UUID, mapping *analysis.SourceMapping) error {
+	if mapping == nil {
+		return nil
+	}
+
+	var links [][]any
+	for _, link := range mapping.Links {
+		if len(link.TestPath) > maxSourcePathLength || len(link.SourcePath) > maxSourcePathLength {
+			continue
+		}
+		links = append(links, []any{analysisID, link.TestPath, link.SourcePath})
+	}
+	if len(links) > 0 {
+		if _, err := tx.Conn().CopyFrom(
+			ctx,
+			pgx.Identifier{"analysis_test_sources"},
+			db.TestSourceCopyColumns,
+			pgx.CopyFromRows(links),
+		); err != nil {
+			return fmt.Errorf("copy test sources: %w", err)
+		}
+	}
+
+	var untested [][]any
+	for _, file := range mapping.Untested {
+		if len(file.Path) > maxSourcePathLength {
+			continue
+		}
+		untested = append(untested, []any{analysisID, file.Path, file.Language})
+	}
+	if len(untested) > 0 {
+		if _, err := tx.Conn().CopyFrom(
+			ctx,
+			pgx.Identifier{"analysis_untested_files"},
+			db.UntestedFileCopyColumns,
+			pgx.CopyFromRows(untested),
+		); err != nil {
+			return fmt.Errorf("copy untested files: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	testdb "github.com/specvital/collector/internal/testutil/postgres"
+)
+
+func TestAnalysisRepository_SaveSourceMapping(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	repo := NewAnalysisRepository(pool)
+	ctx := context.Background()
+
+	analysisID, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+		Owner:          "map-owner",
+		Repo:           "map-repo",
+		CommitSHA:      "map123",
+		Branch:         "main",
+		ExternalRepoID: "map-id",
+	})
+	if err != nil {
+		t.Fatalf("CreateAnalysisRecord failed: %v", err)
+	}
+
+	err = repo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+		AnalysisID: analysisID,
+		Inventory: &analysis.Inventory{
+			Files: []analysis.TestFile{{Path: "pkg/user_test.go", Framework: "go-testing"}},
+		},
+		SourceMapping: &analysis.SourceMapping{
+			Links: []analysis.TestSourceLink{{SourcePath: "pkg/user.go", TestPath: "pkg/user_test.go"}},
+			Untested: []analysis.UntestedFile{
+				{Language: "go", Path: "pkg/order.go"},
+				{Language: "go", Path: "pkg/invoice.go"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SaveAnalysisInventory failed: %v", err)
+	}
+
+	var sourcePath string
+	if err := pool.QueryRow(ctx,
+		"SELECT source_file_path FROM analysis_test_sources WHERE analysis_id = $1 AND test_file_path = 'pkg/user_test.go'",
+		toPgUUID(analysisID),
+	).Scan(&sourcePath); err != nil {
+		t.Fatalf("query test sources: %v", err)
+	}
+	if sourcePath != "pkg/user.go" {
+		t.Errorf("expected pkg/user.go, got %s", sourcePath)
+	}
+
+	var untested int
+	if err := pool.QueryRow(ctx,
+		"SELECT COUNT(*) FROM analysis_untested_files WHERE analysis_id = $1 AND language = 'go'",
+		toPgUUID(analysisID),
+	).Scan(&untested); err != nil {
+		t.Fatalf("query untested files: %v", err)
+	}
+	if untested != 2 {
+		t.Errorf("expected 2 untested files, got %d", untested)
+	}
+}
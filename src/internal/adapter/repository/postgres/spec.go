@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
+)
+
+var _ analysis.SpecRepository = (*SpecRepository)(nil)
+
+type SpecRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewSpecRepository(pool *pgxpool.Pool) *SpecRepository {
+	return &SpecRepository{pool: pool}
+}
+
+func (r *SpecRepository) FindCompletedAnalysis(ctx context.Context, owner, repo, commitSHA string) (analysis.UUID, error) {
+	return findCompletedAnalysis(ctx, db.New(r.pool), owner, repo, commitSHA)
+}
+
+func (r *SpecRepository) FindLatestCompletedAnalysis(ctx context.Context, owner, repo string) (analysis.UUID, error) {
+	id, err := db.New(r.pool).FindLatestCompletedAnalysis(ctx, db.FindLatestCompletedAnalysisParams{
+		Host:  defaultHost,
+		Owner: owner,
+		Name:  repo,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return analysis.NilUUID, analysis.ErrAnalysisNotFound
+	}
+	if err != nil {
+		return analysis.NilUUID, fmt.Errorf("find latest completed analysis: %w", err)
+	}
+	return fromPgUUID(id), nil
+}
+
+func (r *SpecRepository) FindUpstreamLatestCompletedAnalysis(ctx context.Context, owner, repo string) (analysis.UUID, error) {
+	id, err := db.New(r.pool).FindUpstreamLatestCompletedAnalysis(ctx, db.FindUpstreamLatestCompletedAnalysisParams{
+		Host:  defaultHost,
+		Owner: owner,
+		Name:  repo,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return analysis.NilUUID, analysis.ErrAnalysisNotFound
+	}
+	if err != nil {
+		return analysis.NilUUID, fmt.Errorf("find upstream latest completed analysis: %w", err)
+	}
+	return fromPgUUID(id), nil
+}
+
+// GetSpecDocument rebuilds the suite and test tree of an analysis.
+// Tests of implicit file-level suites become file-level tests again.
+func (r *SpecRepository) GetSpecDocument(ctx context.Context, analysisID analysis.UUID) (*analysis.SpecDocument, error) {
+	if analysisID == analysis.NilUUID {
+		return nil, fmt.Errorf("%w: analysis ID is required", analysis.ErrInvalidInput)
+	}
+
+	queries := db.New(r.pool)
+	pgID := toPgUUID(analysisID)
+
+	info, err := queries.GetCompletedAnalysisWithCodebase(ctx, pgID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, analysis.ErrAnalysisNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get analysis: %w", err)
+	}
+
+	suites, err := queries.GetTestSuitesByAnalysisID(ctx, pgID)
+	if err != nil {
+		return nil, fmt.Errorf("get test suites: %w", err)
+	}
+	cases, err := queries.GetTestCasesByAnalysisID(ctx, pgID)
+	if err != nil {
+		return nil, fmt.Errorf("get test cases: %w", err)
+	}
+
+	return &analysis.SpecDocument{
+		Branch:      info.BranchName.String,
+		CommitSHA:   info.CommitSha,
+		CompletedAt: info.CompletedAt.Time,
+		Host:        info.Host,
+		Inventory:   buildSpecInventory(suites, cases),
+		Owner:       info.Owner,
+		Repo:        info.Name,
+	}, nil
+}
+
+type specSuiteNode struct {
+	children []*specSuiteNode
+	row      db.TestSuite
+}
+
+// buildSpecInventory expects suites ordered by file path and line, as GetTestSuitesByAnalysisID returns them.
+func buildSpecInventory(suites []db.TestSuite, cases []db.GetTestCasesByAnalysisIDRow) *analysis.Inventory {
+	testsBySuite := make(map[[16]byte][]analysis.Test)
+	for _, c := range cases {
+		testsBySuite[c.SuiteID.Bytes] = append(testsBySuite[c.SuiteID.Bytes], analysis.Test{
+			Location: specLocation(c.LineNumber, c.EndLineNumber),
+			Name:     c.Name,
+			// db.TestStatus shares the values of analysis.TestStatus.
+			Status: analysis.TestStatus(c.Status),
+		})
+	}
+
+	nodes := make(map[[16]byte]*specSuiteNode, len(suites))
+	for _, s := range suites {
+		nodes[s.ID.Bytes] = &specSuiteNode{row: s}
+	}
+
+	inventory := &analysis.Inventory{}
+	fileIndex := make(map[string]int)
+	for _, s := range suites {
+		node := nodes[s.ID.Bytes]
+		if s.ParentID.Valid {
+			if parent, ok := nodes[s.ParentID.Bytes]; ok {
+				parent.children = append(parent.children, node)
+				continue
+			}
+		}
+
+		i, ok := fileIndex[s.FilePath]
+		if !ok {
+			i = len(inventory.Files)
+			fileIndex[s.FilePath] = i
+			inventory.Files = append(inventory.Files, analysis.TestFile{
+				Framework: s.Framework.String,
+				Package:   s.PackagePath.String,
+				Path:      s.FilePath,
+			})
+		}
+		file := &inventory.Files[i]
+		if s.Depth == 0 && s.Name == s.FilePath {
+			file.Tests = append(file.Tests, testsBySuite[s.ID.Bytes]...)
+			continue
+		}
+		file.Suites = append(file.Suites, node.toSuite(testsBySuite))
+	}
+	return inventory
+}
+
+func (n *specSuiteNode) toSuite(testsBySuite map[[16]byte][]analysis.Test) analysis.TestSuite {
+	suite := analysis.TestSuite{
+		Location: specLocation(n.row.LineNumber, n.row.EndLineNumber),
+		Name:     n.row.Name,
+		Tests:    testsBySuite[n.row.ID.Bytes],
+	}
+	for _, child := range n.children {
+		suite.Suites = append(suite.Suites, child.toSuite(testsBySuite))
+	}
+	return suite
+}
+
+func specLocation(start, end pgtype.Int4) analysis.Location {
+	return analysis.Location{StartLine: int(start.Int32), EndLine: int(end.Int32)}
+}
@@ -0,0 +1,260 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	testdb "github.com/specvital/collector/internal/testutil/postgres"
+)
+
+func TestSpecRepository_GetSpecDocument(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	repo := NewAnalysisRepository(pool)
+	specRepo := NewSpecRepository(pool)
+	ctx := context.Background()
+
+	if _, err := specRepo.FindLatestCompletedAnalysis(ctx, "spec-owner", "spec-repo"); !errors.Is(err, analysis.ErrAnalysisNotFound) {
+		t.Fatalf("expected ErrAnalysisNotFound, got %v", err)
+	}
+
+	analysisID, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+		Owner:          "spec-owner",
+		Repo:           "spec-repo",
+		CommitSHA:      "spec123",
+		Branch:         "main",
+		ExternalRepoID: "spec-id",
+	})
+	if err != nil {
+		t.Fatalf("CreateAnalysisRecord failed: %v", err)
+	}
+
+	if _, err := specRepo.GetSpecDocument(ctx, analysisID); !errors.Is(err, analysis.ErrAnalysisNotFound) {
+		t.Fatalf("expected ErrAnalysisNotFound for running analysis, got %v", err)
+	}
+
+	inventory := &analysis.Inventory{
+		Files: []analysis.TestFile{
+			{
+				Framework: "jest",
+				Path:      "src/cart.test.ts",
+				Suites: []analysis.TestSuite{
+					{
+						Location: analysis.Location{StartLine: 3, EndLine: 20},
+						Name:     "Cart",
+						Suites: []analysis.TestSuite{
+							{
+								Location: analysis.Location{StartLine: 4, EndLine: 12},
+								Name:     "add",
+								Tests: []analysis.Test{
+									{Location: analysis.Location{StartLine: 5, EndLine: 7}, Name: "adds an item", Status: analysis.TestStatusActive},
+									{Location: analysis.Location{StartLine: 9, EndLine: 11}, Name: "rejects negatives", Status: analysis.TestStatusSkipped},
+								},
+							},
+						},
+						Tests: []analysis.Test{
+							{Location: analysis.Location{StartLine: 14, EndLine: 16}, Name: "starts empty", Status: analysis.TestStatusActive},
+						},
+					},
+				},
+				Tests: []analysis.Test{
+					{Location: analysis.Location{StartLine: 22, EndLine: 24}, Name: "top level", Status: analysis.TestStatusTodo},
+				},
+			},
+		},
+	}
+	if err := repo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+		AnalysisID: analysisID,
+		Inventory:  inventory,
+	}); err != nil {
+		t.Fatalf("SaveAnalysisInventory failed: %v", err)
+	}
+
+	latest, err := specRepo.FindLatestCompletedAnalysis(ctx, "spec-owner", "spec-repo")
+	if err != nil {
+		t.Fatalf("FindLatestCompletedAnalysis failed: %v", err)
+	}
+	if latest != analysisID {
+		t.Errorf("expected latest analysis %v, got %v", analysisID, latest)
+	}
+	byCommit, err := specRepo.FindCompletedAnalysis(ctx, "spec-owner", "spec-repo", "spec123")
+	if err != nil {
+		t.Fatalf("FindCompletedAnalysis failed: %v", err)
+	}
+	if byCommit != analysisID {
+		t.Errorf("expected analysis %v, got %v", analysisID, byCommit)
+	}
+
+	doc, err := specRepo.GetSpecDocument(ctx, analysisID)
+	if err != nil {
+		t.Fatalf("GetSpecDocument failed: %v", err)
+	}
+	if doc.Owner != "spec-owner" || doc.Repo != "spec-repo" || doc.CommitSHA != "spec123" || doc.Branch != "main" || doc.Host != "github.com" {
+		t.Errorf("unexpected document header: %+v", doc)
+	}
+	if doc.CompletedAt.IsZero() {
+		t.Error("expected CompletedAt to be set")
+	}
+	if !reflect.DeepEqual(doc.Inventory, inventory) {
+		t.Errorf("expected inventory %+v, got %+v", inventory, doc.Inventory)
+	}
+}
+
+func TestSpecRepository_FindLatestCompletedAnalysis_IgnoresHistorical(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	repo := NewAnalysisRepository(pool)
+	specRepo := NewSpecRepository(pool)
+	ctx := context.Background()
+	headCommittedAt := time.Now().Add(-time.Hour)
+
+	complete := func(commitSHA string, isHistorical bool, committedAt time.Time) analysis.UUID {
+		t.Helper()
+		analysisID, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+			Owner:          "latest-owner",
+			Repo:           "latest-repo",
+			CommitSHA:      commitSHA,
+			Branch:         "main",
+			ExternalRepoID: "latest-id",
+			IsHistorical:   isHistorical,
+		})
+		if err != nil {
+			t.Fatalf("CreateAnalysisRecord failed: %v", err)
+		}
+		if err := repo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+			AnalysisID:  analysisID,
+			CommittedAt: committedAt,
+			Inventory:   &analysis.Inventory{},
+		}); err != nil {
+			t.Fatalf("SaveAnalysisInventory failed: %v", err)
+		}
+		return analysisID
+	}
+	head := complete("head123", false, headCommittedAt)
+	complete("tag123", true, headCommittedAt.Add(-24*time.Hour))
+
+	latest, err := specRepo.FindLatestCompletedAnalysis(ctx, "latest-owner", "latest-repo")
+	if err != nil {
+		t.Fatalf("FindLatestCompletedAnalysis failed: %v", err)
+	}
+	if latest != head {
+		t.Errorf("expected HEAD analysis %v, got %v", head, latest)
+	}
+}
+
+func TestBuildSpecInventory_Empty(t *testing.T) {
+	inventory := buildSpecInventory(nil, nil)
+	if inventory == nil || len(inventory.Files) != 0 {
+		t.Errorf("expected empty inventory, got %+v", inventory)
+	}
+}
+
+func TestSpecRepository_FindUpstreamLatestCompletedAnalysis(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	repo := NewAnalysisRepository(pool)
+	codebaseRepo := NewCodebaseRepository(pool)
+	specRepo := NewSpecRepository(pool)
+	ctx := context.Background()
+
+	upstreamID, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+		Owner:          "upstream-owner",
+		Repo:           "shared-repo",
+		CommitSHA:      "upstream123",
+		Branch:         "main",
+		ExternalRepoID: "upstream-id",
+	})
+	if err != nil {
+		t.Fatalf("CreateAnalysisRecord failed: %v", err)
+	}
+	if _, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+		Owner:          "fork-owner",
+		Repo:           "shared-repo",
+		CommitSHA:      "fork123",
+		Branch:         "main",
+		ExternalRepoID: "fork-id",
+	}); err != nil {
+		t.Fatalf("CreateAnalysisRecord failed: %v", err)
+	}
+
+	fork, err := codebaseRepo.FindByOwnerName(ctx, "github.com", "fork-owner", "shared-repo")
+	if err != nil {
+		t.Fatalf("FindByOwnerName failed: %v", err)
+	}
+	if err := codebaseRepo.UpdateMetadata(ctx, fork.ID, analysis.RepoInfo{
+		IsFork:               true,
+		ParentExternalRepoID: "upstream-id",
+		ParentFullName:       "upstream-owner/shared-repo",
+	}); err != nil {
+		t.Fatalf("UpdateMetadata failed: %v", err)
+	}
+
+	if _, err := specRepo.FindUpstreamLatestCompletedAnalysis(ctx, "fork-owner", "shared-repo"); !errors.Is(err, analysis.ErrAnalysisNotFound) {
+		t.Fatalf("expected ErrAnalysisNotFound while upstream analysis is running, got %v", err)
+	}
+
+	if err := repo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+		AnalysisID: upstreamID,
+		Inventory:  &analysis.Inventory{},
+	}); err != nil {
+		t.Fatalf("SaveAnalysisInventory failed: %v", err)
+	}
+
+	got, err := specRepo.FindUpstreamLatestCompletedAnalysis(ctx, "fork-owner", "shared-repo")
+	if err != nil {
+		t.Fatalf("FindUpstreamLatestCompletedAnalysis failed: %v", err)
+	}
+	if got != upstreamID {
+		t.Errorf("expected upstream analysis %v, got %v", upstreamID, got)
+	}
+
+	backfilledID, err := repo.CreateAnalysisRecord(ctx, analysis.CreateAnalysisRecordParams{
+		Owner:          "upstream-owner",
+		Repo:           "shared-repo",
+		CommitSHA:      "backfill123",
+		Branch:         "main",
+		ExternalRepoID: "upstream-id",
+		IsHistorical:   true,
+	})
+	if err != nil {
+		t.Fatalf("CreateAnalysisRecord failed: %v", err)
+	}
+	if err := repo.SaveAnalysisInventory(ctx, analysis.SaveAnalysisInventoryParams{
+		AnalysisID:  backfilledID,
+		CommittedAt: time.Now().Add(-24 * time.Hour),
+		Inventory:   &analysis.Inventory{},
+	}); err != nil {
+		t.Fatalf("SaveAnalysisInventory failed: %v", err)
+	}
+
+	got, err = specRepo.FindUpstreamLatestCompletedAnalysis(ctx, "fork-owner", "shared-repo")
+	if err != nil {
+		t.Fatalf("FindUpstreamLatestCompletedAnalysis failed: %v", err)
+	}
+	if got != upstreamID {
+		t.Errorf("expected backfilled upstream analysis to be ignored, got %v", got)
+	}
+
+	if _, err := specRepo.FindUpstreamLatestCompletedAnalysis(ctx, "upstream-owner", "shared-repo"); !errors.Is(err, analysis.ErrAnalysisNotFound) {
+		t.Errorf("expected ErrAnalysisNotFound for a repository that is not a fork, got %v", err)
+	}
+}
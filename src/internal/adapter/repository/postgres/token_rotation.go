@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/specvital/core/pkg/crypto"
+
+	"github.com/specvital/collector/internal/infra/db"
+)
+
+// RotatingEncryptor is an encryptor that holds several keys, such as keyring.Keyring.
+type RotatingEncryptor interface {
+	crypto.Encryptor
+	// IsCurrent reports whether ciphertext was written with the key new ciphertexts use.
+	IsCurrent(ciphertext string) bool
+}
+
+type TokenRotationResult struct {
+	// Failed counts secrets that could not be decrypted, e.g. because their key was already removed.
+	Failed  int
+	Rotated int
+	Scanned int
+	// Skipped counts secrets already on the current key or replaced concurrently, e.g. by a re-login.
+	Skipped int
+}
+
+type TokenRotator struct {
+	encryptor RotatingEncryptor
+	pool      *pgxpool.Pool
+}
+
+func NewTokenRotator(pool *pgxpool.Pool, encryptor RotatingEncryptor) *TokenRotator {
+	return &TokenRotator{
+		encryptor: encryptor,
+		pool:      pool,
+	}
+}
+
+// RotateOAuthTokens re-encrypts every stored OAuth access token with the current key, batchSize
+// rows at a time. Each row is replaced only if it still holds the ciphertext that was read, so
+// tokens stored concurrently are never overwritten. updated_at is left alone, as bumping it
+// would lift invalid-token marks.
+func (r *TokenRotator) RotateOAuthTokens(ctx context.Context, batchSize int) (TokenRotationResult, error) {
+	if batchSize <= 0 {
+		return TokenRotationResult{}, fmt.Errorf("batch size must be positive, got %d", batchSize)
+	}
+
+	var result TokenRotationResult
+	queries := db.New(r.pool)
+	afterID := pgtype.UUID{Valid: true}
+
+	for {
+		rows, err := queries.ListOAuthAccessTokens(ctx, db.ListOAuthAccessTokensParams{
+			AfterID:   afterID,
+			BatchSize: int32(batchSize),
+		})
+		if err != nil {
+			return result, fmt.Errorf("list oauth access tokens: %w", err)
+		}
+
+		for _, row := range rows {
+			replace := func(ciphertext string) (int64, error) {
+				return queries.ReplaceOAuthAccessToken(ctx, db.ReplaceOAuthAccessTokenParams{
+					NewAccessToken: pgtype.Text{String: ciphertext, Valid: true},
+					ID:             row.ID,
+					OldAccessToken: row.AccessToken,
+				})
+			}
+			err := r.rotate(ctx, row.AccessToken.String, replace, &result,
+				"oauth_account_id", fromPgUUID(row.ID).String(),
+			)
+			if err != nil {
+				return result, fmt.Errorf("rotate oauth access token: %w", err)
+			}
+		}
+
+		if len(rows) < batchSize {
+			return result, nil
+		}
+		afterID = rows[len(rows)-1].ID
+	}
+}
+
+// RotateDeployCredentials re-encrypts every codebase's deploy credential like RotateOAuthTokens.
+func (r *TokenRotator) RotateDeployCredentials(ctx context.Context, batchSize int) (TokenRotationResult, error) {
+	if batchSize <= 0 {
+		return TokenRotationResult{}, fmt.Errorf("batch size must be positive, got %d", batchSize)
+	}
+
+	var result TokenRotationResult
+	queries := db.New(r.pool)
+	afterID := pgtype.UUID{Valid: true}
+
+	for {
+		rows, err := queries.ListCodebaseCredentialSecrets(ctx, db.ListCodebaseCredentialSecretsParams{
+			AfterID:   afterID,
+			BatchSize: int32(batchSize),
+		})
+		if err != nil {
+			return result, fmt.Errorf("list deploy credentials: %w", err)
+		}
+
+		for _, row := range rows {
+			replace := func(ciphertext string) (int64, error) {
+				return queries.ReplaceCodebaseCredentialSecret(ctx, db.ReplaceCodebaseCredentialSecretParams{
+					NewSecret:  ciphertext,
+					CodebaseID: row.CodebaseID,
+					OldSecret:  row.Secret,
+				})
+			}
+			err := r.rotate(ctx, row.Secret, replace, &result,
+				"codebase_id", fromPgUUID(row.CodebaseID).String(),
+			)
+			if err != nil {
+				return result, fmt.Errorf("rotate deploy credential: %w", err)
+			}
+		}
+
+		if len(rows) < batchSize {
+			return result, nil
+		}
+		afterID = rows[len(rows)-1].CodebaseID
+	}
+}
+
+// rotate re-encrypts a single secret and stores it through replace, which reports the rows it
+// updated. logAttrs identify the row in the warning logged for a secret that cannot be decrypted.
+func (r *TokenRotator) rotate(
+	ctx context.Context,
+	ciphertext string,
+	replace func(ciphertext string) (int64, error),
+	result *TokenRotationResult,
+	logAttrs ...any,
+) error {
+	result.Scanned++
+	if r.encryptor.IsCurrent(ciphertext) {
+		result.Skipped++
+		return nil
+	}
+
+	plaintext, err := r.encryptor.Decrypt(ciphertext)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to decrypt secret, skipping", append(logAttrs, "error", err)...)
+		result.Failed++
+		return nil
+	}
+
+	rotated, err := r.encryptor.Encrypt(plaintext)
+	if err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+
+	replaced, err := replace(rotated)
+	if err != nil {
+		return err
+	}
+	if replaced == 0 {
+		result.Skipped++
+		return nil
+	}
+	result.Rotated++
+	return nil
+}
@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/keyring"
+	testdb "github.com/specvital/collector/internal/testutil/postgres"
+)
+
+const (
+	rotationOldKey = "YWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWE="
+	rotationNewKey = "YmJiYmJiYmJiYmJiYmJiYmJiYmJiYmJiYmJiYmJiYmI="
+)
+
+func TestTokenRotator_RotateOAuthTokens(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	oldRing, err := keyring.New(rotationOldKey, "")
+	if err != nil {
+		t.Fatalf("keyring.New failed: %v", err)
+	}
+	ring, err := keyring.New(rotationOldKey, "k2:"+rotationNewKey)
+	if err != nil {
+		t.Fatalf("keyring.New failed: %v", err)
+	}
+
+	insertAccount := func(username, ciphertext string) string {
+		t.Helper()
+		var userID string
+		if err := pool.QueryRow(ctx, `INSERT INTO users (username) VALUES ($1) RETURNING id::text`, username).Scan(&userID); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		_, err := pool.Exec(ctx, `
+			INSERT INTO oauth_accounts (user_id, provider, provider_user_id, access_token, token_invalidated_at)
+			VALUES ($1::uuid, 'github', $2, $3, CASE WHEN $2 = 'revoked' THEN now() + interval '1 minute' END)
+		`, userID, username, ciphertext)
+		if err != nil {
+			t.Fatalf("failed to create oauth account: %v", err)
+		}
+		return userID
+	}
+	encrypt := func(enc interface{ Encrypt(string) (string, error) }, token string) string {
+		t.Helper()
+		ciphertext, err := enc.Encrypt(token)
+		if err != nil {
+			t.Fatalf("Encrypt failed: %v", err)
+		}
+		return ciphertext
+	}
+
+	legacyUsers := map[string]string{}
+	for _, name := range []string{"legacy1", "legacy2", "legacy3", "revoked"} {
+		legacyUsers[insertAccount(name, encrypt(oldRing, "gho_"+name))] = "gho_" + name
+	}
+	currentUser := insertAccount("current", encrypt(ring, "gho_current"))
+	insertAccount("unknown", "k9:AAAA")
+
+	result, err := NewTokenRotator(pool, ring).RotateOAuthTokens(ctx, 2)
+	if err != nil {
+		t.Fatalf("RotateOAuthTokens failed: %v", err)
+	}
+	want := TokenRotationResult{Failed: 1, Rotated: 4, Scanned: 6, Skipped: 1}
+	if result != want {
+		t.Errorf("expected %+v, got %+v", want, result)
+	}
+
+	repo := NewUserRepository(pool, ring)
+	for userID, token := range legacyUsers {
+		var stored string
+		if err := pool.QueryRow(ctx, `SELECT access_token FROM oauth_accounts WHERE user_id = $1::uuid`, userID).Scan(&stored); err != nil {
+			t.Fatalf("failed to read token: %v", err)
+		}
+		if !strings.HasPrefix(stored, "k2:") {
+			t.Errorf("expected %s to be rotated to k2, got %q", token, stored)
+		}
+		if token == "gho_revoked" {
+			continue
+		}
+		got, err := repo.GetOAuthToken(ctx, userID, "github")
+		if err != nil || got != token {
+			t.Errorf("GetOAuthToken = %q, %v; want %q", got, err, token)
+		}
+	}
+	if got, err := repo.GetOAuthToken(ctx, currentUser, "github"); err != nil || got != "gho_current" {
+		t.Errorf("GetOAuthToken = %q, %v; want gho_current", got, err)
+	}
+
+	var stillInvalid bool
+	err = pool.QueryRow(ctx, `
+		SELECT token_invalidated_at >= updated_at FROM oauth_accounts WHERE provider_user_id = 'revoked'
+	`).Scan(&stillInvalid)
+	if err != nil {
+		t.Fatalf("failed to read invalid mark: %v", err)
+	}
+	if !stillInvalid {
+		t.Error("expected rotation to keep the invalid-token mark")
+	}
+
+	again, err := NewTokenRotator(pool, ring).RotateOAuthTokens(ctx, 2)
+	if err != nil {
+		t.Fatalf("RotateOAuthTokens failed: %v", err)
+	}
+	if again.Rotated != 0 || again.Skipped != 5 {
+		t.Errorf("expected a second run to skip rotated tokens, got %+v", again)
+	}
+}
+
+func TestTokenRotator_RotateDeployCredentials(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	oldRing, err := keyring.New("", "k1:"+rotationOldKey)
+	if err != nil {
+		t.Fatalf("keyring.New failed: %v", err)
+	}
+	ring, err := keyring.New("", "k2:"+rotationNewKey+",k1:"+rotationOldKey)
+	if err != nil {
+		t.Fatalf("keyring.New failed: %v", err)
+	}
+
+	codebases := NewCodebaseRepository(pool)
+	oldCredentials := NewDeployCredentialRepository(pool, oldRing)
+	for i, name := range []string{"api", "billing", "worker"} {
+		codebase, err := codebases.Upsert(ctx, analysis.UpsertCodebaseParams{
+			ExternalRepoID: fmt.Sprintf("%d", i+1),
+			Host:           "github.com",
+			IsPrivate:      true,
+			Name:           name,
+			Owner:          "acme",
+		})
+		if err != nil {
+			t.Fatalf("failed to create codebase: %v", err)
+		}
+		credential := analysis.DeployCredential{Kind: analysis.DeployCredentialToken, Secret: "github_pat_" + name}
+		if err := oldCredentials.SaveDeployCredential(ctx, codebase.ID, credential); err != nil {
+			t.Fatalf("SaveDeployCredential failed: %v", err)
+		}
+	}
+
+	result, err := NewTokenRotator(pool, ring).RotateDeployCredentials(ctx, 2)
+	if err != nil {
+		t.Fatalf("RotateDeployCredentials failed: %v", err)
+	}
+	want := TokenRotationResult{Rotated: 3, Scanned: 3}
+	if result != want {
+		t.Errorf("expected %+v, got %+v", want, result)
+	}
+
+	newOnly, err := keyring.New("", "k2:"+rotationNewKey)
+	if err != nil {
+		t.Fatalf("keyring.New failed: %v", err)
+	}
+	got, err := NewDeployCredentialRepository(pool, newOnly).GetDeployCredential(ctx, "github.com", "acme", "billing")
+	if err != nil {
+		t.Fatalf("GetDeployCredential with the new key only failed: %v", err)
+	}
+	if got.Secret != "github_pat_billing" {
+		t.Errorf("expected github_pat_billing, got %q", got.Secret)
+	}
+}
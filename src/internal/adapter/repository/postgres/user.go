@@ -14,7 +14,10 @@ import (
 	"github.com/specvital/collector/internal/infra/db"
 )
 
-var _ analysis.TokenLookup = (*UserRepository)(nil)
+var (
+	_ analysis.TokenInvalidator = (*UserRepository)(nil)
+	_ analysis.TokenLookup      = (*UserRepository)(nil)
+)
 
 type UserRepository struct {
 	encryptor crypto.Encryptor
@@ -28,17 +31,62 @@ func NewUserRepository(pool *pgxpool.Pool, encryptor crypto.Encryptor) *UserRepo
 	}
 }
 
+// GetOAuthToken returns analysis.ErrTokenInvalid while the token is marked invalid.
+// Storing a new token bumps updated_at past the mark, which lifts it.
 func (r *UserRepository) GetOAuthToken(ctx context.Context, userID string, provider string) (string, error) {
+	account, err := r.getOAuthAccount(ctx, userID, provider)
+	if err != nil {
+		return "", err
+	}
+
+	if account.TokenInvalidatedAt.Valid && !account.TokenInvalidatedAt.Time.Before(account.UpdatedAt.Time) {
+		return "", analysis.ErrTokenInvalid
+	}
+
+	decrypted, err := r.encryptor.Decrypt(account.AccessToken.String)
+	if err != nil {
+		return "", fmt.Errorf("decrypt access token: %w", err)
+	}
+
+	return decrypted, nil
+}
+
+func (r *UserRepository) MarkOAuthTokenInvalid(ctx context.Context, userID, provider, token string) error {
+	account, err := r.getOAuthAccount(ctx, userID, provider)
+	if err != nil {
+		return err
+	}
+
+	stored, err := r.encryptor.Decrypt(account.AccessToken.String)
+	if err != nil {
+		return fmt.Errorf("decrypt access token: %w", err)
+	}
+	if stored != token {
+		return nil
+	}
+
+	err = db.New(r.pool).MarkOAuthAccountTokenInvalid(ctx, db.MarkOAuthAccountTokenInvalidParams{
+		ID:          account.ID,
+		AccessToken: account.AccessToken,
+	})
+	if err != nil {
+		return fmt.Errorf("mark oauth token invalid: %w", err)
+	}
+	return nil
+}
+
+// getOAuthAccount returns analysis.ErrTokenNotFound if the account has no access token.
+func (r *UserRepository) getOAuthAccount(ctx context.Context, userID string, provider string) (db.OauthAccount, error) {
 	if userID == "" {
-		return "", fmt.Errorf("user ID is required")
+		return db.OauthAccount{}, fmt.Errorf("user ID is required")
 	}
 	if provider == "" {
-		return "", fmt.Errorf("provider is required")
+		return db.OauthAccount{}, fmt.Errorf("provider is required")
 	}
 
 	var pgUserID pgtype.UUID
 	if err := pgUserID.Scan(userID); err != nil {
-		return "", fmt.Errorf("invalid user ID format: %w", err)
+		return db.OauthAccount{}, fmt.Errorf("invalid user ID format: %w", err)
 	}
 
 	queries := db.New(r.pool)
@@ -48,19 +96,13 @@ func (r *UserRepository) GetOAuthToken(ctx context.Context, userID string, provi
 	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return "", analysis.ErrTokenNotFound
+			return db.OauthAccount{}, analysis.ErrTokenNotFound
 		}
-		return "", fmt.Errorf("query oauth account: %w", err)
+		return db.OauthAccount{}, fmt.Errorf("query oauth account: %w", err)
 	}
 
 	if !account.AccessToken.Valid || account.AccessToken.String == "" {
-		return "", analysis.ErrTokenNotFound
-	}
-
-	decrypted, err := r.encryptor.Decrypt(account.AccessToken.String)
-	if err != nil {
-		return "", fmt.Errorf("decrypt access token: %w", err)
+		return db.OauthAccount{}, analysis.ErrTokenNotFound
 	}
-
-	return decrypted, nil
+	return account, nil
 }
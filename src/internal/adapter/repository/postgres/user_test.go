@@ -161,6 +161,60 @@ func TestUserRepository_GetOAuthToken(t *testing.T) {
 	})
 }
 
+func TestUserRepository_MarkOAuthTokenInvalid(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	pool, cleanup := testdb.SetupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserRepository(pool, &passthroughEncryptor{})
+	ctx := context.Background()
+
+	var userID string
+	err := pool.QueryRow(ctx, `
+		INSERT INTO users (username) VALUES ('revoked_user')
+		RETURNING id::text
+	`).Scan(&userID)
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	_, err = pool.Exec(ctx, `
+		INSERT INTO oauth_accounts (user_id, provider, provider_user_id, access_token)
+		VALUES ($1::uuid, 'github', 'github_revoked', 'ghp_revoked')
+	`, userID)
+	if err != nil {
+		t.Fatalf("failed to create oauth account: %v", err)
+	}
+
+	if err := repo.MarkOAuthTokenInvalid(ctx, userID, "github", "ghp_replaced"); err != nil {
+		t.Fatalf("MarkOAuthTokenInvalid failed: %v", err)
+	}
+	if token, err := repo.GetOAuthToken(ctx, userID, "github"); err != nil || token != "ghp_revoked" {
+		t.Fatalf("expected a different token to leave the stored one valid, got %q, %v", token, err)
+	}
+
+	if err := repo.MarkOAuthTokenInvalid(ctx, userID, "github", "ghp_revoked"); err != nil {
+		t.Fatalf("MarkOAuthTokenInvalid failed: %v", err)
+	}
+	_, err = repo.GetOAuthToken(ctx, userID, "github")
+	if !errors.Is(err, analysis.ErrTokenInvalid) || !errors.Is(err, analysis.ErrTokenNotFound) {
+		t.Errorf("expected ErrTokenInvalid matching ErrTokenNotFound, got %v", err)
+	}
+
+	_, err = pool.Exec(ctx, `
+		UPDATE oauth_accounts SET access_token = 'ghp_reauthorized', updated_at = clock_timestamp()
+		WHERE user_id = $1::uuid
+	`, userID)
+	if err != nil {
+		t.Fatalf("failed to reauthorize: %v", err)
+	}
+	if token, err := repo.GetOAuthToken(ctx, userID, "github"); err != nil || token != "ghp_reauthorized" {
+		t.Errorf("expected reauthorization to lift the mark, got %q, %v", token, err)
+	}
+}
+
 func TestNewUserRepository(t *testing.T) {
 	repo := NewUserRepository(nil, &passthroughEncryptor{})
 	if repo == nil {
@@ -0,0 +1,130 @@
+package specdoc
+
+import (
+	"html/template"
+	"io"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+const htmlStyle = `body{font-family:system-ui,sans-serif;max-width:960px;margin:2rem auto;padding:0 1rem;line-height:1.5}
+a{color:#0969da;text-decoration:none}a:hover{text-decoration:underline}
+table{border-collapse:collapse;width:100%}th,td{border-bottom:1px solid #d0d7de;padding:.25rem .5rem;text-align:left}
+td.num,th.num{text-align:right}ul{list-style:none;padding-left:1.25rem}
+.suite>a{font-weight:600}.skipped,.todo{color:#6e7781}.xfail{color:#cf222e}.focused{color:#9a6700}`
+
+var htmlTemplates = template.Must(template.New("spec").Parse(`
+{{- define "index"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Doc.Owner}}/{{.Doc.Repo}} spec</title>
+<style>{{.Style}}</style>
+</head>
+<body>
+<h1>{{.Doc.Owner}}/{{.Doc.Repo}}</h1>
+<p>Specification of commit <a href="{{.Doc.CommitURL}}"><code>{{.ShortSHA}}</code></a>
+{{- with .Doc.Branch}} on <code>{{.}}</code>{{end}}
+{{- with .AnalyzedAt}}, analyzed {{.}}{{end}}.</p>
+<p>{{range $i, $l := .Legend}}{{if $i}} · {{end}}{{$l}}{{end}}</p>
+<table>
+<thead><tr><th>File</th><th>Framework</th><th class="num">Tests</th><th class="num">Skipped</th></tr></thead>
+<tbody>
+{{- range .Pages}}
+<tr><td><a href="{{.Href}}">{{.Path}}</a></td><td>{{.Framework}}</td><td class="num">{{.Tests}}</td><td class="num">{{.Skipped}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+</body>
+</html>
+{{end}}
+
+{{- define "file"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Page.Path}}</title>
+<style>{{.Style}}</style>
+</head>
+<body>
+<h1>{{.Page.Path}}</h1>
+<p><a href="{{.Page.Root}}index.html">← {{.Doc.Owner}}/{{.Doc.Repo}}</a> · {{.Page.Framework}} · {{.Page.Tests}} tests · <a href="{{.Page.Source}}">source</a></p>
+{{template "nodes" .Page.Nodes}}
+</body>
+</html>
+{{end}}
+
+{{- define "comparison"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Page.Fork.Owner}}/{{.Page.Fork.Repo}} compared with {{.Page.Upstream.Owner}}/{{.Page.Upstream.Repo}}</title>
+<style>{{.Style}}</style>
+</head>
+<body>
+<h1>{{.Page.Fork.Owner}}/{{.Page.Fork.Repo}} compared with {{.Page.Upstream.Owner}}/{{.Page.Upstream.Repo}}</h1>
+<p><a href="index.html">← {{.Page.Fork.Owner}}/{{.Page.Fork.Repo}}</a> · upstream commit <a href="{{.Page.Upstream.CommitURL}}"><code>{{.UpstreamSHA}}</code></a> · {{.Page.Shared}} shared · {{len .Page.Added}} added · {{len .Page.Removed}} removed</p>
+<h2>Added in fork</h2>
+{{template "compared" .Page.Added}}
+<h2>Removed from upstream</h2>
+{{template "compared" .Page.Removed}}
+</body>
+</html>
+{{end}}
+
+{{- define "compared"}}
+{{- if .}}<table>
+<thead><tr><th>File</th><th>Test</th></tr></thead>
+<tbody>
+{{- range .}}
+<tr><td>{{.FilePath}}</td><td><a href="{{.URL}}">{{.Name}}</a></td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- else}}<p>None.</p>
+{{- end}}
+{{- end}}
+
+{{- define "nodes"}}<ul>
+{{- range .}}
+{{- if .Suite}}
+<li class="suite"><a href="{{.URL}}">{{.Name}}</a>{{template "nodes" .Children}}</li>
+{{- else}}
+<li class="{{.Status}}">{{.Marker}} <a href="{{.URL}}">{{.Name}}</a></li>
+{{- end}}
+{{- end}}
+</ul>
+{{- end}}`))
+
+type htmlRenderer struct{}
+
+func (htmlRenderer) ext() string {
+	return ".html"
+}
+
+func (htmlRenderer) renderIndex(w io.Writer, doc *analysis.SpecDocument, pages []filePage) error {
+	return htmlTemplates.ExecuteTemplate(w, "index", map[string]any{
+		"AnalyzedAt": analyzedAt(doc),
+		"Doc":        doc,
+		"Legend":     legend(),
+		"Pages":      pages,
+		"ShortSHA":   shortSHA(doc.CommitSHA),
+		"Style":      template.CSS(htmlStyle),
+	})
+}
+
+func (htmlRenderer) renderComparison(w io.Writer, page comparisonPage) error {
+	return htmlTemplates.ExecuteTemplate(w, "comparison", map[string]any{
+		"Page":        page,
+		"Style":       template.CSS(htmlStyle),
+		"UpstreamSHA": shortSHA(page.Upstream.CommitSHA),
+	})
+}
+
+func (htmlRenderer) renderFile(w io.Writer, doc *analysis.SpecDocument, page filePage) error {
+	return htmlTemplates.ExecuteTemplate(w, "file", map[string]any{
+		"Doc":   doc,
+		"Page":  page,
+		"Style": template.CSS(htmlStyle),
+	})
+}
@@ -0,0 +1,124 @@
+package specdoc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+	"|", `\|`,
+	"#", `\#`,
+)
+
+type markdownRenderer struct{}
+
+func (markdownRenderer) ext() string {
+	return ".md"
+}
+
+func (markdownRenderer) renderIndex(w io.Writer, doc *analysis.SpecDocument, pages []filePage) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# %s/%s\n\n", markdownEscaper.Replace(doc.Owner), markdownEscaper.Replace(doc.Repo))
+	fmt.Fprintf(bw, "Specification of commit [`%s`](%s)", shortSHA(doc.CommitSHA), doc.CommitURL())
+	if doc.Branch != "" {
+		fmt.Fprintf(bw, " on `%s`", doc.Branch)
+	}
+	if at := analyzedAt(doc); at != "" {
+		fmt.Fprintf(bw, ", analyzed %s", at)
+	}
+	bw.WriteString(".\n\n")
+
+	fmt.Fprintf(bw, "%s\n\n", strings.Join(legend(), " · "))
+
+	bw.WriteString("| File | Framework | Tests | Skipped |\n")
+	bw.WriteString("| --- | --- | ---: | ---: |\n")
+	for _, page := range pages {
+		fmt.Fprintf(bw, "| [%s](%s) | %s | %d | %d |\n",
+			markdownEscaper.Replace(page.Path),
+			pageLink(page.Href),
+			markdownEscaper.Replace(page.Framework),
+			page.Tests,
+			page.Skipped,
+		)
+	}
+	return bw.Flush()
+}
+
+func (markdownRenderer) renderComparison(w io.Writer, page comparisonPage) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# %s/%s compared with %s/%s\n\n",
+		markdownEscaper.Replace(page.Fork.Owner),
+		markdownEscaper.Replace(page.Fork.Repo),
+		markdownEscaper.Replace(page.Upstream.Owner),
+		markdownEscaper.Replace(page.Upstream.Repo),
+	)
+	fmt.Fprintf(bw, "[← %s/%s](index.md) · upstream commit [`%s`](%s) · %d shared · %d added · %d removed\n\n",
+		markdownEscaper.Replace(page.Fork.Owner),
+		markdownEscaper.Replace(page.Fork.Repo),
+		shortSHA(page.Upstream.CommitSHA),
+		page.Upstream.CommitURL(),
+		page.Shared,
+		len(page.Added),
+		len(page.Removed),
+	)
+	writeMarkdownCompared(bw, "Added in fork", page.Added)
+	bw.WriteString("\n")
+	writeMarkdownCompared(bw, "Removed from upstream", page.Removed)
+	return bw.Flush()
+}
+
+func writeMarkdownCompared(w *bufio.Writer, title string, nodes []comparedNode) {
+	fmt.Fprintf(w, "## %s\n\n", title)
+	if len(nodes) == 0 {
+		w.WriteString("None.\n")
+		return
+	}
+	w.WriteString("| File | Test |\n")
+	w.WriteString("| --- | --- |\n")
+	for _, node := range nodes {
+		fmt.Fprintf(w, "| %s | [%s](%s) |\n",
+			markdownEscaper.Replace(node.FilePath),
+			markdownEscaper.Replace(node.Name),
+			node.URL,
+		)
+	}
+}
+
+func (markdownRenderer) renderFile(w io.Writer, doc *analysis.SpecDocument, page filePage) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# %s\n\n", markdownEscaper.Replace(page.Path))
+	fmt.Fprintf(bw, "[← %s/%s](%s) · %s · %d tests · [source](%s)\n\n",
+		markdownEscaper.Replace(doc.Owner),
+		markdownEscaper.Replace(doc.Repo),
+		pageLink(page.Root+"index.md"),
+		markdownEscaper.Replace(page.Framework),
+		page.Tests,
+		page.Source,
+	)
+	writeMarkdownNodes(bw, page.Nodes, 0)
+	return bw.Flush()
+}
+
+func writeMarkdownNodes(w *bufio.Writer, nodes []specNode, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, node := range nodes {
+		if node.Suite {
+			fmt.Fprintf(w, "%s- **[%s](%s)**\n", indent, markdownEscaper.Replace(node.Name), node.URL)
+			writeMarkdownNodes(w, node.Children, depth+1)
+			continue
+		}
+		fmt.Fprintf(w, "%s- %s [%s](%s)\n", indent, node.Marker, markdownEscaper.Replace(node.Name), node.URL)
+	}
+}
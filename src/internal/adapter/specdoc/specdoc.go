@@ -0,0 +1,284 @@
+// Package specdoc renders stored analyses as browsable specification documents.
+package specdoc
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+type Format string
+
+const (
+	FormatHTML     Format = "html"
+	FormatMarkdown Format = "markdown"
+)
+
+// ParseFormat accepts "markdown" (or "md") and "html".
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "html":
+		return FormatHTML, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported spec format %q", analysis.ErrInvalidInput, s)
+	}
+}
+
+var statusMarkers = map[analysis.TestStatus]string{
+	analysis.TestStatusActive:  "✓",
+	analysis.TestStatusFocused: "◉",
+	analysis.TestStatusSkipped: "⊘",
+	analysis.TestStatusTodo:    "○",
+	analysis.TestStatusXfail:   "✗",
+}
+
+var legendOrder = []analysis.TestStatus{
+	analysis.TestStatusActive,
+	analysis.TestStatusFocused,
+	analysis.TestStatusSkipped,
+	analysis.TestStatusTodo,
+	analysis.TestStatusXfail,
+}
+
+type renderer interface {
+	ext() string
+	renderComparison(w io.Writer, page comparisonPage) error
+	renderFile(w io.Writer, doc *analysis.SpecDocument, page filePage) error
+	renderIndex(w io.Writer, doc *analysis.SpecDocument, pages []filePage) error
+}
+
+// filePage is the page of one test file. Nodes interleave suites and tests in source order.
+type filePage struct {
+	Framework string
+	Nodes     []specNode
+	Path      string
+	// Href is the page path relative to the index; Root is the index directory relative to the page.
+	Href    string
+	Root    string
+	Skipped int
+	Source  string
+	Tests   int
+}
+
+// comparisonPage lists the tests a fork added to and removed from its upstream repository.
+type comparisonPage struct {
+	Added    []comparedNode
+	Fork     *analysis.SpecDocument
+	Removed  []comparedNode
+	Shared   int
+	Upstream *analysis.SpecDocument
+}
+
+type comparedNode struct {
+	FilePath string
+	// Name joins the enclosing suites and the test name, e.g. "Parser › parses input".
+	Name string
+	URL  string
+}
+
+type specNode struct {
+	Children []specNode
+	Marker   string
+	Name     string
+	Status   string
+	Suite    bool
+	URL      string
+	line     int
+}
+
+// Export writes an index page and one page per test file into dir, mirroring the repository layout,
+// and returns the number of file pages written. Files whose path would escape dir are skipped.
+func Export(dir string, doc *analysis.SpecDocument, format Format) (int, error) {
+	if doc == nil || doc.Inventory == nil {
+		return 0, fmt.Errorf("%w: spec document is required", analysis.ErrInvalidInput)
+	}
+
+	r, err := newRenderer(format)
+	if err != nil {
+		return 0, err
+	}
+
+	pages := buildPages(doc, r.ext())
+	for _, page := range pages {
+		var buf bytes.Buffer
+		if err := r.renderFile(&buf, doc, page); err != nil {
+			return 0, fmt.Errorf("render %s: %w", page.Path, err)
+		}
+		if err := writePage(dir, page.Href, buf.Bytes()); err != nil {
+			return 0, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := r.renderIndex(&buf, doc, pages); err != nil {
+		return 0, fmt.Errorf("render index: %w", err)
+	}
+	if err := writePage(dir, "index"+r.ext(), buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(pages), nil
+}
+
+// ExportComparison writes the comparison of a fork with its upstream repository into dir as
+// "upstream" plus the format's extension, next to the index written by Export.
+func ExportComparison(dir string, comparison *analysis.ForkComparison, format Format) error {
+	if comparison == nil || comparison.Fork == nil || comparison.Upstream == nil {
+		return fmt.Errorf("%w: fork comparison is required", analysis.ErrInvalidInput)
+	}
+
+	r, err := newRenderer(format)
+	if err != nil {
+		return err
+	}
+
+	page := comparisonPage{
+		Added:    comparedNodes(comparison.Fork, comparison.Added),
+		Fork:     comparison.Fork,
+		Removed:  comparedNodes(comparison.Upstream, comparison.Removed),
+		Shared:   comparison.Shared,
+		Upstream: comparison.Upstream,
+	}
+
+	var buf bytes.Buffer
+	if err := r.renderComparison(&buf, page); err != nil {
+		return fmt.Errorf("render comparison: %w", err)
+	}
+	return writePage(dir, "upstream"+r.ext(), buf.Bytes())
+}
+
+func newRenderer(format Format) (renderer, error) {
+	switch format {
+	case FormatMarkdown:
+		return markdownRenderer{}, nil
+	case FormatHTML:
+		return htmlRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported spec format %q", analysis.ErrInvalidInput, format)
+	}
+}
+
+func comparedNodes(doc *analysis.SpecDocument, tests []analysis.ComparedTest) []comparedNode {
+	nodes := make([]comparedNode, 0, len(tests))
+	for _, test := range tests {
+		nodes = append(nodes, comparedNode{
+			FilePath: test.FilePath,
+			Name:     strings.Join(append(slices.Clone(test.Suites), test.Name), " › "),
+			URL:      doc.SourceURL(test.FilePath, test.Location),
+		})
+	}
+	return nodes
+}
+
+func buildPages(doc *analysis.SpecDocument, ext string) []filePage {
+	pages := make([]filePage, 0, len(doc.Inventory.Files))
+	for _, file := range doc.Inventory.Files {
+		if !filepath.IsLocal(filepath.FromSlash(file.Path)) {
+			continue
+		}
+
+		page := filePage{
+			Framework: file.Framework,
+			Href:      file.Path + ext,
+			Path:      file.Path,
+			Root:      strings.Repeat("../", strings.Count(path.Clean(file.Path), "/")),
+			Source:    doc.SourceURL(file.Path, analysis.Location{}),
+		}
+		for _, test := range file.Tests {
+			page.Nodes = append(page.Nodes, testNode(doc, file.Path, test, &page))
+		}
+		for _, suite := range file.Suites {
+			page.Nodes = append(page.Nodes, suiteNode(doc, file.Path, suite, &page))
+		}
+		sortNodes(page.Nodes)
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+func suiteNode(doc *analysis.SpecDocument, filePath string, suite analysis.TestSuite, page *filePage) specNode {
+	node := specNode{
+		Name:  suite.Name,
+		Suite: true,
+		URL:   doc.SourceURL(filePath, suite.Location),
+		line:  suite.Location.StartLine,
+	}
+	for _, test := range suite.Tests {
+		node.Children = append(node.Children, testNode(doc, filePath, test, page))
+	}
+	for _, child := range suite.Suites {
+		node.Children = append(node.Children, suiteNode(doc, filePath, child, page))
+	}
+	sortNodes(node.Children)
+	return node
+}
+
+func testNode(doc *analysis.SpecDocument, filePath string, test analysis.Test, page *filePage) specNode {
+	page.Tests++
+	if test.Status == analysis.TestStatusSkipped {
+		page.Skipped++
+	}
+	return specNode{
+		Marker: cmp.Or(statusMarkers[test.Status], statusMarkers[analysis.TestStatusActive]),
+		Name:   test.Name,
+		Status: string(cmp.Or(test.Status, analysis.TestStatusActive)),
+		URL:    doc.SourceURL(filePath, test.Location),
+		line:   test.Location.StartLine,
+	}
+}
+
+func sortNodes(nodes []specNode) {
+	slices.SortStableFunc(nodes, func(a, b specNode) int {
+		return cmp.Compare(a.line, b.line)
+	})
+}
+
+func writePage(dir, href string, data []byte) error {
+	target := filepath.Join(dir, filepath.FromSlash(href))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("create directory for %s: %w", href, err)
+	}
+	if err := os.WriteFile(target, data, 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", href, err)
+	}
+	return nil
+}
+
+// pageLink escapes a relative page path for use in a link.
+func pageLink(p string) string {
+	return (&url.URL{Path: p}).String()
+}
+
+// legend explains the status markers, e.g. "✓ active".
+func legend() []string {
+	entries := make([]string, 0, len(legendOrder))
+	for _, status := range legendOrder {
+		entries = append(entries, statusMarkers[status]+" "+string(status))
+	}
+	return entries
+}
+
+func analyzedAt(doc *analysis.SpecDocument) string {
+	if doc.CompletedAt.IsZero() {
+		return ""
+	}
+	return doc.CompletedAt.UTC().Format(time.DateTime + " UTC")
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,226 @@
+package specdoc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+func newTestDocument() *analysis.SpecDocument {
+	return &analysis.SpecDocument{
+		Branch:      "main",
+		CommitSHA:   "abc123def456",
+		CompletedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Host:        "github.com",
+		Inventory: &analysis.Inventory{
+			Files: []analysis.TestFile{
+				{
+					Framework: "jest",
+					Path:      "src/cart/cart.test.ts",
+					Suites: []analysis.TestSuite{
+						{
+							Location: analysis.Location{StartLine: 3, EndLine: 20},
+							Name:     "Cart",
+							Suites: []analysis.TestSuite{
+								{
+									Location: analysis.Location{StartLine: 10, EndLine: 18},
+									Name:     "remove",
+									Tests: []analysis.Test{
+										{Location: analysis.Location{StartLine: 11, EndLine: 13}, Name: "drops the item", Status: analysis.TestStatusSkipped},
+									},
+								},
+							},
+							Tests: []analysis.Test{
+								{Location: analysis.Location{StartLine: 4, EndLine: 6}, Name: "adds *items*", Status: analysis.TestStatusActive},
+							},
+						},
+					},
+					Tests: []analysis.Test{
+						{Location: analysis.Location{StartLine: 22}, Name: "<script>", Status: analysis.TestStatusTodo},
+					},
+				},
+				{Framework: "jest", Path: "../escape.test.ts", Tests: []analysis.Test{{Name: "outside"}}},
+			},
+		},
+		Owner: "octo",
+		Repo:  "shop",
+	}
+}
+
+func TestExport_Markdown(t *testing.T) {
+	dir := t.TempDir()
+
+	pages, err := Export(dir, newTestDocument(), FormatMarkdown)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pages != 1 {
+		t.Errorf("expected 1 page, got %d", pages)
+	}
+
+	index := readFile(t, filepath.Join(dir, "index.md"))
+	for _, want := range []string{
+		"# octo/shop",
+		"[`abc123d`](https://github.com/octo/shop/commit/abc123def456) on `main`, analyzed 2025-01-02 03:04:05 UTC.",
+		"| [src/cart/cart.test.ts](src/cart/cart.test.ts.md) | jest | 3 | 1 |",
+	} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index missing %q:\n%s", want, index)
+		}
+	}
+
+	page := readFile(t, filepath.Join(dir, "src", "cart", "cart.test.ts.md"))
+	want := `# src/cart/cart.test.ts
+
+[← octo/shop](../../index.md) · jest · 3 tests · [source](https://github.com/octo/shop/blob/abc123def456/src/cart/cart.test.ts)
+
+- **[Cart](https://github.com/octo/shop/blob/abc123def456/src/cart/cart.test.ts#L3-L20)**
+  - ✓ [adds \*items\*](https://github.com/octo/shop/blob/abc123def456/src/cart/cart.test.ts#L4-L6)
+  - **[remove](https://github.com/octo/shop/blob/abc123def456/src/cart/cart.test.ts#L10-L18)**
+    - ⊘ [drops the item](https://github.com/octo/shop/blob/abc123def456/src/cart/cart.test.ts#L11-L13)
+- ○ [\<script\>](https://github.com/octo/shop/blob/abc123def456/src/cart/cart.test.ts#L22)
+`
+	if page != want {
+		t.Errorf("unexpected page:\n%s\nwant:\n%s", page, want)
+	}
+
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "escape.test.ts.md")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected escaping path to be skipped, got %v", err)
+	}
+}
+
+func TestExport_HTML(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := Export(dir, newTestDocument(), FormatHTML); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	index := readFile(t, filepath.Join(dir, "index.html"))
+	if !strings.Contains(index, `<a href="src/cart/cart.test.ts.html">src/cart/cart.test.ts</a>`) {
+		t.Errorf("index missing file link:\n%s", index)
+	}
+
+	page := readFile(t, filepath.Join(dir, "src", "cart", "cart.test.ts.html"))
+	for _, want := range []string{
+		`<a href="../../index.html">← octo/shop</a>`,
+		`<li class="suite"><a href="https://github.com/octo/shop/blob/abc123def456/src/cart/cart.test.ts#L3-L20">Cart</a><ul>`,
+		`<li class="skipped">⊘ <a href="https://github.com/octo/shop/blob/abc123def456/src/cart/cart.test.ts#L11-L13">drops the item</a></li>`,
+		`<li class="todo">○ <a href="https://github.com/octo/shop/blob/abc123def456/src/cart/cart.test.ts#L22">&lt;script&gt;</a></li>`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page missing %q:\n%s", want, page)
+		}
+	}
+}
+
+func TestExport_InvalidInput(t *testing.T) {
+	if _, err := Export(t.TempDir(), nil, FormatMarkdown); !errors.Is(err, analysis.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for nil document, got %v", err)
+	}
+	if _, err := Export(t.TempDir(), newTestDocument(), Format("pdf")); !errors.Is(err, analysis.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for unknown format, got %v", err)
+	}
+}
+
+func TestExportComparison(t *testing.T) {
+	fork := newTestDocument()
+	upstream := newTestDocument()
+	upstream.CommitSHA = "fedcba987654"
+	upstream.Owner = "upstream"
+	comparison := &analysis.ForkComparison{
+		Added: []analysis.ComparedTest{
+			{FilePath: "src/cart/cart.test.ts", Location: analysis.Location{StartLine: 11, EndLine: 13}, Name: "drops the item", Suites: []string{"Cart", "remove"}},
+		},
+		Fork:     fork,
+		Shared:   2,
+		Upstream: upstream,
+	}
+
+	t.Run("markdown", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := ExportComparison(dir, comparison, FormatMarkdown); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		page := readFile(t, filepath.Join(dir, "upstream.md"))
+		want := `# octo/shop compared with upstream/shop
+
+[← octo/shop](index.md) · upstream commit [` + "`fedcba9`" + `](https://github.com/upstream/shop/commit/fedcba987654) · 2 shared · 1 added · 0 removed
+
+## Added in fork
+
+| File | Test |
+| --- | --- |
+| src/cart/cart.test.ts | [Cart › remove › drops the item](https://github.com/octo/shop/blob/abc123def456/src/cart/cart.test.ts#L11-L13) |
+
+## Removed from upstream
+
+None.
+`
+		if page != want {
+			t.Errorf("unexpected page:\n%s\nwant:\n%s", page, want)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := ExportComparison(dir, comparison, FormatHTML); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		page := readFile(t, filepath.Join(dir, "upstream.html"))
+		for _, want := range []string{
+			`<h1>octo/shop compared with upstream/shop</h1>`,
+			`<tr><td>src/cart/cart.test.ts</td><td><a href="https://github.com/octo/shop/blob/abc123def456/src/cart/cart.test.ts#L11-L13">Cart › remove › drops the item</a></td></tr>`,
+			`<p>None.</p>`,
+		} {
+			if !strings.Contains(page, want) {
+				t.Errorf("page missing %q:\n%s", want, page)
+			}
+		}
+	})
+
+	if err := ExportComparison(t.TempDir(), &analysis.ForkComparison{Fork: fork}, FormatMarkdown); !errors.Is(err, analysis.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput without upstream, got %v", err)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Format
+		wantErr bool
+	}{
+		{input: "markdown", want: FormatMarkdown},
+		{input: "MD", want: FormatMarkdown},
+		{input: "html", want: FormatHTML},
+		{input: "pdf", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseFormat(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
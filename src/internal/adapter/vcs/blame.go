@@ -0,0 +1,307 @@
+package vcs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+const defaultBlameConcurrency = 4
+
+var _ analysis.Blamer = (*GitBlamer)(nil)
+
+// GitBlamer implements analysis.Blamer with git blame on the cloned repository.
+// Blame names the commit that last changed a declaration line; unless that commit added
+// the file, git log -L traces the line back to the commit that introduced it.
+// Shallow clones are unshallowed on demand, since blame on a depth-1 clone
+// attributes every line to the boundary commit. A line may date back to any commit,
+// so blame needs the full history; the fetch is skipped for inventories without files.
+type GitBlamer struct {
+	concurrency int
+}
+
+// NewGitBlamer creates a new GitBlamer.
+func NewGitBlamer() *GitBlamer {
+	return &GitBlamer{concurrency: defaultBlameConcurrency}
+}
+
+// repoRootProvider is implemented by sources backed by a local git working tree.
+type repoRootProvider interface {
+	Root() string
+}
+
+// Blame fills Test.Blame for every file of the inventory.
+// Files that cannot be blamed (e.g. generated or untracked) are skipped with a warning.
+func (b *GitBlamer) Blame(ctx context.Context, src analysis.Source, inventory *analysis.Inventory) error {
+	if inventory == nil || len(inventory.Files) == 0 {
+		return nil
+	}
+
+	provider, ok := src.(repoRootProvider)
+	if !ok {
+		return fmt.Errorf("source type %T does not implement repoRootProvider interface", src)
+	}
+	root := provider.Root()
+
+	if err := ensureHistory(ctx, root); err != nil {
+		return err
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(b.concurrency)
+	for i := range inventory.Files {
+		file := &inventory.Files[i]
+		g.Go(func() error {
+			lines, err := blameFile(gctx, root, src.CommitSHA(), file.Path)
+			if err != nil {
+				if gctx.Err() != nil {
+					return gctx.Err()
+				}
+				slog.WarnContext(gctx, "failed to blame test file, skipping",
+					"error", err,
+					"file", file.Path,
+				)
+				return nil
+			}
+			analysis.ApplyBlame(file, lines)
+			return introduceFile(gctx, root, src.CommitSHA(), file)
+		})
+	}
+	return g.Wait()
+}
+
+// ensureHistory fetches the full history of a shallow clone.
+func ensureHistory(ctx context.Context, root string) error {
+	shallow, err := isShallow(ctx, root)
+	if err != nil || !shallow {
+		return err
+	}
+
+	if _, err := runGit(ctx, root, "fetch", "--unshallow", "--quiet", "origin"); err != nil {
+		return fmt.Errorf("unshallow repository: %w", err)
+	}
+	return nil
+}
+
+// ensureHistorySince deepens a shallow clone to the commits of rev made since then, plus
+// one more generation so the oldest of them is diffed against its parent rather than
+// an empty tree. A rev committed before since has no commits in the window to fetch.
+func ensureHistorySince(ctx context.Context, root, rev string, since time.Time) error {
+	shallow, err := isShallow(ctx, root)
+	if err != nil || !shallow {
+		return err
+	}
+
+	out, err := runGit(ctx, root, "log", "-1", "--format=%ct", rev)
+	if err != nil {
+		return fmt.Errorf("read commit time: %w", err)
+	}
+	committedAt, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return fmt.Errorf("parse commit time %q: %w", out, err)
+	}
+	if time.Unix(committedAt, 0).Before(since) {
+		return nil
+	}
+
+	if _, err := runGit(ctx, root, "fetch", "--quiet", "--shallow-since="+since.Format(time.RFC3339), "origin"); err != nil {
+		return fmt.Errorf("deepen repository: %w", err)
+	}
+	if _, err := runGit(ctx, root, "fetch", "--quiet", "--deepen=1", "origin"); err != nil {
+		return fmt.Errorf("deepen repository: %w", err)
+	}
+	return nil
+}
+
+func isShallow(ctx context.Context, root string) (bool, error) {
+	out, err := runGit(ctx, root, "rev-parse", "--is-shallow-repository")
+	if err != nil {
+		return false, fmt.Errorf("check shallow repository: %w", err)
+	}
+	return strings.TrimSpace(string(out)) == "true", nil
+}
+
+// introduceFile replaces the last change of each blamed declaration with the commit that
+// introduced it. Lines that cannot be traced keep their last change.
+func introduceFile(ctx context.Context, root, commitSHA string, file *analysis.TestFile) error {
+	rev := commitSHA
+	if rev == "" {
+		rev = "HEAD"
+	}
+
+	added, err := fileAddedCommit(ctx, root, rev, file.Path)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		slog.WarnContext(ctx, "failed to find commit adding test file, skipping introductions",
+			"error", err,
+			"file", file.Path,
+		)
+		return nil
+	}
+
+	var introduce func(tests []analysis.Test) error
+	introduce = func(tests []analysis.Test) error {
+		for i := range tests {
+			blame := tests[i].Blame
+			// A line changed by the commit adding the file cannot be older than that commit.
+			if blame == nil || blame.CommitSHA == added {
+				continue
+			}
+			introduction, err := introducingCommit(ctx, root, rev, file.Path, tests[i].Location.StartLine)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				slog.WarnContext(ctx, "failed to trace test declaration, keeping its last change",
+					"error", err,
+					"file", file.Path,
+					"test", tests[i].Name,
+				)
+				continue
+			}
+			blame.Introduce(introduction)
+		}
+		return nil
+	}
+	var walk func(suites []analysis.TestSuite) error
+	walk = func(suites []analysis.TestSuite) error {
+		for i := range suites {
+			if err := introduce(suites[i].Tests); err != nil {
+				return err
+			}
+			if err := walk(suites[i].Suites); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := introduce(file.Tests); err != nil {
+		return err
+	}
+	return walk(file.Suites)
+}
+
+// fileAddedCommit returns the oldest commit that added path, following renames like blame does.
+func fileAddedCommit(ctx context.Context, root, rev, path string) (string, error) {
+	out, err := runGit(ctx, root, "log", "--diff-filter=A", "--format=%H", rev, "--", path)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Fields(string(out))
+	if len(lines) == 0 {
+		return "", fmt.Errorf("no commit adds %s", path)
+	}
+	return lines[len(lines)-1], nil
+}
+
+// introducingCommit follows a line back through its edits to the commit that added it.
+func introducingCommit(ctx context.Context, root, rev, path string, line int) (analysis.LineBlame, error) {
+	out, err := runGit(ctx, root, "log", fmt.Sprintf("-L%d,%d:%s", line, line, path),
+		"--no-patch", "--format=%H%x09%an%x09%ae%x09%at", rev)
+	if err != nil {
+		return analysis.LineBlame{}, err
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	fields := strings.Split(lines[len(lines)-1], "\t")
+	if len(fields) != 4 || fields[0] == "" {
+		return analysis.LineBlame{}, fmt.Errorf("unexpected log output %q", out)
+	}
+	sec, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return analysis.LineBlame{}, fmt.Errorf("parse author time %q: %w", fields[3], err)
+	}
+	return analysis.LineBlame{
+		AuthorEmail: fields[2],
+		AuthorName:  fields[1],
+		AuthoredAt:  time.Unix(sec, 0).UTC(),
+		CommitSHA:   fields[0],
+	}, nil
+}
+
+func blameFile(ctx context.Context, root, commitSHA, path string) ([]analysis.LineBlame, error) {
+	args := []string{"blame", "--porcelain", "-w"}
+	if commitSHA != "" {
+		args = append(args, commitSHA)
+	}
+	args = append(args, "--", path)
+
+	out, err := runGit(ctx, root, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseBlamePorcelain(out)
+}
+
+// parseBlamePorcelain parses "git blame --porcelain" output into per-line blame, where result[0] is line 1.
+// Commit metadata is only printed the first time a commit appears, so it is cached by SHA.
+func parseBlamePorcelain(out []byte) ([]analysis.LineBlame, error) {
+	commits := make(map[string]*analysis.LineBlame)
+	var result []analysis.LineBlame
+	var current *analysis.LineBlame
+	var currentLine int
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "\t") {
+			if current == nil {
+				return nil, fmt.Errorf("content line without header")
+			}
+			for len(result) < currentLine {
+				result = append(result, analysis.LineBlame{})
+			}
+			result[currentLine-1] = *current
+			current = nil
+			continue
+		}
+
+		if current == nil {
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("unexpected blame header %q", line)
+			}
+			n, err := strconv.Atoi(fields[2])
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("unexpected blame line number in %q", line)
+			}
+			sha := fields[0]
+			if _, ok := commits[sha]; !ok {
+				commits[sha] = &analysis.LineBlame{CommitSHA: sha}
+			}
+			current = commits[sha]
+			currentLine = n
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "author":
+			current.AuthorName = value
+		case "author-mail":
+			current.AuthorEmail = strings.Trim(value, "<>")
+		case "author-time":
+			if sec, err := strconv.ParseInt(value, 10, 64); err == nil {
+				current.AuthoredAt = time.Unix(sec, 0).UTC()
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read blame output: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,166 @@
+package vcs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+func TestParseBlamePorcelain(t *testing.T) {
+	out := "aaaaaaaa 1 1 2\n" +
+		"author Alice\n" +
+		"author-mail <alice@example.com>\n" +
+		"author-time 1704067200\n" +
+		"author-tz +0000\n" +
+		"summary add test\n" +
+		"filename a_test.go\n" +
+		"\tfunc TestA(t *testing.T) {\n" +
+		"aaaaaaaa 2 2\n" +
+		"\t}\n" +
+		"bbbbbbbb 3 3 1\n" +
+		"author Bob\n" +
+		"author-mail <bob@example.com>\n" +
+		"author-time 1717200000\n" +
+		"filename a_test.go\n" +
+		"\t// comment\n"
+
+	lines, err := parseBlamePorcelain([]byte(out))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(lines))
+	}
+	if lines[1].CommitSHA != "aaaaaaaa" || lines[1].AuthorEmail != "alice@example.com" {
+		t.Errorf("expected cached commit metadata on line 2, got %+v", lines[1])
+	}
+	if lines[2].AuthorName != "Bob" || !lines[2].AuthoredAt.Equal(time.Unix(1717200000, 0)) {
+		t.Errorf("unexpected line 3 blame: %+v", lines[2])
+	}
+
+	if _, err := parseBlamePorcelain([]byte("garbage\n")); err == nil {
+		t.Error("expected error for malformed header")
+	}
+}
+
+func TestGitBlamer_Blame(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.commit("2024-01-01T00:00:00Z", "alice", "a_test.go", "package a\n\nfunc TestA(t *testing.T) {\n}\n")
+	repo.commit("2024-06-01T00:00:00Z", "bob", "a_test.go", "package a\n\nfunc TestA(t *testing.T) {\n\tt.Skip()\n}\n")
+
+	ctx := context.Background()
+	src, err := NewGitVCS().Clone(ctx, repo.url(), nil)
+	if err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	defer src.Close(ctx)
+
+	inventory := &analysis.Inventory{
+		Files: []analysis.TestFile{
+			{
+				Path:  "a_test.go",
+				Tests: []analysis.Test{{Name: "TestA", Location: analysis.Location{StartLine: 3, EndLine: 5}}},
+			},
+			{
+				Path:  "missing_test.go",
+				Tests: []analysis.Test{{Name: "TestMissing", Location: analysis.Location{StartLine: 1}}},
+			},
+		},
+	}
+
+	if err := NewGitBlamer().Blame(ctx, src, inventory); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	blame := inventory.Files[0].Tests[0].Blame
+	if blame == nil {
+		t.Fatal("expected blame for TestA")
+	}
+	if blame.AuthorName != "alice" {
+		t.Errorf("expected declaration introduced by alice, got %q", blame.AuthorName)
+	}
+	if !blame.IntroducedAt.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected introduced at: %v", blame.IntroducedAt)
+	}
+	if !blame.LastModifiedAt.Equal(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected last modification by the skip commit, got %v", blame.LastModifiedAt)
+	}
+	if inventory.Files[1].Tests[0].Blame != nil {
+		t.Error("expected missing file to be skipped")
+	}
+}
+
+func TestGitBlamer_Blame_RenamedDeclaration(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.commit("2024-01-01T00:00:00Z", "alice", "a_test.go", "package a\n\nfunc TestA(t *testing.T) {\n}\n")
+	repo.commit("2024-02-01T00:00:00Z", "bob", "a_test.go", "package a\n\nfunc TestA(t *testing.T) {\n}\n\nfunc TestB(t *testing.T) {\n}\n")
+	repo.commit("2024-03-01T00:00:00Z", "carol", "a_test.go", "package a\n\nfunc TestA(t *testing.T) {\n}\n\nfunc TestRenamed(t *testing.T) {\n}\n")
+
+	ctx := context.Background()
+	src, err := NewGitVCS().Clone(ctx, repo.url(), nil)
+	if err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	defer src.Close(ctx)
+
+	inventory := &analysis.Inventory{
+		Files: []analysis.TestFile{
+			{
+				Path: "a_test.go",
+				Suites: []analysis.TestSuite{{
+					Name: "a",
+					Tests: []analysis.Test{
+						{Name: "TestA", Location: analysis.Location{StartLine: 3, EndLine: 4}},
+						{Name: "TestRenamed", Location: analysis.Location{StartLine: 6, EndLine: 7}},
+					},
+				}},
+			},
+		},
+	}
+
+	if err := NewGitBlamer().Blame(ctx, src, inventory); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := inventory.Files[0].Suites[0].Tests
+	if blame := tests[0].Blame; blame == nil || blame.AuthorName != "alice" {
+		t.Errorf("expected TestA introduced by alice, got %+v", blame)
+	}
+	blame := tests[1].Blame
+	if blame == nil {
+		t.Fatal("expected blame for TestRenamed")
+	}
+	if blame.AuthorName != "bob" || !blame.IntroducedAt.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected rename traced back to bob's commit, got %+v", blame)
+	}
+	if !blame.LastModifiedAt.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected last modification by the rename, got %v", blame.LastModifiedAt)
+	}
+}
+
+func TestGitBlamer_Blame_InvalidSource(t *testing.T) {
+	inventory := &analysis.Inventory{Files: []analysis.TestFile{{Path: "a_test.go"}}}
+
+	err := NewGitBlamer().Blame(context.Background(), &rootlessSource{}, inventory)
+	if err == nil {
+		t.Fatal("expected error for source without repository root")
+	}
+}
+
+// rootlessSource implements analysis.Source without a local working tree.
+type rootlessSource struct{}
+
+func (s *rootlessSource) Branch() string                { return "" }
+func (s *rootlessSource) CommitSHA() string             { return "" }
+func (s *rootlessSource) CommittedAt() time.Time        { return time.Time{} }
+func (s *rootlessSource) Close(_ context.Context) error { return nil }
+
+func (s *rootlessSource) Checkout(_ context.Context, _ string) error {
+	return nil
+}
+
+func (s *rootlessSource) VerifyCommitExists(_ context.Context, _ string) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,112 @@
+package vcs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+// churnCommitMarker starts the header line of each commit in the churn log.
+const churnCommitMarker = "\x1e"
+
+var _ analysis.ChurnReader = (*GitChurnReader)(nil)
+
+// GitChurnReader implements analysis.ChurnReader with git log on the cloned repository.
+// Shallow clones are deepened on demand to the start of the churn window only.
+type GitChurnReader struct{}
+
+// NewGitChurnReader creates a new GitChurnReader.
+func NewGitChurnReader() *GitChurnReader {
+	return &GitChurnReader{}
+}
+
+// Churn counts non-merge commits with renames disabled, so a moved file starts a new history.
+// Binary changes count as commits without lines.
+func (r *GitChurnReader) Churn(ctx context.Context, src analysis.Source, paths []string, since time.Time) (map[string]analysis.FileChurn, error) {
+	if len(paths) == 0 {
+		return map[string]analysis.FileChurn{}, nil
+	}
+
+	provider, ok := src.(repoRootProvider)
+	if !ok {
+		return nil, fmt.Errorf("source type %T does not implement repoRootProvider interface", src)
+	}
+	root := provider.Root()
+
+	rev := src.CommitSHA()
+	if rev == "" {
+		rev = "HEAD"
+	}
+	if err := ensureHistorySince(ctx, root, rev, since); err != nil {
+		return nil, err
+	}
+	out, err := runGit(ctx, root, "-c", "core.quotePath=false", "log", "--no-merges", "--no-renames", "--numstat",
+		"--format="+churnCommitMarker+"%H%x09%ae", "--since="+since.Format(time.RFC3339), rev)
+	if err != nil {
+		return nil, fmt.Errorf("read churn: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		wanted[p] = true
+	}
+	return parseChurnLog(out, wanted)
+}
+
+// parseChurnLog aggregates "git log --numstat" output of the churn format for the wanted paths.
+// Authors are distinguished by case-insensitive email.
+func parseChurnLog(out []byte, wanted map[string]bool) (map[string]analysis.FileChurn, error) {
+	churn := make(map[string]analysis.FileChurn)
+	authors := make(map[string]map[string]bool)
+	var author string
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if header, ok := strings.CutPrefix(line, churnCommitMarker); ok {
+			_, email, _ := strings.Cut(header, "\t")
+			author = strings.ToLower(email)
+			continue
+		}
+		if line == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected numstat line %q", line)
+		}
+		path := fields[2]
+		if !wanted[path] {
+			continue
+		}
+
+		entry := churn[path]
+		entry.Path = path
+		entry.Commits++
+		// Binary files report "-" for both counts.
+		if added, err := strconv.Atoi(fields[0]); err == nil {
+			entry.LinesAdded += added
+		}
+		if removed, err := strconv.Atoi(fields[1]); err == nil {
+			entry.LinesRemoved += removed
+		}
+		if authors[path] == nil {
+			authors[path] = make(map[string]bool)
+		}
+		authors[path][author] = true
+		entry.Authors = len(authors[path])
+		churn[path] = entry
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read churn log: %w", err)
+	}
+	return churn, nil
+}
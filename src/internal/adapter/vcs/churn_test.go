@@ -0,0 +1,80 @@
+package vcs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+func TestParseChurnLog(t *testing.T) {
+	out := "\x1eaaaa\tAlice@example.com\n" +
+		"\n" +
+		"3\t1\ta_test.go\n" +
+		"-\t-\tfixtures/logo.png\n" +
+		"10\t0\tmain.go\n" +
+		"\x1ebbbb\talice@example.com\n" +
+		"\n" +
+		"2\t2\ta_test.go\n" +
+		"\x1ecccc\tbob@example.com\n" +
+		"\n" +
+		"1\t0\ta_test.go\n"
+
+	churn, err := parseChurnLog([]byte(out), map[string]bool{"a_test.go": true, "fixtures/logo.png": true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := analysis.FileChurn{Authors: 2, Commits: 3, LinesAdded: 6, LinesRemoved: 3, Path: "a_test.go"}
+	if churn["a_test.go"] != want {
+		t.Errorf("expected %+v, got %+v", want, churn["a_test.go"])
+	}
+	if binary := churn["fixtures/logo.png"]; binary.Commits != 1 || binary.LinesAdded != 0 {
+		t.Errorf("unexpected binary churn: %+v", binary)
+	}
+	if _, ok := churn["main.go"]; ok {
+		t.Error("expected unwanted path to be skipped")
+	}
+
+	if _, err := parseChurnLog([]byte("garbage\n"), nil); err == nil {
+		t.Error("expected error for malformed numstat line")
+	}
+}
+
+func TestGitChurnReader_Churn(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.commit("2023-01-01T00:00:00Z", "alice", "a_test.go", "package a\n")
+	repo.commit("2024-03-01T00:00:00Z", "alice", "a_test.go", "package a\n\nfunc TestA(t *testing.T) {}\n")
+	repo.commit("2024-04-01T00:00:00Z", "bob", "a_test.go", "package a\n\nfunc TestA(t *testing.T) {\n}\n")
+	repo.commit("2024-04-02T00:00:00Z", "bob", "b_test.go", "package a\n")
+
+	ctx := context.Background()
+	src, err := NewGitVCS().Clone(ctx, repo.url(), nil)
+	if err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	defer src.Close(ctx)
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	churn, err := NewGitChurnReader().Churn(ctx, src, []string{"a_test.go", "c_test.go"}, since)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := analysis.FileChurn{Authors: 2, Commits: 2, LinesAdded: 4, LinesRemoved: 1, Path: "a_test.go"}
+	if churn["a_test.go"] != want {
+		t.Errorf("expected %+v, got %+v", want, churn["a_test.go"])
+	}
+	if len(churn) != 1 {
+		t.Errorf("expected only a_test.go, got %+v", churn)
+	}
+
+	shallow, err := isShallow(ctx, src.(repoRootProvider).Root())
+	if err != nil {
+		t.Fatalf("check shallow: %v", err)
+	}
+	if !shallow {
+		t.Error("expected history before the window to stay unfetched")
+	}
+}
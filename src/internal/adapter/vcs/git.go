@@ -3,9 +3,12 @@ package vcs
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,11 +19,14 @@ import (
 // GitVCS implements analysis.VCS using specvital/core's GitSource.
 // It is a thin, stateless adapter that delegates to the underlying source package.
 // Concurrency control (semaphore) is managed by the use case layer, not here.
-type GitVCS struct{}
+type GitVCS struct {
+	// sshCommand is the ssh binary used with deploy keys.
+	sshCommand string
+}
 
 // NewGitVCS creates a new GitVCS.
 func NewGitVCS() *GitVCS {
-	return &GitVCS{}
+	return &GitVCS{sshCommand: "ssh"}
 }
 
 // Clone implements analysis.VCS by cloning a Git repository.
@@ -61,7 +67,8 @@ func (v *GitVCS) GetHeadCommit(ctx context.Context, url string, token *string) (
 		return analysis.CommitInfo{SHA: sha, IsPrivate: false}, nil
 	}
 
-	if token == nil {
+	// An empty public repository answers anonymously, so a token cannot change the result.
+	if token == nil || errors.Is(err, analysis.ErrEmptyRepository) {
 		return analysis.CommitInfo{}, fmt.Errorf("git ls-remote %q: %w", url, err)
 	}
 
@@ -73,31 +80,87 @@ func (v *GitVCS) GetHeadCommit(ctx context.Context, url string, token *string) (
 	return analysis.CommitInfo{SHA: sha, IsPrivate: true}, nil
 }
 
-func (v *GitVCS) lsRemote(ctx context.Context, url string, token *string) (string, error) {
-	targetURL := url
-	if token != nil {
-		targetURL = strings.Replace(url, "https://", fmt.Sprintf("https://x-access-token:%s@", *token), 1)
+// ListRemoteTags lists tags using git ls-remote --tags without cloning.
+func (v *GitVCS) ListRemoteTags(ctx context.Context, url string, token *string) ([]analysis.ReleaseTag, error) {
+	if url == "" {
+		return nil, fmt.Errorf("list remote tags: URL is required")
 	}
 
-	cmd := exec.CommandContext(ctx, "git", "ls-remote", targetURL, "HEAD")
-	cmd.Env = []string{
-		"PATH=" + os.Getenv("PATH"),
-		"GIT_TERMINAL_PROMPT=0",
-		"GIT_ASKPASS=",
-		"HOME=/nonexistent",
+	tags, err := runLsRemoteTags(ctx, url, authGitEnv(token))
+	if err != nil {
+		return nil, fmt.Errorf("git ls-remote --tags %q: %w", url, err)
+	}
+	return tags, nil
+}
+
+func runLsRemoteTags(ctx context.Context, remote string, env []string) ([]analysis.ReleaseTag, error) {
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--tags", remote)
+	cmd.Env = env
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s: %w", stderr.String(), err)
+	}
+
+	return parseTagRefs(stdout.String()), nil
+}
+
+// parseTagRefs prefers the peeled "^{}" entry of annotated tags, which names the tagged commit.
+func parseTagRefs(output string) []analysis.ReleaseTag {
+	const tagPrefix = "refs/tags/"
+	const peeledSuffix = "^{}"
+
+	var tags []analysis.ReleaseTag
+	index := make(map[string]int)
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Fields(line)
+		if len(parts) != 2 || !strings.HasPrefix(parts[1], tagPrefix) {
+			continue
+		}
+
+		sha := parts[0]
+		name := strings.TrimPrefix(parts[1], tagPrefix)
+		peeled := strings.HasSuffix(name, peeledSuffix)
+		name = strings.TrimSuffix(name, peeledSuffix)
+
+		if i, ok := index[name]; ok {
+			if peeled {
+				tags[i].SHA = sha
+			}
+			continue
+		}
+		index[name] = len(tags)
+		tags = append(tags, analysis.ReleaseTag{Name: name, SHA: sha})
 	}
+	return tags
+}
+
+func (v *GitVCS) lsRemote(ctx context.Context, url string, token *string) (string, error) {
+	return runLsRemote(ctx, url, authGitEnv(token))
+}
+
+func runLsRemote(ctx context.Context, remote string, env []string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", remote, "HEAD")
+	cmd.Env = env
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
+		if isRepoNotFound(stderr.String()) {
+			return "", fmt.Errorf("%s: %w", strings.TrimSpace(stderr.String()), analysis.ErrRepoNotFound)
+		}
 		return "", fmt.Errorf("%s: %w", stderr.String(), err)
 	}
 
+	// A repository without commits has no HEAD to advertise.
 	output := strings.TrimSpace(stdout.String())
 	if output == "" {
-		return "", fmt.Errorf("empty response")
+		return "", analysis.ErrEmptyRepository
 	}
 
 	parts := strings.Fields(output)
@@ -108,24 +171,131 @@ func (v *GitVCS) lsRemote(ctx context.Context, url string, token *string) (strin
 	return parts[0], nil
 }
 
-// gitSourceAdapter adapts source.GitSource to implement analysis.Source.
+// repoNotFoundMarkers are git's answers when a repository does not exist or the credentials
+// cannot see it. GitHub answers both the same way; without credentials it asks for a username,
+// and over SSH it refuses keys it does not know.
+var repoNotFoundMarkers = []string{
+	"repository not found",
+	"could not read username",
+	"authentication failed",
+	"permission denied (publickey)",
+}
+
+func isRepoNotFound(stderr string) bool {
+	msg := strings.ToLower(stderr)
+	for _, marker := range repoNotFoundMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// isolatedGitEnv prevents git from prompting for credentials or reading user configuration.
+func isolatedGitEnv() []string {
+	return []string{
+		"PATH=" + os.Getenv("PATH"),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_ASKPASS=",
+		"HOME=/nonexistent",
+	}
+}
+
+// authGitEnv is isolatedGitEnv with the token sent as an x-access-token Basic authorization
+// header. Passing it through the environment keeps the token out of the command line, where
+// any local user could read it from the process list.
+func authGitEnv(token *string) []string {
+	env := isolatedGitEnv()
+	if token == nil {
+		return env
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + *token))
+	return append(env,
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_VALUE_0=Authorization: Basic "+credentials,
+	)
+}
+
+// clonedSource is a clone of a repository: core's GitSource, or an sshClone for deploy keys.
+type clonedSource interface {
+	source.Source
+	Branch() string
+	CommitSHA() string
+	CommittedAt() time.Time
+}
+
+// gitSourceAdapter adapts a clonedSource to implement analysis.Source.
 // It also provides access to the underlying source.Source for parser integration.
+// After Checkout, commitSHA and committedAt override the values captured at clone time.
 type gitSourceAdapter struct {
-	gitSrc *source.GitSource
+	commitSHA   string
+	committedAt time.Time
+	gitSrc      clonedSource
 }
 
 func (a *gitSourceAdapter) Branch() string {
 	return a.gitSrc.Branch()
 }
 
+// Checkout detaches the working tree at sha.
+// Commits outside the shallow clone are fetched with "git fetch --depth 1 origin <sha>".
+func (a *gitSourceAdapter) Checkout(ctx context.Context, sha string) error {
+	if sha == "" {
+		return fmt.Errorf("checkout: SHA is required")
+	}
+	if sha == a.CommitSHA() {
+		return nil
+	}
+
+	root := a.gitSrc.Root()
+	if _, err := runGit(ctx, root, "cat-file", "-e", sha+"^{commit}"); err != nil {
+		if _, err := runGit(ctx, root, "fetch", "--depth", "1", "origin", sha); err != nil {
+			return fmt.Errorf("fetch commit %s: %w", sha, err)
+		}
+	}
+
+	if _, err := runGit(ctx, root, "checkout", "--detach", "--quiet", sha); err != nil {
+		return fmt.Errorf("checkout %s: %w", sha, err)
+	}
+
+	out, err := runGit(ctx, root, "show", "-s", "--format=%H %ct", sha)
+	if err != nil {
+		return fmt.Errorf("read commit %s: %w", sha, err)
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return fmt.Errorf("unexpected commit format: %q", out)
+	}
+	sec, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("parse commit time %q: %w", fields[1], err)
+	}
+
+	a.commitSHA = fields[0]
+	a.committedAt = time.Unix(sec, 0).UTC()
+	return nil
+}
+
 func (a *gitSourceAdapter) CommitSHA() string {
+	if a.commitSHA != "" {
+		return a.commitSHA
+	}
 	return a.gitSrc.CommitSHA()
 }
 
 func (a *gitSourceAdapter) CommittedAt() time.Time {
+	if a.commitSHA != "" {
+		return a.committedAt
+	}
 	return a.gitSrc.CommittedAt()
 }
 
+// Root returns the path of the cloned working tree.
+func (a *gitSourceAdapter) Root() string {
+	return a.gitSrc.Root()
+}
+
 func (a *gitSourceAdapter) Close(_ context.Context) error {
 	return a.gitSrc.Close()
 }
@@ -170,3 +340,28 @@ func (a *gitSourceAdapter) VerifyCommitExists(ctx context.Context, sha string) (
 func (a *gitSourceAdapter) CoreSource() source.Source {
 	return a.gitSrc
 }
+
+// runGit runs git in isolation from the user's configuration and credential helpers.
+// Fetches from a clone authenticate with what the clone stored for its origin.
+func runGit(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	return runGitEnv(ctx, dir, isolatedGitEnv(), args...)
+}
+
+// runGitEnv runs git with env instead of the process environment.
+func runGitEnv(ctx context.Context, dir string, env []string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = env
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("git %s: %s: %w", args[0], strings.TrimSpace(stderr.String()), err)
+	}
+	return stdout.Bytes(), nil
+}
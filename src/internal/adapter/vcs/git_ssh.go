@@ -0,0 +1,269 @@
+package vcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/core/pkg/source"
+)
+
+var (
+	_ analysis.DeployCredentialTagLister = (*GitVCS)(nil)
+	_ analysis.DeployCredentialVCS       = (*GitVCS)(nil)
+)
+
+// githubKnownHosts pins GitHub's published ed25519 host key, so deploy keys are never offered
+// to an impostor. See https://docs.github.com/en/authentication/keeping-your-account-and-data-secure/githubs-ssh-key-fingerprints
+const githubKnownHosts = "github.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl\n"
+
+// CloneWithCredential implements analysis.DeployCredentialVCS. Tokens are used like OAuth
+// tokens; SSH keys clone over SSH and stay configured in the clone for later fetches.
+func (v *GitVCS) CloneWithCredential(ctx context.Context, url string, credential analysis.DeployCredential) (analysis.Source, error) {
+	if credential.Kind != analysis.DeployCredentialSSHKey {
+		return v.Clone(ctx, url, credential.APIToken())
+	}
+	if url == "" {
+		return nil, fmt.Errorf("clone repository: URL is required")
+	}
+
+	key, err := v.newSSHKey(credential.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("clone repository %q: %w", url, err)
+	}
+
+	clone, err := cloneSSH(ctx, url, key)
+	if err != nil {
+		key.remove()
+		return nil, fmt.Errorf("clone repository %q: %w", url, err)
+	}
+	return &gitSourceAdapter{gitSrc: clone}, nil
+}
+
+// GetHeadCommitWithCredential implements analysis.DeployCredentialVCS.
+func (v *GitVCS) GetHeadCommitWithCredential(ctx context.Context, url string, credential analysis.DeployCredential) (analysis.CommitInfo, error) {
+	if credential.Kind != analysis.DeployCredentialSSHKey {
+		return v.GetHeadCommit(ctx, url, credential.APIToken())
+	}
+	if url == "" {
+		return analysis.CommitInfo{}, fmt.Errorf("get head commit: URL is required")
+	}
+
+	sha, err := v.lsRemote(ctx, url, nil)
+	if err == nil {
+		return analysis.CommitInfo{SHA: sha, IsPrivate: false}, nil
+	}
+	if errors.Is(err, analysis.ErrEmptyRepository) {
+		return analysis.CommitInfo{}, fmt.Errorf("git ls-remote %q: %w", url, err)
+	}
+
+	remote, err := sshRemoteURL(url)
+	if err != nil {
+		return analysis.CommitInfo{}, fmt.Errorf("git ls-remote %q: %w", url, err)
+	}
+	key, err := v.newSSHKey(credential.Secret)
+	if err != nil {
+		return analysis.CommitInfo{}, fmt.Errorf("git ls-remote %q: %w", url, err)
+	}
+	defer key.remove()
+
+	sha, err = runLsRemote(ctx, remote, append(isolatedGitEnv(), "GIT_SSH_COMMAND="+key.command))
+	if err != nil {
+		return analysis.CommitInfo{}, fmt.Errorf("git ls-remote %q: %w", remote, err)
+	}
+	return analysis.CommitInfo{SHA: sha, IsPrivate: true}, nil
+}
+
+// ListRemoteTagsWithCredential implements analysis.DeployCredentialTagLister.
+func (v *GitVCS) ListRemoteTagsWithCredential(ctx context.Context, url string, credential analysis.DeployCredential) ([]analysis.ReleaseTag, error) {
+	if credential.Kind != analysis.DeployCredentialSSHKey {
+		return v.ListRemoteTags(ctx, url, credential.APIToken())
+	}
+	if url == "" {
+		return nil, fmt.Errorf("list remote tags: URL is required")
+	}
+
+	remote, err := sshRemoteURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("git ls-remote --tags %q: %w", url, err)
+	}
+	key, err := v.newSSHKey(credential.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("git ls-remote --tags %q: %w", url, err)
+	}
+	defer key.remove()
+
+	tags, err := runLsRemoteTags(ctx, remote, append(isolatedGitEnv(), "GIT_SSH_COMMAND="+key.command))
+	if err != nil {
+		return nil, fmt.Errorf("git ls-remote --tags %q: %w", remote, err)
+	}
+	return tags, nil
+}
+
+// sshRemoteURL converts an https repository URL into the scp-like form git uses for SSH.
+// Only github.com is supported, as its host key is the only one pinned.
+func sshRemoteURL(repoURL string) (string, error) {
+	parsed, err := url.Parse(repoURL)
+	if err != nil || parsed.Scheme != "https" {
+		return "", fmt.Errorf("SSH deploy keys need an https repository URL, got %q", repoURL)
+	}
+	if parsed.Host != "github.com" {
+		return "", fmt.Errorf("SSH deploy keys are only supported for github.com, got %q", parsed.Host)
+	}
+	path := strings.Trim(parsed.Path, "/")
+	if strings.Count(path, "/") != 1 {
+		return "", fmt.Errorf("repository URL must name owner/repo, got %q", repoURL)
+	}
+	return fmt.Sprintf("git@%s:%s.git", parsed.Host, strings.TrimSuffix(path, ".git")), nil
+}
+
+// sshKey is a private key written to a private temporary directory along with the pinned
+// host keys. command is the ssh invocation for GIT_SSH_COMMAND or core.sshCommand.
+type sshKey struct {
+	command string
+	dir     string
+}
+
+func (v *GitVCS) newSSHKey(privateKey string) (*sshKey, error) {
+	dir, err := os.MkdirTemp("", "deploykey-*")
+	if err != nil {
+		return nil, fmt.Errorf("create SSH key directory: %w", err)
+	}
+	key := &sshKey{dir: dir}
+
+	// ssh rejects keys whose PEM block lacks the final newline.
+	if !strings.HasSuffix(privateKey, "\n") {
+		privateKey += "\n"
+	}
+	keyPath := filepath.Join(dir, "id")
+	knownHostsPath := filepath.Join(dir, "known_hosts")
+	if err := os.WriteFile(keyPath, []byte(privateKey), 0o600); err != nil {
+		key.remove()
+		return nil, fmt.Errorf("write SSH key: %w", err)
+	}
+	if err := os.WriteFile(knownHostsPath, []byte(githubKnownHosts), 0o600); err != nil {
+		key.remove()
+		return nil, fmt.Errorf("write known hosts: %w", err)
+	}
+
+	key.command = strings.Join([]string{
+		v.sshCommand,
+		"-i", shellQuote(keyPath),
+		"-o", "IdentitiesOnly=yes",
+		"-o", "IdentityAgent=none",
+		"-o", "BatchMode=yes",
+		"-o", "StrictHostKeyChecking=yes",
+		"-o", "UserKnownHostsFile=" + shellQuote(knownHostsPath),
+	}, " ")
+	return key, nil
+}
+
+func (k *sshKey) remove() error {
+	return os.RemoveAll(k.dir)
+}
+
+// shellQuote quotes s for the shell git runs ssh commands with.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// sshClone is a shallow clone made over SSH. It takes the place of core's GitSource, which
+// only authenticates over https, and owns the key until Close.
+type sshClone struct {
+	*source.LocalSource
+	branch      string
+	closeErr    error
+	closeOnce   sync.Once
+	committedAt time.Time
+	commitSHA   string
+	key         *sshKey
+}
+
+func cloneSSH(ctx context.Context, repoURL string, key *sshKey) (*sshClone, error) {
+	remote, err := sshRemoteURL(repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "gitsource-*")
+	if err != nil {
+		return nil, fmt.Errorf("create clone directory: %w", err)
+	}
+
+	clone, err := readSSHClone(ctx, remote, dir, key)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return clone, nil
+}
+
+func readSSHClone(ctx context.Context, remote, dir string, key *sshKey) (*sshClone, error) {
+	env := append(isolatedGitEnv(), "GIT_SSH_COMMAND="+key.command)
+	if _, err := runGitEnv(ctx, "", env, "clone", "--single-branch", "--depth", "1", "--quiet", remote, dir); err != nil {
+		return nil, err
+	}
+	// Checkout and VerifyCommitExists fetch from origin later on.
+	if _, err := runGit(ctx, dir, "config", "core.sshCommand", key.command); err != nil {
+		return nil, err
+	}
+
+	out, err := runGit(ctx, dir, "show", "-s", "--format=%H %ct", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("unexpected commit format: %q", out)
+	}
+	sec, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse commit time %q: %w", fields[1], err)
+	}
+
+	branch, err := runGit(ctx, dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := source.NewLocalSource(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sshClone{
+		LocalSource: local,
+		branch:      strings.TrimSpace(string(branch)),
+		committedAt: time.Unix(sec, 0).UTC(),
+		commitSHA:   fields[0],
+		key:         key,
+	}, nil
+}
+
+func (c *sshClone) Branch() string {
+	return c.branch
+}
+
+func (c *sshClone) CommitSHA() string {
+	return c.commitSHA
+}
+
+func (c *sshClone) CommittedAt() time.Time {
+	return c.committedAt
+}
+
+// Close removes the clone and the key. It is idempotent.
+func (c *sshClone) Close() error {
+	c.closeOnce.Do(func() {
+		c.closeErr = errors.Join(os.RemoveAll(c.Root()), c.key.remove())
+	})
+	return c.closeErr
+}
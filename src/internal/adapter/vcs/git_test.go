@@ -2,8 +2,13 @@ package vcs
 
 import (
 	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewGitVCS(t *testing.T) {
@@ -89,6 +94,84 @@ func TestGitVCS_GetHeadCommit_ContextCancellation(t *testing.T) {
 	}
 }
 
+func TestParseTagRefs(t *testing.T) {
+	output := "aaa\trefs/tags/v1.0.0\n" +
+		"bbb\trefs/tags/v1.1.0\n" +
+		"ccc\trefs/tags/v1.1.0^{}\n" +
+		"ddd\trefs/heads/main\n"
+
+	tags := parseTagRefs(output)
+
+	if len(tags) != 2 {
+		t.Fatalf("expected 2 tags, got %v", tags)
+	}
+	if tags[0].Name != "v1.0.0" || tags[0].SHA != "aaa" {
+		t.Errorf("unexpected lightweight tag: %+v", tags[0])
+	}
+	if tags[1].Name != "v1.1.0" || tags[1].SHA != "ccc" {
+		t.Errorf("expected annotated tag peeled to commit, got %+v", tags[1])
+	}
+}
+
+func TestIsRepoNotFound(t *testing.T) {
+	tests := []struct {
+		stderr string
+		want   bool
+	}{
+		{stderr: "remote: Repository not found.\nfatal: repository 'https://github.com/o/r/' not found\n", want: true},
+		{stderr: "fatal: could not read Username for 'https://github.com': terminal prompts disabled\n", want: true},
+		{stderr: "remote: Invalid username or password.\nfatal: Authentication failed for 'https://github.com/o/r/'\n", want: true},
+		{stderr: "fatal: unable to access 'https://github.com/o/r/': Could not resolve host: github.com\n", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isRepoNotFound(tt.stderr); got != tt.want {
+			t.Errorf("isRepoNotFound(%q) = %v, want %v", tt.stderr, got, tt.want)
+		}
+	}
+}
+
+func TestAuthGitEnv(t *testing.T) {
+	if got := authGitEnv(nil); len(got) != len(isolatedGitEnv()) {
+		t.Errorf("expected the isolated environment without a token, got %v", got)
+	}
+
+	token := "secret-token"
+	env := authGitEnv(&token)
+	want := "GIT_CONFIG_VALUE_0=Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte("x-access-token:secret-token"))
+	if !slices.Contains(env, "GIT_CONFIG_KEY_0=http.extraHeader") || !slices.Contains(env, want) {
+		t.Errorf("expected an http.extraHeader authorization, got %v", env)
+	}
+	for _, v := range env {
+		if strings.Contains(v, token) {
+			t.Errorf("expected the token to be encoded, got %q", v)
+		}
+	}
+}
+
+func TestGitVCS_ListRemoteTags(t *testing.T) {
+	repo := newTestRepo(t)
+	first := repo.commit("2024-01-01T00:00:00Z", "alice", "a.txt", "a")
+	repo.tag("v1.0.0")
+	second := repo.commit("2024-02-01T00:00:00Z", "alice", "a.txt", "b")
+	repo.git("2024-02-01T00:00:00Z", "alice", "tag", "-a", "v1.1.0", "-m", "release")
+
+	tags, err := NewGitVCS().ListRemoteTags(context.Background(), repo.url(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{"v1.0.0": first, "v1.1.0": second}
+	if len(tags) != len(want) {
+		t.Fatalf("expected %d tags, got %v", len(want), tags)
+	}
+	for _, tag := range tags {
+		if want[tag.Name] != tag.SHA {
+			t.Errorf("tag %s: expected %s, got %s", tag.Name, want[tag.Name], tag.SHA)
+		}
+	}
+}
+
 func TestGitSourceAdapter_Interface(t *testing.T) {
 	// This test verifies that gitSourceAdapter implements the expected methods
 	// without needing an actual GitSource (compile-time check)
@@ -97,9 +180,42 @@ func TestGitSourceAdapter_Interface(t *testing.T) {
 	// These calls will panic if called on nil, but we're just checking compilation
 	// analysis.Source methods
 	_ = func() string { return adapter.Branch() }
+	_ = func() error { return adapter.Checkout(context.Background(), "sha") }
 	_ = func() string { return adapter.CommitSHA() }
 	_ = func() error { return adapter.Close(context.Background()) }
 	_ = func() (bool, error) { return adapter.VerifyCommitExists(context.Background(), "sha") }
 	// coreSourceProvider method
 	_ = func() interface{} { return adapter.CoreSource() }
 }
+
+func TestGitSourceAdapter_Checkout(t *testing.T) {
+	repo := newTestRepo(t)
+	old := repo.commit("2024-01-01T00:00:00Z", "alice", "a_test.go", "package a\n")
+	repo.commit("2024-02-01T00:00:00Z", "alice", "b_test.go", "package a\n")
+
+	ctx := context.Background()
+	src, err := NewGitVCS().Clone(ctx, repo.url(), nil)
+	if err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	defer src.Close(ctx)
+
+	if err := src.Checkout(ctx, old); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if src.CommitSHA() != old {
+		t.Errorf("expected commit %s, got %s", old, src.CommitSHA())
+	}
+	if !src.CommittedAt().Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected committed at of the checked-out commit, got %v", src.CommittedAt())
+	}
+
+	root := src.(*gitSourceAdapter).Root()
+	if _, err := os.Stat(filepath.Join(root, "b_test.go")); !os.IsNotExist(err) {
+		t.Errorf("expected b_test.go to be absent at %s", old)
+	}
+
+	if err := src.Checkout(ctx, "0000000000000000000000000000000000000000"); err == nil {
+		t.Error("expected error for unknown commit")
+	}
+}
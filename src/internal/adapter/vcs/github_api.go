@@ -2,10 +2,15 @@ package vcs
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/specvital/collector/internal/domain/analysis"
 )
@@ -13,11 +18,38 @@ import (
 const (
 	gitHubHost    = "github.com"
 	gitHubAPIBase = "https://api.github.com"
+
+	noLicenseAssertion = "NOASSERTION"
+
+	// maxCachedRepos bounds the ETag cache; an arbitrary entry is evicted when it is full.
+	maxCachedRepos = 1000
+	// secondaryRateLimitWait is GitHub's documented minimum wait when a secondary rate limit
+	// response carries neither Retry-After nor a reset time.
+	secondaryRateLimitWait = time.Minute
 )
 
+// GitHubAPIClient tracks the primary rate limit of each token and refuses further calls
+// until the window resets, returning *analysis.RateLimitError. Repository metadata is
+// revalidated with If-None-Match so unchanged repositories are answered from cache;
+// GitHub does not count 304 responses against the rate limit.
 type GitHubAPIClient struct {
 	apiBase    string
 	httpClient *http.Client
+	mu         sync.Mutex
+	now        func() time.Time
+	rateLimits map[string]rateLimit
+	repoCache  map[string]cachedRepoInfo
+	tokenCache map[string]cachedTokenInfo
+}
+
+type cachedRepoInfo struct {
+	etag string
+	info analysis.RepoInfo
+}
+
+type rateLimit struct {
+	remaining int
+	resetAt   time.Time
 }
 
 var _ analysis.VCSAPIClient = (*GitHubAPIClient)(nil)
@@ -29,6 +61,10 @@ func NewGitHubAPIClient(httpClient *http.Client) *GitHubAPIClient {
 	return &GitHubAPIClient{
 		apiBase:    gitHubAPIBase,
 		httpClient: httpClient,
+		now:        time.Now,
+		rateLimits: make(map[string]rateLimit),
+		repoCache:  make(map[string]cachedRepoInfo),
+		tokenCache: make(map[string]cachedTokenInfo),
 	}
 }
 
@@ -43,7 +79,13 @@ func (c *GitHubAPIClient) GetRepoInfo(ctx context.Context, host, owner, repo str
 		return analysis.RepoInfo{}, fmt.Errorf("%w: repo is required", analysis.ErrInvalidInput)
 	}
 
+	tokenKey := rateLimitKey(token)
+	if err := c.checkRateLimit(tokenKey); err != nil {
+		return analysis.RepoInfo{}, fmt.Errorf("get repository %s/%s: %w", owner, repo, err)
+	}
+
 	url := fmt.Sprintf("%s/repos/%s/%s", c.apiBase, owner, repo)
+	cacheKey := tokenKey + "/" + strings.ToLower(owner+"/"+repo)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -55,6 +97,10 @@ func (c *GitHubAPIClient) GetRepoInfo(ctx context.Context, host, owner, repo str
 	if token != nil && *token != "" {
 		req.Header.Set("Authorization", "Bearer "+*token)
 	}
+	cached, hasCached := c.cachedRepo(cacheKey)
+	if hasCached {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -62,27 +108,164 @@ func (c *GitHubAPIClient) GetRepoInfo(ctx context.Context, host, owner, repo str
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
+	c.recordRateLimit(tokenKey, resp.Header)
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && hasCached:
+		return cached.info, nil
+	case resp.StatusCode == http.StatusNotFound:
+		c.evictRepo(cacheKey)
 		return analysis.RepoInfo{}, fmt.Errorf("%w: %s/%s", analysis.ErrRepoNotFound, owner, repo)
-	}
-	if resp.StatusCode != http.StatusOK {
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests:
+		if resetAt, limited := c.rateLimitReset(resp); limited {
+			return analysis.RepoInfo{}, fmt.Errorf("get repository %s/%s: %w", owner, repo, &analysis.RateLimitError{ResetAt: resetAt})
+		}
+		return analysis.RepoInfo{}, fmt.Errorf("get repository %s/%s: unexpected status %d", owner, repo, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
 		return analysis.RepoInfo{}, fmt.Errorf("get repository %s/%s: unexpected status %d", owner, repo, resp.StatusCode)
 	}
 
 	var result struct {
-		ID    int64  `json:"id"`
+		Archived      bool   `json:"archived"`
+		DefaultBranch string `json:"default_branch"`
+		Disabled      bool   `json:"disabled"`
+		Fork          bool   `json:"fork"`
+		ID            int64  `json:"id"`
+		Language      string `json:"language"`
+		License       *struct {
+			SPDXID string `json:"spdx_id"`
+		} `json:"license"`
 		Name  string `json:"name"`
 		Owner struct {
 			Login string `json:"login"`
 		} `json:"owner"`
+		Parent *struct {
+			FullName string `json:"full_name"`
+			ID       int64  `json:"id"`
+		} `json:"parent"`
+		PushedAt        *time.Time `json:"pushed_at"`
+		Size            int64      `json:"size"`
+		StargazersCount int        `json:"stargazers_count"`
+		Topics          []string   `json:"topics"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return analysis.RepoInfo{}, fmt.Errorf("decode response: %w", err)
 	}
 
-	return analysis.RepoInfo{
+	info := analysis.RepoInfo{
+		DefaultBranch:  result.DefaultBranch,
 		ExternalRepoID: strconv.FormatInt(result.ID, 10),
+		IsArchived:     result.Archived,
+		IsDisabled:     result.Disabled,
+		IsFork:         result.Fork,
+		Language:       result.Language,
 		Name:           result.Name,
 		Owner:          result.Owner.Login,
-	}, nil
+		PushedAt:       result.PushedAt,
+		SizeKB:         result.Size,
+		Stars:          result.StargazersCount,
+		Topics:         result.Topics,
+	}
+	// GitHub reports NOASSERTION for license files it cannot classify.
+	if result.License != nil && result.License.SPDXID != noLicenseAssertion {
+		info.License = result.License.SPDXID
+	}
+	if result.Parent != nil {
+		info.ParentExternalRepoID = strconv.FormatInt(result.Parent.ID, 10)
+		info.ParentFullName = result.Parent.FullName
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		c.cacheRepo(cacheKey, cachedRepoInfo{etag: etag, info: info})
+	}
+	return info, nil
+}
+
+// rateLimitKey identifies the rate limit bucket of a token without keeping the token itself as a key.
+// Unauthenticated requests share the per-IP bucket.
+func rateLimitKey(token *string) string {
+	if token == nil || *token == "" {
+		return "anonymous"
+	}
+	sum := sha256.Sum256([]byte(*token))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (c *GitHubAPIClient) checkRateLimit(tokenKey string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	limit, ok := c.rateLimits[tokenKey]
+	if !ok || limit.remaining > 0 {
+		return nil
+	}
+	if !c.now().Before(limit.resetAt) {
+		delete(c.rateLimits, tokenKey)
+		return nil
+	}
+	return &analysis.RateLimitError{ResetAt: limit.resetAt}
+}
+
+func (c *GitHubAPIClient) recordRateLimit(tokenKey string, header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	resetAt, ok := parseRateLimitReset(header)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	c.rateLimits[tokenKey] = rateLimit{remaining: remaining, resetAt: resetAt}
+	c.mu.Unlock()
+}
+
+// rateLimitReset distinguishes rate limit responses from permission errors, which share status 403.
+// Retry-After takes precedence as it is what secondary rate limits send.
+func (c *GitHubAPIClient) rateLimitReset(resp *http.Response) (time.Time, bool) {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		return c.now().Add(time.Duration(seconds) * time.Second), true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if resetAt, ok := parseRateLimitReset(resp.Header); ok {
+			return resetAt, true
+		}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return c.now().Add(secondaryRateLimitWait), true
+	}
+	return time.Time{}, false
+}
+
+func parseRateLimitReset(header http.Header) (time.Time, bool) {
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(reset, 0), true
+}
+
+func (c *GitHubAPIClient) cachedRepo(key string) (cachedRepoInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.repoCache[key]
+	return cached, ok
+}
+
+func (c *GitHubAPIClient) cacheRepo(key string, entry cachedRepoInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.repoCache[key]; !ok && len(c.repoCache) >= maxCachedRepos {
+		for k := range c.repoCache {
+			delete(c.repoCache, k)
+			break
+		}
+	}
+	c.repoCache[key] = entry
+}
+
+func (c *GitHubAPIClient) evictRepo(key string) {
+	c.mu.Lock()
+	delete(c.repoCache, key)
+	c.mu.Unlock()
 }
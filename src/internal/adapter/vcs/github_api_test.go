@@ -5,8 +5,10 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/specvital/collector/internal/domain/analysis"
 )
@@ -210,9 +212,239 @@ func TestGitHubAPIClient_GetRepoInfo(t *testing.T) {
 	})
 }
 
-func newTestClient(server *httptest.Server) *GitHubAPIClient {
-	return &GitHubAPIClient{
-		apiBase:    server.URL,
-		httpClient: server.Client(),
+func TestGitHubAPIClient_GetRepoInfo_Metadata(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want func(t *testing.T, info analysis.RepoInfo)
+	}{
+		{
+			name: "archived fork with license and topics",
+			body: `{
+				"id": 7, "name": "fork", "owner": {"login": "me"},
+				"default_branch": "develop", "archived": true, "disabled": false, "fork": true,
+				"parent": {"id": 99, "full_name": "upstream/project"},
+				"language": "Go", "license": {"spdx_id": "Apache-2.0"},
+				"size": 1234, "stargazers_count": 56, "topics": ["cli", "testing"],
+				"pushed_at": "2025-02-03T04:05:06Z"
+			}`,
+			want: func(t *testing.T, info analysis.RepoInfo) {
+				if info.DefaultBranch != "develop" || !info.IsArchived || info.IsDisabled || !info.IsFork {
+					t.Errorf("unexpected lifecycle fields: %+v", info)
+				}
+				if info.ParentFullName != "upstream/project" || info.ParentExternalRepoID != "99" {
+					t.Errorf("expected parent 99 upstream/project, got %q %q", info.ParentExternalRepoID, info.ParentFullName)
+				}
+				if info.Language != "Go" || info.License != "Apache-2.0" {
+					t.Errorf("unexpected language/license: %q %q", info.Language, info.License)
+				}
+				if info.SizeKB != 1234 || info.Stars != 56 {
+					t.Errorf("unexpected size/stars: %d %d", info.SizeKB, info.Stars)
+				}
+				if len(info.Topics) != 2 || info.Topics[0] != "cli" {
+					t.Errorf("unexpected topics: %v", info.Topics)
+				}
+				want := time.Date(2025, 2, 3, 4, 5, 6, 0, time.UTC)
+				if info.PushedAt == nil || !info.PushedAt.Equal(want) {
+					t.Errorf("expected pushed_at %v, got %v", want, info.PushedAt)
+				}
+			},
+		},
+		{
+			name: "empty repository with unclassified license",
+			body: `{
+				"id": 8, "name": "empty", "owner": {"login": "me"},
+				"language": null, "license": {"spdx_id": "NOASSERTION"}, "pushed_at": null, "parent": null
+			}`,
+			want: func(t *testing.T, info analysis.RepoInfo) {
+				if info.Language != "" || info.License != "" || info.ParentFullName != "" || info.ParentExternalRepoID != "" {
+					t.Errorf("expected empty language/license/parent, got %+v", info)
+				}
+				if info.PushedAt != nil {
+					t.Errorf("expected nil pushed_at, got %v", info.PushedAt)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			info, err := newTestClient(server).GetRepoInfo(context.Background(), "github.com", "me", "repo", nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.want(t, info)
+		})
+	}
+}
+
+func TestGitHubAPIClient_GetRepoInfo_ConditionalRequest(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if requests > 1 {
+			t.Errorf("expected If-None-Match on request %d", requests)
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": 42, "name": "repo", "owner": {"login": "owner"}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	for range 2 {
+		info, err := client.GetRepoInfo(context.Background(), "github.com", "owner", "repo", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.ExternalRepoID != "42" || info.Owner != "owner" || info.Name != "repo" {
+			t.Errorf("unexpected repo info: %+v", info)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+
+	t.Run("cache is scoped per token", func(t *testing.T) {
+		token := "other-token"
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") != "" {
+				t.Error("expected no If-None-Match for a different token")
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": 42, "name": "repo", "owner": {"login": "owner"}}`))
+		})
+
+		if _, err := client.GetRepoInfo(context.Background(), "github.com", "owner", "repo", &token); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestGitHubAPIClient_GetRepoInfo_RateLimit(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	reset := now.Add(10 * time.Minute)
+
+	tests := []struct {
+		name        string
+		header      map[string]string
+		status      int
+		wantLimited bool
+		wantResetAt time.Time
+	}{
+		{
+			name:        "primary rate limit exhausted",
+			status:      http.StatusForbidden,
+			header:      map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(reset.Unix(), 10)},
+			wantLimited: true,
+			wantResetAt: reset,
+		},
+		{
+			name:        "secondary rate limit with Retry-After",
+			status:      http.StatusForbidden,
+			header:      map[string]string{"Retry-After": "30", "X-RateLimit-Remaining": "12"},
+			wantLimited: true,
+			wantResetAt: now.Add(30 * time.Second),
+		},
+		{
+			name:        "too many requests without headers",
+			status:      http.StatusTooManyRequests,
+			wantLimited: true,
+			wantResetAt: now.Add(secondaryRateLimitWait),
+		},
+		{
+			name:   "forbidden without rate limit headers",
+			status: http.StatusForbidden,
+			header: map[string]string{"X-RateLimit-Remaining": "4999"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := newTestClient(server)
+			client.now = func() time.Time { return now }
+
+			_, err := client.GetRepoInfo(context.Background(), "github.com", "owner", "repo", nil)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			var rateLimitErr *analysis.RateLimitError
+			if !tt.wantLimited {
+				if errors.As(err, &rateLimitErr) {
+					t.Errorf("expected plain error, got %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &rateLimitErr) || !errors.Is(err, analysis.ErrRateLimited) {
+				t.Fatalf("expected RateLimitError, got %v", err)
+			}
+			if !rateLimitErr.ResetAt.Equal(tt.wantResetAt) {
+				t.Errorf("expected reset at %v, got %v", tt.wantResetAt, rateLimitErr.ResetAt)
+			}
+		})
 	}
+
+	t.Run("exhausted token fails fast until reset", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests++
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": 1, "name": "repo", "owner": {"login": "owner"}}`))
+		}))
+		defer server.Close()
+
+		client := newTestClient(server)
+		client.now = func() time.Time { return now }
+		token := "exhausted"
+		other := "fresh"
+
+		if _, err := client.GetRepoInfo(context.Background(), "github.com", "owner", "repo", &token); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := client.GetRepoInfo(context.Background(), "github.com", "owner", "repo", &token); !errors.Is(err, analysis.ErrRateLimited) {
+			t.Errorf("expected ErrRateLimited, got %v", err)
+		}
+		if requests != 1 {
+			t.Errorf("expected no request while rate limited, got %d requests", requests)
+		}
+
+		if _, err := client.GetRepoInfo(context.Background(), "github.com", "owner", "repo", &other); err != nil {
+			t.Errorf("expected other token to be unaffected, got %v", err)
+		}
+
+		now = reset.Add(time.Second)
+		if _, err := client.GetRepoInfo(context.Background(), "github.com", "owner", "repo", &token); err != nil {
+			t.Errorf("expected request after reset to succeed, got %v", err)
+		}
+		if requests != 3 {
+			t.Errorf("expected 3 requests, got %d", requests)
+		}
+	})
+}
+
+func newTestClient(server *httptest.Server) *GitHubAPIClient {
+	client := NewGitHubAPIClient(server.Client())
+	client.apiBase = server.URL
+	return client
 }
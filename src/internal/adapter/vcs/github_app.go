@@ -0,0 +1,235 @@
+package vcs
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+const (
+	// appJWTLifetime stays below the 10 minute maximum GitHub accepts for app JWTs.
+	appJWTLifetime = 9 * time.Minute
+	// appJWTClockSkew backdates the issue time to tolerate clock drift against GitHub.
+	appJWTClockSkew = 60 * time.Second
+	// installationTokenRefreshMargin renews cached tokens before they expire mid-clone.
+	installationTokenRefreshMargin = 5 * time.Minute
+	// maxCachedInstallationTokens bounds the token cache; an arbitrary entry is evicted when it
+	// is full of unexpired tokens.
+	maxCachedInstallationTokens = 1000
+)
+
+var _ analysis.InstallationTokenProvider = (*GitHubAppTokenProvider)(nil)
+
+// GitHubAppTokenProvider exchanges GitHub App JWTs for installation access tokens
+// scoped to a single repository. Tokens are cached per installation and repository until shortly before expiry.
+type GitHubAppTokenProvider struct {
+	apiBase       string
+	appID         int64
+	httpClient    *http.Client
+	installations analysis.InstallationRepository
+	mu            sync.Mutex
+	now           func() time.Time
+	privateKey    *rsa.PrivateKey
+	tokens        map[string]installationToken
+}
+
+type installationToken struct {
+	expiresAt time.Time
+	token     string
+}
+
+// NewGitHubAppTokenProvider parses the app's PEM-encoded RSA private key (PKCS#1 or PKCS#8).
+func NewGitHubAppTokenProvider(
+	appID int64,
+	privateKeyPEM []byte,
+	installations analysis.InstallationRepository,
+	httpClient *http.Client,
+) (*GitHubAppTokenProvider, error) {
+	if appID <= 0 {
+		return nil, fmt.Errorf("%w: app ID is required", analysis.ErrInvalidInput)
+	}
+	if installations == nil {
+		return nil, fmt.Errorf("%w: installation repository is required", analysis.ErrInvalidInput)
+	}
+	key, err := parseRSAPrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &GitHubAppTokenProvider{
+		apiBase:       gitHubAPIBase,
+		appID:         appID,
+		httpClient:    httpClient,
+		installations: installations,
+		now:           time.Now,
+		privateKey:    key,
+		tokens:        make(map[string]installationToken),
+	}, nil
+}
+
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: private key is not PEM encoded", analysis.ErrInvalidInput)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: parse private key: %w", analysis.ErrInvalidInput, err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: private key is not an RSA key", analysis.ErrInvalidInput)
+	}
+	return key, nil
+}
+
+// GetInstallationToken returns analysis.ErrTokenNotFound when the app is not installed on the owner's account
+// or the installation does not include the repository.
+func (p *GitHubAppTokenProvider) GetInstallationToken(ctx context.Context, owner, repo string) (string, error) {
+	if owner == "" || repo == "" {
+		return "", fmt.Errorf("%w: owner and repo are required", analysis.ErrInvalidInput)
+	}
+
+	installationID, err := p.installations.FindInstallationID(ctx, owner)
+	if err != nil {
+		if errors.Is(err, analysis.ErrInstallationNotFound) {
+			return "", fmt.Errorf("%w: no GitHub App installation for %s", analysis.ErrTokenNotFound, owner)
+		}
+		return "", fmt.Errorf("find installation for %s: %w", owner, err)
+	}
+
+	key := strconv.FormatInt(installationID, 10) + "/" + strings.ToLower(repo)
+	p.mu.Lock()
+	cached, ok := p.tokens[key]
+	p.mu.Unlock()
+	if ok && p.now().Add(installationTokenRefreshMargin).Before(cached.expiresAt) {
+		return cached.token, nil
+	}
+
+	token, err := p.createInstallationToken(ctx, installationID, owner, repo)
+	if err != nil {
+		return "", err
+	}
+
+	p.cacheToken(key, token)
+	return token.token, nil
+}
+
+// cacheToken drops expired tokens before adding one, so repositories that are no longer
+// accessed do not stay cached.
+func (p *GitHubAppTokenProvider) cacheToken(key string, token installationToken) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	for k, cached := range p.tokens {
+		if !now.Before(cached.expiresAt) {
+			delete(p.tokens, k)
+		}
+	}
+	if _, ok := p.tokens[key]; !ok && len(p.tokens) >= maxCachedInstallationTokens {
+		for k := range p.tokens {
+			delete(p.tokens, k)
+			break
+		}
+	}
+	p.tokens[key] = token
+}
+
+func (p *GitHubAppTokenProvider) createInstallationToken(ctx context.Context, installationID int64, owner, repo string) (installationToken, error) {
+	jwt, err := p.signJWT()
+	if err != nil {
+		return installationToken{}, err
+	}
+
+	body, err := json.Marshal(map[string][]string{"repositories": {repo}})
+	if err != nil {
+		return installationToken{}, fmt.Errorf("encode token request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", p.apiBase, installationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return installationToken{}, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return installationToken{}, fmt.Errorf("create installation token for %s/%s: %w", owner, repo, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+	case http.StatusNotFound, http.StatusUnprocessableEntity:
+		// The installation was removed, or it does not include the repository.
+		return installationToken{}, fmt.Errorf("%w: GitHub App installation %d cannot access %s/%s",
+			analysis.ErrTokenNotFound, installationID, owner, repo)
+	default:
+		return installationToken{}, fmt.Errorf("create installation token for %s/%s: unexpected status %d",
+			owner, repo, resp.StatusCode)
+	}
+
+	var result struct {
+		ExpiresAt time.Time `json:"expires_at"`
+		Token     string    `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return installationToken{}, fmt.Errorf("decode response: %w", err)
+	}
+	if result.Token == "" {
+		return installationToken{}, fmt.Errorf("create installation token for %s/%s: empty token", owner, repo)
+	}
+
+	return installationToken{expiresAt: result.ExpiresAt, token: result.Token}, nil
+}
+
+// signJWT creates the RS256 JWT authenticating as the app itself.
+func (p *GitHubAppTokenProvider) signJWT() (string, error) {
+	now := p.now()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("encode JWT header: %w", err)
+	}
+	claims, err := json.Marshal(map[string]any{
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iat": now.Add(-appJWTClockSkew).Unix(),
+		"iss": strconv.FormatInt(p.appID, 10),
+	})
+	if err != nil {
+		return "", fmt.Errorf("encode JWT claims: %w", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign JWT: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,253 @@
+package vcs
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+type mockInstallationRepository struct {
+	findInstallationIDFn func(ctx context.Context, accountLogin string) (int64, error)
+}
+
+func (m *mockInstallationRepository) FindInstallationID(ctx context.Context, accountLogin string) (int64, error) {
+	return m.findInstallationIDFn(ctx, accountLogin)
+}
+
+func newTestAppKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func newTestAppTokenProvider(t *testing.T, server *httptest.Server, keyPEM []byte, installationID int64) *GitHubAppTokenProvider {
+	t.Helper()
+	installations := &mockInstallationRepository{
+		findInstallationIDFn: func(ctx context.Context, accountLogin string) (int64, error) {
+			if installationID == 0 {
+				return 0, analysis.ErrInstallationNotFound
+			}
+			return installationID, nil
+		},
+	}
+	provider, err := NewGitHubAppTokenProvider(42, keyPEM, installations, server.Client())
+	if err != nil {
+		t.Fatalf("NewGitHubAppTokenProvider failed: %v", err)
+	}
+	provider.apiBase = server.URL
+	return provider
+}
+
+// verifyAppJWT checks the RS256 signature and returns the claims.
+func verifyAppJWT(t *testing.T, key *rsa.PublicKey, authorization string) map[string]any {
+	t.Helper()
+	jwt, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		t.Fatalf("unexpected Authorization header: %s", authorization)
+	}
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 JWT parts, got %d", len(parts))
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("decode signature: %v", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		t.Fatalf("invalid JWT signature: %v", err)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("decode claims: %v", err)
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("unmarshal claims: %v", err)
+	}
+	return claims
+}
+
+func TestGitHubAppTokenProvider_GetInstallationToken(t *testing.T) {
+	key, keyPEM := newTestAppKey(t)
+
+	t.Run("exchanges a signed JWT and caches the token until expiry", func(t *testing.T) {
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.Method != http.MethodPost || r.URL.Path != "/app/installations/7/access_tokens" {
+				t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			}
+			claims := verifyAppJWT(t, &key.PublicKey, r.Header.Get("Authorization"))
+			if claims["iss"] != "42" {
+				t.Errorf("expected iss 42, got %v", claims["iss"])
+			}
+			if exp := int64(claims["exp"].(float64)); exp > now.Add(10*time.Minute).Unix() {
+				t.Errorf("JWT expires too late: %d", exp)
+			}
+
+			var body struct {
+				Repositories []string `json:"repositories"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if len(body.Repositories) != 1 || body.Repositories[0] != "private-repo" {
+				t.Errorf("expected token scoped to private-repo, got %v", body.Repositories)
+			}
+
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token": "ghs_%d", "expires_at": %q}`, requests, now.Add(time.Hour).Format(time.RFC3339))
+		}))
+		defer server.Close()
+
+		provider := newTestAppTokenProvider(t, server, keyPEM, 7)
+		provider.now = func() time.Time { return now }
+
+		for range 2 {
+			token, err := provider.GetInstallationToken(context.Background(), "acme", "private-repo")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if token != "ghs_1" {
+				t.Errorf("expected cached token ghs_1, got %s", token)
+			}
+		}
+		if requests != 1 {
+			t.Errorf("expected 1 token request, got %d", requests)
+		}
+
+		now = now.Add(56 * time.Minute)
+		token, err := provider.GetInstallationToken(context.Background(), "acme", "private-repo")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if token != "ghs_2" {
+			t.Errorf("expected renewed token ghs_2, got %s", token)
+		}
+	})
+
+	t.Run("cache drops expired tokens and stays bounded", func(t *testing.T) {
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token": "ghs_token", "expires_at": %q}`, now.Add(time.Hour).Format(time.RFC3339))
+		}))
+		defer server.Close()
+
+		provider := newTestAppTokenProvider(t, server, keyPEM, 7)
+		provider.now = func() time.Time { return now }
+
+		for i := range maxCachedInstallationTokens + 1 {
+			if _, err := provider.GetInstallationToken(context.Background(), "acme", fmt.Sprintf("repo-%d", i)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if len(provider.tokens) != maxCachedInstallationTokens {
+			t.Errorf("expected %d cached tokens, got %d", maxCachedInstallationTokens, len(provider.tokens))
+		}
+
+		now = now.Add(2 * time.Hour)
+		if _, err := provider.GetInstallationToken(context.Background(), "acme", "fresh-repo"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(provider.tokens) != 1 {
+			t.Errorf("expected expired tokens to be dropped, got %d cached", len(provider.tokens))
+		}
+	})
+
+	t.Run("no installation returns ErrTokenNotFound without calling GitHub", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected request")
+		}))
+		defer server.Close()
+
+		provider := newTestAppTokenProvider(t, server, keyPEM, 0)
+
+		_, err := provider.GetInstallationToken(context.Background(), "acme", "repo")
+		if !errors.Is(err, analysis.ErrTokenNotFound) {
+			t.Errorf("expected ErrTokenNotFound, got %v", err)
+		}
+	})
+
+	t.Run("repository outside installation returns ErrTokenNotFound", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}))
+		defer server.Close()
+
+		provider := newTestAppTokenProvider(t, server, keyPEM, 7)
+
+		_, err := provider.GetInstallationToken(context.Background(), "acme", "other-repo")
+		if !errors.Is(err, analysis.ErrTokenNotFound) {
+			t.Errorf("expected ErrTokenNotFound, got %v", err)
+		}
+	})
+
+	t.Run("server error is an infrastructure error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		provider := newTestAppTokenProvider(t, server, keyPEM, 7)
+
+		_, err := provider.GetInstallationToken(context.Background(), "acme", "repo")
+		if err == nil || errors.Is(err, analysis.ErrTokenNotFound) {
+			t.Errorf("expected infrastructure error, got %v", err)
+		}
+	})
+}
+
+func TestNewGitHubAppTokenProvider(t *testing.T) {
+	key, pkcs1PEM := newTestAppKey(t)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal PKCS#8: %v", err)
+	}
+	pkcs8PEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})
+	installations := &mockInstallationRepository{}
+
+	tests := []struct {
+		name    string
+		appID   int64
+		key     []byte
+		wantErr bool
+	}{
+		{name: "PKCS#1 key", appID: 1, key: pkcs1PEM},
+		{name: "PKCS#8 key", appID: 1, key: pkcs8PEM},
+		{name: "missing app ID", key: pkcs1PEM, wantErr: true},
+		{name: "not PEM", appID: 1, key: []byte("not a key"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGitHubAppTokenProvider(tt.appID, tt.key, installations, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, analysis.ErrInvalidInput) {
+				t.Errorf("expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package vcs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+const (
+	// tokenVerificationTTL bounds how long a verification result is reused. A revoked token
+	// is therefore noticed within this window even while it stays cached as valid.
+	tokenVerificationTTL = 10 * time.Minute
+	// maxCachedTokens bounds the verification cache; an arbitrary entry is evicted when it is full.
+	maxCachedTokens = 1000
+)
+
+type cachedTokenInfo struct {
+	err       error
+	expiresAt time.Time
+	info      analysis.TokenInfo
+}
+
+var _ analysis.TokenVerifier = (*GitHubAPIClient)(nil)
+
+// VerifyToken asks GitHub for the authenticated user and reads the granted scopes from
+// X-OAuth-Scopes. Both valid and rejected tokens are cached for tokenVerificationTTL.
+func (c *GitHubAPIClient) VerifyToken(ctx context.Context, token string) (analysis.TokenInfo, error) {
+	if token == "" {
+		return analysis.TokenInfo{}, fmt.Errorf("%w: token is required", analysis.ErrInvalidInput)
+	}
+
+	tokenKey := rateLimitKey(&token)
+	if cached, ok := c.cachedToken(tokenKey); ok {
+		return cached.info, cached.err
+	}
+	if err := c.checkRateLimit(tokenKey); err != nil {
+		return analysis.TokenInfo{}, fmt.Errorf("verify token: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiBase+"/user", nil)
+	if err != nil {
+		return analysis.TokenInfo{}, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return analysis.TokenInfo{}, fmt.Errorf("verify token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	c.recordRateLimit(tokenKey, resp.Header)
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		err := fmt.Errorf("verify token: %w", analysis.ErrTokenInvalid)
+		c.cacheToken(tokenKey, cachedTokenInfo{err: err, expiresAt: c.now().Add(tokenVerificationTTL)})
+		return analysis.TokenInfo{}, err
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests:
+		if resetAt, limited := c.rateLimitReset(resp); limited {
+			return analysis.TokenInfo{}, fmt.Errorf("verify token: %w", &analysis.RateLimitError{ResetAt: resetAt})
+		}
+		return analysis.TokenInfo{}, fmt.Errorf("verify token: unexpected status %d", resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return analysis.TokenInfo{}, fmt.Errorf("verify token: unexpected status %d", resp.StatusCode)
+	}
+
+	info := analysis.TokenInfo{Scopes: parseScopes(resp.Header)}
+	c.cacheToken(tokenKey, cachedTokenInfo{expiresAt: c.now().Add(tokenVerificationTTL), info: info})
+	return info, nil
+}
+
+// parseScopes returns nil when GitHub sends no X-OAuth-Scopes header, as for fine-grained tokens,
+// and an empty slice for a classic token without scopes.
+func parseScopes(header http.Header) []string {
+	values, ok := header[http.CanonicalHeaderKey("X-OAuth-Scopes")]
+	if !ok {
+		return nil
+	}
+	scopes := []string{}
+	for _, value := range values {
+		for _, scope := range strings.Split(value, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+func (c *GitHubAPIClient) cachedToken(key string) (cachedTokenInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.tokenCache[key]
+	if !ok || !c.now().Before(cached.expiresAt) {
+		return cachedTokenInfo{}, false
+	}
+	return cached, true
+}
+
+func (c *GitHubAPIClient) cacheToken(key string, entry cachedTokenInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.tokenCache[key]; !ok && len(c.tokenCache) >= maxCachedTokens {
+		for k := range c.tokenCache {
+			delete(c.tokenCache, k)
+			break
+		}
+	}
+	c.tokenCache[key] = entry
+}
@@ -0,0 +1,94 @@
+package vcs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+func TestGitHubAPIClient_VerifyToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		scopes     []string
+		status     int
+		wantErr    error
+		wantScopes []string
+	}{
+		{name: "classic token", scopes: []string{"repo, read:user"}, status: http.StatusOK, wantScopes: []string{"repo", "read:user"}},
+		{name: "classic token without scopes", scopes: []string{""}, status: http.StatusOK, wantScopes: []string{}},
+		{name: "fine-grained token", status: http.StatusOK},
+		{name: "revoked token", status: http.StatusUnauthorized, wantErr: analysis.ErrTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/user" {
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+				if r.Header.Get("Authorization") != "Bearer gho_token" {
+					t.Errorf("unexpected Authorization header: %q", r.Header.Get("Authorization"))
+				}
+				if tt.scopes != nil {
+					w.Header()["X-Oauth-Scopes"] = tt.scopes
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"login": "octocat"}`))
+			}))
+			defer server.Close()
+
+			info, err := newTestClient(server).VerifyToken(context.Background(), "gho_token")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(info.Scopes, tt.wantScopes) {
+				t.Errorf("expected scopes %#v, got %#v", tt.wantScopes, info.Scopes)
+			}
+		})
+	}
+}
+
+func TestGitHubAPIClient_VerifyToken_Cache(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") == "Bearer gho_revoked" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("X-OAuth-Scopes", "repo")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	client.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for range 2 {
+		if _, err := client.VerifyToken(ctx, "gho_valid"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := client.VerifyToken(ctx, "gho_revoked"); !errors.Is(err, analysis.ErrTokenInvalid) {
+			t.Fatalf("expected ErrTokenInvalid, got %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected repeated verifications to be cached, got %d requests", requests)
+	}
+
+	now = now.Add(tokenVerificationTTL)
+	if _, err := client.VerifyToken(ctx, "gho_valid"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("expected expired verification to be repeated, got %d requests", requests)
+	}
+}
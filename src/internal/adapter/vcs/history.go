@@ -0,0 +1,129 @@
+package vcs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+var _ analysis.HistoryReader = (*GitHistoryReader)(nil)
+
+// GitHistoryReader implements analysis.HistoryReader with a treeless bare clone,
+// which downloads commit objects only.
+type GitHistoryReader struct{}
+
+// NewGitHistoryReader creates a new GitHistoryReader.
+func NewGitHistoryReader() *GitHistoryReader {
+	return &GitHistoryReader{}
+}
+
+func (r *GitHistoryReader) ListCommits(ctx context.Context, url string, token *string, since time.Time) ([]analysis.HistoryCommit, error) {
+	dir, cleanup, err := bareClone(ctx, url, token, true)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	args := []string{"log", "--first-parent", "--format=%H%x09%ct"}
+	if !since.IsZero() {
+		args = append(args, "--since="+since.Format(time.RFC3339))
+	}
+	args = append(args, "HEAD")
+
+	out, err := runGit(ctx, dir, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list commits %q: %w", url, err)
+	}
+	return parseHistory(out, since)
+}
+
+func (r *GitHistoryReader) ListTags(ctx context.Context, url string, token *string, since time.Time) ([]analysis.HistoryCommit, error) {
+	dir, cleanup, err := bareClone(ctx, url, token, false)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	out, err := runGit(ctx, dir, "log", "--no-walk=sorted", "--tags", "--decorate-refs=refs/tags/",
+		"--format=%H%x09%ct%x09%D")
+	if err != nil {
+		return nil, fmt.Errorf("list tags %q: %w", url, err)
+	}
+	return parseHistory(out, since)
+}
+
+// bareClone clones without trees or blobs into a temporary directory.
+// singleBranch limits the clone to the default branch; tags need all refs.
+func bareClone(ctx context.Context, url string, token *string, singleBranch bool) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "specvital-history-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("create temp directory: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	args := []string{"clone", "--bare", "--quiet", "--filter=tree:0"}
+	if singleBranch {
+		args = append(args, "--single-branch", "--no-tags")
+	}
+	args = append(args, url, dir)
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = authGitEnv(token)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		cleanup()
+		if ctx.Err() != nil {
+			return "", nil, ctx.Err()
+		}
+		return "", nil, fmt.Errorf("clone history %q: %s: %w", url, strings.TrimSpace(stderr.String()), err)
+	}
+	return dir, cleanup, nil
+}
+
+// parseHistory parses "<sha>\t<unix time>[\t<decorations>]" lines.
+// Decorations such as "tag: v1.0.0, tag: latest" yield the first tag name.
+func parseHistory(out []byte, since time.Time) ([]analysis.HistoryCommit, error) {
+	var commits []analysis.HistoryCommit
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 2 {
+			continue
+		}
+		sec, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse commit time %q: %w", fields[1], err)
+		}
+
+		commit := analysis.HistoryCommit{
+			CommittedAt: time.Unix(sec, 0).UTC(),
+			SHA:         fields[0],
+		}
+		if !since.IsZero() && commit.CommittedAt.Before(since) {
+			continue
+		}
+		if len(fields) > 2 {
+			for _, ref := range strings.Split(fields[2], ", ") {
+				if tag, ok := strings.CutPrefix(ref, "tag: "); ok {
+					commit.Tag = tag
+					break
+				}
+			}
+		}
+		commits = append(commits, commit)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+	return commits, nil
+}
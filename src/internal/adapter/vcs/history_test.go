@@ -0,0 +1,149 @@
+package vcs
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseHistory(t *testing.T) {
+	out := "aaa\t1717200000\ttag: v1.1.0, tag: latest\n" +
+		"bbb\t1704067200\t\n" +
+		"ccc\t1672531200\n"
+
+	commits, err := parseHistory([]byte(out), time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("expected 2 commits after since filter, got %d", len(commits))
+	}
+	if commits[0].Tag != "v1.1.0" {
+		t.Errorf("expected first tag v1.1.0, got %q", commits[0].Tag)
+	}
+	if commits[1].Tag != "" || !commits[1].CommittedAt.Equal(time.Unix(1704067200, 0)) {
+		t.Errorf("unexpected second commit: %+v", commits[1])
+	}
+
+	if _, err := parseHistory([]byte("aaa\tnot-a-time\n"), time.Time{}); err == nil {
+		t.Error("expected error for invalid commit time")
+	}
+}
+
+func TestGitHistoryReader(t *testing.T) {
+	repo := newTestRepo(t)
+	first := repo.commit("2024-01-01T00:00:00Z", "alice", "a_test.go", "package a\n")
+	repo.tag("v1.0.0")
+	second := repo.commit("2024-02-01T00:00:00Z", "alice", "a_test.go", "package a\n\n// b\n")
+	third := repo.commit("2024-03-01T00:00:00Z", "bob", "a_test.go", "package a\n\n// c\n")
+	repo.tag("v1.1.0")
+
+	ctx := context.Background()
+	reader := NewGitHistoryReader()
+
+	t.Run("list commits newest first", func(t *testing.T) {
+		commits, err := reader.ListCommits(ctx, repo.url(), nil, time.Time{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(commits) != 3 || commits[0].SHA != third || commits[2].SHA != first {
+			t.Fatalf("unexpected commits: %+v", commits)
+		}
+	})
+
+	t.Run("list commits since", func(t *testing.T) {
+		commits, err := reader.ListCommits(ctx, repo.url(), nil, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(commits) != 2 || commits[1].SHA != second {
+			t.Fatalf("unexpected commits: %+v", commits)
+		}
+	})
+
+	t.Run("list tags", func(t *testing.T) {
+		tags, err := reader.ListTags(ctx, repo.url(), nil, time.Time{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(tags) != 2 {
+			t.Fatalf("expected 2 tags, got %+v", tags)
+		}
+		if tags[0].Tag != "v1.1.0" || tags[0].SHA != third || tags[1].Tag != "v1.0.0" || tags[1].SHA != first {
+			t.Errorf("unexpected tags: %+v", tags)
+		}
+	})
+
+	t.Run("unknown repository", func(t *testing.T) {
+		_, err := reader.ListCommits(ctx, "file://"+filepath.Join(t.TempDir(), "missing"), nil, time.Time{})
+		if err == nil {
+			t.Fatal("expected error for missing repository")
+		}
+	})
+}
+
+// testRepo is a local git repository served over file:// for clone-based tests.
+type testRepo struct {
+	dir string
+	t   *testing.T
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	r := &testRepo{dir: t.TempDir(), t: t}
+	r.git("2024-01-01T00:00:00Z", "alice", "init", "-q", "-b", "main")
+	// Allow fetching arbitrary commits by SHA, as GitHub does.
+	r.git("2024-01-01T00:00:00Z", "alice", "config", "uploadpack.allowAnySHA1InWant", "true")
+	return r
+}
+
+func (r *testRepo) url() string {
+	return "file://" + r.dir
+}
+
+// commit writes name with content and commits it, returning the commit SHA.
+func (r *testRepo) commit(date, author, name, content string) string {
+	r.t.Helper()
+	path := filepath.Join(r.dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		r.t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		r.t.Fatalf("write: %v", err)
+	}
+	r.git(date, author, "add", ".")
+	r.git(date, author, "commit", "-q", "-m", "update "+name)
+	return r.git(date, author, "rev-parse", "HEAD")
+}
+
+func (r *testRepo) tag(name string) {
+	r.t.Helper()
+	r.git("2024-01-01T00:00:00Z", "alice", "tag", name)
+}
+
+func (r *testRepo) git(date, author string, args ...string) string {
+	r.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME="+author,
+		"GIT_AUTHOR_EMAIL="+author+"@example.com",
+		"GIT_AUTHOR_DATE="+date,
+		"GIT_COMMITTER_NAME="+author,
+		"GIT_COMMITTER_EMAIL="+author+"@example.com",
+		"GIT_COMMITTER_DATE="+date,
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
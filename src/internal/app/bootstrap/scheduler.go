@@ -15,6 +15,7 @@ import (
 
 const (
 	autoRefreshSchedule      = "@every 1h"
+	releaseTagSchedule       = "@every 1h"
 	schedulerShutdownTimeout = 30 * time.Second
 )
 
@@ -22,6 +23,13 @@ type SchedulerConfig struct {
 	ServiceName     string
 	DatabaseURL     string
 	ShutdownTimeout time.Duration
+	// The encryption keys and the GitHub App credentials are optional. They enable auto-refresh of
+	// private codebases through deploy credentials and viewers' OAuth tokens, and through app
+	// installations respectively.
+	EncryptionKey       string
+	EncryptionKeys      string
+	GitHubAppID         int64
+	GitHubAppPrivateKey string
 }
 
 func (c *SchedulerConfig) Validate() error {
@@ -65,7 +73,11 @@ func StartScheduler(cfg SchedulerConfig) error {
 	slog.Info("postgres connected")
 
 	container, err := app.NewSchedulerContainer(ctx, app.ContainerConfig{
-		Pool: pool,
+		EncryptionKey:       cfg.EncryptionKey,
+		EncryptionKeys:      cfg.EncryptionKeys,
+		GitHubAppID:         cfg.GitHubAppID,
+		GitHubAppPrivateKey: cfg.GitHubAppPrivateKey,
+		Pool:                pool,
 	})
 	if err != nil {
 		return fmt.Errorf("container: %w", err)
@@ -82,8 +94,17 @@ func StartScheduler(cfg SchedulerConfig) error {
 		return fmt.Errorf("add auto-refresh schedule: %w", err)
 	}
 
+	if err := container.Scheduler.AddFunc(releaseTagSchedule, func() {
+		container.ReleaseTagHandler.RunWithContext(ctx)
+	}); err != nil {
+		return fmt.Errorf("add release tag schedule: %w", err)
+	}
+
 	container.Scheduler.Start()
-	slog.Info("scheduler started", "schedule", autoRefreshSchedule)
+	slog.Info("scheduler started",
+		"schedule", autoRefreshSchedule,
+		"release_tag_schedule", releaseTagSchedule,
+	)
 
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, syscall.SIGTERM, syscall.SIGINT)
@@ -24,6 +24,13 @@ type WorkerConfig struct {
 	ShutdownTimeout time.Duration
 	DatabaseURL     string
 	EncryptionKey   string
+	// EncryptionKeys lists "<key ID>:<base64 key>" entries for key rotation. Either it or EncryptionKey is required.
+	EncryptionKeys string
+	// ChurnWindow is how far back test file churn is measured. Zero selects the default.
+	ChurnWindow time.Duration
+	// GitHubAppID and GitHubAppPrivateKey enable installation tokens for private repositories. Zero disables them.
+	GitHubAppID         int64
+	GitHubAppPrivateKey string
 }
 
 func (c *WorkerConfig) Validate() error {
@@ -33,7 +40,7 @@ func (c *WorkerConfig) Validate() error {
 	if c.DatabaseURL == "" {
 		return fmt.Errorf("database URL is required")
 	}
-	if c.EncryptionKey == "" {
+	if c.EncryptionKey == "" && c.EncryptionKeys == "" {
 		return fmt.Errorf("encryption key is required")
 	}
 	return nil
@@ -71,8 +78,12 @@ func StartWorker(cfg WorkerConfig) error {
 	slog.Info("postgres connected")
 
 	container, err := app.NewWorkerContainer(ctx, app.ContainerConfig{
-		EncryptionKey: cfg.EncryptionKey,
-		Pool:          pool,
+		ChurnWindow:         cfg.ChurnWindow,
+		EncryptionKey:       cfg.EncryptionKey,
+		EncryptionKeys:      cfg.EncryptionKeys,
+		GitHubAppID:         cfg.GitHubAppID,
+		GitHubAppPrivateKey: cfg.GitHubAppPrivateKey,
+		Pool:                pool,
 	})
 	if err != nil {
 		return fmt.Errorf("container: %w", err)
@@ -3,26 +3,41 @@ package app
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/riverqueue/river"
 	"github.com/specvital/collector/internal/adapter/parser"
 	"github.com/specvital/collector/internal/adapter/queue"
+	"github.com/specvital/collector/internal/adapter/repofs"
+	"github.com/specvital/collector/internal/adapter/report"
 	"github.com/specvital/collector/internal/adapter/repository/postgres"
 	"github.com/specvital/collector/internal/adapter/vcs"
+	"github.com/specvital/collector/internal/domain/analysis"
 	handlerscheduler "github.com/specvital/collector/internal/handler/scheduler"
+	"github.com/specvital/collector/internal/infra/keyring"
 	infraqueue "github.com/specvital/collector/internal/infra/queue"
 	infrascheduler "github.com/specvital/collector/internal/infra/scheduler"
+	"github.com/specvital/collector/internal/usecase/access"
 	uc "github.com/specvital/collector/internal/usecase/analysis"
 	"github.com/specvital/collector/internal/usecase/autorefresh"
-	"github.com/specvital/core/pkg/crypto"
+	"github.com/specvital/collector/internal/usecase/backfill"
+	"github.com/specvital/collector/internal/usecase/ingest"
+	"github.com/specvital/collector/internal/usecase/releasetag"
 )
 
-const schedulerLockKey = "scheduler:auto-refresh:lock"
+const (
+	releaseTagLockKey = "scheduler:release-tags:lock"
+	schedulerLockKey  = "scheduler:auto-refresh:lock"
+)
 
 type ContainerConfig struct {
-	EncryptionKey string
-	Pool          *pgxpool.Pool
+	ChurnWindow         time.Duration
+	EncryptionKey       string
+	EncryptionKeys      string
+	GitHubAppID         int64
+	GitHubAppPrivateKey string
+	Pool                *pgxpool.Pool
 }
 
 func (c ContainerConfig) Validate() error {
@@ -32,20 +47,27 @@ func (c ContainerConfig) Validate() error {
 	return nil
 }
 
+func (c ContainerConfig) hasEncryptionKey() bool {
+	return c.EncryptionKey != "" || c.EncryptionKeys != ""
+}
+
 func (c ContainerConfig) ValidateWorker() error {
 	if err := c.Validate(); err != nil {
 		return err
 	}
-	if c.EncryptionKey == "" {
+	if !c.hasEncryptionKey() {
 		return fmt.Errorf("encryption key is required")
 	}
 	return nil
 }
 
 type WorkerContainer struct {
-	AnalyzeWorker *queue.AnalyzeWorker
-	Workers       *river.Workers
-	QueueClient   *infraqueue.Client
+	AnalyzeWorker        *queue.AnalyzeWorker
+	BackfillWorker       *queue.BackfillWorker
+	IngestCoverageWorker *queue.IngestCoverageWorker
+	IngestResultsWorker  *queue.IngestResultsWorker
+	Workers              *river.Workers
+	QueueClient          *infraqueue.Client
 }
 
 func NewWorkerContainer(ctx context.Context, cfg ContainerConfig) (*WorkerContainer, error) {
@@ -53,32 +75,88 @@ func NewWorkerContainer(ctx context.Context, cfg ContainerConfig) (*WorkerContai
 		return nil, fmt.Errorf("invalid container config: %w", err)
 	}
 
-	encryptor, err := crypto.NewEncryptorFromBase64(cfg.EncryptionKey)
+	encryptor, err := keyring.New(cfg.EncryptionKey, cfg.EncryptionKeys)
 	if err != nil {
-		return nil, fmt.Errorf("create encryptor: %w", err)
+		return nil, fmt.Errorf("create keyring: %w", err)
 	}
 
 	analysisRepo := postgres.NewAnalysisRepository(cfg.Pool)
 	codebaseRepo := postgres.NewCodebaseRepository(cfg.Pool)
+	policyRepo := postgres.NewPolicyRepository(cfg.Pool)
 	userRepo := postgres.NewUserRepository(cfg.Pool, encryptor)
 	gitVCS := vcs.NewGitVCS()
 	githubAPIClient := vcs.NewGitHubAPIClient(nil)
 	coreParser := parser.NewCoreParser()
-	analyzeUC := uc.NewAnalyzeUseCase(analysisRepo, codebaseRepo, gitVCS, githubAPIClient, coreParser, userRepo)
+	deployCredentials := postgres.NewDeployCredentialRepository(cfg.Pool, encryptor)
+	// Analyses and coverage uploads clone within one limit so together they cannot exhaust the worker.
+	cloneLimiter := access.NewCloneLimiter(uc.DefaultMaxConcurrentClones)
+	analyzeOpts := []uc.Option{
+		uc.WithBlamer(vcs.NewGitBlamer()),
+		uc.WithChurnReader(vcs.NewGitChurnReader()),
+		uc.WithChurnWindow(cfg.ChurnWindow),
+		uc.WithCIConfigLoader(repofs.NewCIConfigLoader()),
+		uc.WithCloneLimiter(cloneLimiter),
+		uc.WithCodeownersLoader(repofs.NewCodeownersLoader()),
+		uc.WithDeployCredentials(deployCredentials, gitVCS),
+		uc.WithFileLister(repofs.NewFileLister()),
+		uc.WithLineCounter(repofs.NewLineCounter()),
+		uc.WithPolicyRuleRepository(policyRepo),
+		uc.WithRepoConfigLoader(repofs.NewConfigLoader()),
+		uc.WithTokenVerification(githubAPIClient, userRepo),
+		uc.WithWorkspaceDetector(repofs.NewWorkspaceDetector()),
+	}
+	appTokens, err := newGitHubAppTokenProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	credentialOpts := []access.Option{
+		access.WithDeployCredentials(deployCredentials),
+		access.WithTokenVerification(githubAPIClient, userRepo),
+	}
+	if appTokens != nil {
+		analyzeOpts = append(analyzeOpts, uc.WithInstallationTokenProvider(appTokens))
+		credentialOpts = append(credentialOpts, access.WithInstallationTokenProvider(appTokens))
+	}
+	credentials := access.NewResolver(userRepo, credentialOpts...)
+	analyzeUC := uc.NewAnalyzeUseCase(
+		analysisRepo, codebaseRepo, gitVCS, githubAPIClient, coreParser, userRepo, analyzeOpts...,
+	)
 	analyzeWorker := queue.NewAnalyzeWorker(analyzeUC)
 
-	workers := river.NewWorkers()
-	river.AddWorker(workers, analyzeWorker)
-
 	queueClient, err := infraqueue.NewClient(ctx, cfg.Pool)
 	if err != nil {
 		return nil, fmt.Errorf("create queue client: %w", err)
 	}
 
+	backfillUC := backfill.NewBackfillUseCase(vcs.NewGitHistoryReader(), queueClient, credentials)
+	backfillWorker := queue.NewBackfillWorker(backfillUC)
+
+	ingestUC := ingest.NewIngestResultsUseCase(report.NewParser(), postgres.NewExecutionRepository(cfg.Pool))
+	reportUploads := postgres.NewReportUploadRepository(cfg.Pool)
+	ingestResultsWorker := queue.NewIngestResultsWorker(ingestUC, reportUploads)
+
+	coverageUC := ingest.NewIngestCoverageUseCase(
+		report.NewParser(),
+		postgres.NewCoverageRepository(cfg.Pool),
+		access.NewCloner(gitVCS, gitVCS, cloneLimiter),
+		repofs.NewFileLister(),
+		credentials,
+	)
+	ingestCoverageWorker := queue.NewIngestCoverageWorker(coverageUC, reportUploads)
+
+	workers := river.NewWorkers()
+	river.AddWorker(workers, analyzeWorker)
+	river.AddWorker(workers, backfillWorker)
+	river.AddWorker(workers, ingestCoverageWorker)
+	river.AddWorker(workers, ingestResultsWorker)
+
 	return &WorkerContainer{
-		AnalyzeWorker: analyzeWorker,
-		Workers:       workers,
-		QueueClient:   queueClient,
+		AnalyzeWorker:        analyzeWorker,
+		BackfillWorker:       backfillWorker,
+		IngestCoverageWorker: ingestCoverageWorker,
+		IngestResultsWorker:  ingestResultsWorker,
+		Workers:              workers,
+		QueueClient:          queueClient,
 	}, nil
 }
 
@@ -93,8 +171,10 @@ func (c *WorkerContainer) Close() error {
 
 type SchedulerContainer struct {
 	AutoRefreshHandler *handlerscheduler.AutoRefreshHandler
+	ReleaseTagHandler  *handlerscheduler.ReleaseTagHandler
 	Scheduler          *infrascheduler.Scheduler
 	queueClient        *infraqueue.Client
+	releaseTagLock     *infrascheduler.DistributedLock
 	schedulerLock      *infrascheduler.DistributedLock
 }
 
@@ -112,16 +192,55 @@ func NewSchedulerContainer(ctx context.Context, cfg ContainerConfig) (*Scheduler
 
 	schedulerLock := infrascheduler.NewDistributedLock(cfg.Pool, schedulerLockKey)
 
+	repoAPI := vcs.NewGitHubAPIClient(nil)
+	codebaseRepo := postgres.NewCodebaseRepository(cfg.Pool)
 	gitVCS := vcs.NewGitVCS()
-	autoRefreshUC := autorefresh.NewAutoRefreshUseCase(analysisRepo, queueClient, gitVCS)
+	autoRefreshOpts := []autorefresh.Option{
+		autorefresh.WithMetadataRefresh(repoAPI, codebaseRepo),
+		autorefresh.WithStaleDetection(repoAPI, codebaseRepo, analysisRepo),
+	}
+	var credentialOpts []access.Option
+	var tokenLookup analysis.TokenLookup
+	if cfg.hasEncryptionKey() {
+		encryptor, err := keyring.New(cfg.EncryptionKey, cfg.EncryptionKeys)
+		if err != nil {
+			return nil, fmt.Errorf("create keyring: %w", err)
+		}
+		deployCredentials := postgres.NewDeployCredentialRepository(cfg.Pool, encryptor)
+		tokenLookup = postgres.NewUserRepository(cfg.Pool, encryptor)
+		autoRefreshOpts = append(autoRefreshOpts,
+			autorefresh.WithDeployCredentials(deployCredentials, gitVCS),
+			autorefresh.WithTokenLookup(tokenLookup),
+		)
+		credentialOpts = append(credentialOpts, access.WithDeployCredentials(deployCredentials))
+	}
+	appTokens, err := newGitHubAppTokenProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if appTokens != nil {
+		autoRefreshOpts = append(autoRefreshOpts, autorefresh.WithInstallationTokenProvider(appTokens))
+		credentialOpts = append(credentialOpts, access.WithInstallationTokenProvider(appTokens))
+	}
+
+	autoRefreshUC := autorefresh.NewAutoRefreshUseCase(analysisRepo, queueClient, gitVCS, autoRefreshOpts...)
 	autoRefreshHandler := handlerscheduler.NewAutoRefreshHandler(autoRefreshUC, schedulerLock)
 
+	releaseTagLock := infrascheduler.NewDistributedLock(cfg.Pool, releaseTagLockKey)
+	releaseTagRepo := postgres.NewReleaseTagRepository(cfg.Pool)
+	releaseTagUC := releasetag.NewReleaseTagUseCase(analysisRepo, releaseTagRepo, gitVCS, queueClient,
+		releasetag.WithCredentials(access.NewCodebaseResolver(tokenLookup, credentialOpts...), gitVCS),
+	)
+	releaseTagHandler := handlerscheduler.NewReleaseTagHandler(releaseTagUC, releaseTagLock)
+
 	scheduler := infrascheduler.New()
 
 	return &SchedulerContainer{
 		AutoRefreshHandler: autoRefreshHandler,
+		ReleaseTagHandler:  releaseTagHandler,
 		Scheduler:          scheduler,
 		queueClient:        queueClient,
+		releaseTagLock:     releaseTagLock,
 		schedulerLock:      schedulerLock,
 	}, nil
 }
@@ -135,6 +254,12 @@ func (c *SchedulerContainer) Close() error {
 		}
 	}
 
+	if c.releaseTagLock != nil {
+		if err := c.releaseTagLock.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close release tag lock: %w", err))
+		}
+	}
+
 	if c.queueClient != nil {
 		if err := c.queueClient.Close(); err != nil {
 			errs = append(errs, fmt.Errorf("close queue client: %w", err))
@@ -146,3 +271,17 @@ func (c *SchedulerContainer) Close() error {
 	}
 	return nil
 }
+
+// newGitHubAppTokenProvider returns nil when no GitHub App is configured.
+func newGitHubAppTokenProvider(cfg ContainerConfig) (*vcs.GitHubAppTokenProvider, error) {
+	if cfg.GitHubAppID == 0 {
+		return nil, nil
+	}
+	provider, err := vcs.NewGitHubAppTokenProvider(
+		cfg.GitHubAppID, []byte(cfg.GitHubAppPrivateKey), postgres.NewInstallationRepository(cfg.Pool), nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create GitHub App token provider: %w", err)
+	}
+	return provider, nil
+}
@@ -0,0 +1,98 @@
+package analysis
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+const (
+	// AnomalyTestDropRatio is the share of tests that must disappear between analyses to flag a drop.
+	AnomalyTestDropRatio = 0.2
+	// AnomalySkippedJumpMin is the minimum growth in skipped tests to flag a jump,
+	// which must also at least double the previous count.
+	AnomalySkippedJumpMin = 10
+)
+
+type AnomalyKind string
+
+const (
+	AnomalyKindFrameworkVanished AnomalyKind = "framework_vanished"
+	AnomalyKindSkippedJump       AnomalyKind = "skipped_jump"
+	AnomalyKindTestDrop          AnomalyKind = "test_drop"
+)
+
+// Anomaly is a suspicious change of an analysis against the previous completed analysis
+// of the codebase, typically a parser regression or an accidental mass deletion.
+type Anomaly struct {
+	Current int
+	// Framework is set for AnomalyKindFrameworkVanished.
+	Framework string
+	Kind      AnomalyKind
+	Previous  int
+}
+
+// FrameworkTotals counts the tests of one framework in an analysis.
+type FrameworkTotals struct {
+	Framework string
+	Skipped   int
+	Tests     int
+}
+
+// DetectAnomalies compares the per-framework totals of two analyses.
+// Anomalies are ordered by kind, then framework.
+func DetectAnomalies(previous, current []FrameworkTotals) []Anomaly {
+	prevTests, prevSkipped := sumFrameworkTotals(previous)
+	curTests, curSkipped := sumFrameworkTotals(current)
+
+	var anomalies []Anomaly
+	present := make(map[string]bool, len(current))
+	for _, totals := range current {
+		if totals.Tests > 0 {
+			present[totals.Framework] = true
+		}
+	}
+	for _, totals := range previous {
+		if totals.Framework == "" || totals.Tests == 0 || present[totals.Framework] {
+			continue
+		}
+		anomalies = append(anomalies, Anomaly{
+			Current:   0,
+			Framework: totals.Framework,
+			Kind:      AnomalyKindFrameworkVanished,
+			Previous:  totals.Tests,
+		})
+	}
+
+	if curSkipped-prevSkipped >= AnomalySkippedJumpMin && curSkipped >= 2*prevSkipped {
+		anomalies = append(anomalies, Anomaly{
+			Current:  curSkipped,
+			Kind:     AnomalyKindSkippedJump,
+			Previous: prevSkipped,
+		})
+	}
+
+	if prevTests > 0 && float64(prevTests-curTests) > float64(prevTests)*AnomalyTestDropRatio {
+		anomalies = append(anomalies, Anomaly{
+			Current:  curTests,
+			Kind:     AnomalyKindTestDrop,
+			Previous: prevTests,
+		})
+	}
+
+	slices.SortStableFunc(anomalies, func(a, b Anomaly) int {
+		return cmp.Or(
+			strings.Compare(string(a.Kind), string(b.Kind)),
+			strings.Compare(a.Framework, b.Framework),
+		)
+	})
+	return anomalies
+}
+
+func sumFrameworkTotals(totals []FrameworkTotals) (tests, skipped int) {
+	for _, t := range totals {
+		tests += t.Tests
+		skipped += t.Skipped
+	}
+	return tests, skipped
+}
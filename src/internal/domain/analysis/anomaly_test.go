@@ -0,0 +1,69 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectAnomalies(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []FrameworkTotals
+		current  []FrameworkTotals
+		want     []Anomaly
+	}{
+		{
+			name:     "steady inventory",
+			previous: []FrameworkTotals{{Framework: "jest", Tests: 100, Skipped: 2}},
+			current:  []FrameworkTotals{{Framework: "jest", Tests: 95, Skipped: 3}},
+		},
+		{
+			name:     "test drop beyond threshold",
+			previous: []FrameworkTotals{{Framework: "jest", Tests: 100}},
+			current:  []FrameworkTotals{{Framework: "jest", Tests: 79}},
+			want:     []Anomaly{{Current: 79, Kind: AnomalyKindTestDrop, Previous: 100}},
+		},
+		{
+			name:     "drop of exactly the threshold is tolerated",
+			previous: []FrameworkTotals{{Framework: "jest", Tests: 100}},
+			current:  []FrameworkTotals{{Framework: "jest", Tests: 80}},
+		},
+		{
+			name: "framework vanishes",
+			previous: []FrameworkTotals{
+				{Framework: "jest", Tests: 100},
+				{Framework: "playwright", Tests: 10},
+			},
+			current: []FrameworkTotals{{Framework: "jest", Tests: 105}},
+			want:    []Anomaly{{Framework: "playwright", Kind: AnomalyKindFrameworkVanished, Previous: 10}},
+		},
+		{
+			name:     "skipped tests jump",
+			previous: []FrameworkTotals{{Framework: "pytest", Tests: 200, Skipped: 5}},
+			current:  []FrameworkTotals{{Framework: "pytest", Tests: 200, Skipped: 40}},
+			want:     []Anomaly{{Current: 40, Kind: AnomalyKindSkippedJump, Previous: 5}},
+		},
+		{
+			name:     "small skipped growth is tolerated",
+			previous: []FrameworkTotals{{Framework: "pytest", Tests: 200, Skipped: 0}},
+			current:  []FrameworkTotals{{Framework: "pytest", Tests: 200, Skipped: 9}},
+		},
+		{
+			name:     "everything vanishes",
+			previous: []FrameworkTotals{{Framework: "go-testing", Tests: 50}},
+			want: []Anomaly{
+				{Framework: "go-testing", Kind: AnomalyKindFrameworkVanished, Previous: 50},
+				{Current: 0, Kind: AnomalyKindTestDrop, Previous: 50},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectAnomalies(tt.previous, tt.current)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
@@ -6,20 +6,54 @@ import (
 )
 
 type AutoRefreshRepository interface {
+	// GetCodebasesForAutoRefresh leaves out archived and disabled codebases until their metadata is due for a recheck.
 	GetCodebasesForAutoRefresh(ctx context.Context) ([]CodebaseRefreshInfo, error)
+	// UpdateEmptyState records that a codebase has no commits yet, or clears the mark once it has.
+	UpdateEmptyState(ctx context.Context, id UUID, isEmpty bool) error
+}
+
+// CodebaseViewerLookup finds the users whose OAuth tokens may still read a codebase.
+type CodebaseViewerLookup interface {
+	// ListRecentViewers returns up to limit users who viewed the codebase and have a usable
+	// OAuth token, most recent first.
+	ListRecentViewers(ctx context.Context, codebaseID UUID, limit int) ([]string, error)
 }
 
 type CodebaseRefreshInfo struct {
 	ConsecutiveFailures int
-	Host                string
-	ID                  UUID
-	LastCommitSHA       string
-	LastCompletedAt     *time.Time
-	LastViewedAt        time.Time
+	// EmptyCheckedAt is when the repository was last found to have no commits. Nil if it has commits.
+	EmptyCheckedAt  *time.Time
+	Host            string
+	ID              UUID
+	IsArchived      bool
+	IsDisabled      bool
+	IsPrivate       bool
+	LastCommitSHA   string
+	LastCompletedAt *time.Time
+	LastViewedAt    time.Time
+	// LastViewerID is the user who most recently viewed the codebase and has an OAuth token. Nil if none.
+	LastViewerID *string
+	// MetadataRefreshedAt is when the repository metadata was last fetched from the provider. Nil if never.
+	MetadataRefreshedAt *time.Time
 	Name                string
 	Owner               string
 }
 
+func (c CodebaseRefreshInfo) Lifecycle() RepoLifecycle {
+	return RepoLifecycle{
+		EmptyCheckedAt: c.EmptyCheckedAt,
+		IsArchived:     c.IsArchived,
+		IsDisabled:     c.IsDisabled,
+	}
+}
+
+// ShouldRefreshAt applies ShouldRefreshAt to the codebase.
+func (c CodebaseRefreshInfo) ShouldRefreshAt(now time.Time) bool {
+	return ShouldRefreshAt(c.LastViewedAt, c.LastCompletedAt, c.ConsecutiveFailures, c.Lifecycle(), now)
+}
+
 type TaskQueue interface {
 	EnqueueAnalysis(ctx context.Context, owner, repo, commitSHA string) error
+	// EnqueueAnalysisWithUser analyzes with the user's OAuth token, e.g. for private repositories.
+	EnqueueAnalysisWithUser(ctx context.Context, owner, repo, commitSHA string, userID *string) error
 }
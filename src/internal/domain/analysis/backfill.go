@@ -0,0 +1,129 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// BackfillStrategy selects which past commits a backfill analyzes.
+type BackfillStrategy string
+
+const (
+	BackfillStrategyEveryN BackfillStrategy = "every_n"
+	BackfillStrategyTags   BackfillStrategy = "tags"
+	BackfillStrategyWeekly BackfillStrategy = "weekly"
+)
+
+const (
+	DefaultBackfillMaxCommits = 52
+	MaxBackfillMaxCommits     = 500
+)
+
+// HistoryCommit is a commit of the default branch history. Tag is set for tagged commits only.
+type HistoryCommit struct {
+	CommittedAt time.Time
+	SHA         string
+	Tag         string
+}
+
+// HistoryReader lists past commits of a remote repository without a full checkout.
+type HistoryReader interface {
+	// ListCommits returns first-parent commits of the default branch committed at or after since, newest first.
+	ListCommits(ctx context.Context, url string, token *string, since time.Time) ([]HistoryCommit, error)
+	// ListTags returns tagged commits committed at or after since, newest first.
+	ListTags(ctx context.Context, url string, token *string, since time.Time) ([]HistoryCommit, error)
+}
+
+// HistoricalTaskQueue enqueues exact-commit analyses at low priority.
+type HistoricalTaskQueue interface {
+	EnqueueHistoricalAnalysis(ctx context.Context, owner, repo, commitSHA string, userID *string) error
+}
+
+// BackfillPlan describes how commits are sampled from history.
+// Every is only used by BackfillStrategyEveryN. Zero MaxCommits means DefaultBackfillMaxCommits.
+type BackfillPlan struct {
+	Every      int
+	MaxCommits int
+	Since      time.Time
+	Strategy   BackfillStrategy
+}
+
+func (p BackfillPlan) Validate() error {
+	switch p.Strategy {
+	case BackfillStrategyEveryN:
+		if p.Every < 1 {
+			return fmt.Errorf("%w: every must be at least 1", ErrInvalidInput)
+		}
+	case BackfillStrategyTags, BackfillStrategyWeekly:
+	default:
+		return fmt.Errorf("%w: unknown backfill strategy %q", ErrInvalidInput, p.Strategy)
+	}
+	if p.MaxCommits < 0 || p.MaxCommits > MaxBackfillMaxCommits {
+		return fmt.Errorf("%w: max commits must be between 0 and %d", ErrInvalidInput, MaxBackfillMaxCommits)
+	}
+	return nil
+}
+
+type BackfillRequest struct {
+	Owner  string
+	Repo   string
+	Plan   BackfillPlan
+	UserID *string
+}
+
+func (r BackfillRequest) Validate() error {
+	if r.Owner == "" {
+		return fmt.Errorf("%w: owner is required", ErrInvalidInput)
+	}
+	if r.Repo == "" {
+		return fmt.Errorf("%w: repo is required", ErrInvalidInput)
+	}
+	if !isValidGitHubName(r.Owner) || !isValidGitHubName(r.Repo) {
+		return fmt.Errorf("%w: invalid characters in owner/repo", ErrInvalidInput)
+	}
+	return r.Plan.Validate()
+}
+
+// SampleCommits picks the commits to analyze from newest-first history and returns them oldest first,
+// so that trends fill in chronologically. Only the newest MaxCommits samples are kept.
+func SampleCommits(commits []HistoryCommit, plan BackfillPlan) []HistoryCommit {
+	maxCommits := plan.MaxCommits
+	if maxCommits == 0 {
+		maxCommits = DefaultBackfillMaxCommits
+	}
+
+	var sampled []HistoryCommit
+	seenWeeks := make(map[[2]int]bool)
+	for i, c := range commits {
+		if !plan.Since.IsZero() && c.CommittedAt.Before(plan.Since) {
+			continue
+		}
+
+		switch plan.Strategy {
+		case BackfillStrategyEveryN:
+			if i%plan.Every != 0 {
+				continue
+			}
+		case BackfillStrategyWeekly:
+			year, week := c.CommittedAt.UTC().ISOWeek()
+			if seenWeeks[[2]int{year, week}] {
+				continue
+			}
+			seenWeeks[[2]int{year, week}] = true
+		case BackfillStrategyTags:
+			if c.Tag == "" {
+				continue
+			}
+		}
+
+		sampled = append(sampled, c)
+		if len(sampled) == maxCommits {
+			break
+		}
+	}
+
+	slices.Reverse(sampled)
+	return sampled
+}
@@ -0,0 +1,84 @@
+package analysis
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackfillPlan_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		plan    BackfillPlan
+		wantErr bool
+	}{
+		{name: "every n", plan: BackfillPlan{Strategy: BackfillStrategyEveryN, Every: 10}},
+		{name: "weekly", plan: BackfillPlan{Strategy: BackfillStrategyWeekly}},
+		{name: "tags with max", plan: BackfillPlan{Strategy: BackfillStrategyTags, MaxCommits: 20}},
+		{name: "every n without interval", plan: BackfillPlan{Strategy: BackfillStrategyEveryN}, wantErr: true},
+		{name: "unknown strategy", plan: BackfillPlan{Strategy: "daily"}, wantErr: true},
+		{name: "too many commits", plan: BackfillPlan{Strategy: BackfillStrategyWeekly, MaxCommits: MaxBackfillMaxCommits + 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.plan.Validate()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Errorf("expected ErrInvalidInput, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestSampleCommits(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 12, 0, 0, 0, time.UTC) }
+	// Newest first, as returned by HistoryReader. Jan 1 2024 is a Monday.
+	commits := []HistoryCommit{
+		{SHA: "c15", CommittedAt: day(15), Tag: "v1.1.0"},
+		{SHA: "c10", CommittedAt: day(10)},
+		{SHA: "c09", CommittedAt: day(9)},
+		{SHA: "c05", CommittedAt: day(5), Tag: "v1.0.0"},
+		{SHA: "c02", CommittedAt: day(2)},
+		{SHA: "c01", CommittedAt: day(1)},
+	}
+
+	shas := func(cs []HistoryCommit) []string {
+		var out []string
+		for _, c := range cs {
+			out = append(out, c.SHA)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		plan BackfillPlan
+		want []string
+	}{
+		{name: "every 2", plan: BackfillPlan{Strategy: BackfillStrategyEveryN, Every: 2}, want: []string{"c02", "c09", "c15"}},
+		{name: "weekly keeps newest per week", plan: BackfillPlan{Strategy: BackfillStrategyWeekly}, want: []string{"c05", "c10", "c15"}},
+		{name: "tags", plan: BackfillPlan{Strategy: BackfillStrategyTags}, want: []string{"c05", "c15"}},
+		{name: "since filters older commits", plan: BackfillPlan{Strategy: BackfillStrategyWeekly, Since: day(6)}, want: []string{"c10", "c15"}},
+		{name: "max commits keeps newest", plan: BackfillPlan{Strategy: BackfillStrategyEveryN, Every: 1, MaxCommits: 2}, want: []string{"c10", "c15"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := shas(SampleCommits(commits, tt.plan))
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package analysis
+
+import (
+	"context"
+	"time"
+)
+
+// Blame records who introduced a test declaration and when the test last changed.
+// The introducing commit is the one that added the declaration line, even if the line
+// was renamed or reformatted later.
+type Blame struct {
+	AuthorEmail    string
+	AuthorName     string
+	CommitSHA      string
+	IntroducedAt   time.Time
+	LastModifiedAt time.Time
+}
+
+// LineBlame is the commit that last touched a single line.
+type LineBlame struct {
+	AuthorEmail string
+	AuthorName  string
+	AuthoredAt  time.Time
+	CommitSHA   string
+}
+
+// Blamer attributes the tests of an inventory to commits using the clone history.
+// Implementations fetch missing history on demand and fill Test.Blame in place.
+type Blamer interface {
+	Blame(ctx context.Context, src Source, inventory *Inventory) error
+}
+
+// ApplyBlame sets Blame on every test of file from its per-line blame, where lines[0] is line 1.
+// The commit that last changed the declaration line stands in for the introducing commit
+// until Introduce traces the line back; the most recent commit within the test's line
+// range gives LastModifiedAt.
+func ApplyBlame(file *TestFile, lines []LineBlame) {
+	if file == nil || len(lines) == 0 {
+		return
+	}
+	applyBlameToTests(file.Tests, lines)
+	applyBlameToSuites(file.Suites, lines)
+}
+
+func applyBlameToSuites(suites []TestSuite, lines []LineBlame) {
+	for i := range suites {
+		applyBlameToTests(suites[i].Tests, lines)
+		applyBlameToSuites(suites[i].Suites, lines)
+	}
+}
+
+func applyBlameToTests(tests []Test, lines []LineBlame) {
+	for i := range tests {
+		tests[i].Blame = blameRange(lines, tests[i].Location)
+	}
+}
+
+func blameRange(lines []LineBlame, loc Location) *Blame {
+	start := loc.StartLine
+	if start < 1 || start > len(lines) {
+		return nil
+	}
+	end := max(loc.EndLine, start)
+	end = min(end, len(lines))
+
+	decl := lines[start-1]
+	if decl.CommitSHA == "" {
+		return nil
+	}
+
+	blame := &Blame{
+		AuthorEmail:    decl.AuthorEmail,
+		AuthorName:     decl.AuthorName,
+		CommitSHA:      decl.CommitSHA,
+		IntroducedAt:   decl.AuthoredAt,
+		LastModifiedAt: decl.AuthoredAt,
+	}
+	for _, line := range lines[start:end] {
+		if line.AuthoredAt.After(blame.LastModifiedAt) {
+			blame.LastModifiedAt = line.AuthoredAt
+		}
+	}
+	return blame
+}
+
+// Introduce attributes the blame to the commit that introduced the declaration line.
+// LastModifiedAt is kept, as the introduction can only be older than the last change.
+func (b *Blame) Introduce(introduction LineBlame) {
+	b.AuthorEmail = introduction.AuthorEmail
+	b.AuthorName = introduction.AuthorName
+	b.CommitSHA = introduction.CommitSHA
+	b.IntroducedAt = introduction.AuthoredAt
+}
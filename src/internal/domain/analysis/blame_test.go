@@ -0,0 +1,63 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplyBlame(t *testing.T) {
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	t3 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	lines := []LineBlame{
+		{CommitSHA: "aaa", AuthorName: "Alice", AuthorEmail: "alice@example.com", AuthoredAt: t1},
+		{CommitSHA: "aaa", AuthorName: "Alice", AuthorEmail: "alice@example.com", AuthoredAt: t1},
+		{CommitSHA: "bbb", AuthorName: "Bob", AuthorEmail: "bob@example.com", AuthoredAt: t2},
+		{CommitSHA: "ccc", AuthorName: "Carol", AuthorEmail: "carol@example.com", AuthoredAt: t3},
+	}
+	file := &TestFile{
+		Tests: []Test{
+			{Name: "TestA", Location: Location{StartLine: 1, EndLine: 3}},
+			{Name: "TestOutOfRange", Location: Location{StartLine: 10, EndLine: 12}},
+		},
+		Suites: []TestSuite{
+			{Name: "Suite", Tests: []Test{{Name: "no end line", Location: Location{StartLine: 4}}}},
+		},
+	}
+
+	ApplyBlame(file, lines)
+
+	got := file.Tests[0].Blame
+	if got == nil {
+		t.Fatal("expected blame for TestA")
+	}
+	if got.CommitSHA != "aaa" || got.AuthorName != "Alice" || !got.IntroducedAt.Equal(t1) {
+		t.Errorf("unexpected introduction: %+v", got)
+	}
+	if !got.LastModifiedAt.Equal(t2) {
+		t.Errorf("expected last modified %v, got %v", t2, got.LastModifiedAt)
+	}
+
+	if file.Tests[1].Blame != nil {
+		t.Errorf("expected nil blame for out of range test, got %+v", file.Tests[1].Blame)
+	}
+
+	nested := file.Suites[0].Tests[0].Blame
+	if nested == nil || nested.CommitSHA != "ccc" || !nested.LastModifiedAt.Equal(t3) {
+		t.Errorf("unexpected nested blame: %+v", nested)
+	}
+}
+
+func TestBlame_Introduce(t *testing.T) {
+	changedAt := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	introducedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	blame := &Blame{AuthorName: "Bob", CommitSHA: "bbb", IntroducedAt: changedAt, LastModifiedAt: changedAt}
+
+	blame.Introduce(LineBlame{AuthorEmail: "alice@example.com", AuthorName: "Alice", AuthoredAt: introducedAt, CommitSHA: "aaa"})
+
+	want := Blame{AuthorEmail: "alice@example.com", AuthorName: "Alice", CommitSHA: "aaa", IntroducedAt: introducedAt, LastModifiedAt: changedAt}
+	if *blame != want {
+		t.Errorf("expected %+v, got %+v", want, *blame)
+	}
+}
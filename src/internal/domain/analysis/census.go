@@ -0,0 +1,68 @@
+package analysis
+
+import (
+	"context"
+	"maps"
+	"slices"
+)
+
+// LineCounter counts the non-blank lines of files in a cloned source.
+type LineCounter interface {
+	// CountLines returns non-blank line counts keyed by path. Unreadable files are omitted.
+	CountLines(ctx context.Context, src Source, files []string) (map[string]int, error)
+}
+
+// LanguageCensus is the amount of production code in one language.
+type LanguageCensus struct {
+	Files    int
+	Language string
+	Lines    int
+}
+
+// SourceCensus is the amount of production code of an analysis, per language.
+type SourceCensus struct {
+	Languages  []LanguageCensus
+	TotalFiles int
+	TotalLines int
+}
+
+// NewSourceCensus aggregates line counts of production source files by language.
+// Languages are sorted by name.
+func NewSourceCensus(sources []string, lines map[string]int) SourceCensus {
+	byLanguage := make(map[string]LanguageCensus)
+	var census SourceCensus
+	for _, source := range sources {
+		language := SourceLanguage(source)
+		entry := byLanguage[language]
+		entry.Language = language
+		entry.Files++
+		entry.Lines += lines[source]
+		byLanguage[language] = entry
+
+		census.TotalFiles++
+		census.TotalLines += lines[source]
+	}
+
+	for _, language := range slices.Sorted(maps.Keys(byLanguage)) {
+		census.Languages = append(census.Languages, byLanguage[language])
+	}
+	return census
+}
+
+// TestFileRatio returns test files per production source file.
+// ok is false when the codebase has no source files.
+func (c SourceCensus) TestFileRatio(testFiles int) (ratio float64, ok bool) {
+	if c.TotalFiles == 0 {
+		return 0, false
+	}
+	return float64(testFiles) / float64(c.TotalFiles), true
+}
+
+// TestsPerKLOC returns tests per thousand non-blank lines of production code.
+// ok is false when the codebase has no source lines.
+func (c SourceCensus) TestsPerKLOC(tests int) (ratio float64, ok bool) {
+	if c.TotalLines == 0 {
+		return 0, false
+	}
+	return float64(tests) * 1000 / float64(c.TotalLines), true
+}
@@ -0,0 +1,44 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewSourceCensus(t *testing.T) {
+	sources := []string{"pkg/a.go", "pkg/b.go", "web/app.ts", "web/legacy.js"}
+	lines := map[string]int{"pkg/a.go": 120, "pkg/b.go": 80, "web/app.ts": 300}
+
+	census := NewSourceCensus(sources, lines)
+
+	want := []LanguageCensus{
+		{Files: 2, Language: "go", Lines: 200},
+		{Files: 1, Language: "javascript", Lines: 0},
+		{Files: 1, Language: "typescript", Lines: 300},
+	}
+	if !reflect.DeepEqual(census.Languages, want) {
+		t.Errorf("expected %v, got %v", want, census.Languages)
+	}
+	if census.TotalFiles != 4 || census.TotalLines != 500 {
+		t.Errorf("unexpected totals: files=%d lines=%d", census.TotalFiles, census.TotalLines)
+	}
+}
+
+func TestSourceCensus_Ratios(t *testing.T) {
+	census := SourceCensus{TotalFiles: 40, TotalLines: 8000}
+
+	if ratio, ok := census.TestFileRatio(10); !ok || ratio != 0.25 {
+		t.Errorf("TestFileRatio = %v, %v; want 0.25, true", ratio, ok)
+	}
+	if ratio, ok := census.TestsPerKLOC(20); !ok || ratio != 2.5 {
+		t.Errorf("TestsPerKLOC = %v, %v; want 2.5, true", ratio, ok)
+	}
+
+	var empty SourceCensus
+	if _, ok := empty.TestFileRatio(10); ok {
+		t.Error("expected no test file ratio without source files")
+	}
+	if _, ok := empty.TestsPerKLOC(10); ok {
+		t.Error("expected no tests per KLOC without source lines")
+	}
+}
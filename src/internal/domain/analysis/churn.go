@@ -0,0 +1,54 @@
+package analysis
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultChurnWindow is how far back test file churn is measured from the analyzed commit.
+const DefaultChurnWindow = 90 * 24 * time.Hour
+
+// FileChurn is how often a file changed within the churn window.
+type FileChurn struct {
+	Authors      int
+	Commits      int
+	LinesAdded   int
+	LinesRemoved int
+	Path         string
+}
+
+// ChurnReport is the churn of every test file of an analysis.
+type ChurnReport struct {
+	Files []FileChurn
+	// Since is the start of the window, which ends at the analyzed commit.
+	Since time.Time
+}
+
+// ChurnReader reads per-file change history from the clone.
+type ChurnReader interface {
+	// Churn returns, keyed by path, the churn of the given paths over commits reachable
+	// from the source commit and committed at or after since. Unchanged paths are omitted.
+	Churn(ctx context.Context, src Source, paths []string, since time.Time) (map[string]FileChurn, error)
+}
+
+// NewChurnReport lists the churn of every test file of the inventory sorted by path,
+// with zero churn for files that did not change within the window.
+func NewChurnReport(inventory *Inventory, churn map[string]FileChurn, since time.Time) ChurnReport {
+	report := ChurnReport{Since: since}
+	if inventory == nil {
+		return report
+	}
+
+	report.Files = make([]FileChurn, 0, len(inventory.Files))
+	for _, f := range inventory.Files {
+		entry := churn[f.Path]
+		entry.Path = f.Path
+		report.Files = append(report.Files, entry)
+	}
+	slices.SortFunc(report.Files, func(a, b FileChurn) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return report
+}
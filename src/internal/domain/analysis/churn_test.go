@@ -0,0 +1,28 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewChurnReport(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	inventory := &Inventory{Files: []TestFile{{Path: "b_test.go"}, {Path: "a_test.go"}}}
+	churn := map[string]FileChurn{
+		"b_test.go": {Authors: 2, Commits: 3, LinesAdded: 10, LinesRemoved: 4},
+	}
+
+	got := NewChurnReport(inventory, churn, since)
+
+	want := ChurnReport{
+		Files: []FileChurn{
+			{Path: "a_test.go"},
+			{Authors: 2, Commits: 3, LinesAdded: 10, LinesRemoved: 4, Path: "b_test.go"},
+		},
+		Since: since,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
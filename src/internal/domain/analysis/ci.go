@@ -0,0 +1,240 @@
+package analysis
+
+import (
+	"context"
+	"path"
+	"slices"
+	"strings"
+)
+
+var (
+	jvmFrameworks    = []string{"junit4", "junit5", "kotest", "testng"}
+	dotnetFrameworks = []string{"mstest", "nunit", "xunit"}
+	pythonFrameworks = []string{"pytest", "unittest"}
+	swiftFrameworks  = []string{"swift-testing", "xctest"}
+)
+
+// commandWrappers prefix a command without changing what it runs.
+var commandWrappers = [][]string{
+	{"bundle", "exec"},
+	{"coverage", "run", "-m"},
+	{"hatch", "run"},
+	{"npm", "exec"},
+	{"pdm", "run"},
+	{"pipenv", "run"},
+	{"pnpm", "dlx"},
+	{"pnpm", "exec"},
+	{"poetry", "run"},
+	{"python", "-m"},
+	{"python3", "-m"},
+	{"uv", "run"},
+	{"yarn", "dlx"},
+	{"yarn", "exec"},
+	{"bunx"},
+	{"env"},
+	{"nohup"},
+	{"npx"},
+	{"pnpx"},
+	{"sudo"},
+	{"time"},
+	{"xvfb-run"},
+}
+
+// ciActionFrameworks maps GitHub Actions that run tests to the frameworks they run.
+var ciActionFrameworks = map[string][]string{
+	"cypress-io/github-action": {"cypress"},
+}
+
+// CICommand is a test command run by a CI job.
+type CICommand struct {
+	Command string
+	// ConfigPath is the CI configuration file declaring the command.
+	ConfigPath string
+	// Frameworks are the frameworks the command runs, following package scripts,
+	// make targets and shell scripts it delegates to.
+	Frameworks []string
+	Job        string
+}
+
+// CIFramework tells whether CI runs the tests of a detected framework.
+type CIFramework struct {
+	Executed  bool
+	Framework string
+	TestFiles int
+}
+
+// CIReport is the test execution declared by the CI configuration of a repository.
+type CIReport struct {
+	Commands    []CICommand
+	ConfigPaths []string
+	Frameworks  []CIFramework
+}
+
+// CIConfigLoader reads test commands from CI configuration files of the source.
+type CIConfigLoader interface {
+	// Load returns nil when the repository has no recognized CI configuration.
+	// files is the listing of src from FileLister.
+	Load(ctx context.Context, src Source, files []string) (*CIReport, error)
+}
+
+// RunnerFrameworks returns the frameworks a direct test runner invocation runs,
+// e.g. "npx jest --ci" runs jest and "./gradlew :app:check" runs the JVM frameworks.
+// Indirect invocations such as "npm test" or "make test" return nil.
+func RunnerFrameworks(command string) []string {
+	args := CommandArgs(command)
+	if len(args) == 0 {
+		return nil
+	}
+
+	runner := strings.TrimSuffix(path.Base(args[0]), ".js")
+	rest := args[1:]
+	switch runner {
+	case "jest", "mocha", "vitest":
+		return []string{runner}
+	case "playwright":
+		if slices.Contains(rest, "test") {
+			return []string{"playwright"}
+		}
+	case "cypress":
+		if slices.Contains(rest, "run") {
+			return []string{"cypress"}
+		}
+	case "pytest", "py.test", "tox", "nox":
+		return slices.Clone(pythonFrameworks)
+	case "unittest":
+		return []string{"unittest"}
+	case "python", "python3":
+		// Django's test runner
+		if len(rest) > 1 && path.Base(rest[0]) == "manage.py" && rest[1] == "test" {
+			return []string{"unittest"}
+		}
+	case "go":
+		if slices.Contains(rest, "test") {
+			return []string{"go-testing"}
+		}
+	case "gotestsum":
+		return []string{"go-testing"}
+	case "cargo":
+		if slices.Contains(rest, "test") || slices.Contains(rest, "nextest") {
+			return []string{"cargo-test"}
+		}
+	case "rspec":
+		return []string{"rspec"}
+	case "rails":
+		if slices.Contains(rest, "test") {
+			return []string{"minitest"}
+		}
+	case "rake":
+		switch {
+		case len(rest) == 0:
+			return []string{"minitest", "rspec"}
+		case slices.Contains(rest, "spec"):
+			return []string{"rspec"}
+		case slices.Contains(rest, "test"):
+			return []string{"minitest"}
+		}
+	case "ruby":
+		if len(rest) > 0 && strings.HasPrefix(rest[0], "-Itest") {
+			return []string{"minitest"}
+		}
+	case "phpunit", "pest", "paratest":
+		return []string{"phpunit"}
+	case "dotnet":
+		if slices.Contains(rest, "test") || slices.Contains(rest, "vstest") {
+			return slices.Clone(dotnetFrameworks)
+		}
+	case "mvn", "mvnw":
+		for _, goal := range rest {
+			if slices.Contains([]string{"test", "verify", "install", "package", "integration-test", "deploy"}, goal) {
+				return slices.Clone(jvmFrameworks)
+			}
+		}
+	case "gradle", "gradlew":
+		for _, task := range rest {
+			name := task[strings.LastIndex(task, ":")+1:]
+			if strings.HasSuffix(strings.ToLower(name), "test") || name == "check" || name == "build" {
+				return slices.Clone(jvmFrameworks)
+			}
+		}
+	case "swift":
+		if slices.Contains(rest, "test") {
+			return slices.Clone(swiftFrameworks)
+		}
+	case "xcodebuild":
+		if slices.Contains(rest, "test") || slices.Contains(rest, "test-without-building") {
+			return slices.Clone(swiftFrameworks)
+		}
+	case "ctest":
+		return []string{"gtest"}
+	}
+	return nil
+}
+
+// ActionFrameworks returns the frameworks a GitHub Actions "uses" reference runs.
+func ActionFrameworks(uses string) []string {
+	action, _, _ := strings.Cut(uses, "@")
+	return slices.Clone(ciActionFrameworks[action])
+}
+
+// CommandArgs splits a shell command into words, dropping leading variable
+// assignments and wrappers listed in commandWrappers.
+func CommandArgs(command string) []string {
+	args := strings.Fields(command)
+	for i := range args {
+		args[i] = strings.Trim(args[i], `"'`)
+	}
+
+	for len(args) > 0 {
+		if strings.Contains(args[0], "=") && !strings.HasPrefix(args[0], "-") {
+			args = args[1:]
+			continue
+		}
+		wrapped := false
+		for _, wrapper := range commandWrappers {
+			if len(args) > len(wrapper) && slices.Equal(args[:len(wrapper)], wrapper) {
+				args = args[len(wrapper):]
+				wrapped = true
+				break
+			}
+		}
+		if !wrapped {
+			break
+		}
+	}
+	return args
+}
+
+// EvaluateCIFrameworks reports, for each framework of the inventory, whether any CI command runs it.
+// Results are sorted by framework.
+func EvaluateCIFrameworks(inventory *Inventory, commands []CICommand) []CIFramework {
+	if inventory == nil {
+		return nil
+	}
+
+	executed := make(map[string]bool)
+	for _, command := range commands {
+		for _, framework := range command.Frameworks {
+			executed[framework] = true
+		}
+	}
+
+	testFiles := make(map[string]int)
+	for _, f := range inventory.Files {
+		if f.Framework != "" {
+			testFiles[f.Framework]++
+		}
+	}
+
+	frameworks := make([]CIFramework, 0, len(testFiles))
+	for framework, count := range testFiles {
+		frameworks = append(frameworks, CIFramework{
+			Executed:  executed[framework],
+			Framework: framework,
+			TestFiles: count,
+		})
+	}
+	slices.SortFunc(frameworks, func(a, b CIFramework) int {
+		return strings.Compare(a.Framework, b.Framework)
+	})
+	return frameworks
+}
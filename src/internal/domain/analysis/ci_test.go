@@ -0,0 +1,91 @@
+package analysis
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestRunnerFrameworks(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{command: "npx jest --ci", want: []string{"jest"}},
+		{command: "CI=true ./node_modules/.bin/vitest run", want: []string{"vitest"}},
+		{command: "pnpm exec playwright test --project=chromium", want: []string{"playwright"}},
+		{command: "npx playwright install --with-deps"},
+		{command: "cypress run --browser chrome", want: []string{"cypress"}},
+		{command: "poetry run pytest -x tests/", want: []string{"pytest", "unittest"}},
+		{command: "python -m unittest discover", want: []string{"unittest"}},
+		{command: "python manage.py test", want: []string{"unittest"}},
+		{command: "go test -race ./...", want: []string{"go-testing"}},
+		{command: "go build ./..."},
+		{command: "cargo nextest run", want: []string{"cargo-test"}},
+		{command: "bundle exec rspec spec/models", want: []string{"rspec"}},
+		{command: "bin/rails test", want: []string{"minitest"}},
+		{command: "vendor/bin/phpunit --testsuite unit", want: []string{"phpunit"}},
+		{command: "dotnet test --no-build", want: []string{"mstest", "nunit", "xunit"}},
+		{command: "./mvnw -B verify", want: []string{"junit4", "junit5", "kotest", "testng"}},
+		{command: "./gradlew :app:testDebugUnitTest", want: []string{"junit4", "junit5", "kotest", "testng"}},
+		{command: "./gradlew assemble"},
+		{command: "xcodebuild test -scheme App", want: []string{"swift-testing", "xctest"}},
+		{command: "npm test"},
+		{command: "make test"},
+		{command: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got := RunnerFrameworks(tt.command)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("RunnerFrameworks(%q) = %v, want %v", tt.command, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActionFrameworks(t *testing.T) {
+	if got := ActionFrameworks("cypress-io/github-action@v6"); !slices.Equal(got, []string{"cypress"}) {
+		t.Errorf("expected cypress, got %v", got)
+	}
+	if got := ActionFrameworks("actions/checkout@v4"); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
+
+func TestCommandArgs(t *testing.T) {
+	got := CommandArgs(`NODE_ENV=test FORCE_COLOR=1 npx "jest" --ci`)
+	want := []string{"jest", "--ci"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestEvaluateCIFrameworks(t *testing.T) {
+	inventory := &Inventory{Files: []TestFile{
+		{Path: "a.test.ts", Framework: "jest"},
+		{Path: "b.test.ts", Framework: "jest"},
+		{Path: "e2e/login.spec.ts", Framework: "playwright"},
+		{Path: "api/test_app.py", Framework: "pytest"},
+	}}
+	commands := []CICommand{
+		{Command: "npx jest", Frameworks: []string{"jest"}},
+		{Command: "pytest", Frameworks: []string{"pytest", "unittest"}},
+	}
+
+	got := EvaluateCIFrameworks(inventory, commands)
+
+	want := []CIFramework{
+		{Executed: true, Framework: "jest", TestFiles: 2},
+		{Executed: false, Framework: "playwright", TestFiles: 1},
+		{Executed: true, Framework: "pytest", TestFiles: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	if got := EvaluateCIFrameworks(nil, commands); got != nil {
+		t.Errorf("expected nil for nil inventory, got %+v", got)
+	}
+}
@@ -18,6 +18,19 @@ type Codebase struct {
 	Owner          string
 }
 
+// StaleReason records why a codebase no longer tracks a live repository.
+type StaleReason string
+
+const (
+	// StaleReasonAccessRevoked means a private repository is no longer readable with any stored credential.
+	StaleReasonAccessRevoked StaleReason = "access_revoked"
+	StaleReasonDeleted       StaleReason = "deleted"
+	// StaleReasonMadePrivate means a public repository still exists but only authenticated users can see it.
+	StaleReasonMadePrivate StaleReason = "made_private"
+	// StaleReasonReplaced means a different repository now has the codebase's owner and name.
+	StaleReasonReplaced StaleReason = "replaced"
+)
+
 type UpsertCodebaseParams struct {
 	DefaultBranch  string
 	ExternalRepoID string
@@ -47,10 +60,22 @@ type CodebaseRepository interface {
 	FindByExternalID(ctx context.Context, host, externalRepoID string) (*Codebase, error)
 	FindByOwnerName(ctx context.Context, host, owner, name string) (*Codebase, error)
 	FindWithLastCommit(ctx context.Context, host, owner, name string) (*Codebase, error)
-	MarkStale(ctx context.Context, id UUID) error
+	MarkStale(ctx context.Context, id UUID, reason StaleReason) error
+	// MarkStaleAndUpsert retires the codebase with StaleReasonReplaced and creates the one now owning its name.
 	MarkStaleAndUpsert(ctx context.Context, staleID UUID, params UpsertCodebaseParams) (*Codebase, error)
 	UnmarkStale(ctx context.Context, id UUID, owner, name string) (*Codebase, error)
+	UpdateMetadata(ctx context.Context, id UUID, info RepoInfo) error
 	UpdateOwnerName(ctx context.Context, id UUID, owner, name string) (*Codebase, error)
 	UpdateVisibility(ctx context.Context, id UUID, isPrivate bool) error
 	Upsert(ctx context.Context, params UpsertCodebaseParams) (*Codebase, error)
 }
+
+// CodebaseMetadataRepository stores repository metadata reported by the hosting provider.
+type CodebaseMetadataRepository interface {
+	UpdateMetadata(ctx context.Context, id UUID, info RepoInfo) error
+}
+
+// CodebaseStaleRepository retires codebases whose repository is gone.
+type CodebaseStaleRepository interface {
+	MarkStale(ctx context.Context, id UUID, reason StaleReason) error
+}
@@ -0,0 +1,158 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// FileCoverage is the line coverage of one file as reported by a coverage tool.
+type FileCoverage struct {
+	CoveredLines int
+	// Path is as reported until normalized against the repository tree.
+	Path           string
+	TotalLines     int
+	UncoveredLines []int
+}
+
+// IngestCoverageRequest carries a coverage report for the analysis of a commit.
+type IngestCoverageRequest struct {
+	CommitSHA string
+	Format    ReportFormat
+	Owner     string
+	Repo      string
+	Report    []byte
+	// ReportName distinguishes reports of the same commit (e.g. per package or CI job).
+	// Re-ingesting a report name replaces its previous coverage.
+	ReportName string
+	// UserID selects the OAuth token used to clone private repositories.
+	UserID *string
+}
+
+func (r IngestCoverageRequest) Validate() error {
+	if r.Format != ReportFormatLcov && r.Format != ReportFormatCobertura {
+		return fmt.Errorf("%w: unsupported coverage format %q", ErrInvalidInput, r.Format)
+	}
+	return validateReportUpload(r.Owner, r.Repo, r.CommitSHA, r.ReportName, r.Report)
+}
+
+// CoverageParser reads per-file line coverage from a coverage report.
+// Returns ErrInvalidReport if the report is malformed.
+type CoverageParser interface {
+	ParseCoverage(format ReportFormat, data []byte) ([]FileCoverage, error)
+}
+
+// CoverageRepository stores coverage next to completed analyses.
+type CoverageRepository interface {
+	// FindCompletedAnalysis returns ErrAnalysisNotFound if the commit has no completed analysis.
+	FindCompletedAnalysis(ctx context.Context, owner, repo, commitSHA string) (UUID, error)
+	// SaveFileCoverage replaces the coverage previously stored under reportName.
+	SaveFileCoverage(ctx context.Context, analysisID UUID, reportName string, files []FileCoverage) error
+}
+
+// NormalizeCoveragePaths rewrites reported paths to repository-relative paths of repoFiles.
+//
+// Reports name files relative to the CI workspace, an absolute checkout directory or a
+// sub-project, so a reported path matches the repository file it ends with
+// ("/home/runner/work/app/app/src/a.ts" → "src/a.ts") or that ends with it
+// ("a.py" reported under a "src" source root → "src/a.py", if unambiguous).
+// Files outside the tree, such as generated or vendored code, are returned as unmatched.
+// Entries normalizing to the same file are merged.
+func NormalizeCoveragePaths(files []FileCoverage, repoFiles []string) (normalized []FileCoverage, unmatched []string) {
+	known := make(map[string]bool, len(repoFiles))
+	bySuffix := make(map[string][]string)
+	for _, file := range repoFiles {
+		known[file] = true
+		for rest := file; ; {
+			i := strings.IndexByte(rest, '/')
+			if i < 0 {
+				break
+			}
+			rest = rest[i+1:]
+			bySuffix[rest] = append(bySuffix[rest], file)
+		}
+	}
+
+	index := make(map[string]int)
+	for _, file := range files {
+		resolved, ok := resolveCoveragePath(file.Path, known, bySuffix)
+		if !ok {
+			unmatched = append(unmatched, file.Path)
+			continue
+		}
+		file.Path = resolved
+		if i, seen := index[resolved]; seen {
+			normalized[i] = mergeFileCoverage(normalized[i], file)
+			continue
+		}
+		index[resolved] = len(normalized)
+		normalized = append(normalized, file)
+	}
+	return normalized, unmatched
+}
+
+func resolveCoveragePath(reported string, known map[string]bool, bySuffix map[string][]string) (string, bool) {
+	p := path.Clean(strings.ReplaceAll(reported, "\\", "/"))
+	p = strings.TrimLeft(strings.TrimPrefix(p, "./"), "/")
+	if i := strings.Index(p, ":/"); i == 1 {
+		p = p[i+2:] // Windows drive letter
+	}
+	if p == "." || p == "" {
+		return "", false
+	}
+
+	// The reported path is the repository path with a workspace prefix; the longest suffix wins.
+	for rest := p; ; {
+		if known[rest] {
+			return rest, true
+		}
+		i := strings.IndexByte(rest, '/')
+		if i < 0 {
+			break
+		}
+		rest = rest[i+1:]
+	}
+
+	// The reported path is relative to a sub-project of the repository.
+	if candidates := bySuffix[p]; len(candidates) == 1 {
+		return candidates[0], true
+	}
+	return "", false
+}
+
+// mergeFileCoverage combines two reports of the same file, counting a line as covered
+// if either covers it. Without comparable line details the report covering more lines is kept.
+func mergeFileCoverage(a, b FileCoverage) FileCoverage {
+	if a.TotalLines != b.TotalLines || !a.hasLineDetails() || !b.hasLineDetails() {
+		if b.CoveredLines > a.CoveredLines {
+			return b
+		}
+		return a
+	}
+
+	uncoveredInB := make(map[int]bool, len(b.UncoveredLines))
+	for _, line := range b.UncoveredLines {
+		uncoveredInB[line] = true
+	}
+	merged := FileCoverage{Path: a.Path, TotalLines: a.TotalLines}
+	for _, line := range a.UncoveredLines {
+		if uncoveredInB[line] {
+			merged.UncoveredLines = append(merged.UncoveredLines, line)
+		}
+	}
+	merged.CoveredLines = merged.TotalLines - len(merged.UncoveredLines)
+	return merged
+}
+
+func (c FileCoverage) hasLineDetails() bool {
+	return len(c.UncoveredLines) == c.TotalLines-c.CoveredLines
+}
+
+// LineRate returns the covered fraction of lines, or false for files without coverable lines.
+func (c FileCoverage) LineRate() (float64, bool) {
+	if c.TotalLines == 0 {
+		return 0, false
+	}
+	return float64(c.CoveredLines) / float64(c.TotalLines), true
+}
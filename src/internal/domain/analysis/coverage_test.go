@@ -0,0 +1,80 @@
+package analysis
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeCoveragePaths(t *testing.T) {
+	repoFiles := []string{
+		"src/cart.ts",
+		"src/order.ts",
+		"api/app/models.py",
+		"web/app/models.py",
+		"pkg/util/strings.go",
+	}
+
+	tests := []struct {
+		name          string
+		reported      string
+		wantPath      string
+		wantUnmatched bool
+	}{
+		{name: "relative path", reported: "src/cart.ts", wantPath: "src/cart.ts"},
+		{name: "dot prefix", reported: "./src/cart.ts", wantPath: "src/cart.ts"},
+		{name: "absolute CI workspace", reported: "/home/runner/work/shop/shop/src/order.ts", wantPath: "src/order.ts"},
+		{name: "windows path", reported: `D:\a\shop\shop\src\order.ts`, wantPath: "src/order.ts"},
+		{name: "relative to sub-project", reported: "util/strings.go", wantPath: "pkg/util/strings.go"},
+		{name: "ambiguous sub-project path", reported: "app/models.py", wantUnmatched: true},
+		{name: "outside the tree", reported: "node_modules/lodash/index.js", wantUnmatched: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized, unmatched := NormalizeCoveragePaths([]FileCoverage{{Path: tt.reported, TotalLines: 1}}, repoFiles)
+			if tt.wantUnmatched {
+				if len(normalized) != 0 || len(unmatched) != 1 {
+					t.Errorf("expected unmatched, got %v", normalized)
+				}
+				return
+			}
+			if len(normalized) != 1 || normalized[0].Path != tt.wantPath {
+				t.Errorf("expected %s, got %v (unmatched %v)", tt.wantPath, normalized, unmatched)
+			}
+		})
+	}
+}
+
+func TestNormalizeCoveragePaths_MergesDuplicates(t *testing.T) {
+	files := []FileCoverage{
+		{Path: "/ci/src/cart.ts", CoveredLines: 2, TotalLines: 5, UncoveredLines: []int{2, 3, 4}},
+		{Path: "src/cart.ts", CoveredLines: 3, TotalLines: 5, UncoveredLines: []int{1, 3}},
+	}
+
+	normalized, _ := NormalizeCoveragePaths(files, []string{"src/cart.ts"})
+
+	want := []FileCoverage{{Path: "src/cart.ts", CoveredLines: 4, TotalLines: 5, UncoveredLines: []int{3}}}
+	if !reflect.DeepEqual(normalized, want) {
+		t.Errorf("expected %+v, got %+v", want, normalized)
+	}
+}
+
+func TestIngestCoverageRequest_Validate(t *testing.T) {
+	req := IngestCoverageRequest{
+		CommitSHA:  "abc123",
+		Format:     ReportFormatLcov,
+		Owner:      "owner",
+		Repo:       "repo",
+		Report:     []byte("SF:a.go\nend_of_record\n"),
+		ReportName: "backend",
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("expected valid request, got %v", err)
+	}
+
+	req.Format = ReportFormatJUnit
+	if err := req.Validate(); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for junit format, got %v", err)
+	}
+}
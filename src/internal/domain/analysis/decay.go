@@ -22,6 +22,17 @@ const (
 
 const maxConsecutiveFailures = 5
 
+// emptyRepoRecheckInterval is the minimum wait before checking an empty repository for its first push again.
+const emptyRepoRecheckInterval = 24 * time.Hour
+
+// RepoLifecycle is the hosting state of a repository as far as it affects refreshing.
+type RepoLifecycle struct {
+	// EmptyCheckedAt is when the repository was last found to have no commits. Nil if it has commits.
+	EmptyCheckedAt *time.Time
+	IsArchived     bool
+	IsDisabled     bool
+}
+
 // CalculateRefreshIntervalAt returns refresh interval based on last viewed time.
 // Returns 0 if lastViewedAt is future or idle exceeds 90 days.
 func CalculateRefreshIntervalAt(lastViewedAt, now time.Time) time.Duration {
@@ -49,7 +60,13 @@ func CalculateRefreshIntervalAt(lastViewedAt, now time.Time) time.Duration {
 }
 
 // ShouldRefreshAt determines if codebase should be refreshed based on decay rules.
-func ShouldRefreshAt(lastViewedAt time.Time, lastCompletedAt *time.Time, consecutiveFailures int, now time.Time) bool {
+// Archived and disabled repositories cannot change and are never refreshed;
+// empty repositories are rechecked no more than once per emptyRepoRecheckInterval.
+func ShouldRefreshAt(lastViewedAt time.Time, lastCompletedAt *time.Time, consecutiveFailures int, lifecycle RepoLifecycle, now time.Time) bool {
+	if lifecycle.IsArchived || lifecycle.IsDisabled {
+		return false
+	}
+
 	if consecutiveFailures >= maxConsecutiveFailures {
 		return false
 	}
@@ -59,6 +76,13 @@ func ShouldRefreshAt(lastViewedAt time.Time, lastCompletedAt *time.Time, consecu
 		return false
 	}
 
+	if lifecycle.EmptyCheckedAt != nil {
+		nextCheckTime := lifecycle.EmptyCheckedAt.Add(max(interval, emptyRepoRecheckInterval))
+		if now.Before(nextCheckTime) {
+			return false
+		}
+	}
+
 	if lastCompletedAt == nil {
 		return true
 	}
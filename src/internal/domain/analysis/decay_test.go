@@ -120,6 +120,7 @@ func TestShouldRefreshAt(t *testing.T) {
 		lastViewedAt        time.Time
 		lastCompletedAt     *time.Time
 		consecutiveFailures int
+		lifecycle           RepoLifecycle
 		want                bool
 	}{
 		{
@@ -222,11 +223,43 @@ func TestShouldRefreshAt(t *testing.T) {
 			consecutiveFailures: 0,
 			want:                false,
 		},
+		// Repository lifecycle
+		{
+			name:            "archived - never refresh",
+			lastViewedAt:    now,
+			lastCompletedAt: timePtr(now.Add(-7 * time.Hour)),
+			lifecycle:       RepoLifecycle{IsArchived: true},
+			want:            false,
+		},
+		{
+			name:         "disabled - never refresh",
+			lastViewedAt: now,
+			lifecycle:    RepoLifecycle{IsDisabled: true},
+			want:         false,
+		},
+		{
+			name:         "empty checked recently - back off",
+			lastViewedAt: now,
+			lifecycle:    RepoLifecycle{EmptyCheckedAt: timePtr(now.Add(-7 * time.Hour))},
+			want:         false,
+		},
+		{
+			name:         "empty checked a day ago - recheck",
+			lastViewedAt: now,
+			lifecycle:    RepoLifecycle{EmptyCheckedAt: timePtr(now.Add(-24 * time.Hour))},
+			want:         true,
+		},
+		{
+			name:         "empty with 70 days idle - back off by decay interval",
+			lastViewedAt: now.AddDate(0, 0, -70),
+			lifecycle:    RepoLifecycle{EmptyCheckedAt: timePtr(now.AddDate(0, 0, -3))},
+			want:         false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := ShouldRefreshAt(tt.lastViewedAt, tt.lastCompletedAt, tt.consecutiveFailures, now)
+			got := ShouldRefreshAt(tt.lastViewedAt, tt.lastCompletedAt, tt.consecutiveFailures, tt.lifecycle, now)
 			if got != tt.want {
 				t.Errorf("ShouldRefreshAt() = %v, want %v", got, tt.want)
 			}
@@ -0,0 +1,68 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrDeployCredentialNotFound indicates the codebase has no credential of its own.
+// Like ErrTokenNotFound, it is expected and callers fall back to other credentials.
+var ErrDeployCredentialNotFound = errors.New("deploy credential not found")
+
+type DeployCredentialKind string
+
+const (
+	// DeployCredentialSSHKey is a PEM-encoded private key registered as the repository's deploy key.
+	DeployCredentialSSHKey DeployCredentialKind = "ssh_key"
+	// DeployCredentialToken is an access token scoped to the repository, used over HTTPS.
+	DeployCredentialToken DeployCredentialKind = "token"
+)
+
+// DeployCredential is a codebase's own credential. It lets service repositories without a human
+// owner be analyzed, and is preferred over users' OAuth tokens and app installation tokens.
+type DeployCredential struct {
+	Kind   DeployCredentialKind
+	Secret string
+}
+
+func (c DeployCredential) Validate() error {
+	switch c.Kind {
+	case DeployCredentialToken:
+		if strings.TrimSpace(c.Secret) == "" {
+			return fmt.Errorf("%w: token is required", ErrInvalidInput)
+		}
+	case DeployCredentialSSHKey:
+		if !strings.Contains(c.Secret, "PRIVATE KEY-----") {
+			return fmt.Errorf("%w: SSH key must be a PEM-encoded private key", ErrInvalidInput)
+		}
+	default:
+		return fmt.Errorf("%w: unknown deploy credential kind %q", ErrInvalidInput, c.Kind)
+	}
+	return nil
+}
+
+// APIToken returns the credential's token for API requests, or nil for an SSH key,
+// which only grants git access.
+func (c DeployCredential) APIToken() *string {
+	if c.Kind != DeployCredentialToken {
+		return nil
+	}
+	token := c.Secret
+	return &token
+}
+
+// DeployCredentialLookup retrieves codebases' deploy credentials.
+type DeployCredentialLookup interface {
+	// GetDeployCredential returns ErrDeployCredentialNotFound if the codebase has none,
+	// including when no codebase is named owner/repo yet. Other errors are infrastructure failures.
+	GetDeployCredential(ctx context.Context, host, owner, repo string) (DeployCredential, error)
+}
+
+// DeployCredentialVCS reads repositories with a deploy credential instead of an OAuth token.
+type DeployCredentialVCS interface {
+	CloneWithCredential(ctx context.Context, url string, credential DeployCredential) (Source, error)
+	// GetHeadCommitWithCredential follows GetHeadCommit, trying the credential once anonymous access fails.
+	GetHeadCommitWithCredential(ctx context.Context, url string, credential DeployCredential) (CommitInfo, error)
+}
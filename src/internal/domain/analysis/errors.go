@@ -1,9 +1,31 @@
 package analysis
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
 	ErrAlreadyCompleted = errors.New("analysis already completed")
+	ErrAnalysisNotFound = errors.New("analysis not found")
+	ErrEmptyRepository  = errors.New("repository has no commits")
 	ErrInvalidInput     = errors.New("invalid input")
+	ErrInvalidReport    = errors.New("invalid test report")
+	ErrRateLimited      = errors.New("rate limited")
 	ErrRepoNotFound     = errors.New("repository not found")
 )
+
+// RateLimitError reports that a VCS API refused a request until ResetAt.
+// It matches ErrRateLimited with errors.Is; use errors.As to read the reset time.
+type RateLimitError struct {
+	ResetAt time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrRateLimited, e.ResetAt.UTC().Format(time.RFC3339))
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
@@ -0,0 +1,245 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+var ErrReportUploadNotFound = errors.New("report upload not found")
+
+type ReportFormat string
+
+const (
+	ReportFormatCobertura ReportFormat = "cobertura"
+	ReportFormatJUnit     ReportFormat = "junit"
+	ReportFormatLcov      ReportFormat = "lcov"
+)
+
+const (
+	// MaxReportNameLength bounds IngestResultsRequest.ReportName.
+	MaxReportNameLength = 255
+	// MaxReportSize bounds an ingested report. Reports are kept in a ReportUploadStore until
+	// their job is done, since they are too large to travel inside the job arguments.
+	MaxReportSize = 8 * 1024 * 1024
+)
+
+type ExecutionOutcome string
+
+const (
+	ExecutionOutcomeFailed  ExecutionOutcome = "failed"
+	ExecutionOutcomePassed  ExecutionOutcome = "passed"
+	ExecutionOutcomeSkipped ExecutionOutcome = "skipped"
+)
+
+// TestExecution is one executed test case read from a CI report.
+type TestExecution struct {
+	// ClassName is the reporter's class or module name (e.g. "com.acme.CartTest", "tests.test_cart").
+	ClassName  string
+	DurationMs int
+	// File is the test file as reported, possibly absolute or relative to another root.
+	File    string
+	Message string
+	Name    string
+	Outcome ExecutionOutcome
+	// SuitePath is the chain of enclosing report suites, outermost first.
+	SuitePath []string
+}
+
+// StaticTest is a statically discovered test case of an analysis.
+type StaticTest struct {
+	FilePath  string
+	ID        UUID
+	Name      string
+	SuitePath []string
+}
+
+// ExecutionMatch pairs an execution with the static test it ran. TestCaseID is nil when unmatched.
+type ExecutionMatch struct {
+	Execution  TestExecution
+	TestCaseID *UUID
+}
+
+// IngestResultsRequest carries a CI test report for the analysis of a commit.
+type IngestResultsRequest struct {
+	CommitSHA string
+	Format    ReportFormat
+	Owner     string
+	Repo      string
+	Report    []byte
+	// ReportName distinguishes reports of the same commit (e.g. CI job names).
+	// Re-ingesting a report name replaces its previous results.
+	ReportName string
+}
+
+func (r IngestResultsRequest) Validate() error {
+	if r.Format != ReportFormatJUnit {
+		return fmt.Errorf("%w: unsupported report format %q", ErrInvalidInput, r.Format)
+	}
+	return validateReportUpload(r.Owner, r.Repo, r.CommitSHA, r.ReportName, r.Report)
+}
+
+// validateReportUpload checks the fields shared by CI report uploads.
+func validateReportUpload(owner, repo, commitSHA, reportName string, report []byte) error {
+	if owner == "" {
+		return fmt.Errorf("%w: owner is required", ErrInvalidInput)
+	}
+	if repo == "" {
+		return fmt.Errorf("%w: repo is required", ErrInvalidInput)
+	}
+	if commitSHA == "" {
+		return fmt.Errorf("%w: commit SHA is required", ErrInvalidInput)
+	}
+	if reportName == "" {
+		return fmt.Errorf("%w: report name is required", ErrInvalidInput)
+	}
+	if len(reportName) > MaxReportNameLength {
+		return fmt.Errorf("%w: report name exceeds %d characters", ErrInvalidInput, MaxReportNameLength)
+	}
+	if len(report) == 0 {
+		return fmt.Errorf("%w: report is empty", ErrInvalidInput)
+	}
+	if len(report) > MaxReportSize {
+		return fmt.Errorf("%w: report exceeds %d bytes", ErrInvalidInput, MaxReportSize)
+	}
+	return nil
+}
+
+// ReportUploadStore holds uploaded CI reports until the job that ingests them is done.
+// Jobs carry the upload ID instead of the report.
+type ReportUploadStore interface {
+	// GetReportUpload returns ErrReportUploadNotFound if the upload does not exist.
+	GetReportUpload(ctx context.Context, id UUID) ([]byte, error)
+	DeleteReportUpload(ctx context.Context, id UUID) error
+}
+
+// ReportParser reads test executions from a CI report.
+// Returns ErrInvalidReport if the report is malformed.
+type ReportParser interface {
+	Parse(format ReportFormat, data []byte) ([]TestExecution, error)
+}
+
+// ExecutionRepository links CI results to completed analyses.
+type ExecutionRepository interface {
+	// FindCompletedAnalysis returns ErrAnalysisNotFound if the commit has no completed analysis.
+	FindCompletedAnalysis(ctx context.Context, owner, repo, commitSHA string) (UUID, error)
+	GetStaticTests(ctx context.Context, analysisID UUID) ([]StaticTest, error)
+	// SaveTestExecutions replaces the results previously stored under reportName.
+	SaveTestExecutions(ctx context.Context, analysisID UUID, reportName string, matches []ExecutionMatch) error
+}
+
+// MatchExecutions links each execution to the static test it most likely ran.
+//
+// Candidates share the execution's name, its name with a parameter suffix such as "[1]" removed,
+// or its full title (suite chain and name joined by spaces, as jest-junit reports it).
+// Candidates are ranked by file agreement (reported file, then class name as a dotted path)
+// and suite chain agreement. An execution stays unmatched when the best rank is shared.
+func MatchExecutions(static []StaticTest, executions []TestExecution) []ExecutionMatch {
+	byName := make(map[string][]int)
+	for i, test := range static {
+		byName[test.Name] = append(byName[test.Name], i)
+		if len(test.SuitePath) > 0 {
+			title := strings.Join(append(slices.Clone(test.SuitePath), test.Name), " ")
+			byName[title] = append(byName[title], i)
+		}
+	}
+
+	matches := make([]ExecutionMatch, 0, len(executions))
+	for _, execution := range executions {
+		match := ExecutionMatch{Execution: execution}
+		if i, ok := bestStaticMatch(static, execution, candidateIndexes(byName, execution.Name)); ok {
+			id := static[i].ID
+			match.TestCaseID = &id
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+func candidateIndexes(byName map[string][]int, name string) []int {
+	if candidates := byName[name]; len(candidates) > 0 {
+		return candidates
+	}
+	if trimmed := trimParameterSuffix(name); trimmed != name {
+		return byName[trimmed]
+	}
+	return nil
+}
+
+func bestStaticMatch(static []StaticTest, execution TestExecution, candidates []int) (int, bool) {
+	best, bestScore, ties := -1, -1, 0
+	for _, i := range slices.Compact(slices.Sorted(slices.Values(candidates))) {
+		test := static[i]
+		if execution.File != "" && !reportPathMatches(test.FilePath, execution.File) {
+			continue
+		}
+
+		score := 0
+		if execution.File != "" {
+			score += 4
+		} else if execution.ClassName != "" && classNameMatchesFile(execution.ClassName, test.FilePath) {
+			score += 2
+		}
+		score += suiteAgreement(test.SuitePath, execution)
+
+		switch {
+		case score > bestScore:
+			best, bestScore, ties = i, score, 1
+		case score == bestScore:
+			ties++
+		}
+	}
+	return best, best >= 0 && ties == 1
+}
+
+// suiteAgreement scores 2 when the static chain is a suffix of the report's suite path,
+// and 1 when every suite of the chain appears in the report's suite path or class name.
+func suiteAgreement(chain []string, execution TestExecution) int {
+	if len(chain) == 0 {
+		return 0
+	}
+	reported := execution.SuitePath
+	if len(reported) >= len(chain) && slices.Equal(reported[len(reported)-len(chain):], chain) {
+		return 2
+	}
+	for _, suite := range chain {
+		if !slices.Contains(reported, suite) && !strings.Contains(execution.ClassName, suite) {
+			return 0
+		}
+	}
+	return 1
+}
+
+// reportPathMatches compares a repository-relative path with a reported path
+// that may be absolute or relative to a different working directory.
+func reportPathMatches(repoPath, reported string) bool {
+	reported = strings.TrimPrefix(path.Clean(strings.ReplaceAll(reported, "\\", "/")), "./")
+	return reported == repoPath ||
+		strings.HasSuffix(reported, "/"+repoPath) ||
+		strings.HasSuffix(repoPath, "/"+reported)
+}
+
+// classNameMatchesFile reports whether a dotted class or module name names the file,
+// e.g. "com.acme.CartTest" for "src/test/java/com/acme/CartTest.java" or
+// "tests.test_cart.TestCart" for "tests/test_cart.py".
+func classNameMatchesFile(className, filePath string) bool {
+	file := strings.TrimSuffix(filePath, path.Ext(filePath))
+	class := strings.ReplaceAll(className, ".", "/")
+	return class == file ||
+		strings.HasSuffix(file, "/"+class) ||
+		strings.HasPrefix(class, file+"/")
+}
+
+func trimParameterSuffix(name string) string {
+	for _, pair := range [][2]string{{"[", "]"}, {"(", ")"}} {
+		if strings.HasSuffix(name, pair[1]) {
+			if i := strings.LastIndex(name, pair[0]); i > 0 {
+				return strings.TrimSpace(name[:i])
+			}
+		}
+	}
+	return name
+}
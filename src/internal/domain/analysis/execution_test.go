@@ -0,0 +1,126 @@
+package analysis
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMatchExecutions(t *testing.T) {
+	idA := NewUUID()
+	idB := NewUUID()
+	idC := NewUUID()
+	idD := NewUUID()
+
+	static := []StaticTest{
+		{FilePath: "src/cart.test.ts", ID: idA, Name: "adds item", SuitePath: []string{"Cart"}},
+		{FilePath: "src/order.test.ts", ID: idB, Name: "adds item", SuitePath: []string{"Order"}},
+		{FilePath: "tests/test_cart.py", ID: idC, Name: "test_total", SuitePath: []string{"TestCart"}},
+		{FilePath: "src/test/java/com/acme/CartTest.java", ID: idD, Name: "checkout", SuitePath: []string{"CartTest"}},
+	}
+
+	tests := []struct {
+		name      string
+		execution TestExecution
+		want      *UUID
+	}{
+		{
+			name:      "reported file disambiguates",
+			execution: TestExecution{File: "/home/runner/work/app/src/order.test.ts", Name: "adds item"},
+			want:      &idB,
+		},
+		{
+			name:      "suite chain disambiguates",
+			execution: TestExecution{Name: "adds item", SuitePath: []string{"root", "Cart"}},
+			want:      &idA,
+		},
+		{
+			name:      "full title as reported by jest-junit",
+			execution: TestExecution{Name: "Cart adds item"},
+			want:      &idA,
+		},
+		{
+			name:      "python module class name",
+			execution: TestExecution{ClassName: "tests.test_cart.TestCart", Name: "test_total"},
+			want:      &idC,
+		},
+		{
+			name:      "java class name",
+			execution: TestExecution{ClassName: "com.acme.CartTest", Name: "checkout"},
+			want:      &idD,
+		},
+		{
+			name:      "parameterized name",
+			execution: TestExecution{ClassName: "tests.test_cart.TestCart", Name: "test_total[10-20]"},
+			want:      &idC,
+		},
+		{
+			name:      "ambiguous name stays unmatched",
+			execution: TestExecution{Name: "adds item"},
+			want:      nil,
+		},
+		{
+			name:      "unknown test",
+			execution: TestExecution{Name: "removes item"},
+			want:      nil,
+		},
+		{
+			name:      "reported file mismatch",
+			execution: TestExecution{File: "src/other.test.ts", Name: "adds item"},
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MatchExecutions(static, []TestExecution{tt.execution})
+			if len(got) != 1 {
+				t.Fatalf("expected 1 match, got %d", len(got))
+			}
+			switch {
+			case tt.want == nil && got[0].TestCaseID != nil:
+				t.Errorf("expected no match, got %v", *got[0].TestCaseID)
+			case tt.want != nil && got[0].TestCaseID == nil:
+				t.Errorf("expected %v, got no match", *tt.want)
+			case tt.want != nil && *got[0].TestCaseID != *tt.want:
+				t.Errorf("expected %v, got %v", *tt.want, *got[0].TestCaseID)
+			}
+		})
+	}
+}
+
+func TestIngestResultsRequest_Validate(t *testing.T) {
+	valid := IngestResultsRequest{
+		CommitSHA:  "abc123",
+		Format:     ReportFormatJUnit,
+		Owner:      "owner",
+		Repo:       "repo",
+		Report:     []byte("<testsuites/>"),
+		ReportName: "unit",
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected valid request, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*IngestResultsRequest)
+	}{
+		{name: "missing commit", modify: func(r *IngestResultsRequest) { r.CommitSHA = "" }},
+		{name: "unsupported format", modify: func(r *IngestResultsRequest) { r.Format = "trx" }},
+		{name: "missing report name", modify: func(r *IngestResultsRequest) { r.ReportName = "" }},
+		{name: "long report name", modify: func(r *IngestResultsRequest) { r.ReportName = strings.Repeat("x", MaxReportNameLength+1) }},
+		{name: "empty report", modify: func(r *IngestResultsRequest) { r.Report = nil }},
+		{name: "oversized report", modify: func(r *IngestResultsRequest) { r.Report = make([]byte, MaxReportSize+1) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.modify(&req)
+			if err := req.Validate(); !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+}
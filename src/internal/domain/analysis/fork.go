@@ -0,0 +1,103 @@
+package analysis
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// ComparedTest is a test located by file and suite path, which stay stable between
+// a fork and its upstream while line numbers drift.
+type ComparedTest struct {
+	FilePath string
+	Location Location
+	Name     string
+	// Suites are the names of the enclosing suites, outermost first.
+	Suites []string
+}
+
+// ForkComparison lists the tests a fork has added and removed relative to its upstream repository.
+type ForkComparison struct {
+	// Added tests exist only in the fork; their locations refer to Fork.
+	Added []ComparedTest
+	Fork  *SpecDocument
+	// Removed tests exist only upstream; their locations refer to Upstream.
+	Removed  []ComparedTest
+	Shared   int
+	Upstream *SpecDocument
+}
+
+// CompareFork matches tests by file path, suite path and name. Identical tests are matched
+// one to one, so a test duplicated in the fork counts as added. Added and Removed are
+// ordered by file path, then line.
+func CompareFork(fork, upstream *SpecDocument) *ForkComparison {
+	result := &ForkComparison{Fork: fork, Upstream: upstream}
+
+	upstreamTests := flattenTests(upstream.Inventory)
+	unmatched := make(map[string]int, len(upstreamTests))
+	for _, test := range upstreamTests {
+		unmatched[test.key()]++
+	}
+
+	for _, test := range flattenTests(fork.Inventory) {
+		key := test.key()
+		if unmatched[key] > 0 {
+			unmatched[key]--
+			result.Shared++
+			continue
+		}
+		result.Added = append(result.Added, test)
+	}
+
+	for _, test := range upstreamTests {
+		key := test.key()
+		if unmatched[key] > 0 {
+			unmatched[key]--
+			result.Removed = append(result.Removed, test)
+		}
+	}
+
+	sortComparedTests(result.Added)
+	sortComparedTests(result.Removed)
+	return result
+}
+
+func (t ComparedTest) key() string {
+	return t.FilePath + "\x00" + strings.Join(t.Suites, "\x00") + "\x00\x00" + t.Name
+}
+
+func flattenTests(inv *Inventory) []ComparedTest {
+	if inv == nil {
+		return nil
+	}
+	var tests []ComparedTest
+	for _, file := range inv.Files {
+		for _, test := range file.Tests {
+			tests = append(tests, ComparedTest{FilePath: file.Path, Location: test.Location, Name: test.Name})
+		}
+		for _, suite := range file.Suites {
+			tests = flattenSuite(tests, file.Path, nil, suite)
+		}
+	}
+	return tests
+}
+
+func flattenSuite(tests []ComparedTest, filePath string, parents []string, suite TestSuite) []ComparedTest {
+	suites := append(slices.Clip(parents), suite.Name)
+	for _, test := range suite.Tests {
+		tests = append(tests, ComparedTest{FilePath: filePath, Location: test.Location, Name: test.Name, Suites: suites})
+	}
+	for _, child := range suite.Suites {
+		tests = flattenSuite(tests, filePath, suites, child)
+	}
+	return tests
+}
+
+func sortComparedTests(tests []ComparedTest) {
+	slices.SortStableFunc(tests, func(a, b ComparedTest) int {
+		return cmp.Or(
+			cmp.Compare(a.FilePath, b.FilePath),
+			cmp.Compare(a.Location.StartLine, b.Location.StartLine),
+		)
+	})
+}
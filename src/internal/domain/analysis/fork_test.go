@@ -0,0 +1,77 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareFork(t *testing.T) {
+	upstream := &SpecDocument{Inventory: &Inventory{Files: []TestFile{
+		{
+			Path: "a.test.ts",
+			Suites: []TestSuite{{
+				Name: "Cart",
+				Suites: []TestSuite{{
+					Name:  "remove",
+					Tests: []Test{{Location: Location{StartLine: 12}, Name: "drops the item"}},
+				}},
+				Tests: []Test{
+					{Location: Location{StartLine: 4}, Name: "adds items"},
+					{Location: Location{StartLine: 8}, Name: "totals"},
+				},
+			}},
+		},
+		{Path: "b.test.ts", Tests: []Test{{Location: Location{StartLine: 1}, Name: "works"}}},
+	}}}
+	fork := &SpecDocument{Inventory: &Inventory{Files: []TestFile{
+		{
+			Path: "a.test.ts",
+			Suites: []TestSuite{{
+				Name: "Cart",
+				Tests: []Test{
+					{Location: Location{StartLine: 6}, Name: "adds items"},
+					{Location: Location{StartLine: 9}, Name: "adds items"},
+					{Location: Location{StartLine: 20}, Name: "applies coupons"},
+				},
+			}},
+			Tests: []Test{{Location: Location{StartLine: 30}, Name: "totals"}},
+		},
+		{Path: "b.test.ts", Tests: []Test{{Location: Location{StartLine: 3}, Name: "works"}}},
+	}}}
+
+	got := CompareFork(fork, upstream)
+
+	if got.Shared != 2 {
+		t.Errorf("Shared = %d, want 2", got.Shared)
+	}
+	wantAdded := []ComparedTest{
+		{FilePath: "a.test.ts", Location: Location{StartLine: 9}, Name: "adds items", Suites: []string{"Cart"}},
+		{FilePath: "a.test.ts", Location: Location{StartLine: 20}, Name: "applies coupons", Suites: []string{"Cart"}},
+		{FilePath: "a.test.ts", Location: Location{StartLine: 30}, Name: "totals"},
+	}
+	if !reflect.DeepEqual(got.Added, wantAdded) {
+		t.Errorf("Added = %+v, want %+v", got.Added, wantAdded)
+	}
+	wantRemoved := []ComparedTest{
+		{FilePath: "a.test.ts", Location: Location{StartLine: 8}, Name: "totals", Suites: []string{"Cart"}},
+		{FilePath: "a.test.ts", Location: Location{StartLine: 12}, Name: "drops the item", Suites: []string{"Cart", "remove"}},
+	}
+	if !reflect.DeepEqual(got.Removed, wantRemoved) {
+		t.Errorf("Removed = %+v, want %+v", got.Removed, wantRemoved)
+	}
+	if got.Fork != fork || got.Upstream != upstream {
+		t.Error("expected comparison to reference both documents")
+	}
+}
+
+func TestCompareFork_EmptyInventory(t *testing.T) {
+	upstream := &SpecDocument{Inventory: &Inventory{Files: []TestFile{
+		{Path: "a.test.ts", Tests: []Test{{Name: "works"}}},
+	}}}
+
+	got := CompareFork(&SpecDocument{}, upstream)
+
+	if got.Shared != 0 || len(got.Added) != 0 || len(got.Removed) != 1 {
+		t.Errorf("unexpected comparison: shared=%d added=%d removed=%d", got.Shared, len(got.Added), len(got.Removed))
+	}
+}
@@ -0,0 +1,24 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInstallationNotFound indicates the GitHub App is not installed on the account, or the installation is suspended.
+var ErrInstallationNotFound = errors.New("github app installation not found")
+
+// InstallationRepository finds GitHub App installations recorded for repository owners.
+type InstallationRepository interface {
+	// FindInstallationID returns ErrInstallationNotFound unless the account has an active installation.
+	FindInstallationID(ctx context.Context, accountLogin string) (int64, error)
+}
+
+// InstallationTokenProvider issues GitHub App installation tokens scoped to a single repository,
+// so private repositories can be cloned without a user's OAuth grant.
+//
+// Implementations should return ErrTokenNotFound when the app cannot access the repository
+// (expected, triggers graceful degradation) and other errors for infrastructure failures.
+type InstallationTokenProvider interface {
+	GetInstallationToken(ctx context.Context, owner, repo string) (string, error)
+}
@@ -7,8 +7,12 @@ type Inventory struct {
 type TestFile struct {
 	Path      string
 	Framework string
-	Suites    []TestSuite
-	Tests     []Test
+	// Owners are the CODEOWNERS entries (teams or users) owning the file.
+	Owners []string
+	// Package is the workspace path containing the file. Empty outside monorepo packages.
+	Package string
+	Suites  []TestSuite
+	Tests   []Test
 }
 
 type TestSuite struct {
@@ -19,6 +23,8 @@ type TestSuite struct {
 }
 
 type Test struct {
+	// Blame is nil when history was not analyzed or the line could not be attributed.
+	Blame    *Blame
 	Name     string
 	Location Location
 	Status   TestStatus
@@ -38,3 +44,53 @@ const (
 	TestStatusTodo    TestStatus = "todo"
 	TestStatusXfail   TestStatus = "xfail"
 )
+
+// TestCounts are the suite and test totals of an inventory or one of its files.
+// Suites are counted as they are stored: the top-level tests of a file form one implicit suite.
+type TestCounts struct {
+	ByStatus map[TestStatus]int
+	Suites   int
+	Tests    int
+}
+
+// Counts totals the suites and tests of all files. A nil inventory counts nothing.
+func (inv *Inventory) Counts() TestCounts {
+	counts := TestCounts{ByStatus: make(map[TestStatus]int)}
+	if inv == nil {
+		return counts
+	}
+	for _, file := range inv.Files {
+		counts.addFile(file)
+	}
+	return counts
+}
+
+// Counts totals the suites and tests of the file.
+func (f TestFile) Counts() TestCounts {
+	counts := TestCounts{ByStatus: make(map[TestStatus]int)}
+	counts.addFile(f)
+	return counts
+}
+
+func (c *TestCounts) addFile(file TestFile) {
+	if len(file.Tests) > 0 {
+		c.Suites++
+		c.addTests(file.Tests)
+	}
+	c.addSuites(file.Suites)
+}
+
+func (c *TestCounts) addSuites(suites []TestSuite) {
+	for _, suite := range suites {
+		c.Suites++
+		c.addTests(suite.Tests)
+		c.addSuites(suite.Suites)
+	}
+}
+
+func (c *TestCounts) addTests(tests []Test) {
+	for _, test := range tests {
+		c.ByStatus[test.Status]++
+		c.Tests++
+	}
+}
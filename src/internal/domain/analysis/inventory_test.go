@@ -0,0 +1,37 @@
+package analysis
+
+import "testing"
+
+func TestInventory_Counts(t *testing.T) {
+	file := TestFile{
+		Path:  "api/user_test.go",
+		Tests: []Test{{Name: "TestTop", Status: TestStatusSkipped}},
+		Suites: []TestSuite{
+			{
+				Name:   "User",
+				Tests:  []Test{{Name: "creates", Status: TestStatusActive}},
+				Suites: []TestSuite{{Name: "nested", Tests: []Test{{Name: "x", Status: TestStatusSkipped}}}},
+			},
+			{Name: "empty"},
+		},
+	}
+
+	got := file.Counts()
+	if got.Suites != 4 || got.Tests != 3 {
+		t.Errorf("expected 4 suites and 3 tests, got %+v", got)
+	}
+	if got.ByStatus[TestStatusSkipped] != 2 || got.ByStatus[TestStatusActive] != 1 {
+		t.Errorf("unexpected status counts: %v", got.ByStatus)
+	}
+
+	inventory := &Inventory{Files: []TestFile{file, {Path: "b_test.go", Tests: []Test{{Name: "TestB", Status: TestStatusTodo}}}}}
+	total := inventory.Counts()
+	if total.Suites != 5 || total.Tests != 4 || total.ByStatus[TestStatusTodo] != 1 {
+		t.Errorf("unexpected inventory counts: %+v", total)
+	}
+
+	var empty *Inventory
+	if counts := empty.Counts(); counts.Suites != 0 || counts.Tests != 0 || counts.ByStatus == nil {
+		t.Errorf("expected empty counts for nil inventory, got %+v", counts)
+	}
+}
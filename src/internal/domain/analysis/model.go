@@ -9,7 +9,11 @@ type AnalyzeRequest struct {
 	Owner     string
 	Repo      string
 	CommitSHA string
-	UserID    *string
+	// ExactCommit checks out CommitSHA instead of analyzing the default branch HEAD.
+	// Used by historical backfill and release tags; the analysis is recorded as historical
+	// unless CommitSHA is still the HEAD commit.
+	ExactCommit bool
+	UserID      *string
 }
 
 func (r AnalyzeRequest) Validate() error {
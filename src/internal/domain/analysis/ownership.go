@@ -0,0 +1,77 @@
+package analysis
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// CodeownersRule is a single CODEOWNERS line.
+// An empty Owners list explicitly leaves matching paths without an owner.
+type CodeownersRule struct {
+	Owners  []string
+	Pattern string
+}
+
+// Codeowners holds the rules of a CODEOWNERS file in file order.
+type Codeowners struct {
+	Path  string
+	Rules []CodeownersRule
+}
+
+// CodeownersLoader reads the CODEOWNERS file of a cloned source.
+// Returns nil without error when the repository has no CODEOWNERS file.
+type CodeownersLoader interface {
+	Load(ctx context.Context, src Source) (*Codeowners, error)
+}
+
+// OwnersOf returns the owners of filePath. The last matching rule wins, as on GitHub.
+func (c *Codeowners) OwnersOf(filePath string) []string {
+	if c == nil {
+		return nil
+	}
+	for i := len(c.Rules) - 1; i >= 0; i-- {
+		if matchCodeownersPattern(c.Rules[i].Pattern, filePath) {
+			return c.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// AssignOwners sets TestFile.Owners from the CODEOWNERS rules.
+func AssignOwners(inventory *Inventory, codeowners *Codeowners) {
+	if inventory == nil || codeowners == nil {
+		return
+	}
+	for i := range inventory.Files {
+		inventory.Files[i].Owners = codeowners.OwnersOf(inventory.Files[i].Path)
+	}
+}
+
+// matchCodeownersPattern applies gitignore-style semantics:
+// a leading or inner slash anchors the pattern to the root, otherwise it matches at any depth;
+// a pattern naming a directory matches everything below it, except "dir/*" which stays one level deep.
+func matchCodeownersPattern(pattern, filePath string) bool {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	p := strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return false
+	}
+	if !anchored && !strings.HasPrefix(p, "**") {
+		p = "**/" + p
+	}
+
+	if !dirOnly {
+		if matched, _ := doublestar.Match(p, filePath); matched {
+			return true
+		}
+	}
+	if strings.HasSuffix(p, "/*") {
+		return false
+	}
+	matched, _ := doublestar.Match(p+"/**", filePath)
+	return matched
+}
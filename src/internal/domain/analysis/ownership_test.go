@@ -0,0 +1,70 @@
+package analysis
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCodeowners_OwnersOf(t *testing.T) {
+	codeowners := &Codeowners{
+		Rules: []CodeownersRule{
+			{Pattern: "*", Owners: []string{"@org/everyone"}},
+			{Pattern: "*.go", Owners: []string{"@org/backend"}},
+			{Pattern: "/web/", Owners: []string{"@org/frontend"}},
+			{Pattern: "docs/*", Owners: []string{"docs@example.com"}},
+			{Pattern: "fixtures/", Owners: []string{"@qa"}},
+			{Pattern: "web/legacy/**", Owners: []string{}},
+			{Pattern: "/services/billing", Owners: []string{"@org/billing", "@alice"}},
+		},
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "README.md", want: []string{"@org/everyone"}},
+		{path: "pkg/order_test.go", want: []string{"@org/backend"}},
+		{path: "web/src/app.test.ts", want: []string{"@org/frontend"}},
+		{path: "docs/guide.test.md", want: []string{"docs@example.com"}},
+		{path: "docs/nested/guide.test.md", want: []string{"@org/everyone"}},
+		{path: "pkg/fixtures/data/x_test.go", want: []string{"@qa"}},
+		{path: "web/legacy/old.test.js", want: []string{}},
+		{path: "services/billing/invoice_test.go", want: []string{"@org/billing", "@alice"}},
+		{path: "other/services/billing/invoice_test.py", want: []string{"@org/everyone"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got := codeowners.OwnersOf(tt.path)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("OwnersOf(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("nil codeowners", func(t *testing.T) {
+		var c *Codeowners
+		if got := c.OwnersOf("a.go"); got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+}
+
+func TestAssignOwners(t *testing.T) {
+	inventory := &Inventory{
+		Files: []TestFile{
+			{Path: "api/user_test.go"},
+			{Path: "web/user.test.ts"},
+		},
+	}
+	codeowners := &Codeowners{Rules: []CodeownersRule{{Pattern: "/api/", Owners: []string{"@org/api"}}}}
+
+	AssignOwners(inventory, codeowners)
+
+	if !slices.Equal(inventory.Files[0].Owners, []string{"@org/api"}) {
+		t.Errorf("expected @org/api, got %v", inventory.Files[0].Owners)
+	}
+	if len(inventory.Files[1].Owners) != 0 {
+		t.Errorf("expected no owners, got %v", inventory.Files[1].Owners)
+	}
+}
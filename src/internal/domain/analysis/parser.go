@@ -1,7 +1,71 @@
 package analysis
 
-import "context"
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
 
 type Parser interface {
-	Scan(ctx context.Context, src Source) (*Inventory, error)
+	Scan(ctx context.Context, src Source, opts ScanOptions) (*Inventory, error)
+}
+
+// ScanOptions narrows which test files a scan reports.
+// Path patterns are doublestar globs matched against slash-separated paths relative to the root.
+// Framework names are compared case-insensitively. Empty lists impose no restriction.
+type ScanOptions struct {
+	Exclude        []string
+	FrameworkAllow []string
+	FrameworkDeny  []string
+	Include        []string
+}
+
+// Matches reports whether a test file passes the path and framework filters.
+func (o ScanOptions) Matches(file TestFile) bool {
+	if len(o.Include) > 0 && !MatchesAnyGlob(o.Include, file.Path) {
+		return false
+	}
+	if MatchesAnyGlob(o.Exclude, file.Path) {
+		return false
+	}
+	if len(o.FrameworkAllow) > 0 && !containsFold(o.FrameworkAllow, file.Framework) {
+		return false
+	}
+	if containsFold(o.FrameworkDeny, file.Framework) {
+		return false
+	}
+	return true
+}
+
+// Filter returns an inventory containing only the files accepted by Matches.
+func (o ScanOptions) Filter(inventory *Inventory) *Inventory {
+	if inventory == nil {
+		return nil
+	}
+	files := make([]TestFile, 0, len(inventory.Files))
+	for _, f := range inventory.Files {
+		if o.Matches(f) {
+			files = append(files, f)
+		}
+	}
+	return &Inventory{Files: files}
+}
+
+// MatchesAnyGlob reports whether the slash-separated path p matches any doublestar pattern.
+// Invalid patterns match nothing.
+func MatchesAnyGlob(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		if matched, err := doublestar.Match(pattern, p); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, v string) bool {
+	return slices.ContainsFunc(values, func(s string) bool {
+		return strings.EqualFold(s, v)
+	})
 }
@@ -0,0 +1,112 @@
+package analysis
+
+import "testing"
+
+func TestScanOptions_Matches(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ScanOptions
+		file TestFile
+		want bool
+	}{
+		{
+			name: "empty options match everything",
+			opts: ScanOptions{},
+			file: TestFile{Path: "src/a.test.ts", Framework: "jest"},
+			want: true,
+		},
+		{
+			name: "include matches",
+			opts: ScanOptions{Include: []string{"packages/**"}},
+			file: TestFile{Path: "packages/api/a.test.ts"},
+			want: true,
+		},
+		{
+			name: "include does not match",
+			opts: ScanOptions{Include: []string{"packages/**"}},
+			file: TestFile{Path: "scripts/a.test.ts"},
+			want: false,
+		},
+		{
+			name: "exclude matches",
+			opts: ScanOptions{Exclude: []string{"**/fixtures/**"}},
+			file: TestFile{Path: "src/fixtures/sample.test.ts"},
+			want: false,
+		},
+		{
+			name: "exclude wins over include",
+			opts: ScanOptions{Include: []string{"src/**"}, Exclude: []string{"src/vendor/**"}},
+			file: TestFile{Path: "src/vendor/lib_test.go"},
+			want: false,
+		},
+		{
+			name: "framework allow is case-insensitive",
+			opts: ScanOptions{FrameworkAllow: []string{"Jest"}},
+			file: TestFile{Path: "a.test.ts", Framework: "jest"},
+			want: true,
+		},
+		{
+			name: "framework not in allow list",
+			opts: ScanOptions{FrameworkAllow: []string{"jest"}},
+			file: TestFile{Path: "a.spec.ts", Framework: "mocha"},
+			want: false,
+		},
+		{
+			name: "framework denied",
+			opts: ScanOptions{FrameworkDeny: []string{"mocha"}},
+			file: TestFile{Path: "a.spec.ts", Framework: "mocha"},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.Matches(tt.file); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanOptions_Filter(t *testing.T) {
+	t.Run("nil inventory", func(t *testing.T) {
+		if got := (ScanOptions{}).Filter(nil); got != nil {
+			t.Errorf("expected nil, got %+v", got)
+		}
+	})
+
+	t.Run("keeps matching files only", func(t *testing.T) {
+		inventory := &Inventory{Files: []TestFile{
+			{Path: "src/a_test.go"},
+			{Path: "vendor/x/b_test.go"},
+		}}
+
+		got := ScanOptions{Exclude: []string{"vendor/**"}}.Filter(inventory)
+
+		if len(got.Files) != 1 || got.Files[0].Path != "src/a_test.go" {
+			t.Errorf("unexpected files: %+v", got.Files)
+		}
+	})
+}
+
+func TestMatchesAnyGlob(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		want     bool
+	}{
+		{name: "doublestar", patterns: []string{"packages/**"}, path: "packages/a/b.ts", want: true},
+		{name: "any pattern", patterns: []string{"docs/*", "*.yml"}, path: "ci.yml", want: true},
+		{name: "no match", patterns: []string{"packages/*"}, path: "packages/a/b.ts", want: false},
+		{name: "invalid pattern", patterns: []string{"src/[a"}, path: "src/[a", want: false},
+		{name: "no patterns", path: "a.ts", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesAnyGlob(tt.patterns, tt.path); got != tt.want {
+				t.Errorf("MatchesAnyGlob(%v, %q) = %v, want %v", tt.patterns, tt.path, got, tt.want)
+			}
+		})
+	}
+}
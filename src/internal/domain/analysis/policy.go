@@ -0,0 +1,248 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// PolicyRuleKind identifies how a policy rule is evaluated against an inventory.
+type PolicyRuleKind string
+
+const (
+	// PolicyRuleKindMaxStatusCount fails when more than Threshold tests have Status.
+	// Example: "no focused tests" is {Status: focused, Threshold: 0}.
+	PolicyRuleKindMaxStatusCount PolicyRuleKind = "max_status_count"
+	// PolicyRuleKindMaxStatusRatio fails when the ratio of tests with Status exceeds Threshold (0..1).
+	// Example: "skipped ratio < 5%" is {Status: skipped, Threshold: 0.05}.
+	PolicyRuleKindMaxStatusRatio PolicyRuleKind = "max_status_ratio"
+	// PolicyRuleKindRequireTestFile fails when a source file matching Path has no corresponding test file.
+	// Example: "every file under src/ has a test file" is {Path: "src/**"}.
+	PolicyRuleKindRequireTestFile PolicyRuleKind = "require_test_file"
+)
+
+// PolicySource identifies where a policy rule was declared.
+type PolicySource string
+
+const (
+	PolicySourceCodebase   PolicySource = "codebase"
+	PolicySourceRepository PolicySource = "repository"
+)
+
+const maxPolicyDetailFiles = 10
+
+// testFileMarkers are stripped from test file stems to find the source file they cover.
+var testFileMarkers = []string{".test", ".spec", "_test", "_spec", "-test", "-spec", "Tests", "Test", "Spec"}
+
+// PolicyRule is a user-defined constraint evaluated over an analysis inventory.
+type PolicyRule struct {
+	Kind      PolicyRuleKind
+	Name      string
+	Path      string
+	Source    PolicySource
+	Status    TestStatus
+	Threshold float64
+}
+
+func (r PolicyRule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("%w: policy name is required", ErrInvalidInput)
+	}
+	switch r.Kind {
+	case PolicyRuleKindMaxStatusCount:
+		if !isKnownTestStatus(r.Status) {
+			return fmt.Errorf("%w: policy %q has unknown status %q", ErrInvalidInput, r.Name, r.Status)
+		}
+		if r.Threshold < 0 {
+			return fmt.Errorf("%w: policy %q threshold must not be negative", ErrInvalidInput, r.Name)
+		}
+	case PolicyRuleKindMaxStatusRatio:
+		if !isKnownTestStatus(r.Status) {
+			return fmt.Errorf("%w: policy %q has unknown status %q", ErrInvalidInput, r.Name, r.Status)
+		}
+		if r.Threshold < 0 || r.Threshold > 1 {
+			return fmt.Errorf("%w: policy %q threshold must be between 0 and 1", ErrInvalidInput, r.Name)
+		}
+	case PolicyRuleKindRequireTestFile:
+		if r.Path == "" {
+			return fmt.Errorf("%w: policy %q requires a path pattern", ErrInvalidInput, r.Name)
+		}
+		if !doublestar.ValidatePattern(r.Path) {
+			return fmt.Errorf("%w: policy %q has invalid path pattern %q", ErrInvalidInput, r.Name, r.Path)
+		}
+	default:
+		return fmt.Errorf("%w: policy %q has unknown kind %q", ErrInvalidInput, r.Name, r.Kind)
+	}
+	return nil
+}
+
+// PolicyResult is the outcome of evaluating a single PolicyRule.
+type PolicyResult struct {
+	Detail string
+	Kind   PolicyRuleKind
+	Name   string
+	Passed bool
+	Source PolicySource
+}
+
+// InvalidPolicyResult is the failed result recorded for a rule rejected by Validate.
+func InvalidPolicyResult(rule PolicyRule, err error) PolicyResult {
+	return PolicyResult{
+		Detail: fmt.Sprintf("rule skipped: %v", err),
+		Kind:   rule.Kind,
+		Name:   rule.Name,
+		Source: rule.Source,
+	}
+}
+
+// PolicyRuleRepository provides policy rules configured at the codebase level.
+type PolicyRuleRepository interface {
+	GetCodebasePolicyRules(ctx context.Context, codebaseID UUID) ([]PolicyRule, error)
+}
+
+// MergePolicyRules combines repository and codebase rules.
+// Codebase rules override repository rules with the same name.
+func MergePolicyRules(repoRules, codebaseRules []PolicyRule) []PolicyRule {
+	merged := make([]PolicyRule, 0, len(repoRules)+len(codebaseRules))
+	overridden := make(map[string]bool, len(codebaseRules))
+	for _, r := range codebaseRules {
+		overridden[r.Name] = true
+	}
+	for _, r := range repoRules {
+		if !overridden[r.Name] {
+			merged = append(merged, r)
+		}
+	}
+	return append(merged, codebaseRules...)
+}
+
+// HasRequireTestFileRule reports whether any rule needs the source file listing.
+func HasRequireTestFileRule(rules []PolicyRule) bool {
+	return slices.ContainsFunc(rules, func(r PolicyRule) bool {
+		return r.Kind == PolicyRuleKindRequireTestFile
+	})
+}
+
+// EvaluatePolicies evaluates rules against the inventory.
+// files lists all repository file paths (slash-separated, relative to the root)
+// and is only consulted by require_test_file rules.
+func EvaluatePolicies(rules []PolicyRule, inventory *Inventory, files []string) []PolicyResult {
+	counts := inventory.Counts()
+
+	results := make([]PolicyResult, 0, len(rules))
+	for _, rule := range rules {
+		result := PolicyResult{
+			Kind:   rule.Kind,
+			Name:   rule.Name,
+			Source: rule.Source,
+		}
+
+		switch rule.Kind {
+		case PolicyRuleKindMaxStatusCount:
+			count := counts.ByStatus[rule.Status]
+			result.Passed = float64(count) <= rule.Threshold
+			result.Detail = fmt.Sprintf("%d %s tests (max %g)", count, rule.Status, rule.Threshold)
+		case PolicyRuleKindMaxStatusRatio:
+			ratio := 0.0
+			if counts.Tests > 0 {
+				ratio = float64(counts.ByStatus[rule.Status]) / float64(counts.Tests)
+			}
+			result.Passed = ratio <= rule.Threshold
+			result.Detail = fmt.Sprintf("%.2f%% %s tests (max %.2f%%)", ratio*100, rule.Status, rule.Threshold*100)
+		case PolicyRuleKindRequireTestFile:
+			untested := findUntestedFiles(rule.Path, inventory, files)
+			result.Passed = len(untested) == 0
+			result.Detail = formatUntestedDetail(untested)
+		default:
+			result.Detail = fmt.Sprintf("unknown policy kind %q", rule.Kind)
+		}
+
+		results = append(results, result)
+	}
+	return results
+}
+
+func isKnownTestStatus(status TestStatus) bool {
+	switch status {
+	case TestStatusActive, TestStatusFocused, TestStatusSkipped, TestStatusTodo, TestStatusXfail:
+		return true
+	default:
+		return false
+	}
+}
+
+func findUntestedFiles(pattern string, inventory *Inventory, files []string) []string {
+	testFiles := make(map[string]bool)
+	testedStems := make(map[string]bool)
+	if inventory != nil {
+		for _, f := range inventory.Files {
+			testFiles[f.Path] = true
+			testedStems[TestFileStem(f.Path)] = true
+		}
+	}
+
+	var untested []string
+	for _, file := range files {
+		if testFiles[file] {
+			continue
+		}
+		matched, err := doublestar.Match(pattern, file)
+		if err != nil || !matched {
+			continue
+		}
+		if !testedStems[fileStem(file)] {
+			untested = append(untested, file)
+		}
+	}
+	return untested
+}
+
+func formatUntestedDetail(untested []string) string {
+	if len(untested) == 0 {
+		return "all matching files have a test file"
+	}
+	shown := untested
+	if len(shown) > maxPolicyDetailFiles {
+		shown = shown[:maxPolicyDetailFiles]
+	}
+	detail := fmt.Sprintf("%d files without a test file: %s", len(untested), strings.Join(shown, ", "))
+	if len(untested) > len(shown) {
+		detail += ", ..."
+	}
+	return detail
+}
+
+// TestFileStem returns the lower-cased base name of a test file with its extension
+// and test markers removed (e.g. "src/FooTest.java" and "foo.spec.ts" both yield "foo").
+func TestFileStem(p string) string {
+	stem := strings.TrimSuffix(path.Base(p), path.Ext(p))
+	for _, marker := range testFileMarkers {
+		if trimmed := strings.TrimSuffix(stem, marker); trimmed != stem && trimmed != "" {
+			stem = trimmed
+			break
+		}
+	}
+	if trimmed, ok := strings.CutPrefix(stem, "test_"); ok && trimmed != "" {
+		stem = trimmed
+	} else if trimmed, ok := strings.CutPrefix(stem, "Test"); ok && startsWord(trimmed) {
+		stem = trimmed
+	}
+	return strings.ToLower(stem)
+}
+
+// startsWord reports whether s begins a new camel-case or snake-case word, so that a "Test"
+// prefix is told apart from words like "Testimonial" or "Testing".
+func startsWord(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r == '_' || unicode.IsUpper(r)
+}
+
+func fileStem(p string) string {
+	return strings.ToLower(strings.TrimSuffix(path.Base(p), path.Ext(p)))
+}
@@ -0,0 +1,225 @@
+package analysis
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPolicyRule_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    PolicyRule
+		wantErr error
+	}{
+		{
+			name:    "valid max status count",
+			rule:    PolicyRule{Name: "no-focused", Kind: PolicyRuleKindMaxStatusCount, Status: TestStatusFocused},
+			wantErr: nil,
+		},
+		{
+			name:    "valid max status ratio",
+			rule:    PolicyRule{Name: "skipped", Kind: PolicyRuleKindMaxStatusRatio, Status: TestStatusSkipped, Threshold: 0.05},
+			wantErr: nil,
+		},
+		{
+			name:    "valid require test file",
+			rule:    PolicyRule{Name: "src", Kind: PolicyRuleKindRequireTestFile, Path: "src/**/*.ts"},
+			wantErr: nil,
+		},
+		{
+			name:    "empty name",
+			rule:    PolicyRule{Kind: PolicyRuleKindMaxStatusCount, Status: TestStatusFocused},
+			wantErr: ErrInvalidInput,
+		},
+		{
+			name:    "unknown kind",
+			rule:    PolicyRule{Name: "x", Kind: "max_smells"},
+			wantErr: ErrInvalidInput,
+		},
+		{
+			name:    "unknown status",
+			rule:    PolicyRule{Name: "x", Kind: PolicyRuleKindMaxStatusCount, Status: "broken"},
+			wantErr: ErrInvalidInput,
+		},
+		{
+			name:    "negative count threshold",
+			rule:    PolicyRule{Name: "x", Kind: PolicyRuleKindMaxStatusCount, Status: TestStatusFocused, Threshold: -1},
+			wantErr: ErrInvalidInput,
+		},
+		{
+			name:    "ratio threshold above 1",
+			rule:    PolicyRule{Name: "x", Kind: PolicyRuleKindMaxStatusRatio, Status: TestStatusSkipped, Threshold: 5},
+			wantErr: ErrInvalidInput,
+		},
+		{
+			name:    "require test file without path",
+			rule:    PolicyRule{Name: "x", Kind: PolicyRuleKindRequireTestFile},
+			wantErr: ErrInvalidInput,
+		},
+		{
+			name:    "require test file with invalid pattern",
+			rule:    PolicyRule{Name: "x", Kind: PolicyRuleKindRequireTestFile, Path: "src/[a"},
+			wantErr: ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMergePolicyRules(t *testing.T) {
+	repoRules := []PolicyRule{
+		{Name: "no-focused", Source: PolicySourceRepository, Threshold: 3},
+		{Name: "skipped", Source: PolicySourceRepository},
+	}
+	codebaseRules := []PolicyRule{
+		{Name: "no-focused", Source: PolicySourceCodebase, Threshold: 0},
+	}
+
+	merged := MergePolicyRules(repoRules, codebaseRules)
+
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(merged))
+	}
+	for _, r := range merged {
+		if r.Name == "no-focused" && r.Source != PolicySourceCodebase {
+			t.Errorf("expected codebase rule to override repository rule, got %+v", r)
+		}
+	}
+}
+
+func TestEvaluatePolicies(t *testing.T) {
+	inventory := &Inventory{
+		Files: []TestFile{
+			{
+				Path: "src/user.test.ts",
+				Suites: []TestSuite{
+					{
+						Name: "User",
+						Tests: []Test{
+							{Name: "creates", Status: TestStatusActive},
+							{Name: "deletes", Status: TestStatusSkipped},
+						},
+						Suites: []TestSuite{
+							{Name: "nested", Tests: []Test{{Name: "focused", Status: TestStatusFocused}}},
+						},
+					},
+				},
+			},
+			{
+				Path:  "pkg/order_test.go",
+				Tests: []Test{{Name: "TestOrder", Status: TestStatusActive}},
+			},
+		},
+	}
+	files := []string{"src/user.ts", "src/account.ts", "src/user.test.ts", "pkg/order.go", "README.md"}
+
+	tests := []struct {
+		name         string
+		rule         PolicyRule
+		wantPassed   bool
+		detailSubstr string
+	}{
+		{
+			name:         "focused count exceeds zero",
+			rule:         PolicyRule{Name: "no-focused", Kind: PolicyRuleKindMaxStatusCount, Status: TestStatusFocused},
+			wantPassed:   false,
+			detailSubstr: "1 focused tests",
+		},
+		{
+			name:       "focused count within threshold",
+			rule:       PolicyRule{Name: "few-focused", Kind: PolicyRuleKindMaxStatusCount, Status: TestStatusFocused, Threshold: 1},
+			wantPassed: true,
+		},
+		{
+			name:         "skipped ratio above threshold",
+			rule:         PolicyRule{Name: "skipped", Kind: PolicyRuleKindMaxStatusRatio, Status: TestStatusSkipped, Threshold: 0.05},
+			wantPassed:   false,
+			detailSubstr: "25.00% skipped tests",
+		},
+		{
+			name:       "skipped ratio within threshold",
+			rule:       PolicyRule{Name: "skipped", Kind: PolicyRuleKindMaxStatusRatio, Status: TestStatusSkipped, Threshold: 0.5},
+			wantPassed: true,
+		},
+		{
+			name:         "untested source file",
+			rule:         PolicyRule{Name: "src", Kind: PolicyRuleKindRequireTestFile, Path: "src/**"},
+			wantPassed:   false,
+			detailSubstr: "src/account.ts",
+		},
+		{
+			name:       "all go files tested",
+			rule:       PolicyRule{Name: "pkg", Kind: PolicyRuleKindRequireTestFile, Path: "pkg/**/*.go"},
+			wantPassed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := EvaluatePolicies([]PolicyRule{tt.rule}, inventory, files)
+			if len(results) != 1 {
+				t.Fatalf("expected 1 result, got %d", len(results))
+			}
+			got := results[0]
+			if got.Passed != tt.wantPassed {
+				t.Errorf("expected passed=%v, got %v (detail: %s)", tt.wantPassed, got.Passed, got.Detail)
+			}
+			if got.Name != tt.rule.Name {
+				t.Errorf("expected name %q, got %q", tt.rule.Name, got.Name)
+			}
+			if tt.detailSubstr != "" && !strings.Contains(got.Detail, tt.detailSubstr) {
+				t.Errorf("expected detail to contain %q, got %q", tt.detailSubstr, got.Detail)
+			}
+		})
+	}
+
+	t.Run("empty inventory passes ratio rule", func(t *testing.T) {
+		rule := PolicyRule{Name: "skipped", Kind: PolicyRuleKindMaxStatusRatio, Status: TestStatusSkipped}
+		results := EvaluatePolicies([]PolicyRule{rule}, &Inventory{}, nil)
+		if !results[0].Passed {
+			t.Errorf("expected pass for empty inventory, got detail %q", results[0].Detail)
+		}
+	})
+}
+
+func TestTestFileStem(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "src/user.test.ts", want: "user"},
+		{path: "src/user.spec.js", want: "user"},
+		{path: "pkg/order_test.go", want: "order"},
+		{path: "tests/test_payment.py", want: "payment"},
+		{path: "src/test/java/UserServiceTest.java", want: "userservice"},
+		{path: "Tests/UserTests.cs", want: "user"},
+		{path: "spec/user_spec.rb", want: "user"},
+		{path: "test.go", want: "test"},
+		{path: "src/TestUserService.java", want: "userservice"},
+		{path: "pkg/TestUtils.go", want: "utils"},
+		{path: "src/Testimonial.java", want: "testimonial"},
+		{path: "src/TestingModule.ts", want: "testingmodule"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := TestFileStem(tt.path); got != tt.want {
+				t.Errorf("TestFileStem(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
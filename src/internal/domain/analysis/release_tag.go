@@ -0,0 +1,45 @@
+package analysis
+
+import "context"
+
+// ReleaseTag is a git tag resolved to the commit it points at.
+type ReleaseTag struct {
+	Name string
+	SHA  string
+}
+
+type TagLister interface {
+	// ListRemoteTags returns the tags of the remote repository.
+	// Annotated tags are peeled so SHA is always a commit.
+	ListRemoteTags(ctx context.Context, url string, token *string) ([]ReleaseTag, error)
+}
+
+// DeployCredentialTagLister lists the tags of repositories read with a deploy credential.
+type DeployCredentialTagLister interface {
+	ListRemoteTagsWithCredential(ctx context.Context, url string, credential DeployCredential) ([]ReleaseTag, error)
+}
+
+// ReleaseTagRepository records the tags seen per codebase.
+// A saved tag is linked to the completed analysis of its commit once one exists.
+type ReleaseTagRepository interface {
+	GetCodebaseTags(ctx context.Context, codebaseID UUID) ([]ReleaseTag, error)
+	SaveCodebaseTags(ctx context.Context, codebaseID UUID, tags []ReleaseTag) error
+}
+
+// NewReleaseTags returns the remote tags that are unknown or now point at a different commit.
+// Order of remote is preserved.
+func NewReleaseTags(known, remote []ReleaseTag) []ReleaseTag {
+	seen := make(map[string]string, len(known))
+	for _, tag := range known {
+		seen[tag.Name] = tag.SHA
+	}
+
+	var fresh []ReleaseTag
+	for _, tag := range remote {
+		if sha, ok := seen[tag.Name]; ok && sha == tag.SHA {
+			continue
+		}
+		fresh = append(fresh, tag)
+	}
+	return fresh
+}
@@ -0,0 +1,48 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewReleaseTags(t *testing.T) {
+	tests := []struct {
+		name   string
+		known  []ReleaseTag
+		remote []ReleaseTag
+		want   []ReleaseTag
+	}{
+		{
+			name:   "nothing known",
+			remote: []ReleaseTag{{Name: "v1.0.0", SHA: "a"}},
+			want:   []ReleaseTag{{Name: "v1.0.0", SHA: "a"}},
+		},
+		{
+			name:   "unchanged tags are skipped",
+			known:  []ReleaseTag{{Name: "v1.0.0", SHA: "a"}},
+			remote: []ReleaseTag{{Name: "v1.0.0", SHA: "a"}, {Name: "v1.1.0", SHA: "b"}},
+			want:   []ReleaseTag{{Name: "v1.1.0", SHA: "b"}},
+		},
+		{
+			name:   "moved tag is new",
+			known:  []ReleaseTag{{Name: "latest", SHA: "a"}},
+			remote: []ReleaseTag{{Name: "latest", SHA: "b"}},
+			want:   []ReleaseTag{{Name: "latest", SHA: "b"}},
+		},
+		{
+			name:   "deleted tags are ignored",
+			known:  []ReleaseTag{{Name: "v0.9.0", SHA: "z"}},
+			remote: nil,
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewReleaseTags(tt.known, tt.remote)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
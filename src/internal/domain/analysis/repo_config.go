@@ -0,0 +1,52 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidRepoConfig indicates the repository config file exists but cannot be applied.
+var ErrInvalidRepoConfig = errors.New("invalid repository config")
+
+// RepoConfig holds settings declared by a repository in its config file.
+type RepoConfig struct {
+	Exclude        []string
+	FrameworkAllow []string
+	FrameworkDeny  []string
+	// Hash identifies the applied config file content. Empty when no config file exists.
+	Hash    string
+	Include []string
+	// InvalidPolicies are the failed results of declared policy rules that did not validate.
+	// They are reported instead of evaluated, so one broken rule does not discard the rest.
+	InvalidPolicies []PolicyResult
+	Policies        []PolicyRule
+	// WorkspaceRoots lists monorepo package directories (globs allowed),
+	// overriding workspace auto-detection when set.
+	WorkspaceRoots []string
+}
+
+// ScanOptions returns the scan filters declared by the config.
+func (c RepoConfig) ScanOptions() ScanOptions {
+	return ScanOptions{
+		Exclude:        c.Exclude,
+		FrameworkAllow: c.FrameworkAllow,
+		FrameworkDeny:  c.FrameworkDeny,
+		Include:        c.Include,
+	}
+}
+
+// RepoConfigLoader reads the repository config from a cloned source.
+//
+// Implementations should return:
+//   - empty RepoConfig: when the repository has no config file
+//   - ErrInvalidRepoConfig: when the file cannot be parsed or declares invalid path globs
+//
+// Invalid policy rules do not fail the load; they are returned in InvalidPolicies.
+type RepoConfigLoader interface {
+	Load(ctx context.Context, src Source) (*RepoConfig, error)
+}
+
+// FileLister lists all files of a cloned source as slash-separated paths relative to its root.
+type FileLister interface {
+	ListFiles(ctx context.Context, src Source) ([]string, error)
+}
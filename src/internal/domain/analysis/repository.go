@@ -18,8 +18,10 @@ type CreateAnalysisRecordParams struct {
 	CodebaseID     *UUID
 	CommitSHA      string
 	ExternalRepoID string
-	Owner          string
-	Repo           string
+	// IsHistorical marks an analysis of a past commit, which never counts as the codebase's latest.
+	IsHistorical bool
+	Owner        string
+	Repo         string
 }
 
 func (p CreateAnalysisRecordParams) Validate() error {
@@ -43,10 +45,21 @@ func (p CreateAnalysisRecordParams) Validate() error {
 }
 
 type SaveAnalysisInventoryParams struct {
-	AnalysisID  UUID
-	CommittedAt time.Time
-	Inventory   *Inventory
-	UserID      *string
+	AnalysisID UUID
+	// CIReport is nil when the repository has no recognized CI configuration.
+	CIReport *CIReport
+	// Churn is nil when test file history was not read.
+	Churn         *ChurnReport
+	CommittedAt   time.Time
+	ConfigHash    string
+	Inventory     *Inventory
+	PolicyResults []PolicyResult
+	// SourceCensus is nil when source lines were not counted.
+	SourceCensus *SourceCensus
+	// SourceMapping is nil when the source tree was not listed.
+	SourceMapping *SourceMapping
+	UserID        *string
+	Workspaces    []Workspace
 }
 
 func (p SaveAnalysisInventoryParams) Validate() error {
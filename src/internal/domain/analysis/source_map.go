@@ -0,0 +1,210 @@
+package analysis
+
+import (
+	"path"
+	"slices"
+	"strings"
+)
+
+// sourceLanguages maps production source extensions to their language.
+var sourceLanguages = map[string]string{
+	".c":     "c",
+	".cc":    "cpp",
+	".cjs":   "javascript",
+	".cpp":   "cpp",
+	".cs":    "csharp",
+	".cts":   "typescript",
+	".cxx":   "cpp",
+	".dart":  "dart",
+	".ex":    "elixir",
+	".go":    "go",
+	".h":     "c",
+	".hpp":   "cpp",
+	".java":  "java",
+	".js":    "javascript",
+	".jsx":   "javascript",
+	".kt":    "kotlin",
+	".mjs":   "javascript",
+	".mts":   "typescript",
+	".php":   "php",
+	".py":    "python",
+	".rb":    "ruby",
+	".rs":    "rust",
+	".scala": "scala",
+	".swift": "swift",
+	".ts":    "typescript",
+	".tsx":   "typescript",
+}
+
+// languageFamilies groups languages whose tests commonly cover each other's files,
+// e.g. a TypeScript test importing a JavaScript module.
+var languageFamilies = map[string]string{
+	"c":          "c",
+	"cpp":        "c",
+	"java":       "jvm",
+	"javascript": "js",
+	"kotlin":     "jvm",
+	"scala":      "jvm",
+	"typescript": "js",
+}
+
+// testDirSegments mark directories holding tests or test support code rather than production code.
+var testDirSegments = []string{"__mocks__", "__tests__", "fixtures", "spec", "specs", "test", "testdata", "tests"}
+
+// layoutDirSegments are conventional layout directories ignored when comparing a test's
+// directory with a source file's, so "src/test/java/x" lines up with "src/main/java/x".
+var layoutDirSegments = []string{"__tests__", "lib", "main", "source", "spec", "specs", "src", "test", "tests"}
+
+// TestSourceLink maps a test file to a production file it most likely covers.
+type TestSourceLink struct {
+	SourcePath string
+	TestPath   string
+}
+
+// UntestedFile is a production source file no test file maps to.
+type UntestedFile struct {
+	Language string
+	Path     string
+}
+
+// SourceMapping is the static test-to-source mapping of an analysis.
+type SourceMapping struct {
+	Links    []TestSourceLink
+	Untested []UntestedFile
+}
+
+// SourceLanguage returns the language of a production source file, or "" if p is not source code.
+func SourceLanguage(p string) string {
+	if strings.HasSuffix(p, ".d.ts") {
+		return ""
+	}
+	return sourceLanguages[strings.ToLower(path.Ext(p))]
+}
+
+// IsTestLikePath reports whether p looks like a test file or lives in a test directory,
+// regardless of whether a framework picked it up.
+func IsTestLikePath(p string) bool {
+	if TestFileStem(p) != fileStem(p) {
+		return true
+	}
+	for _, segment := range strings.Split(path.Dir(p), "/") {
+		if slices.Contains(testDirSegments, segment) {
+			return true
+		}
+	}
+	return false
+}
+
+// SourceFiles returns the production source files among files: recognized source languages,
+// excluding test files of the inventory, test-like paths and paths matching an exclude glob.
+func SourceFiles(inventory *Inventory, files []string, exclude []string) []string {
+	testFiles := make(map[string]bool)
+	if inventory != nil {
+		for _, f := range inventory.Files {
+			testFiles[f.Path] = true
+		}
+	}
+
+	var sources []string
+	for _, file := range files {
+		if testFiles[file] || SourceLanguage(file) == "" || IsTestLikePath(file) || MatchesAnyGlob(exclude, file) {
+			continue
+		}
+		sources = append(sources, file)
+	}
+	return sources
+}
+
+// MapTestsToSources maps each test file of the inventory to the source files sharing its stem
+// (foo_test.go→foo.go, foo.spec.ts→foo.ts, tests/test_foo.py→foo.py) within the same language family.
+// When several files share the stem, the ones whose directory best matches the test's are chosen.
+// sources are production files as returned by SourceFiles; those without any linked test are reported as untested.
+func MapTestsToSources(inventory *Inventory, sources []string) SourceMapping {
+	sourcesByStem := make(map[string][]string)
+	for _, source := range sources {
+		stem := fileStem(source)
+		sourcesByStem[stem] = append(sourcesByStem[stem], source)
+	}
+
+	var mapping SourceMapping
+	tested := make(map[string]bool)
+	if inventory != nil {
+		for _, f := range inventory.Files {
+			for _, source := range bestSourceMatches(f.Path, sourcesByStem[TestFileStem(f.Path)]) {
+				mapping.Links = append(mapping.Links, TestSourceLink{SourcePath: source, TestPath: f.Path})
+				tested[source] = true
+			}
+		}
+	}
+
+	for _, source := range sources {
+		if !tested[source] {
+			mapping.Untested = append(mapping.Untested, UntestedFile{Language: SourceLanguage(source), Path: source})
+		}
+	}
+	return mapping
+}
+
+func bestSourceMatches(testPath string, candidates []string) []string {
+	family := languageFamily(SourceLanguage(testPath))
+	if family == "" {
+		return nil
+	}
+
+	best := -1
+	var matches []string
+	for _, candidate := range candidates {
+		if languageFamily(SourceLanguage(candidate)) != family {
+			continue
+		}
+		score := directoryAffinity(path.Dir(testPath), path.Dir(candidate))
+		switch {
+		case score > best:
+			best = score
+			matches = []string{candidate}
+		case score == best:
+			matches = append(matches, candidate)
+		}
+	}
+	return matches
+}
+
+// directoryAffinity scores how closely a source directory mirrors a test directory.
+// Identical directories win, then identical layouts, then the number of shared trailing segments.
+func directoryAffinity(testDir, sourceDir string) int {
+	if testDir == sourceDir {
+		return 1000
+	}
+	testSegments := layoutSegments(testDir)
+	sourceSegments := layoutSegments(sourceDir)
+	if slices.Equal(testSegments, sourceSegments) {
+		return 500
+	}
+
+	shared := 0
+	for i, j := len(testSegments)-1, len(sourceSegments)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if testSegments[i] != sourceSegments[j] {
+			break
+		}
+		shared++
+	}
+	return shared
+}
+
+func layoutSegments(dir string) []string {
+	var segments []string
+	for _, segment := range strings.Split(dir, "/") {
+		if segment == "." || segment == "" || slices.Contains(layoutDirSegments, segment) {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	return segments
+}
+
+func languageFamily(language string) string {
+	if family, ok := languageFamilies[language]; ok {
+		return family
+	}
+	return language
+}
@@ -0,0 +1,135 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSourceLanguage(t *testing.T) {
+	tests := map[string]string{
+		"pkg/user.go":       "go",
+		"src/app.tsx":       "typescript",
+		"src/types.d.ts":    "",
+		"lib/util.MJS":      "javascript",
+		"app/models/foo.rb": "ruby",
+		"README.md":         "",
+		"Makefile":          "",
+	}
+	for p, want := range tests {
+		if got := SourceLanguage(p); got != want {
+			t.Errorf("SourceLanguage(%q) = %q, want %q", p, got, want)
+		}
+	}
+}
+
+func TestIsTestLikePath(t *testing.T) {
+	tests := map[string]bool{
+		"pkg/user_test.go":           true,
+		"src/user.spec.ts":           true,
+		"tests/conftest.py":          true,
+		"src/__mocks__/api.ts":       true,
+		"internal/testdata/x.go":     true,
+		"pkg/user.go":                false,
+		"src/latest.ts":              false,
+		"src/contest/leaderboard.ts": false,
+		"pkg/TestUtils.go":           true,
+		"src/Testimonial.java":       false,
+		"src/TestingModule.ts":       false,
+	}
+	for p, want := range tests {
+		if got := IsTestLikePath(p); got != want {
+			t.Errorf("IsTestLikePath(%q) = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestMapTestsToSources(t *testing.T) {
+	inventory := &Inventory{Files: []TestFile{
+		{Path: "pkg/user/user_test.go"},
+		{Path: "src/order.spec.ts"},
+		{Path: "tests/test_billing.py"},
+		{Path: "src/test/java/com/acme/CartTest.java"},
+		{Path: "web/widget.test.js"},
+	}}
+	files := []string{
+		"README.md",
+		"billing.py",
+		"pkg/user/user.go",
+		"pkg/user/user_test.go",
+		"pkg/admin/user.go",
+		"src/order.ts",
+		"src/order.spec.ts",
+		"src/types.d.ts",
+		"src/main/java/com/acme/Cart.java",
+		"src/main/java/com/other/Cart.java",
+		"src/test/java/com/acme/CartTest.java",
+		"tests/conftest.py",
+		"tests/test_billing.py",
+		"web/widget.py",
+		"web/widget.test.js",
+		"pkg/orphan/orphan.go",
+	}
+
+	got := MapTestsToSources(inventory, SourceFiles(inventory, files, nil))
+
+	wantLinks := []TestSourceLink{
+		{SourcePath: "pkg/user/user.go", TestPath: "pkg/user/user_test.go"},
+		{SourcePath: "src/order.ts", TestPath: "src/order.spec.ts"},
+		{SourcePath: "billing.py", TestPath: "tests/test_billing.py"},
+		{SourcePath: "src/main/java/com/acme/Cart.java", TestPath: "src/test/java/com/acme/CartTest.java"},
+	}
+	if !reflect.DeepEqual(got.Links, wantLinks) {
+		t.Errorf("links:\n got %v\nwant %v", got.Links, wantLinks)
+	}
+
+	wantUntested := []UntestedFile{
+		{Language: "go", Path: "pkg/admin/user.go"},
+		{Language: "java", Path: "src/main/java/com/other/Cart.java"},
+		{Language: "python", Path: "web/widget.py"},
+		{Language: "go", Path: "pkg/orphan/orphan.go"},
+	}
+	if !reflect.DeepEqual(got.Untested, wantUntested) {
+		t.Errorf("untested:\n got %v\nwant %v", got.Untested, wantUntested)
+	}
+}
+
+func TestMapTestsToSources_AmbiguousMatchesLinkAll(t *testing.T) {
+	inventory := &Inventory{Files: []TestFile{{Path: "tests/unit/test_parser.py"}}}
+	files := []string{"app/parser.py", "tools/parser.py"}
+
+	got := MapTestsToSources(inventory, SourceFiles(inventory, files, nil))
+
+	if len(got.Links) != 2 {
+		t.Errorf("expected both equally likely sources linked, got %v", got.Links)
+	}
+	if len(got.Untested) != 0 {
+		t.Errorf("expected no untested files, got %v", got.Untested)
+	}
+}
+
+func TestMapTestsToSources_NilInventory(t *testing.T) {
+	got := MapTestsToSources(nil, SourceFiles(nil, []string{"main.go"}, nil))
+
+	if len(got.Links) != 0 || len(got.Untested) != 1 {
+		t.Errorf("unexpected mapping: %+v", got)
+	}
+}
+
+func TestSourceFiles(t *testing.T) {
+	inventory := &Inventory{Files: []TestFile{{Path: "pkg/user_test.go"}}}
+	files := []string{
+		"README.md",
+		"pkg/user.go",
+		"pkg/user_test.go",
+		"pkg/helper_test.go",
+		"vendor/lib/lib.go",
+		"web/app.ts",
+	}
+
+	got := SourceFiles(inventory, files, []string{"vendor/**"})
+
+	want := []string{"pkg/user.go", "web/app.ts"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
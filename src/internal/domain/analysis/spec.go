@@ -0,0 +1,61 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SpecDocument is the behavior specification extracted by a completed analysis:
+// its suite and test tree with the repository and commit it was read from.
+type SpecDocument struct {
+	Branch      string
+	CommitSHA   string
+	CompletedAt time.Time
+	Host        string
+	Inventory   *Inventory
+	Owner       string
+	Repo        string
+}
+
+// SpecRepository loads stored analyses as specification documents.
+type SpecRepository interface {
+	// FindCompletedAnalysis returns ErrAnalysisNotFound if the commit has no completed analysis.
+	FindCompletedAnalysis(ctx context.Context, owner, repo, commitSHA string) (UUID, error)
+	// FindLatestCompletedAnalysis returns the completed analysis of the most recent commit, ignoring
+	// historical analyses of past commits. Returns ErrAnalysisNotFound if there is none.
+	FindLatestCompletedAnalysis(ctx context.Context, owner, repo string) (UUID, error)
+	// FindUpstreamLatestCompletedAnalysis returns the latest completed analysis, as defined by
+	// FindLatestCompletedAnalysis, of the repository a fork was created from. Returns ErrAnalysisNotFound if the repository is not a fork of an analyzed
+	// codebase or the upstream has no completed analysis.
+	FindUpstreamLatestCompletedAnalysis(ctx context.Context, owner, repo string) (UUID, error)
+	// GetSpecDocument returns ErrAnalysisNotFound unless the analysis exists and is completed.
+	GetSpecDocument(ctx context.Context, analysisID UUID) (*SpecDocument, error)
+}
+
+// CommitURL links to the analyzed commit.
+func (d *SpecDocument) CommitURL() string {
+	return fmt.Sprintf("https://%s/%s/%s/commit/%s", d.Host, d.Owner, d.Repo, d.CommitSHA)
+}
+
+// SourceURL links to the lines of a file at the analyzed commit, e.g.
+// https://github.com/owner/repo/blob/<sha>/src/a.test.ts#L10-L24.
+// The fragment names a single line when the end line is unknown and is omitted without a start line.
+func (d *SpecDocument) SourceURL(filePath string, loc Location) string {
+	segments := strings.Split(filePath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	link := fmt.Sprintf("https://%s/%s/%s/blob/%s/%s", d.Host, d.Owner, d.Repo, d.CommitSHA, strings.Join(segments, "/"))
+
+	switch {
+	case loc.StartLine <= 0:
+		return link
+	case loc.EndLine > loc.StartLine:
+		return fmt.Sprintf("%s#L%d-L%d", link, loc.StartLine, loc.EndLine)
+	default:
+		return fmt.Sprintf("%s#L%d", link, loc.StartLine)
+	}
+}
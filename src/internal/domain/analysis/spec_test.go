@@ -0,0 +1,48 @@
+package analysis
+
+import "testing"
+
+func TestSpecDocument_SourceURL(t *testing.T) {
+	doc := &SpecDocument{CommitSHA: "abc123", Host: "github.com", Owner: "octo", Repo: "shop"}
+
+	tests := []struct {
+		name string
+		path string
+		loc  Location
+		want string
+	}{
+		{
+			name: "line range",
+			path: "src/cart.test.ts",
+			loc:  Location{StartLine: 10, EndLine: 24},
+			want: "https://github.com/octo/shop/blob/abc123/src/cart.test.ts#L10-L24",
+		},
+		{
+			name: "unknown end line",
+			path: "src/cart.test.ts",
+			loc:  Location{StartLine: 10},
+			want: "https://github.com/octo/shop/blob/abc123/src/cart.test.ts#L10",
+		},
+		{
+			name: "no location",
+			path: "spec/user spec.rb",
+			want: "https://github.com/octo/shop/blob/abc123/spec/user%20spec.rb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := doc.SourceURL(tt.path, tt.loc); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSpecDocument_CommitURL(t *testing.T) {
+	doc := &SpecDocument{CommitSHA: "abc123", Host: "github.com", Owner: "octo", Repo: "shop"}
+
+	if got, want := doc.CommitURL(), "https://github.com/octo/shop/commit/abc123"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
@@ -3,17 +3,58 @@ package analysis
 import (
 	"context"
 	"errors"
+	"slices"
 )
 
 // ErrTokenNotFound indicates the OAuth token does not exist for the user/provider.
 // This is an expected condition (user hasn't connected OAuth) and should trigger graceful degradation.
 var ErrTokenNotFound = errors.New("oauth token not found")
 
+// ErrTokenInvalid indicates the provider rejected a stored OAuth token, e.g. because the user
+// revoked the grant. The user has to reauthorize. It matches ErrTokenNotFound with errors.Is,
+// so callers degrade to public access without special handling.
+var ErrTokenInvalid error = tokenInvalidError{}
+
+type tokenInvalidError struct{}
+
+func (tokenInvalidError) Error() string {
+	return "oauth token invalid"
+}
+
+func (tokenInvalidError) Is(target error) bool {
+	return target == ErrTokenNotFound
+}
+
 // TokenLookup retrieves OAuth tokens for repository access.
 //
 // Implementations should return:
 //   - ErrTokenNotFound: when token doesn't exist (expected, triggers graceful degradation)
+//   - ErrTokenInvalid: when the token was marked invalid and the user has not reauthorized since
 //   - Other errors: infrastructure failures (should fail the operation)
 type TokenLookup interface {
 	GetOAuthToken(ctx context.Context, userID string, provider string) (string, error)
 }
+
+// TokenInvalidator records that the provider rejected a user's OAuth token.
+type TokenInvalidator interface {
+	// MarkOAuthTokenInvalid marks the stored token invalid only while it is still token,
+	// so a token replaced by a concurrent reauthorization stays valid.
+	MarkOAuthTokenInvalid(ctx context.Context, userID, provider, token string) error
+}
+
+// TokenInfo is what the provider reports about a valid OAuth token.
+type TokenInfo struct {
+	// Scopes granted to the token. Nil if the provider does not report scopes, as for fine-grained tokens.
+	Scopes []string
+}
+
+// MissingScope reports whether the token is known to lack scope.
+func (i TokenInfo) MissingScope(scope string) bool {
+	return i.Scopes != nil && !slices.Contains(i.Scopes, scope)
+}
+
+type TokenVerifier interface {
+	// VerifyToken returns ErrTokenInvalid if the provider rejects the token,
+	// and a *RateLimitError if the API refuses requests until a reset time.
+	VerifyToken(ctx context.Context, token string) (TokenInfo, error)
+}
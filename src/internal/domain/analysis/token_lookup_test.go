@@ -0,0 +1,41 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrTokenInvalid(t *testing.T) {
+	err := fmt.Errorf("verify token: %w", ErrTokenInvalid)
+	if !errors.Is(err, ErrTokenInvalid) {
+		t.Error("expected ErrTokenInvalid to match itself")
+	}
+	if !errors.Is(err, ErrTokenNotFound) {
+		t.Error("expected ErrTokenInvalid to match ErrTokenNotFound")
+	}
+	if errors.Is(ErrTokenNotFound, ErrTokenInvalid) {
+		t.Error("expected ErrTokenNotFound not to match ErrTokenInvalid")
+	}
+}
+
+func TestTokenInfo_MissingScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		want   bool
+	}{
+		{name: "unreported scopes", scopes: nil, want: false},
+		{name: "no scopes", scopes: []string{}, want: true},
+		{name: "other scopes", scopes: []string{"read:user"}, want: true},
+		{name: "granted", scopes: []string{"read:user", "repo"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (TokenInfo{Scopes: tt.scopes}).MissingScope("repo"); got != tt.want {
+				t.Errorf("MissingScope(repo) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,11 +17,15 @@ type VCS interface {
 	// It determines visibility by trying unauthenticated access first:
 	// - Success without token = public repository (IsPrivate=false)
 	// - Failure without token, success with token = private repository (IsPrivate=true)
+	// Returns ErrRepoNotFound if the repository does not exist or the token cannot read it.
 	GetHeadCommit(ctx context.Context, url string, token *string) (CommitInfo, error)
 }
 
 type Source interface {
 	Branch() string
+	// Checkout switches the working tree to sha, fetching it if the clone does not contain it.
+	// CommitSHA and CommittedAt describe the checked-out commit afterwards.
+	Checkout(ctx context.Context, sha string) error
 	CommitSHA() string
 	CommittedAt() time.Time
 	Close(ctx context.Context) error
@@ -32,13 +36,32 @@ type Source interface {
 	VerifyCommitExists(ctx context.Context, sha string) (bool, error)
 }
 
+// RepoInfo is the hosting provider's view of a repository.
 type RepoInfo struct {
+	DefaultBranch  string
 	ExternalRepoID string
-	Name           string
-	Owner          string
+	IsArchived     bool
+	IsDisabled     bool
+	IsFork         bool
+	// Language is the primary language detected by the provider. Empty if unknown.
+	Language string
+	// License is the SPDX identifier, e.g. "MIT". Empty if none was detected.
+	License string
+	Name    string
+	Owner   string
+	// ParentExternalRepoID identifies the repository a fork was created from. Empty for non-forks.
+	ParentExternalRepoID string
+	// ParentFullName is "owner/name" of the repository a fork was created from. Empty for non-forks.
+	ParentFullName string
+	// PushedAt is the time of the last push to any branch. Nil if never pushed.
+	PushedAt *time.Time
+	SizeKB   int64
+	Stars    int
+	Topics   []string
 }
 
 type VCSAPIClient interface {
-	// Returns ErrRepoNotFound if the repository does not exist.
+	// Returns ErrRepoNotFound if the repository does not exist,
+	// and a *RateLimitError if the API refuses requests until a reset time.
 	GetRepoInfo(ctx context.Context, host, owner, repo string, token *string) (RepoInfo, error)
 }
@@ -0,0 +1,66 @@
+package analysis
+
+import (
+	"context"
+	"strings"
+)
+
+// WorkspaceKind identifies the tool that declared a monorepo workspace.
+type WorkspaceKind string
+
+const (
+	WorkspaceKindCargo    WorkspaceKind = "cargo"
+	WorkspaceKindConfig   WorkspaceKind = "config"
+	WorkspaceKindGoModule WorkspaceKind = "go"
+	WorkspaceKindGradle   WorkspaceKind = "gradle"
+	WorkspaceKindNPM      WorkspaceKind = "npm"
+	WorkspaceKindPNPM     WorkspaceKind = "pnpm"
+	WorkspaceKindYarn     WorkspaceKind = "yarn"
+)
+
+// RootWorkspacePath is the Path of a workspace located at the repository root.
+const RootWorkspacePath = "."
+
+// Workspace is a package of a monorepo.
+// Path is the slash-separated package directory relative to the repository root.
+type Workspace struct {
+	Kind WorkspaceKind
+	Name string
+	Path string
+}
+
+// WorkspaceDetector discovers monorepo packages in a cloned source whose files are listed by FileLister.
+// When roots is non-empty, it lists the package directories (globs allowed) and replaces auto-detection.
+// Returns an empty slice for single-package repositories.
+type WorkspaceDetector interface {
+	Detect(ctx context.Context, src Source, files []string, roots []string) ([]Workspace, error)
+}
+
+// AssignPackages sets TestFile.Package to the path of the deepest workspace containing the file.
+// Files outside every workspace keep an empty Package.
+func AssignPackages(inventory *Inventory, workspaces []Workspace) {
+	if inventory == nil || len(workspaces) == 0 {
+		return
+	}
+	for i := range inventory.Files {
+		inventory.Files[i].Package = FindWorkspace(workspaces, inventory.Files[i].Path)
+	}
+}
+
+// FindWorkspace returns the path of the deepest workspace containing filePath.
+// A root workspace (Path ".") contains every file; "" is returned when no workspace matches.
+func FindWorkspace(workspaces []Workspace, filePath string) string {
+	best := ""
+	for _, ws := range workspaces {
+		if ws.Path == RootWorkspacePath {
+			if best == "" {
+				best = RootWorkspacePath
+			}
+			continue
+		}
+		if strings.HasPrefix(filePath, ws.Path+"/") && (best == RootWorkspacePath || len(ws.Path) > len(best)) {
+			best = ws.Path
+		}
+	}
+	return best
+}
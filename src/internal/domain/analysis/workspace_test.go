@@ -0,0 +1,65 @@
+package analysis
+
+import "testing"
+
+func TestFindWorkspace(t *testing.T) {
+	workspaces := []Workspace{
+		{Path: "packages/ui"},
+		{Path: "packages/ui/internal"},
+		{Path: "services/api"},
+	}
+
+	tests := []struct {
+		name       string
+		workspaces []Workspace
+		path       string
+		want       string
+	}{
+		{name: "direct member", workspaces: workspaces, path: "packages/ui/button.test.ts", want: "packages/ui"},
+		{name: "deepest match wins", workspaces: workspaces, path: "packages/ui/internal/x.test.ts", want: "packages/ui/internal"},
+		{name: "prefix is not a directory boundary", workspaces: workspaces, path: "packages/uikit/a.test.ts", want: ""},
+		{name: "outside every workspace", workspaces: workspaces, path: "scripts/a.test.ts", want: ""},
+		{
+			name:       "root workspace as fallback",
+			workspaces: append([]Workspace{{Path: RootWorkspacePath}}, workspaces...),
+			path:       "scripts/a_test.go",
+			want:       RootWorkspacePath,
+		},
+		{
+			name:       "nested module preferred over root",
+			workspaces: append([]Workspace{{Path: RootWorkspacePath}}, workspaces...),
+			path:       "services/api/h_test.go",
+			want:       "services/api",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindWorkspace(tt.workspaces, tt.path); got != tt.want {
+				t.Errorf("FindWorkspace(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssignPackages(t *testing.T) {
+	inventory := &Inventory{
+		Files: []TestFile{
+			{Path: "apps/web/page.test.tsx"},
+			{Path: "tools/lint.test.js"},
+		},
+	}
+
+	AssignPackages(inventory, []Workspace{{Kind: WorkspaceKindPNPM, Name: "web", Path: "apps/web"}})
+
+	if inventory.Files[0].Package != "apps/web" {
+		t.Errorf("expected package apps/web, got %q", inventory.Files[0].Package)
+	}
+	if inventory.Files[1].Package != "" {
+		t.Errorf("expected empty package, got %q", inventory.Files[1].Package)
+	}
+
+	t.Run("nil inventory", func(t *testing.T) {
+		AssignPackages(nil, []Workspace{{Path: "a"}})
+	})
+}
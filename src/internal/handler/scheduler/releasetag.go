@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	infrascheduler "github.com/specvital/collector/internal/infra/scheduler"
+	"github.com/specvital/collector/internal/usecase/releasetag"
+)
+
+type ReleaseTagHandler struct {
+	lock    *infrascheduler.DistributedLock
+	useCase *releasetag.ReleaseTagUseCase
+}
+
+// Pass nil for lock to disable distributed locking (single-instance only).
+func NewReleaseTagHandler(
+	useCase *releasetag.ReleaseTagUseCase,
+	lock *infrascheduler.DistributedLock,
+) *ReleaseTagHandler {
+	return &ReleaseTagHandler{
+		lock:    lock,
+		useCase: useCase,
+	}
+}
+
+func (h *ReleaseTagHandler) Run() {
+	h.RunWithContext(context.Background())
+}
+
+func (h *ReleaseTagHandler) RunWithContext(parentCtx context.Context) {
+	ctx, cancel := context.WithTimeout(parentCtx, defaultJobTimeout)
+	defer cancel()
+
+	start := time.Now()
+
+	if h.lock != nil {
+		acquired, err := h.lock.TryAcquire(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "release tag lock acquisition failed",
+				"error", err,
+			)
+			return
+		}
+		if !acquired {
+			slog.DebugContext(ctx, "release tag tracking skipped: another instance is running")
+			return
+		}
+
+		defer func() {
+			if err := h.lock.Release(ctx); err != nil {
+				slog.WarnContext(ctx, "release tag lock release failed", "error", err)
+			}
+		}()
+	}
+
+	slog.InfoContext(ctx, "release tag job started")
+
+	if err := h.useCase.Execute(ctx); err != nil {
+		slog.ErrorContext(ctx, "release tag job failed",
+			"error", err,
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+		return
+	}
+
+	slog.InfoContext(ctx, "release tag job completed",
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+}
@@ -2,12 +2,25 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	DatabaseURL   string
+	// ChurnWindow is zero when CHURN_WINDOW_DAYS is unset, selecting the default window.
+	ChurnWindow time.Duration
+	DatabaseURL string
+	// EncryptionKey is the legacy key of ciphertexts without a key ID. Optional when EncryptionKeys is set.
 	EncryptionKey string
+	// EncryptionKeys lists "<key ID>:<base64 key>" entries, primary first. See keyring.New.
+	EncryptionKeys string
+	// GitHubAppID is zero when no GitHub App is configured.
+	GitHubAppID int64
+	// GitHubAppPrivateKey is the PEM-encoded private key of the GitHub App.
+	GitHubAppPrivateKey string
 }
 
 func Load() (*Config, error) {
@@ -17,12 +30,49 @@ func Load() (*Config, error) {
 	}
 
 	encryptionKey := os.Getenv("ENCRYPTION_KEY")
-	if encryptionKey == "" {
-		return nil, errors.New("ENCRYPTION_KEY is required")
+	encryptionKeys := os.Getenv("ENCRYPTION_KEYS")
+	if encryptionKey == "" && encryptionKeys == "" {
+		return nil, errors.New("ENCRYPTION_KEY or ENCRYPTION_KEYS is required")
+	}
+
+	var churnWindow time.Duration
+	if raw := os.Getenv("CHURN_WINDOW_DAYS"); raw != "" {
+		days, err := strconv.Atoi(raw)
+		if err != nil || days <= 0 {
+			return nil, fmt.Errorf("CHURN_WINDOW_DAYS must be a positive number of days, got %q", raw)
+		}
+		churnWindow = time.Duration(days) * 24 * time.Hour
+	}
+
+	appID, appPrivateKey, err := LoadGitHubApp()
+	if err != nil {
+		return nil, err
 	}
 
 	return &Config{
-		DatabaseURL:   databaseURL,
-		EncryptionKey: encryptionKey,
+		ChurnWindow:         churnWindow,
+		DatabaseURL:         databaseURL,
+		EncryptionKey:       encryptionKey,
+		EncryptionKeys:      encryptionKeys,
+		GitHubAppID:         appID,
+		GitHubAppPrivateKey: appPrivateKey,
 	}, nil
 }
+
+// LoadGitHubApp reads the optional GitHub App credentials. Both are empty when no app is configured.
+// Literal "\n" sequences in the private key are expanded, as multi-line values are awkward in env files.
+func LoadGitHubApp() (int64, string, error) {
+	var appID int64
+	privateKey := strings.ReplaceAll(os.Getenv("GITHUB_APP_PRIVATE_KEY"), `\n`, "\n")
+	if raw := os.Getenv("GITHUB_APP_ID"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			return 0, "", fmt.Errorf("GITHUB_APP_ID must be a positive number, got %q", raw)
+		}
+		appID = id
+	}
+	if (appID == 0) != (privateKey == "") {
+		return 0, "", errors.New("GITHUB_APP_ID and GITHUB_APP_PRIVATE_KEY must be set together")
+	}
+	return appID, privateKey, nil
+}
@@ -1,8 +1,27 @@
 package db
 
 const InsertTestSuiteBatch = `
-INSERT INTO test_suites (analysis_id, parent_id, name, file_path, line_number, framework, depth)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO test_suites (analysis_id, parent_id, name, file_path, line_number, end_line_number, framework, depth, package_path)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 RETURNING id`
 
-var TestCaseCopyColumns = []string{"suite_id", "name", "line_number", "status", "tags", "modifier"}
+var FileCoverageCopyColumns = []string{"analysis_id", "report_name", "file_path", "total_lines", "covered_lines", "uncovered_lines"}
+
+var FileOwnerCopyColumns = []string{"analysis_id", "file_path", "owner"}
+
+var TestFileChurnCopyColumns = []string{
+	"analysis_id", "file_path", "commits", "lines_added", "lines_removed", "authors", "window_start",
+}
+
+var TestSourceCopyColumns = []string{"analysis_id", "test_file_path", "source_file_path"}
+
+var UntestedFileCopyColumns = []string{"analysis_id", "file_path", "language"}
+
+var TestCaseCopyColumns = []string{
+	"suite_id", "name", "line_number", "end_line_number", "status", "tags", "modifier",
+	"introduced_commit_sha", "introduced_by_name", "introduced_by_email", "introduced_at", "last_modified_at",
+}
+
+var TestExecutionCopyColumns = []string{
+	"analysis_id", "test_case_id", "report_name", "file_path", "suite_path", "name", "outcome", "duration_ms", "message",
+}
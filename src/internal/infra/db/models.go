@@ -232,18 +232,144 @@ func (ns NullTestStatus) Value() (driver.Value, error) {
 }
 
 type Analysis struct {
+	ID               pgtype.UUID        `json:"id"`
+	CodebaseID       pgtype.UUID        `json:"codebase_id"`
+	CommitSha        string             `json:"commit_sha"`
+	BranchName       pgtype.Text        `json:"branch_name"`
+	Status           AnalysisStatus     `json:"status"`
+	ErrorMessage     pgtype.Text        `json:"error_message"`
+	StartedAt        pgtype.Timestamptz `json:"started_at"`
+	CompletedAt      pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	TotalSuites      int32              `json:"total_suites"`
+	TotalTests       int32              `json:"total_tests"`
+	CommittedAt      pgtype.Timestamptz `json:"committed_at"`
+	ConfigHash       pgtype.Text        `json:"config_hash"`
+	TotalSourceFiles pgtype.Int4        `json:"total_source_files"`
+	TotalSourceLines pgtype.Int4        `json:"total_source_lines"`
+	TestFileRatio    pgtype.Float8      `json:"test_file_ratio"`
+	TestsPerKloc     pgtype.Float8      `json:"tests_per_kloc"`
+	IsHistorical     bool               `json:"is_historical"`
+}
+
+type AnalysisAnomaly struct {
+	ID                 pgtype.UUID        `json:"id"`
+	AnalysisID         pgtype.UUID        `json:"analysis_id"`
+	PreviousAnalysisID pgtype.UUID        `json:"previous_analysis_id"`
+	Kind               string             `json:"kind"`
+	Framework          pgtype.Text        `json:"framework"`
+	PreviousValue      int32              `json:"previous_value"`
+	CurrentValue       int32              `json:"current_value"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+}
+
+type AnalysisCiCommand struct {
+	ID         pgtype.UUID        `json:"id"`
+	AnalysisID pgtype.UUID        `json:"analysis_id"`
+	ConfigPath string             `json:"config_path"`
+	JobName    string             `json:"job_name"`
+	Command    string             `json:"command"`
+	Frameworks []string           `json:"frameworks"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type AnalysisCiFramework struct {
+	ID         pgtype.UUID        `json:"id"`
+	AnalysisID pgtype.UUID        `json:"analysis_id"`
+	Framework  string             `json:"framework"`
+	Executed   bool               `json:"executed"`
+	TestFiles  int32              `json:"test_files"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type AnalysisFileCoverage struct {
+	ID             pgtype.UUID        `json:"id"`
+	AnalysisID     pgtype.UUID        `json:"analysis_id"`
+	ReportName     string             `json:"report_name"`
+	FilePath       string             `json:"file_path"`
+	TotalLines     int32              `json:"total_lines"`
+	CoveredLines   int32              `json:"covered_lines"`
+	UncoveredLines []int32            `json:"uncovered_lines"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type AnalysisFileOwner struct {
+	ID         pgtype.UUID        `json:"id"`
+	AnalysisID pgtype.UUID        `json:"analysis_id"`
+	FilePath   string             `json:"file_path"`
+	Owner      string             `json:"owner"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type AnalysisLanguageStat struct {
+	ID          pgtype.UUID        `json:"id"`
+	AnalysisID  pgtype.UUID        `json:"analysis_id"`
+	Language    string             `json:"language"`
+	SourceFiles int32              `json:"source_files"`
+	SourceLines int32              `json:"source_lines"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type AnalysisOwnerSummary struct {
 	ID           pgtype.UUID        `json:"id"`
-	CodebaseID   pgtype.UUID        `json:"codebase_id"`
-	CommitSha    string             `json:"commit_sha"`
-	BranchName   pgtype.Text        `json:"branch_name"`
-	Status       AnalysisStatus     `json:"status"`
-	ErrorMessage pgtype.Text        `json:"error_message"`
-	StartedAt    pgtype.Timestamptz `json:"started_at"`
-	CompletedAt  pgtype.Timestamptz `json:"completed_at"`
-	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	AnalysisID   pgtype.UUID        `json:"analysis_id"`
+	Owner        string             `json:"owner"`
+	TotalFiles   int32              `json:"total_files"`
 	TotalSuites  int32              `json:"total_suites"`
 	TotalTests   int32              `json:"total_tests"`
-	CommittedAt  pgtype.Timestamptz `json:"committed_at"`
+	SkippedTests int32              `json:"skipped_tests"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type AnalysisPackage struct {
+	ID          pgtype.UUID        `json:"id"`
+	AnalysisID  pgtype.UUID        `json:"analysis_id"`
+	Path        string             `json:"path"`
+	Name        string             `json:"name"`
+	Kind        string             `json:"kind"`
+	TotalFiles  int32              `json:"total_files"`
+	TotalSuites int32              `json:"total_suites"`
+	TotalTests  int32              `json:"total_tests"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type AnalysisPolicyResult struct {
+	ID         pgtype.UUID        `json:"id"`
+	AnalysisID pgtype.UUID        `json:"analysis_id"`
+	Name       string             `json:"name"`
+	Kind       string             `json:"kind"`
+	Source     string             `json:"source"`
+	Passed     bool               `json:"passed"`
+	Detail     pgtype.Text        `json:"detail"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type AnalysisTestFileChurn struct {
+	ID           pgtype.UUID        `json:"id"`
+	AnalysisID   pgtype.UUID        `json:"analysis_id"`
+	FilePath     string             `json:"file_path"`
+	Commits      int32              `json:"commits"`
+	LinesAdded   int32              `json:"lines_added"`
+	LinesRemoved int32              `json:"lines_removed"`
+	Authors      int32              `json:"authors"`
+	WindowStart  pgtype.Timestamptz `json:"window_start"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type AnalysisTestSource struct {
+	ID             pgtype.UUID        `json:"id"`
+	AnalysisID     pgtype.UUID        `json:"analysis_id"`
+	TestFilePath   string             `json:"test_file_path"`
+	SourceFilePath string             `json:"source_file_path"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type AnalysisUntestedFile struct {
+	ID         pgtype.UUID        `json:"id"`
+	AnalysisID pgtype.UUID        `json:"analysis_id"`
+	FilePath   string             `json:"file_path"`
+	Language   string             `json:"language"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
 }
 
 type AtlasSchemaRevision struct {
@@ -261,18 +387,63 @@ type AtlasSchemaRevision struct {
 	OperatorVersion string             `json:"operator_version"`
 }
 
+type CodebaseCredential struct {
+	CodebaseID pgtype.UUID        `json:"codebase_id"`
+	Kind       string             `json:"kind"`
+	Secret     string             `json:"secret"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+type CodebasePolicyRule struct {
+	ID          pgtype.UUID        `json:"id"`
+	CodebaseID  pgtype.UUID        `json:"codebase_id"`
+	Name        string             `json:"name"`
+	Kind        string             `json:"kind"`
+	Status      NullTestStatus     `json:"status"`
+	Threshold   pgtype.Float8      `json:"threshold"`
+	PathPattern pgtype.Text        `json:"path_pattern"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type CodebaseTag struct {
+	ID         pgtype.UUID        `json:"id"`
+	CodebaseID pgtype.UUID        `json:"codebase_id"`
+	Name       string             `json:"name"`
+	CommitSha  string             `json:"commit_sha"`
+	AnalysisID pgtype.UUID        `json:"analysis_id"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Codebasis struct {
-	ID             pgtype.UUID        `json:"id"`
-	Host           string             `json:"host"`
-	Owner          string             `json:"owner"`
-	Name           string             `json:"name"`
-	DefaultBranch  pgtype.Text        `json:"default_branch"`
-	CreatedAt      pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
-	LastViewedAt   pgtype.Timestamptz `json:"last_viewed_at"`
-	ExternalRepoID string             `json:"external_repo_id"`
-	IsStale        bool               `json:"is_stale"`
-	IsPrivate      bool               `json:"is_private"`
+	ID                   pgtype.UUID        `json:"id"`
+	Host                 string             `json:"host"`
+	Owner                string             `json:"owner"`
+	Name                 string             `json:"name"`
+	DefaultBranch        pgtype.Text        `json:"default_branch"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	LastViewedAt         pgtype.Timestamptz `json:"last_viewed_at"`
+	ExternalRepoID       string             `json:"external_repo_id"`
+	IsStale              bool               `json:"is_stale"`
+	IsPrivate            bool               `json:"is_private"`
+	IsArchived           bool               `json:"is_archived"`
+	IsDisabled           bool               `json:"is_disabled"`
+	IsFork               bool               `json:"is_fork"`
+	ParentFullName       pgtype.Text        `json:"parent_full_name"`
+	PrimaryLanguage      pgtype.Text        `json:"primary_language"`
+	Topics               []string           `json:"topics"`
+	LicenseSpdxID        pgtype.Text        `json:"license_spdx_id"`
+	SizeKb               int64              `json:"size_kb"`
+	StargazersCount      int32              `json:"stargazers_count"`
+	PushedAt             pgtype.Timestamptz `json:"pushed_at"`
+	MetadataRefreshedAt  pgtype.Timestamptz `json:"metadata_refreshed_at"`
+	EmptyCheckedAt       pgtype.Timestamptz `json:"empty_checked_at"`
+	ParentExternalRepoID pgtype.Text        `json:"parent_external_repo_id"`
+	StaleReason          pgtype.Text        `json:"stale_reason"`
+	StaleAt              pgtype.Timestamptz `json:"stale_at"`
 }
 
 type GithubAppInstallation struct {
@@ -300,15 +471,16 @@ type GithubOrganization struct {
 }
 
 type OauthAccount struct {
-	ID               pgtype.UUID        `json:"id"`
-	UserID           pgtype.UUID        `json:"user_id"`
-	Provider         OauthProvider      `json:"provider"`
-	ProviderUserID   string             `json:"provider_user_id"`
-	ProviderUsername pgtype.Text        `json:"provider_username"`
-	AccessToken      pgtype.Text        `json:"access_token"`
-	Scope            pgtype.Text        `json:"scope"`
-	CreatedAt        pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	ID                 pgtype.UUID        `json:"id"`
+	UserID             pgtype.UUID        `json:"user_id"`
+	Provider           OauthProvider      `json:"provider"`
+	ProviderUserID     string             `json:"provider_user_id"`
+	ProviderUsername   pgtype.Text        `json:"provider_username"`
+	AccessToken        pgtype.Text        `json:"access_token"`
+	Scope              pgtype.Text        `json:"scope"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	TokenInvalidatedAt pgtype.Timestamptz `json:"token_invalidated_at"`
 }
 
 type RefreshToken struct {
@@ -322,6 +494,12 @@ type RefreshToken struct {
 	Replaces  pgtype.UUID        `json:"replaces"`
 }
 
+type ReportUpload struct {
+	ID        pgtype.UUID        `json:"id"`
+	Report    []byte             `json:"report"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
 type RiverClient struct {
 	ID        string             `json:"id"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
@@ -378,24 +556,46 @@ type RiverQueue struct {
 }
 
 type TestCase struct {
-	ID         pgtype.UUID `json:"id"`
-	SuiteID    pgtype.UUID `json:"suite_id"`
-	Name       string      `json:"name"`
-	LineNumber pgtype.Int4 `json:"line_number"`
-	Status     TestStatus  `json:"status"`
-	Tags       []byte      `json:"tags"`
-	Modifier   pgtype.Text `json:"modifier"`
+	ID                  pgtype.UUID        `json:"id"`
+	SuiteID             pgtype.UUID        `json:"suite_id"`
+	Name                string             `json:"name"`
+	LineNumber          pgtype.Int4        `json:"line_number"`
+	Status              TestStatus         `json:"status"`
+	Tags                []byte             `json:"tags"`
+	Modifier            pgtype.Text        `json:"modifier"`
+	IntroducedCommitSha pgtype.Text        `json:"introduced_commit_sha"`
+	IntroducedByName    pgtype.Text        `json:"introduced_by_name"`
+	IntroducedByEmail   pgtype.Text        `json:"introduced_by_email"`
+	IntroducedAt        pgtype.Timestamptz `json:"introduced_at"`
+	LastModifiedAt      pgtype.Timestamptz `json:"last_modified_at"`
+	EndLineNumber       pgtype.Int4        `json:"end_line_number"`
+}
+
+type TestExecution struct {
+	ID         pgtype.UUID        `json:"id"`
+	AnalysisID pgtype.UUID        `json:"analysis_id"`
+	TestCaseID pgtype.UUID        `json:"test_case_id"`
+	ReportName string             `json:"report_name"`
+	FilePath   pgtype.Text        `json:"file_path"`
+	SuitePath  []string           `json:"suite_path"`
+	Name       string             `json:"name"`
+	Outcome    string             `json:"outcome"`
+	DurationMs int32              `json:"duration_ms"`
+	Message    pgtype.Text        `json:"message"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
 }
 
 type TestSuite struct {
-	ID         pgtype.UUID `json:"id"`
-	AnalysisID pgtype.UUID `json:"analysis_id"`
-	ParentID   pgtype.UUID `json:"parent_id"`
-	Name       string      `json:"name"`
-	FilePath   string      `json:"file_path"`
-	LineNumber pgtype.Int4 `json:"line_number"`
-	Framework  pgtype.Text `json:"framework"`
-	Depth      int32       `json:"depth"`
+	ID            pgtype.UUID `json:"id"`
+	AnalysisID    pgtype.UUID `json:"analysis_id"`
+	ParentID      pgtype.UUID `json:"parent_id"`
+	Name          string      `json:"name"`
+	FilePath      string      `json:"file_path"`
+	LineNumber    pgtype.Int4 `json:"line_number"`
+	Framework     pgtype.Text `json:"framework"`
+	Depth         int32       `json:"depth"`
+	PackagePath   pgtype.Text `json:"package_path"`
+	EndLineNumber pgtype.Int4 `json:"end_line_number"`
 }
 
 type User struct {
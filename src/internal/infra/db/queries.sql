@@ -23,13 +23,13 @@ WHERE host = $1 AND owner = $2 AND name = $3 AND is_stale = false;
 SELECT * FROM codebases WHERE id = $1;
 
 -- name: CreateAnalysis :one
-INSERT INTO analyses (id, codebase_id, commit_sha, branch_name, status, started_at)
-VALUES ($1, $2, $3, $4, $5, $6)
+INSERT INTO analyses (id, codebase_id, commit_sha, branch_name, status, started_at, is_historical)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 RETURNING *;
 
 -- name: UpdateAnalysisCompleted :exec
 UPDATE analyses
-SET status = 'completed', total_suites = $2, total_tests = $3, completed_at = $4, committed_at = $5
+SET status = 'completed', total_suites = $2, total_tests = $3, completed_at = $4, committed_at = $5, config_hash = $6
 WHERE id = $1;
 
 -- name: UpdateAnalysisFailed :exec
@@ -38,8 +38,8 @@ SET status = 'failed', error_message = $2, completed_at = $3
 WHERE id = $1;
 
 -- name: CreateTestSuite :one
-INSERT INTO test_suites (analysis_id, parent_id, name, file_path, line_number, framework, depth)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO test_suites (analysis_id, parent_id, name, file_path, line_number, framework, depth, package_path)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING *;
 
 -- name: CreateTestCase :one
@@ -56,12 +56,30 @@ SELECT * FROM test_cases WHERE suite_id = $1 ORDER BY line_number;
 -- name: GetOAuthAccountByUserAndProvider :one
 SELECT * FROM oauth_accounts WHERE user_id = $1 AND provider = $2;
 
+-- name: MarkOAuthAccountTokenInvalid :exec
+UPDATE oauth_accounts
+SET token_invalidated_at = now()
+WHERE id = $1 AND access_token = $2;
+
+-- name: ListOAuthAccessTokens :many
+SELECT id, access_token FROM oauth_accounts
+WHERE id > @after_id AND access_token IS NOT NULL AND access_token <> ''
+ORDER BY id
+LIMIT @batch_size;
+
+-- name: ReplaceOAuthAccessToken :execrows
+UPDATE oauth_accounts
+SET access_token = @new_access_token
+WHERE id = @id AND access_token = @old_access_token;
+
 -- name: MarkCodebaseStale :exec
-UPDATE codebases SET is_stale = true, updated_at = now() WHERE id = $1;
+UPDATE codebases
+SET is_stale = true, stale_reason = $2, stale_at = now(), updated_at = now()
+WHERE id = $1;
 
 -- name: UnmarkCodebaseStale :one
 UPDATE codebases
-SET is_stale = false, owner = $2, name = $3, updated_at = now()
+SET is_stale = false, stale_reason = NULL, stale_at = NULL, owner = $2, name = $3, updated_at = now()
 WHERE id = $1
 RETURNING *;
 
@@ -76,6 +94,30 @@ UPDATE codebases
 SET is_private = $2, updated_at = now()
 WHERE id = $1;
 
+-- name: UpdateCodebaseMetadata :exec
+UPDATE codebases
+SET default_branch = COALESCE(sqlc.narg(default_branch), default_branch),
+    is_archived = @is_archived,
+    is_disabled = @is_disabled,
+    is_fork = @is_fork,
+    parent_full_name = @parent_full_name,
+    parent_external_repo_id = @parent_external_repo_id,
+    primary_language = @primary_language,
+    topics = @topics,
+    license_spdx_id = @license_spdx_id,
+    size_kb = @size_kb,
+    stargazers_count = @stargazers_count,
+    pushed_at = @pushed_at,
+    metadata_refreshed_at = now(),
+    updated_at = now()
+WHERE id = @id;
+
+-- name: UpdateCodebaseEmptyState :exec
+UPDATE codebases
+SET empty_checked_at = CASE WHEN @is_empty::boolean THEN now() END,
+    updated_at = now()
+WHERE id = @id;
+
 -- name: FindCodebaseWithLastCommitByOwnerName :one
 SELECT
     c.*,
@@ -84,8 +126,8 @@ FROM codebases c
 LEFT JOIN (
     SELECT DISTINCT ON (codebase_id) codebase_id, commit_sha
     FROM analyses
-    WHERE status = 'completed'
-    ORDER BY codebase_id, completed_at DESC
+    WHERE status = 'completed' AND is_historical = false
+    ORDER BY codebase_id, committed_at DESC NULLS LAST, completed_at DESC
 ) a ON c.id = a.codebase_id
 WHERE c.host = $1 AND c.owner = $2 AND c.name = $3 AND c.is_stale = false;
 
@@ -96,8 +138,8 @@ WITH latest_completions AS (
         completed_at,
         commit_sha
     FROM analyses
-    WHERE status = 'completed'
-    ORDER BY codebase_id, completed_at DESC
+    WHERE status = 'completed' AND is_historical = false
+    ORDER BY codebase_id, committed_at DESC NULLS LAST, completed_at DESC
 ),
 failure_counts AS (
     SELECT
@@ -106,24 +148,236 @@ failure_counts AS (
     FROM analyses a
     LEFT JOIN latest_completions lc ON a.codebase_id = lc.codebase_id
     WHERE a.status = 'failed'
+      AND a.is_historical = false
       AND a.created_at > COALESCE(lc.completed_at, '1970-01-01'::timestamptz)
     GROUP BY a.codebase_id
+),
+last_viewers AS (
+    SELECT DISTINCT ON (a.codebase_id)
+        a.codebase_id,
+        uah.user_id
+    FROM user_analysis_history uah
+    JOIN analyses a ON a.id = uah.analysis_id
+    JOIN oauth_accounts oa ON oa.user_id = uah.user_id
+    WHERE oa.provider = 'github'
+      AND oa.access_token IS NOT NULL
+      AND (oa.token_invalidated_at IS NULL OR oa.token_invalidated_at < oa.updated_at)
+    ORDER BY a.codebase_id, uah.updated_at DESC
 )
 SELECT
-    c.id, c.host, c.owner, c.name, c.last_viewed_at,
+    c.id, c.host, c.owner, c.name, c.last_viewed_at, c.is_private, c.metadata_refreshed_at,
+    c.is_archived, c.is_disabled, c.empty_checked_at,
     lc.completed_at as last_completed_at,
     lc.commit_sha as last_commit_sha,
-    COALESCE(fc.failure_count, 0)::int as consecutive_failures
+    COALESCE(fc.failure_count, 0)::int as consecutive_failures,
+    lv.user_id as last_viewer_id
 FROM codebases c
 LEFT JOIN latest_completions lc ON c.id = lc.codebase_id
 LEFT JOIN failure_counts fc ON c.id = fc.codebase_id
+LEFT JOIN last_viewers lv ON c.id = lv.codebase_id
 WHERE c.last_viewed_at IS NOT NULL
   AND c.last_viewed_at > now() - interval '90 days'
   AND c.is_stale = false
-  AND c.is_private = false;
+  AND NOT (
+      (c.is_archived OR c.is_disabled)
+      AND c.metadata_refreshed_at > now() - interval '1 day'
+  );
+
+-- name: ListRecentCodebaseViewers :many
+SELECT uah.user_id
+FROM user_analysis_history uah
+JOIN analyses a ON a.id = uah.analysis_id
+JOIN oauth_accounts oa ON oa.user_id = uah.user_id
+WHERE a.codebase_id = $1
+  AND oa.provider = 'github'
+  AND oa.access_token IS NOT NULL
+  AND (oa.token_invalidated_at IS NULL OR oa.token_invalidated_at < oa.updated_at)
+GROUP BY uah.user_id
+ORDER BY MAX(uah.updated_at) DESC
+LIMIT $2;
 
 -- name: RecordUserAnalysisHistory :exec
 INSERT INTO user_analysis_history (user_id, analysis_id)
 VALUES ($1, $2)
 ON CONFLICT ON CONSTRAINT uq_user_analysis_history_user_analysis
 DO UPDATE SET updated_at = now();
+
+-- name: GetCodebasePolicyRules :many
+SELECT * FROM codebase_policy_rules WHERE codebase_id = $1 ORDER BY name;
+
+-- name: InsertAnalysisOwnerSummary :exec
+INSERT INTO analysis_owner_summaries (analysis_id, owner, total_files, total_suites, total_tests, skipped_tests)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: InsertAnalysisPackage :exec
+INSERT INTO analysis_packages (analysis_id, path, name, kind, total_files, total_suites, total_tests)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: InsertAnalysisPolicyResult :exec
+INSERT INTO analysis_policy_results (analysis_id, name, kind, source, passed, detail)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: GetCodebaseTags :many
+SELECT name, commit_sha FROM codebase_tags WHERE codebase_id = $1 ORDER BY name;
+
+-- name: UpsertCodebaseTag :exec
+INSERT INTO codebase_tags (codebase_id, name, commit_sha, analysis_id)
+VALUES ($1, $2, $3, (
+    SELECT a.id FROM analyses a
+    WHERE a.codebase_id = $1 AND a.commit_sha = $3 AND a.status = 'completed'
+))
+ON CONFLICT ON CONSTRAINT uq_codebase_tags_codebase_name
+DO UPDATE SET
+    commit_sha = EXCLUDED.commit_sha,
+    analysis_id = EXCLUDED.analysis_id,
+    updated_at = now();
+
+-- name: LinkCodebaseTagsToAnalysis :exec
+UPDATE codebase_tags ct
+SET analysis_id = a.id, updated_at = now()
+FROM analyses a
+WHERE a.id = $1
+  AND ct.codebase_id = a.codebase_id
+  AND ct.commit_sha = a.commit_sha;
+
+-- name: InsertAnalysisLanguageStat :exec
+INSERT INTO analysis_language_stats (analysis_id, language, source_files, source_lines)
+VALUES ($1, $2, $3, $4);
+
+-- name: UpdateAnalysisSourceCensus :exec
+UPDATE analyses
+SET total_source_files = $2,
+    total_source_lines = $3,
+    test_file_ratio = $4,
+    tests_per_kloc = $5
+WHERE id = $1;
+
+-- name: FindCompletedAnalysisByCommit :one
+SELECT a.id FROM analyses a
+JOIN codebases c ON c.id = a.codebase_id
+WHERE c.host = $1 AND c.owner = $2 AND c.name = $3 AND c.is_stale = false
+  AND a.commit_sha = $4 AND a.status = 'completed'
+ORDER BY a.completed_at DESC
+LIMIT 1;
+
+-- name: GetStaticTestsByAnalysisID :many
+WITH RECURSIVE suite_paths AS (
+    SELECT s.id, s.file_path,
+        CASE WHEN s.name = s.file_path THEN ARRAY[]::text[] ELSE ARRAY[s.name::text] END AS suite_path
+    FROM test_suites s
+    WHERE s.analysis_id = $1 AND s.parent_id IS NULL
+    UNION ALL
+    SELECT s.id, s.file_path, sp.suite_path || s.name::text
+    FROM test_suites s
+    JOIN suite_paths sp ON s.parent_id = sp.id
+)
+SELECT tc.id, sp.file_path, sp.suite_path::text[] AS suite_path, tc.name
+FROM suite_paths sp
+JOIN test_cases tc ON tc.suite_id = sp.id;
+
+-- name: DeleteTestExecutionsByReport :exec
+DELETE FROM test_executions WHERE analysis_id = $1 AND report_name = $2;
+
+-- name: DeleteFileCoverageByReport :exec
+DELETE FROM analysis_file_coverage WHERE analysis_id = $1 AND report_name = $2;
+
+-- name: InsertAnalysisCICommand :exec
+INSERT INTO analysis_ci_commands (analysis_id, config_path, job_name, command, frameworks)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: InsertAnalysisCIFramework :exec
+INSERT INTO analysis_ci_frameworks (analysis_id, framework, executed, test_files)
+VALUES ($1, $2, $3, $4);
+
+-- name: GetPreviousCompletedAnalysisID :one
+SELECT prev.id
+FROM analyses cur
+JOIN analyses prev ON prev.codebase_id = cur.codebase_id
+WHERE cur.id = @analysis_id
+  AND prev.id <> cur.id
+  AND prev.status = 'completed'
+  AND (cur.committed_at IS NULL OR prev.committed_at < cur.committed_at)
+ORDER BY prev.committed_at DESC NULLS LAST, prev.completed_at DESC
+LIMIT 1;
+
+-- name: GetAnalysisFrameworkTotals :many
+SELECT
+    COALESCE(ts.framework, '')::text AS framework,
+    COUNT(tc.id)::int AS total_tests,
+    (COUNT(tc.id) FILTER (WHERE tc.status = 'skipped'))::int AS skipped_tests
+FROM test_suites ts
+JOIN test_cases tc ON tc.suite_id = ts.id
+WHERE ts.analysis_id = $1
+GROUP BY COALESCE(ts.framework, '')
+ORDER BY framework;
+
+-- name: InsertAnalysisAnomaly :exec
+INSERT INTO analysis_anomalies (analysis_id, previous_analysis_id, kind, framework, previous_value, current_value)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: FindLatestCompletedAnalysis :one
+SELECT a.id FROM analyses a
+JOIN codebases c ON c.id = a.codebase_id
+WHERE c.host = $1 AND c.owner = $2 AND c.name = $3 AND c.is_stale = false
+  AND a.status = 'completed' AND a.is_historical = false
+ORDER BY a.committed_at DESC NULLS LAST, a.completed_at DESC
+LIMIT 1;
+
+-- name: FindUpstreamLatestCompletedAnalysis :one
+SELECT a.id FROM codebases f
+JOIN codebases u ON u.host = f.host AND u.external_repo_id = f.parent_external_repo_id AND u.is_stale = false
+JOIN analyses a ON a.codebase_id = u.id
+WHERE f.host = $1 AND f.owner = $2 AND f.name = $3 AND f.is_stale = false
+  AND a.status = 'completed' AND a.is_historical = false
+ORDER BY a.committed_at DESC NULLS LAST, a.completed_at DESC
+LIMIT 1;
+
+-- name: GetCompletedAnalysisWithCodebase :one
+SELECT a.commit_sha, a.branch_name, a.completed_at, c.host, c.owner, c.name
+FROM analyses a
+JOIN codebases c ON c.id = a.codebase_id
+WHERE a.id = $1 AND a.status = 'completed';
+
+-- name: GetTestCasesByAnalysisID :many
+SELECT tc.id, tc.suite_id, tc.name, tc.line_number, tc.end_line_number, tc.status
+FROM test_cases tc
+JOIN test_suites ts ON ts.id = tc.suite_id
+WHERE ts.analysis_id = $1
+ORDER BY tc.suite_id, tc.line_number;
+
+-- name: GetActiveInstallationIDByAccountLogin :one
+SELECT installation_id FROM github_app_installations
+WHERE lower(account_login) = lower(@account_login) AND suspended_at IS NULL;
+
+-- name: GetCodebaseCredential :one
+SELECT cc.kind, cc.secret FROM codebase_credentials cc
+JOIN codebases c ON c.id = cc.codebase_id
+WHERE c.host = $1 AND c.owner = $2 AND c.name = $3 AND c.is_stale = false;
+
+-- name: UpsertCodebaseCredential :exec
+INSERT INTO codebase_credentials (codebase_id, kind, secret)
+VALUES ($1, $2, $3)
+ON CONFLICT (codebase_id) DO UPDATE SET kind = EXCLUDED.kind, secret = EXCLUDED.secret, updated_at = now();
+
+-- name: DeleteCodebaseCredential :execrows
+DELETE FROM codebase_credentials WHERE codebase_id = $1;
+
+-- name: ListCodebaseCredentialSecrets :many
+SELECT codebase_id, secret FROM codebase_credentials
+WHERE codebase_id > @after_id
+ORDER BY codebase_id
+LIMIT @batch_size;
+
+-- name: ReplaceCodebaseCredentialSecret :execrows
+UPDATE codebase_credentials
+SET secret = @new_secret
+WHERE codebase_id = @codebase_id AND secret = @old_secret;
+
+-- name: InsertReportUpload :one
+INSERT INTO report_uploads (report) VALUES ($1) RETURNING id;
+
+-- name: GetReportUpload :one
+SELECT report FROM report_uploads WHERE id = $1;
+
+-- name: DeleteReportUpload :exec
+DELETE FROM report_uploads WHERE id = $1;
@@ -12,18 +12,19 @@ import (
 )
 
 const createAnalysis = `-- name: CreateAnalysis :one
-INSERT INTO analyses (id, codebase_id, commit_sha, branch_name, status, started_at)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, codebase_id, commit_sha, branch_name, status, error_message, started_at, completed_at, created_at, total_suites, total_tests, committed_at
+INSERT INTO analyses (id, codebase_id, commit_sha, branch_name, status, started_at, is_historical)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, codebase_id, commit_sha, branch_name, status, error_message, started_at, completed_at, created_at, total_suites, total_tests, committed_at, config_hash, total_source_files, total_source_lines, test_file_ratio, tests_per_kloc, is_historical
 `
 
 type CreateAnalysisParams struct {
-	ID         pgtype.UUID        `json:"id"`
-	CodebaseID pgtype.UUID        `json:"codebase_id"`
-	CommitSha  string             `json:"commit_sha"`
-	BranchName pgtype.Text        `json:"branch_name"`
-	Status     AnalysisStatus     `json:"status"`
-	StartedAt  pgtype.Timestamptz `json:"started_at"`
+	ID           pgtype.UUID        `json:"id"`
+	CodebaseID   pgtype.UUID        `json:"codebase_id"`
+	CommitSha    string             `json:"commit_sha"`
+	BranchName   pgtype.Text        `json:"branch_name"`
+	Status       AnalysisStatus     `json:"status"`
+	StartedAt    pgtype.Timestamptz `json:"started_at"`
+	IsHistorical bool               `json:"is_historical"`
 }
 
 func (q *Queries) CreateAnalysis(ctx context.Context, arg CreateAnalysisParams) (Analysis, error) {
@@ -34,6 +35,7 @@ func (q *Queries) CreateAnalysis(ctx context.Context, arg CreateAnalysisParams)
 		arg.BranchName,
 		arg.Status,
 		arg.StartedAt,
+		arg.IsHistorical,
 	)
 	var i Analysis
 	err := row.Scan(
@@ -49,6 +51,12 @@ func (q *Queries) CreateAnalysis(ctx context.Context, arg CreateAnalysisParams)
 		&i.TotalSuites,
 		&i.TotalTests,
 		&i.CommittedAt,
+		&i.ConfigHash,
+		&i.TotalSourceFiles,
+		&i.TotalSourceLines,
+		&i.TestFileRatio,
+		&i.TestsPerKloc,
+		&i.IsHistorical,
 	)
 	return i, err
 }
@@ -56,7 +64,7 @@ func (q *Queries) CreateAnalysis(ctx context.Context, arg CreateAnalysisParams)
 const createTestCase = `-- name: CreateTestCase :one
 INSERT INTO test_cases (suite_id, name, line_number, status, tags, modifier)
 VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, suite_id, name, line_number, status, tags, modifier
+RETURNING id, suite_id, name, line_number, status, tags, modifier, introduced_commit_sha, introduced_by_name, introduced_by_email, introduced_at, last_modified_at, end_line_number
 `
 
 type CreateTestCaseParams struct {
@@ -86,24 +94,31 @@ func (q *Queries) CreateTestCase(ctx context.Context, arg CreateTestCaseParams)
 		&i.Status,
 		&i.Tags,
 		&i.Modifier,
+		&i.IntroducedCommitSha,
+		&i.IntroducedByName,
+		&i.IntroducedByEmail,
+		&i.IntroducedAt,
+		&i.LastModifiedAt,
+		&i.EndLineNumber,
 	)
 	return i, err
 }
 
 const createTestSuite = `-- name: CreateTestSuite :one
-INSERT INTO test_suites (analysis_id, parent_id, name, file_path, line_number, framework, depth)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, analysis_id, parent_id, name, file_path, line_number, framework, depth
+INSERT INTO test_suites (analysis_id, parent_id, name, file_path, line_number, framework, depth, package_path)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, analysis_id, parent_id, name, file_path, line_number, framework, depth, package_path, end_line_number
 `
 
 type CreateTestSuiteParams struct {
-	AnalysisID pgtype.UUID `json:"analysis_id"`
-	ParentID   pgtype.UUID `json:"parent_id"`
-	Name       string      `json:"name"`
-	FilePath   string      `json:"file_path"`
-	LineNumber pgtype.Int4 `json:"line_number"`
-	Framework  pgtype.Text `json:"framework"`
-	Depth      int32       `json:"depth"`
+	AnalysisID  pgtype.UUID `json:"analysis_id"`
+	ParentID    pgtype.UUID `json:"parent_id"`
+	Name        string      `json:"name"`
+	FilePath    string      `json:"file_path"`
+	LineNumber  pgtype.Int4 `json:"line_number"`
+	Framework   pgtype.Text `json:"framework"`
+	Depth       int32       `json:"depth"`
+	PackagePath pgtype.Text `json:"package_path"`
 }
 
 func (q *Queries) CreateTestSuite(ctx context.Context, arg CreateTestSuiteParams) (TestSuite, error) {
@@ -115,6 +130,7 @@ func (q *Queries) CreateTestSuite(ctx context.Context, arg CreateTestSuiteParams
 		arg.LineNumber,
 		arg.Framework,
 		arg.Depth,
+		arg.PackagePath,
 	)
 	var i TestSuite
 	err := row.Scan(
@@ -126,12 +142,63 @@ func (q *Queries) CreateTestSuite(ctx context.Context, arg CreateTestSuiteParams
 		&i.LineNumber,
 		&i.Framework,
 		&i.Depth,
+		&i.PackagePath,
+		&i.EndLineNumber,
 	)
 	return i, err
 }
 
+const deleteCodebaseCredential = `-- name: DeleteCodebaseCredential :execrows
+DELETE FROM codebase_credentials WHERE codebase_id = $1
+`
+
+func (q *Queries) DeleteCodebaseCredential(ctx context.Context, codebaseID pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteCodebaseCredential, codebaseID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteFileCoverageByReport = `-- name: DeleteFileCoverageByReport :exec
+DELETE FROM analysis_file_coverage WHERE analysis_id = $1 AND report_name = $2
+`
+
+type DeleteFileCoverageByReportParams struct {
+	AnalysisID pgtype.UUID `json:"analysis_id"`
+	ReportName string      `json:"report_name"`
+}
+
+func (q *Queries) DeleteFileCoverageByReport(ctx context.Context, arg DeleteFileCoverageByReportParams) error {
+	_, err := q.db.Exec(ctx, deleteFileCoverageByReport, arg.AnalysisID, arg.ReportName)
+	return err
+}
+
+const deleteReportUpload = `-- name: DeleteReportUpload :exec
+DELETE FROM report_uploads WHERE id = $1
+`
+
+func (q *Queries) DeleteReportUpload(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteReportUpload, id)
+	return err
+}
+
+const deleteTestExecutionsByReport = `-- name: DeleteTestExecutionsByReport :exec
+DELETE FROM test_executions WHERE analysis_id = $1 AND report_name = $2
+`
+
+type DeleteTestExecutionsByReportParams struct {
+	AnalysisID pgtype.UUID `json:"analysis_id"`
+	ReportName string      `json:"report_name"`
+}
+
+func (q *Queries) DeleteTestExecutionsByReport(ctx context.Context, arg DeleteTestExecutionsByReportParams) error {
+	_, err := q.db.Exec(ctx, deleteTestExecutionsByReport, arg.AnalysisID, arg.ReportName)
+	return err
+}
+
 const findCodebaseByExternalID = `-- name: FindCodebaseByExternalID :one
-SELECT id, host, owner, name, default_branch, created_at, updated_at, last_viewed_at, external_repo_id, is_stale, is_private FROM codebases
+SELECT id, host, owner, name, default_branch, created_at, updated_at, last_viewed_at, external_repo_id, is_stale, is_private, is_archived, is_disabled, is_fork, parent_full_name, primary_language, topics, license_spdx_id, size_kb, stargazers_count, pushed_at, metadata_refreshed_at, empty_checked_at, parent_external_repo_id, stale_reason, stale_at FROM codebases
 WHERE host = $1 AND external_repo_id = $2
 `
 
@@ -155,12 +222,27 @@ func (q *Queries) FindCodebaseByExternalID(ctx context.Context, arg FindCodebase
 		&i.ExternalRepoID,
 		&i.IsStale,
 		&i.IsPrivate,
+		&i.IsArchived,
+		&i.IsDisabled,
+		&i.IsFork,
+		&i.ParentFullName,
+		&i.PrimaryLanguage,
+		&i.Topics,
+		&i.LicenseSpdxID,
+		&i.SizeKb,
+		&i.StargazersCount,
+		&i.PushedAt,
+		&i.MetadataRefreshedAt,
+		&i.EmptyCheckedAt,
+		&i.ParentExternalRepoID,
+		&i.StaleReason,
+		&i.StaleAt,
 	)
 	return i, err
 }
 
 const findCodebaseByOwnerName = `-- name: FindCodebaseByOwnerName :one
-SELECT id, host, owner, name, default_branch, created_at, updated_at, last_viewed_at, external_repo_id, is_stale, is_private FROM codebases
+SELECT id, host, owner, name, default_branch, created_at, updated_at, last_viewed_at, external_repo_id, is_stale, is_private, is_archived, is_disabled, is_fork, parent_full_name, primary_language, topics, license_spdx_id, size_kb, stargazers_count, pushed_at, metadata_refreshed_at, empty_checked_at, parent_external_repo_id, stale_reason, stale_at FROM codebases
 WHERE host = $1 AND owner = $2 AND name = $3 AND is_stale = false
 `
 
@@ -185,20 +267,35 @@ func (q *Queries) FindCodebaseByOwnerName(ctx context.Context, arg FindCodebaseB
 		&i.ExternalRepoID,
 		&i.IsStale,
 		&i.IsPrivate,
+		&i.IsArchived,
+		&i.IsDisabled,
+		&i.IsFork,
+		&i.ParentFullName,
+		&i.PrimaryLanguage,
+		&i.Topics,
+		&i.LicenseSpdxID,
+		&i.SizeKb,
+		&i.StargazersCount,
+		&i.PushedAt,
+		&i.MetadataRefreshedAt,
+		&i.EmptyCheckedAt,
+		&i.ParentExternalRepoID,
+		&i.StaleReason,
+		&i.StaleAt,
 	)
 	return i, err
 }
 
 const findCodebaseWithLastCommitByOwnerName = `-- name: FindCodebaseWithLastCommitByOwnerName :one
 SELECT
-    c.id, c.host, c.owner, c.name, c.default_branch, c.created_at, c.updated_at, c.last_viewed_at, c.external_repo_id, c.is_stale, c.is_private,
+    c.id, c.host, c.owner, c.name, c.default_branch, c.created_at, c.updated_at, c.last_viewed_at, c.external_repo_id, c.is_stale, c.is_private, c.is_archived, c.is_disabled, c.is_fork, c.parent_full_name, c.primary_language, c.topics, c.license_spdx_id, c.size_kb, c.stargazers_count, c.pushed_at, c.metadata_refreshed_at, c.empty_checked_at, c.parent_external_repo_id, c.stale_reason, c.stale_at,
     COALESCE(a.commit_sha, '') as last_commit_sha
 FROM codebases c
 LEFT JOIN (
     SELECT DISTINCT ON (codebase_id) codebase_id, commit_sha
     FROM analyses
-    WHERE status = 'completed'
-    ORDER BY codebase_id, completed_at DESC
+    WHERE status = 'completed' AND is_historical = false
+    ORDER BY codebase_id, committed_at DESC NULLS LAST, completed_at DESC
 ) a ON c.id = a.codebase_id
 WHERE c.host = $1 AND c.owner = $2 AND c.name = $3 AND c.is_stale = false
 `
@@ -210,18 +307,33 @@ type FindCodebaseWithLastCommitByOwnerNameParams struct {
 }
 
 type FindCodebaseWithLastCommitByOwnerNameRow struct {
-	ID             pgtype.UUID        `json:"id"`
-	Host           string             `json:"host"`
-	Owner          string             `json:"owner"`
-	Name           string             `json:"name"`
-	DefaultBranch  pgtype.Text        `json:"default_branch"`
-	CreatedAt      pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
-	LastViewedAt   pgtype.Timestamptz `json:"last_viewed_at"`
-	ExternalRepoID string             `json:"external_repo_id"`
-	IsStale        bool               `json:"is_stale"`
-	IsPrivate      bool               `json:"is_private"`
-	LastCommitSha  string             `json:"last_commit_sha"`
+	ID                   pgtype.UUID        `json:"id"`
+	Host                 string             `json:"host"`
+	Owner                string             `json:"owner"`
+	Name                 string             `json:"name"`
+	DefaultBranch        pgtype.Text        `json:"default_branch"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	LastViewedAt         pgtype.Timestamptz `json:"last_viewed_at"`
+	ExternalRepoID       string             `json:"external_repo_id"`
+	IsStale              bool               `json:"is_stale"`
+	IsPrivate            bool               `json:"is_private"`
+	IsArchived           bool               `json:"is_archived"`
+	IsDisabled           bool               `json:"is_disabled"`
+	IsFork               bool               `json:"is_fork"`
+	ParentFullName       pgtype.Text        `json:"parent_full_name"`
+	PrimaryLanguage      pgtype.Text        `json:"primary_language"`
+	Topics               []string           `json:"topics"`
+	LicenseSpdxID        pgtype.Text        `json:"license_spdx_id"`
+	SizeKb               int64              `json:"size_kb"`
+	StargazersCount      int32              `json:"stargazers_count"`
+	PushedAt             pgtype.Timestamptz `json:"pushed_at"`
+	MetadataRefreshedAt  pgtype.Timestamptz `json:"metadata_refreshed_at"`
+	EmptyCheckedAt       pgtype.Timestamptz `json:"empty_checked_at"`
+	ParentExternalRepoID pgtype.Text        `json:"parent_external_repo_id"`
+	StaleReason          pgtype.Text        `json:"stale_reason"`
+	StaleAt              pgtype.Timestamptz `json:"stale_at"`
+	LastCommitSha        string             `json:"last_commit_sha"`
 }
 
 func (q *Queries) FindCodebaseWithLastCommitByOwnerName(ctx context.Context, arg FindCodebaseWithLastCommitByOwnerNameParams) (FindCodebaseWithLastCommitByOwnerNameRow, error) {
@@ -239,13 +351,151 @@ func (q *Queries) FindCodebaseWithLastCommitByOwnerName(ctx context.Context, arg
 		&i.ExternalRepoID,
 		&i.IsStale,
 		&i.IsPrivate,
+		&i.IsArchived,
+		&i.IsDisabled,
+		&i.IsFork,
+		&i.ParentFullName,
+		&i.PrimaryLanguage,
+		&i.Topics,
+		&i.LicenseSpdxID,
+		&i.SizeKb,
+		&i.StargazersCount,
+		&i.PushedAt,
+		&i.MetadataRefreshedAt,
+		&i.EmptyCheckedAt,
+		&i.ParentExternalRepoID,
+		&i.StaleReason,
+		&i.StaleAt,
 		&i.LastCommitSha,
 	)
 	return i, err
 }
 
+const findCompletedAnalysisByCommit = `-- name: FindCompletedAnalysisByCommit :one
+SELECT a.id FROM analyses a
+JOIN codebases c ON c.id = a.codebase_id
+WHERE c.host = $1 AND c.owner = $2 AND c.name = $3 AND c.is_stale = false
+  AND a.commit_sha = $4 AND a.status = 'completed'
+ORDER BY a.completed_at DESC
+LIMIT 1
+`
+
+type FindCompletedAnalysisByCommitParams struct {
+	Host      string `json:"host"`
+	Owner     string `json:"owner"`
+	Name      string `json:"name"`
+	CommitSha string `json:"commit_sha"`
+}
+
+func (q *Queries) FindCompletedAnalysisByCommit(ctx context.Context, arg FindCompletedAnalysisByCommitParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, findCompletedAnalysisByCommit,
+		arg.Host,
+		arg.Owner,
+		arg.Name,
+		arg.CommitSha,
+	)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const findLatestCompletedAnalysis = `-- name: FindLatestCompletedAnalysis :one
+SELECT a.id FROM analyses a
+JOIN codebases c ON c.id = a.codebase_id
+WHERE c.host = $1 AND c.owner = $2 AND c.name = $3 AND c.is_stale = false
+  AND a.status = 'completed' AND a.is_historical = false
+ORDER BY a.committed_at DESC NULLS LAST, a.completed_at DESC
+LIMIT 1
+`
+
+type FindLatestCompletedAnalysisParams struct {
+	Host  string `json:"host"`
+	Owner string `json:"owner"`
+	Name  string `json:"name"`
+}
+
+func (q *Queries) FindLatestCompletedAnalysis(ctx context.Context, arg FindLatestCompletedAnalysisParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, findLatestCompletedAnalysis, arg.Host, arg.Owner, arg.Name)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const findUpstreamLatestCompletedAnalysis = `-- name: FindUpstreamLatestCompletedAnalysis :one
+SELECT a.id FROM codebases f
+JOIN codebases u ON u.host = f.host AND u.external_repo_id = f.parent_external_repo_id AND u.is_stale = false
+JOIN analyses a ON a.codebase_id = u.id
+WHERE f.host = $1 AND f.owner = $2 AND f.name = $3 AND f.is_stale = false
+  AND a.status = 'completed' AND a.is_historical = false
+ORDER BY a.committed_at DESC NULLS LAST, a.completed_at DESC
+LIMIT 1
+`
+
+type FindUpstreamLatestCompletedAnalysisParams struct {
+	Host  string `json:"host"`
+	Owner string `json:"owner"`
+	Name  string `json:"name"`
+}
+
+func (q *Queries) FindUpstreamLatestCompletedAnalysis(ctx context.Context, arg FindUpstreamLatestCompletedAnalysisParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, findUpstreamLatestCompletedAnalysis, arg.Host, arg.Owner, arg.Name)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getActiveInstallationIDByAccountLogin = `-- name: GetActiveInstallationIDByAccountLogin :one
+SELECT installation_id FROM github_app_installations
+WHERE lower(account_login) = lower($1) AND suspended_at IS NULL
+`
+
+func (q *Queries) GetActiveInstallationIDByAccountLogin(ctx context.Context, accountLogin string) (int64, error) {
+	row := q.db.QueryRow(ctx, getActiveInstallationIDByAccountLogin, accountLogin)
+	var installation_id int64
+	err := row.Scan(&installation_id)
+	return installation_id, err
+}
+
+const getAnalysisFrameworkTotals = `-- name: GetAnalysisFrameworkTotals :many
+SELECT
+    COALESCE(ts.framework, '')::text AS framework,
+    COUNT(tc.id)::int AS total_tests,
+    (COUNT(tc.id) FILTER (WHERE tc.status = 'skipped'))::int AS skipped_tests
+FROM test_suites ts
+JOIN test_cases tc ON tc.suite_id = ts.id
+WHERE ts.analysis_id = $1
+GROUP BY COALESCE(ts.framework, '')
+ORDER BY framework
+`
+
+type GetAnalysisFrameworkTotalsRow struct {
+	Framework    string `json:"framework"`
+	TotalTests   int32  `json:"total_tests"`
+	SkippedTests int32  `json:"skipped_tests"`
+}
+
+func (q *Queries) GetAnalysisFrameworkTotals(ctx context.Context, analysisID pgtype.UUID) ([]GetAnalysisFrameworkTotalsRow, error) {
+	rows, err := q.db.Query(ctx, getAnalysisFrameworkTotals, analysisID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetAnalysisFrameworkTotalsRow{}
+	for rows.Next() {
+		var i GetAnalysisFrameworkTotalsRow
+		if err := rows.Scan(&i.Framework, &i.TotalTests, &i.SkippedTests); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getCodebaseByID = `-- name: GetCodebaseByID :one
-SELECT id, host, owner, name, default_branch, created_at, updated_at, last_viewed_at, external_repo_id, is_stale, is_private FROM codebases WHERE id = $1
+SELECT id, host, owner, name, default_branch, created_at, updated_at, last_viewed_at, external_repo_id, is_stale, is_private, is_archived, is_disabled, is_fork, parent_full_name, primary_language, topics, license_spdx_id, size_kb, stargazers_count, pushed_at, metadata_refreshed_at, empty_checked_at, parent_external_repo_id, stale_reason, stale_at FROM codebases WHERE id = $1
 `
 
 func (q *Queries) GetCodebaseByID(ctx context.Context, id pgtype.UUID) (Codebasis, error) {
@@ -263,10 +513,83 @@ func (q *Queries) GetCodebaseByID(ctx context.Context, id pgtype.UUID) (Codebasi
 		&i.ExternalRepoID,
 		&i.IsStale,
 		&i.IsPrivate,
+		&i.IsArchived,
+		&i.IsDisabled,
+		&i.IsFork,
+		&i.ParentFullName,
+		&i.PrimaryLanguage,
+		&i.Topics,
+		&i.LicenseSpdxID,
+		&i.SizeKb,
+		&i.StargazersCount,
+		&i.PushedAt,
+		&i.MetadataRefreshedAt,
+		&i.EmptyCheckedAt,
+		&i.ParentExternalRepoID,
+		&i.StaleReason,
+		&i.StaleAt,
 	)
 	return i, err
 }
 
+const getCodebaseCredential = `-- name: GetCodebaseCredential :one
+SELECT cc.kind, cc.secret FROM codebase_credentials cc
+JOIN codebases c ON c.id = cc.codebase_id
+WHERE c.host = $1 AND c.owner = $2 AND c.name = $3 AND c.is_stale = false
+`
+
+type GetCodebaseCredentialParams struct {
+	Host  string `json:"host"`
+	Owner string `json:"owner"`
+	Name  string `json:"name"`
+}
+
+type GetCodebaseCredentialRow struct {
+	Kind   string `json:"kind"`
+	Secret string `json:"secret"`
+}
+
+func (q *Queries) GetCodebaseCredential(ctx context.Context, arg GetCodebaseCredentialParams) (GetCodebaseCredentialRow, error) {
+	row := q.db.QueryRow(ctx, getCodebaseCredential, arg.Host, arg.Owner, arg.Name)
+	var i GetCodebaseCredentialRow
+	err := row.Scan(&i.Kind, &i.Secret)
+	return i, err
+}
+
+const getCodebasePolicyRules = `-- name: GetCodebasePolicyRules :many
+SELECT id, codebase_id, name, kind, status, threshold, path_pattern, created_at, updated_at FROM codebase_policy_rules WHERE codebase_id = $1 ORDER BY name
+`
+
+func (q *Queries) GetCodebasePolicyRules(ctx context.Context, codebaseID pgtype.UUID) ([]CodebasePolicyRule, error) {
+	rows, err := q.db.Query(ctx, getCodebasePolicyRules, codebaseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CodebasePolicyRule{}
+	for rows.Next() {
+		var i CodebasePolicyRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.CodebaseID,
+			&i.Name,
+			&i.Kind,
+			&i.Status,
+			&i.Threshold,
+			&i.PathPattern,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getCodebasesForAutoRefresh = `-- name: GetCodebasesForAutoRefresh :many
 WITH latest_completions AS (
     SELECT DISTINCT ON (codebase_id)
@@ -274,8 +597,8 @@ WITH latest_completions AS (
         completed_at,
         commit_sha
     FROM analyses
-    WHERE status = 'completed'
-    ORDER BY codebase_id, completed_at DESC
+    WHERE status = 'completed' AND is_historical = false
+    ORDER BY codebase_id, committed_at DESC NULLS LAST, completed_at DESC
 ),
 failure_counts AS (
     SELECT
@@ -284,21 +607,40 @@ failure_counts AS (
     FROM analyses a
     LEFT JOIN latest_completions lc ON a.codebase_id = lc.codebase_id
     WHERE a.status = 'failed'
+      AND a.is_historical = false
       AND a.created_at > COALESCE(lc.completed_at, '1970-01-01'::timestamptz)
     GROUP BY a.codebase_id
+),
+last_viewers AS (
+    SELECT DISTINCT ON (a.codebase_id)
+        a.codebase_id,
+        uah.user_id
+    FROM user_analysis_history uah
+    JOIN analyses a ON a.id = uah.analysis_id
+    JOIN oauth_accounts oa ON oa.user_id = uah.user_id
+    WHERE oa.provider = 'github'
+      AND oa.access_token IS NOT NULL
+      AND (oa.token_invalidated_at IS NULL OR oa.token_invalidated_at < oa.updated_at)
+    ORDER BY a.codebase_id, uah.updated_at DESC
 )
 SELECT
-    c.id, c.host, c.owner, c.name, c.last_viewed_at,
+    c.id, c.host, c.owner, c.name, c.last_viewed_at, c.is_private, c.metadata_refreshed_at,
+    c.is_archived, c.is_disabled, c.empty_checked_at,
     lc.completed_at as last_completed_at,
     lc.commit_sha as last_commit_sha,
-    COALESCE(fc.failure_count, 0)::int as consecutive_failures
+    COALESCE(fc.failure_count, 0)::int as consecutive_failures,
+    lv.user_id as last_viewer_id
 FROM codebases c
 LEFT JOIN latest_completions lc ON c.id = lc.codebase_id
 LEFT JOIN failure_counts fc ON c.id = fc.codebase_id
+LEFT JOIN last_viewers lv ON c.id = lv.codebase_id
 WHERE c.last_viewed_at IS NOT NULL
   AND c.last_viewed_at > now() - interval '90 days'
   AND c.is_stale = false
-  AND c.is_private = false
+  AND NOT (
+      (c.is_archived OR c.is_disabled)
+      AND c.metadata_refreshed_at > now() - interval '1 day'
+  )
 `
 
 type GetCodebasesForAutoRefreshRow struct {
@@ -307,9 +649,15 @@ type GetCodebasesForAutoRefreshRow struct {
 	Owner               string             `json:"owner"`
 	Name                string             `json:"name"`
 	LastViewedAt        pgtype.Timestamptz `json:"last_viewed_at"`
+	IsPrivate           bool               `json:"is_private"`
+	MetadataRefreshedAt pgtype.Timestamptz `json:"metadata_refreshed_at"`
+	IsArchived          bool               `json:"is_archived"`
+	IsDisabled          bool               `json:"is_disabled"`
+	EmptyCheckedAt      pgtype.Timestamptz `json:"empty_checked_at"`
 	LastCompletedAt     pgtype.Timestamptz `json:"last_completed_at"`
 	LastCommitSha       pgtype.Text        `json:"last_commit_sha"`
 	ConsecutiveFailures int32              `json:"consecutive_failures"`
+	LastViewerID        pgtype.UUID        `json:"last_viewer_id"`
 }
 
 func (q *Queries) GetCodebasesForAutoRefresh(ctx context.Context) ([]GetCodebasesForAutoRefreshRow, error) {
@@ -327,9 +675,15 @@ func (q *Queries) GetCodebasesForAutoRefresh(ctx context.Context) ([]GetCodebase
 			&i.Owner,
 			&i.Name,
 			&i.LastViewedAt,
+			&i.IsPrivate,
+			&i.MetadataRefreshedAt,
+			&i.IsArchived,
+			&i.IsDisabled,
+			&i.EmptyCheckedAt,
 			&i.LastCompletedAt,
 			&i.LastCommitSha,
 			&i.ConsecutiveFailures,
+			&i.LastViewerID,
 		); err != nil {
 			return nil, err
 		}
@@ -341,8 +695,67 @@ func (q *Queries) GetCodebasesForAutoRefresh(ctx context.Context) ([]GetCodebase
 	return items, nil
 }
 
+const getCodebaseTags = `-- name: GetCodebaseTags :many
+SELECT name, commit_sha FROM codebase_tags WHERE codebase_id = $1 ORDER BY name
+`
+
+type GetCodebaseTagsRow struct {
+	Name      string `json:"name"`
+	CommitSha string `json:"commit_sha"`
+}
+
+func (q *Queries) GetCodebaseTags(ctx context.Context, codebaseID pgtype.UUID) ([]GetCodebaseTagsRow, error) {
+	rows, err := q.db.Query(ctx, getCodebaseTags, codebaseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetCodebaseTagsRow{}
+	for rows.Next() {
+		var i GetCodebaseTagsRow
+		if err := rows.Scan(&i.Name, &i.CommitSha); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCompletedAnalysisWithCodebase = `-- name: GetCompletedAnalysisWithCodebase :one
+SELECT a.commit_sha, a.branch_name, a.completed_at, c.host, c.owner, c.name
+FROM analyses a
+JOIN codebases c ON c.id = a.codebase_id
+WHERE a.id = $1 AND a.status = 'completed'
+`
+
+type GetCompletedAnalysisWithCodebaseRow struct {
+	CommitSha   string             `json:"commit_sha"`
+	BranchName  pgtype.Text        `json:"branch_name"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	Host        string             `json:"host"`
+	Owner       string             `json:"owner"`
+	Name        string             `json:"name"`
+}
+
+func (q *Queries) GetCompletedAnalysisWithCodebase(ctx context.Context, id pgtype.UUID) (GetCompletedAnalysisWithCodebaseRow, error) {
+	row := q.db.QueryRow(ctx, getCompletedAnalysisWithCodebase, id)
+	var i GetCompletedAnalysisWithCodebaseRow
+	err := row.Scan(
+		&i.CommitSha,
+		&i.BranchName,
+		&i.CompletedAt,
+		&i.Host,
+		&i.Owner,
+		&i.Name,
+	)
+	return i, err
+}
+
 const getOAuthAccountByUserAndProvider = `-- name: GetOAuthAccountByUserAndProvider :one
-SELECT id, user_id, provider, provider_user_id, provider_username, access_token, scope, created_at, updated_at FROM oauth_accounts WHERE user_id = $1 AND provider = $2
+SELECT id, user_id, provider, provider_user_id, provider_username, access_token, scope, created_at, updated_at, token_invalidated_at FROM oauth_accounts WHERE user_id = $1 AND provider = $2
 `
 
 type GetOAuthAccountByUserAndProviderParams struct {
@@ -363,12 +776,135 @@ func (q *Queries) GetOAuthAccountByUserAndProvider(ctx context.Context, arg GetO
 		&i.Scope,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TokenInvalidatedAt,
 	)
 	return i, err
 }
 
+const getPreviousCompletedAnalysisID = `-- name: GetPreviousCompletedAnalysisID :one
+SELECT prev.id
+FROM analyses cur
+JOIN analyses prev ON prev.codebase_id = cur.codebase_id
+WHERE cur.id = $1
+  AND prev.id <> cur.id
+  AND prev.status = 'completed'
+  AND (cur.committed_at IS NULL OR prev.committed_at < cur.committed_at)
+ORDER BY prev.committed_at DESC NULLS LAST, prev.completed_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetPreviousCompletedAnalysisID(ctx context.Context, analysisID pgtype.UUID) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, getPreviousCompletedAnalysisID, analysisID)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getReportUpload = `-- name: GetReportUpload :one
+SELECT report FROM report_uploads WHERE id = $1
+`
+
+func (q *Queries) GetReportUpload(ctx context.Context, id pgtype.UUID) ([]byte, error) {
+	row := q.db.QueryRow(ctx, getReportUpload, id)
+	var report []byte
+	err := row.Scan(&report)
+	return report, err
+}
+
+const getStaticTestsByAnalysisID = `-- name: GetStaticTestsByAnalysisID :many
+WITH RECURSIVE suite_paths AS (
+    SELECT s.id, s.file_path,
+        CASE WHEN s.name = s.file_path THEN ARRAY[]::text[] ELSE ARRAY[s.name::text] END AS suite_path
+    FROM test_suites s
+    WHERE s.analysis_id = $1 AND s.parent_id IS NULL
+    UNION ALL
+    SELECT s.id, s.file_path, sp.suite_path || s.name::text
+    FROM test_suites s
+    JOIN suite_paths sp ON s.parent_id = sp.id
+)
+SELECT tc.id, sp.file_path, sp.suite_path::text[] AS suite_path, tc.name
+FROM suite_paths sp
+JOIN test_cases tc ON tc.suite_id = sp.id
+`
+
+type GetStaticTestsByAnalysisIDRow struct {
+	ID        pgtype.UUID `json:"id"`
+	FilePath  string      `json:"file_path"`
+	SuitePath []string    `json:"suite_path"`
+	Name      string      `json:"name"`
+}
+
+func (q *Queries) GetStaticTestsByAnalysisID(ctx context.Context, analysisID pgtype.UUID) ([]GetStaticTestsByAnalysisIDRow, error) {
+	rows, err := q.db.Query(ctx, getStaticTestsByAnalysisID, analysisID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetStaticTestsByAnalysisIDRow{}
+	for rows.Next() {
+		var i GetStaticTestsByAnalysisIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FilePath,
+			&i.SuitePath,
+			&i.Name,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTestCasesByAnalysisID = `-- name: GetTestCasesByAnalysisID :many
+SELECT tc.id, tc.suite_id, tc.name, tc.line_number, tc.end_line_number, tc.status
+FROM test_cases tc
+JOIN test_suites ts ON ts.id = tc.suite_id
+WHERE ts.analysis_id = $1
+ORDER BY tc.suite_id, tc.line_number
+`
+
+type GetTestCasesByAnalysisIDRow struct {
+	ID            pgtype.UUID `json:"id"`
+	SuiteID       pgtype.UUID `json:"suite_id"`
+	Name          string      `json:"name"`
+	LineNumber    pgtype.Int4 `json:"line_number"`
+	EndLineNumber pgtype.Int4 `json:"end_line_number"`
+	Status        TestStatus  `json:"status"`
+}
+
+func (q *Queries) GetTestCasesByAnalysisID(ctx context.Context, analysisID pgtype.UUID) ([]GetTestCasesByAnalysisIDRow, error) {
+	rows, err := q.db.Query(ctx, getTestCasesByAnalysisID, analysisID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetTestCasesByAnalysisIDRow{}
+	for rows.Next() {
+		var i GetTestCasesByAnalysisIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SuiteID,
+			&i.Name,
+			&i.LineNumber,
+			&i.EndLineNumber,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTestCasesBySuiteID = `-- name: GetTestCasesBySuiteID :many
-SELECT id, suite_id, name, line_number, status, tags, modifier FROM test_cases WHERE suite_id = $1 ORDER BY line_number
+SELECT id, suite_id, name, line_number, status, tags, modifier, introduced_commit_sha, introduced_by_name, introduced_by_email, introduced_at, last_modified_at, end_line_number FROM test_cases WHERE suite_id = $1 ORDER BY line_number
 `
 
 func (q *Queries) GetTestCasesBySuiteID(ctx context.Context, suiteID pgtype.UUID) ([]TestCase, error) {
@@ -388,6 +924,12 @@ func (q *Queries) GetTestCasesBySuiteID(ctx context.Context, suiteID pgtype.UUID
 			&i.Status,
 			&i.Tags,
 			&i.Modifier,
+			&i.IntroducedCommitSha,
+			&i.IntroducedByName,
+			&i.IntroducedByEmail,
+			&i.IntroducedAt,
+			&i.LastModifiedAt,
+			&i.EndLineNumber,
 		); err != nil {
 			return nil, err
 		}
@@ -400,7 +942,7 @@ func (q *Queries) GetTestCasesBySuiteID(ctx context.Context, suiteID pgtype.UUID
 }
 
 const getTestSuitesByAnalysisID = `-- name: GetTestSuitesByAnalysisID :many
-SELECT id, analysis_id, parent_id, name, file_path, line_number, framework, depth FROM test_suites WHERE analysis_id = $1 ORDER BY file_path, line_number
+SELECT id, analysis_id, parent_id, name, file_path, line_number, framework, depth, package_path, end_line_number FROM test_suites WHERE analysis_id = $1 ORDER BY file_path, line_number
 `
 
 func (q *Queries) GetTestSuitesByAnalysisID(ctx context.Context, analysisID pgtype.UUID) ([]TestSuite, error) {
@@ -421,6 +963,8 @@ func (q *Queries) GetTestSuitesByAnalysisID(ctx context.Context, analysisID pgty
 			&i.LineNumber,
 			&i.Framework,
 			&i.Depth,
+			&i.PackagePath,
+			&i.EndLineNumber,
 		); err != nil {
 			return nil, err
 		}
@@ -432,12 +976,347 @@ func (q *Queries) GetTestSuitesByAnalysisID(ctx context.Context, analysisID pgty
 	return items, nil
 }
 
+const insertAnalysisAnomaly = `-- name: InsertAnalysisAnomaly :exec
+INSERT INTO analysis_anomalies (analysis_id, previous_analysis_id, kind, framework, previous_value, current_value)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type InsertAnalysisAnomalyParams struct {
+	AnalysisID         pgtype.UUID `json:"analysis_id"`
+	PreviousAnalysisID pgtype.UUID `json:"previous_analysis_id"`
+	Kind               string      `json:"kind"`
+	Framework          pgtype.Text `json:"framework"`
+	PreviousValue      int32       `json:"previous_value"`
+	CurrentValue       int32       `json:"current_value"`
+}
+
+func (q *Queries) InsertAnalysisAnomaly(ctx context.Context, arg InsertAnalysisAnomalyParams) error {
+	_, err := q.db.Exec(ctx, insertAnalysisAnomaly,
+		arg.AnalysisID,
+		arg.PreviousAnalysisID,
+		arg.Kind,
+		arg.Framework,
+		arg.PreviousValue,
+		arg.CurrentValue,
+	)
+	return err
+}
+
+const insertAnalysisCICommand = `-- name: InsertAnalysisCICommand :exec
+INSERT INTO analysis_ci_commands (analysis_id, config_path, job_name, command, frameworks)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type InsertAnalysisCICommandParams struct {
+	AnalysisID pgtype.UUID `json:"analysis_id"`
+	ConfigPath string      `json:"config_path"`
+	JobName    string      `json:"job_name"`
+	Command    string      `json:"command"`
+	Frameworks []string    `json:"frameworks"`
+}
+
+func (q *Queries) InsertAnalysisCICommand(ctx context.Context, arg InsertAnalysisCICommandParams) error {
+	_, err := q.db.Exec(ctx, insertAnalysisCICommand,
+		arg.AnalysisID,
+		arg.ConfigPath,
+		arg.JobName,
+		arg.Command,
+		arg.Frameworks,
+	)
+	return err
+}
+
+const insertAnalysisCIFramework = `-- name: InsertAnalysisCIFramework :exec
+INSERT INTO analysis_ci_frameworks (analysis_id, framework, executed, test_files)
+VALUES ($1, $2, $3, $4)
+`
+
+type InsertAnalysisCIFrameworkParams struct {
+	AnalysisID pgtype.UUID `json:"analysis_id"`
+	Framework  string      `json:"framework"`
+	Executed   bool        `json:"executed"`
+	TestFiles  int32       `json:"test_files"`
+}
+
+func (q *Queries) InsertAnalysisCIFramework(ctx context.Context, arg InsertAnalysisCIFrameworkParams) error {
+	_, err := q.db.Exec(ctx, insertAnalysisCIFramework,
+		arg.AnalysisID,
+		arg.Framework,
+		arg.Executed,
+		arg.TestFiles,
+	)
+	return err
+}
+
+const insertAnalysisLanguageStat = `-- name: InsertAnalysisLanguageStat :exec
+INSERT INTO analysis_language_stats (analysis_id, language, source_files, source_lines)
+VALUES ($1, $2, $3, $4)
+`
+
+type InsertAnalysisLanguageStatParams struct {
+	AnalysisID  pgtype.UUID `json:"analysis_id"`
+	Language    string      `json:"language"`
+	SourceFiles int32       `json:"source_files"`
+	SourceLines int32       `json:"source_lines"`
+}
+
+func (q *Queries) InsertAnalysisLanguageStat(ctx context.Context, arg InsertAnalysisLanguageStatParams) error {
+	_, err := q.db.Exec(ctx, insertAnalysisLanguageStat,
+		arg.AnalysisID,
+		arg.Language,
+		arg.SourceFiles,
+		arg.SourceLines,
+	)
+	return err
+}
+
+const insertAnalysisOwnerSummary = `-- name: InsertAnalysisOwnerSummary :exec
+INSERT INTO analysis_owner_summaries (analysis_id, owner, total_files, total_suites, total_tests, skipped_tests)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type InsertAnalysisOwnerSummaryParams struct {
+	AnalysisID   pgtype.UUID `json:"analysis_id"`
+	Owner        string      `json:"owner"`
+	TotalFiles   int32       `json:"total_files"`
+	TotalSuites  int32       `json:"total_suites"`
+	TotalTests   int32       `json:"total_tests"`
+	SkippedTests int32       `json:"skipped_tests"`
+}
+
+func (q *Queries) InsertAnalysisOwnerSummary(ctx context.Context, arg InsertAnalysisOwnerSummaryParams) error {
+	_, err := q.db.Exec(ctx, insertAnalysisOwnerSummary,
+		arg.AnalysisID,
+		arg.Owner,
+		arg.TotalFiles,
+		arg.TotalSuites,
+		arg.TotalTests,
+		arg.SkippedTests,
+	)
+	return err
+}
+
+const insertAnalysisPackage = `-- name: InsertAnalysisPackage :exec
+INSERT INTO analysis_packages (analysis_id, path, name, kind, total_files, total_suites, total_tests)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+type InsertAnalysisPackageParams struct {
+	AnalysisID  pgtype.UUID `json:"analysis_id"`
+	Path        string      `json:"path"`
+	Name        string      `json:"name"`
+	Kind        string      `json:"kind"`
+	TotalFiles  int32       `json:"total_files"`
+	TotalSuites int32       `json:"total_suites"`
+	TotalTests  int32       `json:"total_tests"`
+}
+
+func (q *Queries) InsertAnalysisPackage(ctx context.Context, arg InsertAnalysisPackageParams) error {
+	_, err := q.db.Exec(ctx, insertAnalysisPackage,
+		arg.AnalysisID,
+		arg.Path,
+		arg.Name,
+		arg.Kind,
+		arg.TotalFiles,
+		arg.TotalSuites,
+		arg.TotalTests,
+	)
+	return err
+}
+
+const insertAnalysisPolicyResult = `-- name: InsertAnalysisPolicyResult :exec
+INSERT INTO analysis_policy_results (analysis_id, name, kind, source, passed, detail)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type InsertAnalysisPolicyResultParams struct {
+	AnalysisID pgtype.UUID `json:"analysis_id"`
+	Name       string      `json:"name"`
+	Kind       string      `json:"kind"`
+	Source     string      `json:"source"`
+	Passed     bool        `json:"passed"`
+	Detail     pgtype.Text `json:"detail"`
+}
+
+func (q *Queries) InsertAnalysisPolicyResult(ctx context.Context, arg InsertAnalysisPolicyResultParams) error {
+	_, err := q.db.Exec(ctx, insertAnalysisPolicyResult,
+		arg.AnalysisID,
+		arg.Name,
+		arg.Kind,
+		arg.Source,
+		arg.Passed,
+		arg.Detail,
+	)
+	return err
+}
+
+const insertReportUpload = `-- name: InsertReportUpload :one
+INSERT INTO report_uploads (report) VALUES ($1) RETURNING id
+`
+
+func (q *Queries) InsertReportUpload(ctx context.Context, report []byte) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, insertReportUpload, report)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const linkCodebaseTagsToAnalysis = `-- name: LinkCodebaseTagsToAnalysis :exec
+UPDATE codebase_tags ct
+SET analysis_id = a.id, updated_at = now()
+FROM analyses a
+WHERE a.id = $1
+  AND ct.codebase_id = a.codebase_id
+  AND ct.commit_sha = a.commit_sha
+`
+
+func (q *Queries) LinkCodebaseTagsToAnalysis(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, linkCodebaseTagsToAnalysis, id)
+	return err
+}
+
+const listCodebaseCredentialSecrets = `-- name: ListCodebaseCredentialSecrets :many
+SELECT codebase_id, secret FROM codebase_credentials
+WHERE codebase_id > $1
+ORDER BY codebase_id
+LIMIT $2
+`
+
+type ListCodebaseCredentialSecretsParams struct {
+	AfterID   pgtype.UUID `json:"after_id"`
+	BatchSize int32       `json:"batch_size"`
+}
+
+type ListCodebaseCredentialSecretsRow struct {
+	CodebaseID pgtype.UUID `json:"codebase_id"`
+	Secret     string      `json:"secret"`
+}
+
+func (q *Queries) ListCodebaseCredentialSecrets(ctx context.Context, arg ListCodebaseCredentialSecretsParams) ([]ListCodebaseCredentialSecretsRow, error) {
+	rows, err := q.db.Query(ctx, listCodebaseCredentialSecrets, arg.AfterID, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListCodebaseCredentialSecretsRow{}
+	for rows.Next() {
+		var i ListCodebaseCredentialSecretsRow
+		if err := rows.Scan(&i.CodebaseID, &i.Secret); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOAuthAccessTokens = `-- name: ListOAuthAccessTokens :many
+SELECT id, access_token FROM oauth_accounts
+WHERE id > $1 AND access_token IS NOT NULL AND access_token <> ''
+ORDER BY id
+LIMIT $2
+`
+
+type ListOAuthAccessTokensParams struct {
+	AfterID   pgtype.UUID `json:"after_id"`
+	BatchSize int32       `json:"batch_size"`
+}
+
+type ListOAuthAccessTokensRow struct {
+	ID          pgtype.UUID `json:"id"`
+	AccessToken pgtype.Text `json:"access_token"`
+}
+
+func (q *Queries) ListOAuthAccessTokens(ctx context.Context, arg ListOAuthAccessTokensParams) ([]ListOAuthAccessTokensRow, error) {
+	rows, err := q.db.Query(ctx, listOAuthAccessTokens, arg.AfterID, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOAuthAccessTokensRow{}
+	for rows.Next() {
+		var i ListOAuthAccessTokensRow
+		if err := rows.Scan(&i.ID, &i.AccessToken); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecentCodebaseViewers = `-- name: ListRecentCodebaseViewers :many
+SELECT uah.user_id
+FROM user_analysis_history uah
+JOIN analyses a ON a.id = uah.analysis_id
+JOIN oauth_accounts oa ON oa.user_id = uah.user_id
+WHERE a.codebase_id = $1
+  AND oa.provider = 'github'
+  AND oa.access_token IS NOT NULL
+  AND (oa.token_invalidated_at IS NULL OR oa.token_invalidated_at < oa.updated_at)
+GROUP BY uah.user_id
+ORDER BY MAX(uah.updated_at) DESC
+LIMIT $2
+`
+
+type ListRecentCodebaseViewersParams struct {
+	CodebaseID pgtype.UUID `json:"codebase_id"`
+	Limit      int32       `json:"limit"`
+}
+
+func (q *Queries) ListRecentCodebaseViewers(ctx context.Context, arg ListRecentCodebaseViewersParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listRecentCodebaseViewers, arg.CodebaseID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []pgtype.UUID{}
+	for rows.Next() {
+		var user_id pgtype.UUID
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const markCodebaseStale = `-- name: MarkCodebaseStale :exec
-UPDATE codebases SET is_stale = true, updated_at = now() WHERE id = $1
+UPDATE codebases
+SET is_stale = true, stale_reason = $2, stale_at = now(), updated_at = now()
+WHERE id = $1
+`
+
+type MarkCodebaseStaleParams struct {
+	ID          pgtype.UUID `json:"id"`
+	StaleReason pgtype.Text `json:"stale_reason"`
+}
+
+func (q *Queries) MarkCodebaseStale(ctx context.Context, arg MarkCodebaseStaleParams) error {
+	_, err := q.db.Exec(ctx, markCodebaseStale, arg.ID, arg.StaleReason)
+	return err
+}
+
+const markOAuthAccountTokenInvalid = `-- name: MarkOAuthAccountTokenInvalid :exec
+UPDATE oauth_accounts
+SET token_invalidated_at = now()
+WHERE id = $1 AND access_token = $2
 `
 
-func (q *Queries) MarkCodebaseStale(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, markCodebaseStale, id)
+type MarkOAuthAccountTokenInvalidParams struct {
+	ID          pgtype.UUID `json:"id"`
+	AccessToken pgtype.Text `json:"access_token"`
+}
+
+func (q *Queries) MarkOAuthAccountTokenInvalid(ctx context.Context, arg MarkOAuthAccountTokenInvalidParams) error {
+	_, err := q.db.Exec(ctx, markOAuthAccountTokenInvalid, arg.ID, arg.AccessToken)
 	return err
 }
 
@@ -458,11 +1337,51 @@ func (q *Queries) RecordUserAnalysisHistory(ctx context.Context, arg RecordUserA
 	return err
 }
 
+const replaceCodebaseCredentialSecret = `-- name: ReplaceCodebaseCredentialSecret :execrows
+UPDATE codebase_credentials
+SET secret = $1
+WHERE codebase_id = $2 AND secret = $3
+`
+
+type ReplaceCodebaseCredentialSecretParams struct {
+	NewSecret  string      `json:"new_secret"`
+	CodebaseID pgtype.UUID `json:"codebase_id"`
+	OldSecret  string      `json:"old_secret"`
+}
+
+func (q *Queries) ReplaceCodebaseCredentialSecret(ctx context.Context, arg ReplaceCodebaseCredentialSecretParams) (int64, error) {
+	result, err := q.db.Exec(ctx, replaceCodebaseCredentialSecret, arg.NewSecret, arg.CodebaseID, arg.OldSecret)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const replaceOAuthAccessToken = `-- name: ReplaceOAuthAccessToken :execrows
+UPDATE oauth_accounts
+SET access_token = $1
+WHERE id = $2 AND access_token = $3
+`
+
+type ReplaceOAuthAccessTokenParams struct {
+	NewAccessToken pgtype.Text `json:"new_access_token"`
+	ID             pgtype.UUID `json:"id"`
+	OldAccessToken pgtype.Text `json:"old_access_token"`
+}
+
+func (q *Queries) ReplaceOAuthAccessToken(ctx context.Context, arg ReplaceOAuthAccessTokenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, replaceOAuthAccessToken, arg.NewAccessToken, arg.ID, arg.OldAccessToken)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const unmarkCodebaseStale = `-- name: UnmarkCodebaseStale :one
 UPDATE codebases
-SET is_stale = false, owner = $2, name = $3, updated_at = now()
+SET is_stale = false, stale_reason = NULL, stale_at = NULL, owner = $2, name = $3, updated_at = now()
 WHERE id = $1
-RETURNING id, host, owner, name, default_branch, created_at, updated_at, last_viewed_at, external_repo_id, is_stale, is_private
+RETURNING id, host, owner, name, default_branch, created_at, updated_at, last_viewed_at, external_repo_id, is_stale, is_private, is_archived, is_disabled, is_fork, parent_full_name, primary_language, topics, license_spdx_id, size_kb, stargazers_count, pushed_at, metadata_refreshed_at, empty_checked_at, parent_external_repo_id, stale_reason, stale_at
 `
 
 type UnmarkCodebaseStaleParams struct {
@@ -486,13 +1405,28 @@ func (q *Queries) UnmarkCodebaseStale(ctx context.Context, arg UnmarkCodebaseSta
 		&i.ExternalRepoID,
 		&i.IsStale,
 		&i.IsPrivate,
+		&i.IsArchived,
+		&i.IsDisabled,
+		&i.IsFork,
+		&i.ParentFullName,
+		&i.PrimaryLanguage,
+		&i.Topics,
+		&i.LicenseSpdxID,
+		&i.SizeKb,
+		&i.StargazersCount,
+		&i.PushedAt,
+		&i.MetadataRefreshedAt,
+		&i.EmptyCheckedAt,
+		&i.ParentExternalRepoID,
+		&i.StaleReason,
+		&i.StaleAt,
 	)
 	return i, err
 }
 
 const updateAnalysisCompleted = `-- name: UpdateAnalysisCompleted :exec
 UPDATE analyses
-SET status = 'completed', total_suites = $2, total_tests = $3, completed_at = $4, committed_at = $5
+SET status = 'completed', total_suites = $2, total_tests = $3, completed_at = $4, committed_at = $5, config_hash = $6
 WHERE id = $1
 `
 
@@ -502,6 +1436,7 @@ type UpdateAnalysisCompletedParams struct {
 	TotalTests  int32              `json:"total_tests"`
 	CompletedAt pgtype.Timestamptz `json:"completed_at"`
 	CommittedAt pgtype.Timestamptz `json:"committed_at"`
+	ConfigHash  pgtype.Text        `json:"config_hash"`
 }
 
 func (q *Queries) UpdateAnalysisCompleted(ctx context.Context, arg UpdateAnalysisCompletedParams) error {
@@ -511,6 +1446,7 @@ func (q *Queries) UpdateAnalysisCompleted(ctx context.Context, arg UpdateAnalysi
 		arg.TotalTests,
 		arg.CompletedAt,
 		arg.CommittedAt,
+		arg.ConfigHash,
 	)
 	return err
 }
@@ -532,11 +1468,110 @@ func (q *Queries) UpdateAnalysisFailed(ctx context.Context, arg UpdateAnalysisFa
 	return err
 }
 
+const updateAnalysisSourceCensus = `-- name: UpdateAnalysisSourceCensus :exec
+UPDATE analyses
+SET total_source_files = $2,
+    total_source_lines = $3,
+    test_file_ratio = $4,
+    tests_per_kloc = $5
+WHERE id = $1
+`
+
+type UpdateAnalysisSourceCensusParams struct {
+	ID               pgtype.UUID   `json:"id"`
+	TotalSourceFiles pgtype.Int4   `json:"total_source_files"`
+	TotalSourceLines pgtype.Int4   `json:"total_source_lines"`
+	TestFileRatio    pgtype.Float8 `json:"test_file_ratio"`
+	TestsPerKloc     pgtype.Float8 `json:"tests_per_kloc"`
+}
+
+func (q *Queries) UpdateAnalysisSourceCensus(ctx context.Context, arg UpdateAnalysisSourceCensusParams) error {
+	_, err := q.db.Exec(ctx, updateAnalysisSourceCensus,
+		arg.ID,
+		arg.TotalSourceFiles,
+		arg.TotalSourceLines,
+		arg.TestFileRatio,
+		arg.TestsPerKloc,
+	)
+	return err
+}
+
+const updateCodebaseEmptyState = `-- name: UpdateCodebaseEmptyState :exec
+UPDATE codebases
+SET empty_checked_at = CASE WHEN $1::boolean THEN now() END,
+    updated_at = now()
+WHERE id = $2
+`
+
+type UpdateCodebaseEmptyStateParams struct {
+	IsEmpty bool        `json:"is_empty"`
+	ID      pgtype.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateCodebaseEmptyState(ctx context.Context, arg UpdateCodebaseEmptyStateParams) error {
+	_, err := q.db.Exec(ctx, updateCodebaseEmptyState, arg.IsEmpty, arg.ID)
+	return err
+}
+
+const updateCodebaseMetadata = `-- name: UpdateCodebaseMetadata :exec
+UPDATE codebases
+SET default_branch = COALESCE($1, default_branch),
+    is_archived = $2,
+    is_disabled = $3,
+    is_fork = $4,
+    parent_full_name = $5,
+    parent_external_repo_id = $6,
+    primary_language = $7,
+    topics = $8,
+    license_spdx_id = $9,
+    size_kb = $10,
+    stargazers_count = $11,
+    pushed_at = $12,
+    metadata_refreshed_at = now(),
+    updated_at = now()
+WHERE id = $13
+`
+
+type UpdateCodebaseMetadataParams struct {
+	DefaultBranch        pgtype.Text        `json:"default_branch"`
+	IsArchived           bool               `json:"is_archived"`
+	IsDisabled           bool               `json:"is_disabled"`
+	IsFork               bool               `json:"is_fork"`
+	ParentFullName       pgtype.Text        `json:"parent_full_name"`
+	ParentExternalRepoID pgtype.Text        `json:"parent_external_repo_id"`
+	PrimaryLanguage      pgtype.Text        `json:"primary_language"`
+	Topics               []string           `json:"topics"`
+	LicenseSpdxID        pgtype.Text        `json:"license_spdx_id"`
+	SizeKb               int64              `json:"size_kb"`
+	StargazersCount      int32              `json:"stargazers_count"`
+	PushedAt             pgtype.Timestamptz `json:"pushed_at"`
+	ID                   pgtype.UUID        `json:"id"`
+}
+
+func (q *Queries) UpdateCodebaseMetadata(ctx context.Context, arg UpdateCodebaseMetadataParams) error {
+	_, err := q.db.Exec(ctx, updateCodebaseMetadata,
+		arg.DefaultBranch,
+		arg.IsArchived,
+		arg.IsDisabled,
+		arg.IsFork,
+		arg.ParentFullName,
+		arg.ParentExternalRepoID,
+		arg.PrimaryLanguage,
+		arg.Topics,
+		arg.LicenseSpdxID,
+		arg.SizeKb,
+		arg.StargazersCount,
+		arg.PushedAt,
+		arg.ID,
+	)
+	return err
+}
+
 const updateCodebaseOwnerName = `-- name: UpdateCodebaseOwnerName :one
 UPDATE codebases
 SET owner = $2, name = $3, updated_at = now()
 WHERE id = $1
-RETURNING id, host, owner, name, default_branch, created_at, updated_at, last_viewed_at, external_repo_id, is_stale, is_private
+RETURNING id, host, owner, name, default_branch, created_at, updated_at, last_viewed_at, external_repo_id, is_stale, is_private, is_archived, is_disabled, is_fork, parent_full_name, primary_language, topics, license_spdx_id, size_kb, stargazers_count, pushed_at, metadata_refreshed_at, empty_checked_at, parent_external_repo_id, stale_reason, stale_at
 `
 
 type UpdateCodebaseOwnerNameParams struct {
@@ -560,6 +1595,21 @@ func (q *Queries) UpdateCodebaseOwnerName(ctx context.Context, arg UpdateCodebas
 		&i.ExternalRepoID,
 		&i.IsStale,
 		&i.IsPrivate,
+		&i.IsArchived,
+		&i.IsDisabled,
+		&i.IsFork,
+		&i.ParentFullName,
+		&i.PrimaryLanguage,
+		&i.Topics,
+		&i.LicenseSpdxID,
+		&i.SizeKb,
+		&i.StargazersCount,
+		&i.PushedAt,
+		&i.MetadataRefreshedAt,
+		&i.EmptyCheckedAt,
+		&i.ParentExternalRepoID,
+		&i.StaleReason,
+		&i.StaleAt,
 	)
 	return i, err
 }
@@ -591,7 +1641,7 @@ DO UPDATE SET
     is_stale = false,
     is_private = EXCLUDED.is_private,
     updated_at = now()
-RETURNING id, host, owner, name, default_branch, created_at, updated_at, last_viewed_at, external_repo_id, is_stale, is_private
+RETURNING id, host, owner, name, default_branch, created_at, updated_at, last_viewed_at, external_repo_id, is_stale, is_private, is_archived, is_disabled, is_fork, parent_full_name, primary_language, topics, license_spdx_id, size_kb, stargazers_count, pushed_at, metadata_refreshed_at, empty_checked_at, parent_external_repo_id, stale_reason, stale_at
 `
 
 type UpsertCodebaseParams struct {
@@ -625,6 +1675,62 @@ func (q *Queries) UpsertCodebase(ctx context.Context, arg UpsertCodebaseParams)
 		&i.ExternalRepoID,
 		&i.IsStale,
 		&i.IsPrivate,
+		&i.IsArchived,
+		&i.IsDisabled,
+		&i.IsFork,
+		&i.ParentFullName,
+		&i.PrimaryLanguage,
+		&i.Topics,
+		&i.LicenseSpdxID,
+		&i.SizeKb,
+		&i.StargazersCount,
+		&i.PushedAt,
+		&i.MetadataRefreshedAt,
+		&i.EmptyCheckedAt,
+		&i.ParentExternalRepoID,
+		&i.StaleReason,
+		&i.StaleAt,
 	)
 	return i, err
 }
+
+const upsertCodebaseCredential = `-- name: UpsertCodebaseCredential :exec
+INSERT INTO codebase_credentials (codebase_id, kind, secret)
+VALUES ($1, $2, $3)
+ON CONFLICT (codebase_id) DO UPDATE SET kind = EXCLUDED.kind, secret = EXCLUDED.secret, updated_at = now()
+`
+
+type UpsertCodebaseCredentialParams struct {
+	CodebaseID pgtype.UUID `json:"codebase_id"`
+	Kind       string      `json:"kind"`
+	Secret     string      `json:"secret"`
+}
+
+func (q *Queries) UpsertCodebaseCredential(ctx context.Context, arg UpsertCodebaseCredentialParams) error {
+	_, err := q.db.Exec(ctx, upsertCodebaseCredential, arg.CodebaseID, arg.Kind, arg.Secret)
+	return err
+}
+
+const upsertCodebaseTag = `-- name: UpsertCodebaseTag :exec
+INSERT INTO codebase_tags (codebase_id, name, commit_sha, analysis_id)
+VALUES ($1, $2, $3, (
+    SELECT a.id FROM analyses a
+    WHERE a.codebase_id = $1 AND a.commit_sha = $3 AND a.status = 'completed'
+))
+ON CONFLICT ON CONSTRAINT uq_codebase_tags_codebase_name
+DO UPDATE SET
+    commit_sha = EXCLUDED.commit_sha,
+    analysis_id = EXCLUDED.analysis_id,
+    updated_at = now()
+`
+
+type UpsertCodebaseTagParams struct {
+	CodebaseID pgtype.UUID `json:"codebase_id"`
+	Name       string      `json:"name"`
+	CommitSha  string      `json:"commit_sha"`
+}
+
+func (q *Queries) UpsertCodebaseTag(ctx context.Context, arg UpsertCodebaseTagParams) error {
+	_, err := q.db.Exec(ctx, upsertCodebaseTag, arg.CodebaseID, arg.Name, arg.CommitSha)
+	return err
+}
@@ -1,18 +1,19 @@
-package main
+// Package githuburl parses the GitHub repository URLs accepted by the command-line tools.
+package githuburl
 
 import (
 	"fmt"
 	"strings"
 )
 
-// ParseGitHubURL parses various GitHub URL formats and extracts owner and repo.
+// Parse parses various GitHub URL formats and extracts owner and repo.
 // Supported formats:
 //   - github.com/owner/repo
 //   - https://github.com/owner/repo
 //   - http://github.com/owner/repo
 //   - github.com/owner/repo.git
 //   - https://github.com/owner/repo.git
-func ParseGitHubURL(url string) (owner, repo string, err error) {
+func Parse(url string) (owner, repo string, err error) {
 	if url == "" {
 		return "", "", fmt.Errorf("URL cannot be empty")
 	}
@@ -1,8 +1,8 @@
-package main
+package githuburl
 
 import "testing"
 
-func TestParseGitHubURL(t *testing.T) {
+func TestParse(t *testing.T) {
 	tests := []struct {
 		name      string
 		url       string
@@ -81,17 +81,17 @@ func TestParseGitHubURL(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			owner, repo, err := ParseGitHubURL(tt.url)
+			owner, repo, err := Parse(tt.url)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("ParseGitHubURL() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !tt.wantErr {
 				if owner != tt.wantOwner {
-					t.Errorf("ParseGitHubURL() owner = %v, want %v", owner, tt.wantOwner)
+					t.Errorf("Parse() owner = %v, want %v", owner, tt.wantOwner)
 				}
 				if repo != tt.wantRepo {
-					t.Errorf("ParseGitHubURL() repo = %v, want %v", repo, tt.wantRepo)
+					t.Errorf("Parse() repo = %v, want %v", repo, tt.wantRepo)
 				}
 			}
 		})
@@ -0,0 +1,128 @@
+// Package keyring encrypts secrets with a primary key while still decrypting secrets written
+// with older keys, so encryption keys can be rotated without downtime or re-login.
+//
+// Ciphertexts carry the ID of their key as a prefix, "<key ID>:<ciphertext>". Ciphertexts
+// without a prefix predate key IDs and are decrypted with the legacy key.
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/specvital/core/pkg/crypto"
+)
+
+const keyIDSeparator = ":"
+
+// ErrUnknownKey indicates a ciphertext names a key the keyring does not hold.
+var ErrUnknownKey = errors.New("keyring: unknown key ID")
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+type Keyring struct {
+	keys map[string]crypto.Encryptor
+	// legacy decrypts unprefixed ciphertexts. Nil if no legacy key is configured.
+	legacy crypto.Encryptor
+	// primary is the key ID of new ciphertexts. Empty when writes use the legacy key.
+	primary string
+}
+
+var _ crypto.Encryptor = (*Keyring)(nil)
+
+// New builds a keyring from a legacy base64 key (ENCRYPTION_KEY) and a comma-separated list
+// of "<key ID>:<base64 key>" entries (ENCRYPTION_KEYS). The first entry is the primary key.
+// Without entries, the legacy key encrypts as before, without a prefix. At least one key is required.
+func New(legacyKey, keys string) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]crypto.Encryptor)}
+
+	if legacyKey != "" {
+		enc, err := crypto.NewEncryptorFromBase64(legacyKey)
+		if err != nil {
+			return nil, fmt.Errorf("legacy key: %w", err)
+		}
+		k.legacy = enc
+	}
+
+	for _, entry := range strings.Split(keys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, key, ok := strings.Cut(entry, keyIDSeparator)
+		if !ok || !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("key entry must be <key ID>:<base64 key> with an ID of letters, digits, '-' or '_'")
+		}
+		if _, exists := k.keys[id]; exists {
+			return nil, fmt.Errorf("duplicate key ID %q", id)
+		}
+		enc, err := crypto.NewEncryptorFromBase64(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		k.keys[id] = enc
+		if k.primary == "" {
+			k.primary = id
+		}
+	}
+
+	if k.legacy == nil && k.primary == "" {
+		return nil, errors.New("at least one encryption key is required")
+	}
+	return k, nil
+}
+
+// PrimaryKeyID returns the key ID of new ciphertexts, or "" if they use the legacy key.
+func (k *Keyring) PrimaryKeyID() string {
+	return k.primary
+}
+
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if k.primary == "" {
+		return k.legacy.Encrypt(plaintext)
+	}
+	ciphertext, err := k.keys[k.primary].Encrypt(plaintext)
+	if err != nil {
+		return "", err
+	}
+	return k.primary + keyIDSeparator + ciphertext, nil
+}
+
+// Decrypt returns ErrUnknownKey if the ciphertext names a key the keyring does not hold,
+// or is unprefixed while no legacy key is configured.
+func (k *Keyring) Decrypt(ciphertext string) (string, error) {
+	id, body, prefixed := strings.Cut(ciphertext, keyIDSeparator)
+	if !prefixed {
+		if k.legacy == nil {
+			return "", fmt.Errorf("%w: ciphertext has no key ID and no legacy key is configured", ErrUnknownKey)
+		}
+		return k.legacy.Decrypt(ciphertext)
+	}
+
+	enc, ok := k.keys[id]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownKey, id)
+	}
+	return enc.Decrypt(body)
+}
+
+// IsCurrent reports whether ciphertext was written with the primary key.
+func (k *Keyring) IsCurrent(ciphertext string) bool {
+	id, _, prefixed := strings.Cut(ciphertext, keyIDSeparator)
+	if !prefixed {
+		return k.primary == ""
+	}
+	return id == k.primary
+}
+
+func (k *Keyring) Close() error {
+	var errs []error
+	if k.legacy != nil {
+		errs = append(errs, k.legacy.Close())
+	}
+	for _, enc := range k.keys {
+		errs = append(errs, enc.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,127 @@
+package keyring
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/specvital/core/pkg/crypto"
+)
+
+const (
+	keyA = "YWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWE="
+	keyB = "YmJiYmJiYmJiYmJiYmJiYmJiYmJiYmJiYmJiYmJiYmI="
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		legacy  string
+		keys    string
+		primary string
+		wantErr bool
+	}{
+		{name: "legacy only", legacy: keyA},
+		{name: "first entry is primary", legacy: keyA, keys: "k2:" + keyB + ", k1:" + keyA, primary: "k2"},
+		{name: "keys without legacy", keys: "k1:" + keyA, primary: "k1"},
+		{name: "no keys", wantErr: true},
+		{name: "missing key ID", keys: keyA, wantErr: true},
+		{name: "invalid key ID", keys: "k/1:" + keyA, wantErr: true},
+		{name: "duplicate key ID", keys: "k1:" + keyA + ",k1:" + keyB, wantErr: true},
+		{name: "invalid key", keys: "k1:c2hvcnQ=", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ring, err := New(tt.legacy, tt.keys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+			if err == nil && ring.PrimaryKeyID() != tt.primary {
+				t.Errorf("expected primary %q, got %q", tt.primary, ring.PrimaryKeyID())
+			}
+		})
+	}
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	legacyRing, err := New(keyA, "")
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	legacyCiphertext, err := legacyRing.Encrypt("gho_legacy")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if strings.Contains(legacyCiphertext, ":") {
+		t.Errorf("expected legacy ciphertext without key ID, got %q", legacyCiphertext)
+	}
+
+	oldRing, err := New(keyA, "k1:"+keyA)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	oldCiphertext, err := oldRing.Encrypt("gho_old")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !strings.HasPrefix(oldCiphertext, "k1:") {
+		t.Errorf("expected k1 prefix, got %q", oldCiphertext)
+	}
+
+	ring, err := New(keyA, "k2:"+keyB+",k1:"+keyA)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	newCiphertext, err := ring.Encrypt("gho_new")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	for ciphertext, want := range map[string]string{
+		legacyCiphertext: "gho_legacy",
+		oldCiphertext:    "gho_old",
+		newCiphertext:    "gho_new",
+	} {
+		got, err := ring.Decrypt(ciphertext)
+		if err != nil {
+			t.Fatalf("Decrypt(%q) failed: %v", ciphertext, err)
+		}
+		if got != want {
+			t.Errorf("Decrypt(%q) = %q, want %q", ciphertext, got, want)
+		}
+	}
+
+	if ring.IsCurrent(legacyCiphertext) || ring.IsCurrent(oldCiphertext) || !ring.IsCurrent(newCiphertext) {
+		t.Error("expected only the primary key's ciphertext to be current")
+	}
+	if !legacyRing.IsCurrent(legacyCiphertext) {
+		t.Error("expected unprefixed ciphertext to be current without key IDs")
+	}
+}
+
+func TestKeyring_Decrypt_Errors(t *testing.T) {
+	ring, err := New("", "k1:"+keyA)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := ring.Decrypt("k9:AAAA"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected ErrUnknownKey for unknown key ID, got %v", err)
+	}
+	if _, err := ring.Decrypt("AAAA"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected ErrUnknownKey for unprefixed ciphertext without legacy key, got %v", err)
+	}
+
+	other, err := New("", "k1:"+keyB)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ciphertext, err := other.Encrypt("gho_token")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if _, err := ring.Decrypt(ciphertext); !errors.Is(err, crypto.ErrDecryptionFailed) {
+		t.Errorf("expected ErrDecryptionFailed for a reused key ID, got %v", err)
+	}
+}
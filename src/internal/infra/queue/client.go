@@ -2,17 +2,26 @@ package queue
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/riverqueue/river"
 	"github.com/riverqueue/river/riverdriver/riverpgxv5"
 	adapterqueue "github.com/specvital/collector/internal/adapter/queue"
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/infra/db"
 )
 
+// historicalPriority is River's lowest priority (1 is highest, 4 is lowest).
+const historicalPriority = 4
+
 // Client is insert-only (no worker).
 type Client struct {
 	client *river.Client[pgx.Tx]
+	pool   *pgxpool.Pool
 }
 
 func NewClient(ctx context.Context, pool *pgxpool.Pool) (*Client, error) {
@@ -23,6 +32,7 @@ func NewClient(ctx context.Context, pool *pgxpool.Pool) (*Client, error) {
 
 	return &Client{
 		client: client,
+		pool:   pool,
 	}, nil
 }
 
@@ -57,3 +67,68 @@ func (c *Client) EnqueueAnalysisWithUser(ctx context.Context, owner, repo, commi
 	})
 	return err
 }
+
+// EnqueueHistoricalAnalysis analyzes a past commit exactly as it was.
+// Historical jobs run at the lowest priority so they never delay fresh analyses.
+func (c *Client) EnqueueHistoricalAnalysis(ctx context.Context, owner, repo, commitSHA string, userID *string) error {
+	_, err := c.client.Insert(ctx, adapterqueue.AnalyzeArgs{
+		Owner:       owner,
+		Repo:        repo,
+		CommitSHA:   commitSHA,
+		ExactCommit: true,
+		UserID:      userID,
+	}, &river.InsertOpts{
+		Priority: historicalPriority,
+		UniqueOpts: river.UniqueOpts{
+			ByArgs: true,
+		},
+	})
+	return err
+}
+
+func (c *Client) EnqueueBackfill(ctx context.Context, args adapterqueue.BackfillArgs) error {
+	_, err := c.client.Insert(ctx, args, nil)
+	return err
+}
+
+// EnqueueIngestResults stores the report as an upload and enqueues the job that reads it.
+func (c *Client) EnqueueIngestResults(ctx context.Context, report []byte, args adapterqueue.IngestResultsArgs) error {
+	return c.insertWithUpload(ctx, report, func(uploadID string) river.JobArgs {
+		args.UploadID = uploadID
+		return args
+	})
+}
+
+// EnqueueIngestCoverage stores the report as an upload and enqueues the job that reads it.
+func (c *Client) EnqueueIngestCoverage(ctx context.Context, report []byte, args adapterqueue.IngestCoverageArgs) error {
+	return c.insertWithUpload(ctx, report, func(uploadID string) river.JobArgs {
+		args.UploadID = uploadID
+		return args
+	})
+}
+
+// insertWithUpload stores report and inserts the job built for its upload ID in one transaction,
+// so a job never refers to a missing upload and a failed insert leaves no upload behind.
+func (c *Client) insertWithUpload(ctx context.Context, report []byte, job func(uploadID string) river.JobArgs) error {
+	tx, err := c.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rollback transaction",
+				"operation", "insertWithUpload",
+				"error", rbErr,
+			)
+		}
+	}()
+
+	id, err := db.New(tx).InsertReportUpload(ctx, report)
+	if err != nil {
+		return fmt.Errorf("store report upload: %w", err)
+	}
+	if _, err := c.client.InsertTx(ctx, tx, job(analysis.UUID(id.Bytes).String()), nil); err != nil {
+		return fmt.Errorf("insert job: %w", err)
+	}
+	return tx.Commit(ctx)
+}
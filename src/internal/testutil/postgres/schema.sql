@@ -113,7 +113,194 @@ CREATE TABLE public.analyses (
     created_at timestamp with time zone DEFAULT now() NOT NULL,
     total_suites integer DEFAULT 0 NOT NULL,
     total_tests integer DEFAULT 0 NOT NULL,
-    committed_at timestamp with time zone
+    committed_at timestamp with time zone,
+    config_hash character varying(64),
+    total_source_files integer,
+    total_source_lines integer,
+    test_file_ratio double precision,
+    tests_per_kloc double precision,
+    is_historical boolean DEFAULT false NOT NULL
+);
+
+
+--
+-- Name: analysis_anomalies; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.analysis_anomalies (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    analysis_id uuid NOT NULL,
+    previous_analysis_id uuid,
+    kind character varying(30) NOT NULL,
+    framework character varying(50),
+    previous_value integer NOT NULL,
+    current_value integer NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT chk_analysis_anomalies_kind CHECK (((kind)::text = ANY ((ARRAY['framework_vanished'::character varying, 'skipped_jump'::character varying, 'test_drop'::character varying])::text[])))
+);
+
+
+--
+-- Name: analysis_ci_commands; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.analysis_ci_commands (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    analysis_id uuid NOT NULL,
+    config_path character varying(500) NOT NULL,
+    job_name character varying(255) NOT NULL,
+    command text NOT NULL,
+    frameworks text[] DEFAULT '{}'::text[] NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: analysis_ci_frameworks; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.analysis_ci_frameworks (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    analysis_id uuid NOT NULL,
+    framework character varying(50) NOT NULL,
+    executed boolean NOT NULL,
+    test_files integer DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: analysis_file_coverage; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.analysis_file_coverage (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    analysis_id uuid NOT NULL,
+    report_name character varying(255) NOT NULL,
+    file_path character varying(1000) NOT NULL,
+    total_lines integer NOT NULL,
+    covered_lines integer NOT NULL,
+    uncovered_lines integer[] DEFAULT '{}'::integer[] NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: analysis_file_owners; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.analysis_file_owners (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    analysis_id uuid NOT NULL,
+    file_path character varying(1000) NOT NULL,
+    owner character varying(255) NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: analysis_language_stats; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.analysis_language_stats (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    analysis_id uuid NOT NULL,
+    language character varying(30) NOT NULL,
+    source_files integer DEFAULT 0 NOT NULL,
+    source_lines integer DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: analysis_owner_summaries; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.analysis_owner_summaries (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    analysis_id uuid NOT NULL,
+    owner character varying(255) NOT NULL,
+    total_files integer DEFAULT 0 NOT NULL,
+    total_suites integer DEFAULT 0 NOT NULL,
+    total_tests integer DEFAULT 0 NOT NULL,
+    skipped_tests integer DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: analysis_packages; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.analysis_packages (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    analysis_id uuid NOT NULL,
+    path character varying(500) NOT NULL,
+    name character varying(255) NOT NULL,
+    kind character varying(20) NOT NULL,
+    total_files integer DEFAULT 0 NOT NULL,
+    total_suites integer DEFAULT 0 NOT NULL,
+    total_tests integer DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: analysis_policy_results; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.analysis_policy_results (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    analysis_id uuid NOT NULL,
+    name character varying(100) NOT NULL,
+    kind character varying(50) NOT NULL,
+    source character varying(20) NOT NULL,
+    passed boolean NOT NULL,
+    detail text,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: analysis_test_file_churn; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.analysis_test_file_churn (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    analysis_id uuid NOT NULL,
+    file_path character varying(1000) NOT NULL,
+    commits integer DEFAULT 0 NOT NULL,
+    lines_added integer DEFAULT 0 NOT NULL,
+    lines_removed integer DEFAULT 0 NOT NULL,
+    authors integer DEFAULT 0 NOT NULL,
+    window_start timestamp with time zone NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: analysis_test_sources; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.analysis_test_sources (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    analysis_id uuid NOT NULL,
+    test_file_path character varying(1000) NOT NULL,
+    source_file_path character varying(1000) NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: analysis_untested_files; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.analysis_untested_files (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    analysis_id uuid NOT NULL,
+    file_path character varying(1000) NOT NULL,
+    language character varying(30) NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
 );
 
 
@@ -137,6 +324,52 @@ CREATE TABLE public.atlas_schema_revisions (
 );
 
 
+--
+-- Name: codebase_credentials; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.codebase_credentials (
+    codebase_id uuid NOT NULL,
+    kind character varying(16) NOT NULL,
+    secret text NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT chk_codebase_credentials_kind CHECK (((kind)::text = ANY ((ARRAY['ssh_key'::character varying, 'token'::character varying])::text[])))
+);
+
+
+--
+-- Name: codebase_policy_rules; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.codebase_policy_rules (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    codebase_id uuid NOT NULL,
+    name character varying(100) NOT NULL,
+    kind character varying(50) NOT NULL,
+    status public.test_status,
+    threshold double precision,
+    path_pattern character varying(500),
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: codebase_tags; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.codebase_tags (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    codebase_id uuid NOT NULL,
+    name character varying(255) NOT NULL,
+    commit_sha character varying(40) NOT NULL,
+    analysis_id uuid,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
 --
 -- Name: codebases; Type: TABLE; Schema: public; Owner: -
 --
@@ -152,7 +385,22 @@ CREATE TABLE public.codebases (
     last_viewed_at timestamp with time zone,
     external_repo_id character varying(64) NOT NULL,
     is_stale boolean DEFAULT false NOT NULL,
-    is_private boolean DEFAULT false NOT NULL
+    is_private boolean DEFAULT false NOT NULL,
+    is_archived boolean DEFAULT false NOT NULL,
+    is_disabled boolean DEFAULT false NOT NULL,
+    is_fork boolean DEFAULT false NOT NULL,
+    parent_full_name character varying(511),
+    primary_language character varying(100),
+    topics text[] DEFAULT '{}'::text[] NOT NULL,
+    license_spdx_id character varying(100),
+    size_kb bigint DEFAULT 0 NOT NULL,
+    stargazers_count integer DEFAULT 0 NOT NULL,
+    pushed_at timestamp with time zone,
+    metadata_refreshed_at timestamp with time zone,
+    empty_checked_at timestamp with time zone,
+    parent_external_repo_id character varying(64),
+    stale_reason character varying(32),
+    stale_at timestamp with time zone
 );
 
 
@@ -203,7 +451,8 @@ CREATE TABLE public.oauth_accounts (
     access_token text,
     scope character varying(500),
     created_at timestamp with time zone DEFAULT now() NOT NULL,
-    updated_at timestamp with time zone DEFAULT now() NOT NULL
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    token_invalidated_at timestamp with time zone
 );
 
 
@@ -223,6 +472,17 @@ CREATE TABLE public.refresh_tokens (
 );
 
 
+--
+-- Name: report_uploads; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.report_uploads (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    report bytea NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
 --
 -- Name: river_client; Type: TABLE; Schema: public; Owner: -
 --
@@ -344,7 +604,33 @@ CREATE TABLE public.test_cases (
     line_number integer,
     status public.test_status DEFAULT 'active'::public.test_status NOT NULL,
     tags jsonb DEFAULT '[]'::jsonb NOT NULL,
-    modifier character varying(50)
+    modifier character varying(50),
+    introduced_commit_sha character varying(40),
+    introduced_by_name character varying(255),
+    introduced_by_email character varying(255),
+    introduced_at timestamp with time zone,
+    last_modified_at timestamp with time zone,
+    end_line_number integer
+);
+
+
+--
+-- Name: test_executions; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.test_executions (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    analysis_id uuid NOT NULL,
+    test_case_id uuid,
+    report_name character varying(255) NOT NULL,
+    file_path character varying(1000),
+    suite_path text[] DEFAULT '{}'::text[] NOT NULL,
+    name character varying(2000) NOT NULL,
+    outcome character varying(20) NOT NULL,
+    duration_ms integer DEFAULT 0 NOT NULL,
+    message text,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT chk_test_executions_outcome CHECK (((outcome)::text = ANY ((ARRAY['passed'::character varying, 'failed'::character varying, 'skipped'::character varying])::text[])))
 );
 
 
@@ -361,6 +647,8 @@ CREATE TABLE public.test_suites (
     line_number integer,
     framework character varying(50),
     depth integer DEFAULT 0 NOT NULL,
+    package_path character varying(500),
+    end_line_number integer,
     CONSTRAINT chk_no_self_reference CHECK ((id <> parent_id))
 );
 
@@ -463,6 +751,102 @@ ALTER TABLE ONLY public.analyses
     ADD CONSTRAINT analyses_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: analysis_anomalies analysis_anomalies_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_anomalies
+    ADD CONSTRAINT analysis_anomalies_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: analysis_ci_commands analysis_ci_commands_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_ci_commands
+    ADD CONSTRAINT analysis_ci_commands_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: analysis_ci_frameworks analysis_ci_frameworks_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_ci_frameworks
+    ADD CONSTRAINT analysis_ci_frameworks_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: analysis_file_coverage analysis_file_coverage_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_file_coverage
+    ADD CONSTRAINT analysis_file_coverage_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: analysis_file_owners analysis_file_owners_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_file_owners
+    ADD CONSTRAINT analysis_file_owners_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: analysis_language_stats analysis_language_stats_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_language_stats
+    ADD CONSTRAINT analysis_language_stats_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: analysis_owner_summaries analysis_owner_summaries_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_owner_summaries
+    ADD CONSTRAINT analysis_owner_summaries_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: analysis_packages analysis_packages_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_packages
+    ADD CONSTRAINT analysis_packages_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: analysis_policy_results analysis_policy_results_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_policy_results
+    ADD CONSTRAINT analysis_policy_results_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: analysis_test_file_churn analysis_test_file_churn_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_test_file_churn
+    ADD CONSTRAINT analysis_test_file_churn_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: analysis_test_sources analysis_test_sources_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_test_sources
+    ADD CONSTRAINT analysis_test_sources_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: analysis_untested_files analysis_untested_files_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_untested_files
+    ADD CONSTRAINT analysis_untested_files_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: atlas_schema_revisions atlas_schema_revisions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -471,6 +855,30 @@ ALTER TABLE ONLY public.atlas_schema_revisions
     ADD CONSTRAINT atlas_schema_revisions_pkey PRIMARY KEY (version);
 
 
+--
+-- Name: codebase_credentials codebase_credentials_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.codebase_credentials
+    ADD CONSTRAINT codebase_credentials_pkey PRIMARY KEY (codebase_id);
+
+
+--
+-- Name: codebase_policy_rules codebase_policy_rules_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.codebase_policy_rules
+    ADD CONSTRAINT codebase_policy_rules_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: codebase_tags codebase_tags_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.codebase_tags
+    ADD CONSTRAINT codebase_tags_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: codebases codebases_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -511,6 +919,14 @@ ALTER TABLE ONLY public.refresh_tokens
     ADD CONSTRAINT refresh_tokens_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: report_uploads report_uploads_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.report_uploads
+    ADD CONSTRAINT report_uploads_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: river_client river_client_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -559,6 +975,14 @@ ALTER TABLE ONLY public.test_cases
     ADD CONSTRAINT test_cases_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: test_executions test_executions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.test_executions
+    ADD CONSTRAINT test_executions_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: test_suites test_suites_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -567,6 +991,102 @@ ALTER TABLE ONLY public.test_suites
     ADD CONSTRAINT test_suites_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: analysis_ci_frameworks uq_analysis_ci_frameworks_analysis_framework; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_ci_frameworks
+    ADD CONSTRAINT uq_analysis_ci_frameworks_analysis_framework UNIQUE (analysis_id, framework);
+
+
+--
+-- Name: analysis_file_coverage uq_analysis_file_coverage_report_path; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_file_coverage
+    ADD CONSTRAINT uq_analysis_file_coverage_report_path UNIQUE (analysis_id, report_name, file_path);
+
+
+--
+-- Name: analysis_file_owners uq_analysis_file_owners_analysis_file_owner; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_file_owners
+    ADD CONSTRAINT uq_analysis_file_owners_analysis_file_owner UNIQUE (analysis_id, file_path, owner);
+
+
+--
+-- Name: analysis_language_stats uq_analysis_language_stats_analysis_language; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_language_stats
+    ADD CONSTRAINT uq_analysis_language_stats_analysis_language UNIQUE (analysis_id, language);
+
+
+--
+-- Name: analysis_owner_summaries uq_analysis_owner_summaries_analysis_owner; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_owner_summaries
+    ADD CONSTRAINT uq_analysis_owner_summaries_analysis_owner UNIQUE (analysis_id, owner);
+
+
+--
+-- Name: analysis_packages uq_analysis_packages_analysis_path; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_packages
+    ADD CONSTRAINT uq_analysis_packages_analysis_path UNIQUE (analysis_id, path);
+
+
+--
+-- Name: analysis_policy_results uq_analysis_policy_results_analysis_name; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_policy_results
+    ADD CONSTRAINT uq_analysis_policy_results_analysis_name UNIQUE (analysis_id, name);
+
+
+--
+-- Name: analysis_test_file_churn uq_analysis_test_file_churn_analysis_file; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_test_file_churn
+    ADD CONSTRAINT uq_analysis_test_file_churn_analysis_file UNIQUE (analysis_id, file_path);
+
+
+--
+-- Name: analysis_test_sources uq_analysis_test_sources_analysis_test_source; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_test_sources
+    ADD CONSTRAINT uq_analysis_test_sources_analysis_test_source UNIQUE (analysis_id, test_file_path, source_file_path);
+
+
+--
+-- Name: analysis_untested_files uq_analysis_untested_files_analysis_path; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_untested_files
+    ADD CONSTRAINT uq_analysis_untested_files_analysis_path UNIQUE (analysis_id, file_path);
+
+
+--
+-- Name: codebase_policy_rules uq_codebase_policy_rules_codebase_name; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.codebase_policy_rules
+    ADD CONSTRAINT uq_codebase_policy_rules_codebase_name UNIQUE (codebase_id, name);
+
+
+--
+-- Name: codebase_tags uq_codebase_tags_codebase_name; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.codebase_tags
+    ADD CONSTRAINT uq_codebase_tags_codebase_name UNIQUE (codebase_id, name);
+
+
 --
 -- Name: github_app_installations uq_github_app_installations_account; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -693,6 +1213,41 @@ CREATE INDEX idx_analyses_codebase_status ON public.analyses USING btree (codeba
 CREATE INDEX idx_analyses_created ON public.analyses USING btree (codebase_id, created_at);
 
 
+--
+-- Name: idx_analysis_anomalies_analysis; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_analysis_anomalies_analysis ON public.analysis_anomalies USING btree (analysis_id);
+
+
+--
+-- Name: idx_analysis_ci_commands_analysis; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_analysis_ci_commands_analysis ON public.analysis_ci_commands USING btree (analysis_id);
+
+
+--
+-- Name: idx_analysis_file_owners_analysis_owner; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_analysis_file_owners_analysis_owner ON public.analysis_file_owners USING btree (analysis_id, owner);
+
+
+--
+-- Name: idx_analysis_test_sources_source; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_analysis_test_sources_source ON public.analysis_test_sources USING btree (analysis_id, source_file_path);
+
+
+--
+-- Name: idx_codebase_tags_commit; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_codebase_tags_commit ON public.codebase_tags USING btree (codebase_id, commit_sha);
+
+
 --
 -- Name: idx_codebases_external_repo_id; Type: INDEX; Schema: public; Owner: -
 --
@@ -721,6 +1276,13 @@ CREATE INDEX idx_codebases_last_viewed ON public.codebases USING btree (last_vie
 CREATE INDEX idx_codebases_owner_name ON public.codebases USING btree (owner, name);
 
 
+--
+-- Name: idx_codebases_parent; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_codebases_parent ON public.codebases USING btree (host, parent_external_repo_id) WHERE (parent_external_repo_id IS NOT NULL);
+
+
 --
 -- Name: idx_codebases_public; Type: INDEX; Schema: public; Owner: -
 --
@@ -791,6 +1353,20 @@ CREATE INDEX idx_test_cases_status ON public.test_cases USING btree (suite_id, s
 CREATE INDEX idx_test_cases_suite ON public.test_cases USING btree (suite_id);
 
 
+--
+-- Name: idx_test_executions_analysis_report; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_test_executions_analysis_report ON public.test_executions USING btree (analysis_id, report_name);
+
+
+--
+-- Name: idx_test_executions_test_case; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_test_executions_test_case ON public.test_executions USING btree (test_case_id) WHERE (test_case_id IS NOT NULL);
+
+
 --
 -- Name: idx_test_suites_analysis; Type: INDEX; Schema: public; Owner: -
 --
@@ -946,6 +1522,142 @@ ALTER TABLE ONLY public.analyses
     ADD CONSTRAINT fk_analyses_codebase FOREIGN KEY (codebase_id) REFERENCES public.codebases(id) ON DELETE CASCADE;
 
 
+--
+-- Name: analysis_anomalies fk_analysis_anomalies_analysis; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_anomalies
+    ADD CONSTRAINT fk_analysis_anomalies_analysis FOREIGN KEY (analysis_id) REFERENCES public.analyses(id) ON DELETE CASCADE;
+
+
+--
+-- Name: analysis_anomalies fk_analysis_anomalies_previous_analysis; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_anomalies
+    ADD CONSTRAINT fk_analysis_anomalies_previous_analysis FOREIGN KEY (previous_analysis_id) REFERENCES public.analyses(id) ON DELETE SET NULL;
+
+
+--
+-- Name: analysis_ci_commands fk_analysis_ci_commands_analysis; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_ci_commands
+    ADD CONSTRAINT fk_analysis_ci_commands_analysis FOREIGN KEY (analysis_id) REFERENCES public.analyses(id) ON DELETE CASCADE;
+
+
+--
+-- Name: analysis_ci_frameworks fk_analysis_ci_frameworks_analysis; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_ci_frameworks
+    ADD CONSTRAINT fk_analysis_ci_frameworks_analysis FOREIGN KEY (analysis_id) REFERENCES public.analyses(id) ON DELETE CASCADE;
+
+
+--
+-- Name: analysis_file_coverage fk_analysis_file_coverage_analysis; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_file_coverage
+    ADD CONSTRAINT fk_analysis_file_coverage_analysis FOREIGN KEY (analysis_id) REFERENCES public.analyses(id) ON DELETE CASCADE;
+
+
+--
+-- Name: analysis_file_owners fk_analysis_file_owners_analysis; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_file_owners
+    ADD CONSTRAINT fk_analysis_file_owners_analysis FOREIGN KEY (analysis_id) REFERENCES public.analyses(id) ON DELETE CASCADE;
+
+
+--
+-- Name: analysis_language_stats fk_analysis_language_stats_analysis; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_language_stats
+    ADD CONSTRAINT fk_analysis_language_stats_analysis FOREIGN KEY (analysis_id) REFERENCES public.analyses(id) ON DELETE CASCADE;
+
+
+--
+-- Name: analysis_owner_summaries fk_analysis_owner_summaries_analysis; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_owner_summaries
+    ADD CONSTRAINT fk_analysis_owner_summaries_analysis FOREIGN KEY (analysis_id) REFERENCES public.analyses(id) ON DELETE CASCADE;
+
+
+--
+-- Name: analysis_packages fk_analysis_packages_analysis; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_packages
+    ADD CONSTRAINT fk_analysis_packages_analysis FOREIGN KEY (analysis_id) REFERENCES public.analyses(id) ON DELETE CASCADE;
+
+
+--
+-- Name: analysis_policy_results fk_analysis_policy_results_analysis; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_policy_results
+    ADD CONSTRAINT fk_analysis_policy_results_analysis FOREIGN KEY (analysis_id) REFERENCES public.analyses(id) ON DELETE CASCADE;
+
+
+--
+-- Name: analysis_test_file_churn fk_analysis_test_file_churn_analysis; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_test_file_churn
+    ADD CONSTRAINT fk_analysis_test_file_churn_analysis FOREIGN KEY (analysis_id) REFERENCES public.analyses(id) ON DELETE CASCADE;
+
+
+--
+-- Name: analysis_test_sources fk_analysis_test_sources_analysis; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_test_sources
+    ADD CONSTRAINT fk_analysis_test_sources_analysis FOREIGN KEY (analysis_id) REFERENCES public.analyses(id) ON DELETE CASCADE;
+
+
+--
+-- Name: analysis_untested_files fk_analysis_untested_files_analysis; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.analysis_untested_files
+    ADD CONSTRAINT fk_analysis_untested_files_analysis FOREIGN KEY (analysis_id) REFERENCES public.analyses(id) ON DELETE CASCADE;
+
+
+--
+-- Name: codebase_credentials fk_codebase_credentials_codebase; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.codebase_credentials
+    ADD CONSTRAINT fk_codebase_credentials_codebase FOREIGN KEY (codebase_id) REFERENCES public.codebases(id) ON DELETE CASCADE;
+
+
+--
+-- Name: codebase_policy_rules fk_codebase_policy_rules_codebase; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.codebase_policy_rules
+    ADD CONSTRAINT fk_codebase_policy_rules_codebase FOREIGN KEY (codebase_id) REFERENCES public.codebases(id) ON DELETE CASCADE;
+
+
+--
+-- Name: codebase_tags fk_codebase_tags_analysis; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.codebase_tags
+    ADD CONSTRAINT fk_codebase_tags_analysis FOREIGN KEY (analysis_id) REFERENCES public.analyses(id) ON DELETE SET NULL;
+
+
+--
+-- Name: codebase_tags fk_codebase_tags_codebase; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.codebase_tags
+    ADD CONSTRAINT fk_codebase_tags_codebase FOREIGN KEY (codebase_id) REFERENCES public.codebases(id) ON DELETE CASCADE;
+
+
 --
 -- Name: github_app_installations fk_github_app_installations_installer; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -986,6 +1698,22 @@ ALTER TABLE ONLY public.test_cases
     ADD CONSTRAINT fk_test_cases_suite FOREIGN KEY (suite_id) REFERENCES public.test_suites(id) ON DELETE CASCADE;
 
 
+--
+-- Name: test_executions fk_test_executions_analysis; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.test_executions
+    ADD CONSTRAINT fk_test_executions_analysis FOREIGN KEY (analysis_id) REFERENCES public.analyses(id) ON DELETE CASCADE;
+
+
+--
+-- Name: test_executions fk_test_executions_test_case; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.test_executions
+    ADD CONSTRAINT fk_test_executions_test_case FOREIGN KEY (test_case_id) REFERENCES public.test_cases(id) ON DELETE SET NULL;
+
+
 --
 -- Name: test_suites fk_test_suites_analysis; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
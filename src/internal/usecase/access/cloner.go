@@ -0,0 +1,84 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"golang.org/x/sync/semaphore"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+// ErrDeployVCSRequired is returned when credentials carry a deploy credential but the
+// Cloner has no VCS that can use it.
+var ErrDeployVCSRequired = errors.New("deploy credential requires a deploy VCS")
+
+// CloneLimiter bounds concurrent clones. Use cases of one worker share a limiter so that
+// their clones together cannot exhaust its disk and network.
+type CloneLimiter struct {
+	sem *semaphore.Weighted
+}
+
+// NewCloneLimiter allows n concurrent clones. Values below one allow a single clone.
+func NewCloneLimiter(n int64) *CloneLimiter {
+	return &CloneLimiter{sem: semaphore.NewWeighted(max(n, 1))}
+}
+
+// Cloner clones repositories with resolved Credentials within a CloneLimiter.
+type Cloner struct {
+	deployVCS analysis.DeployCredentialVCS
+	limiter   *CloneLimiter
+	vcs       analysis.VCS
+}
+
+// NewCloner creates a new Cloner.
+// deployVCS is optional - if nil, credentials with a deploy credential fail with ErrDeployVCSRequired.
+func NewCloner(vcs analysis.VCS, deployVCS analysis.DeployCredentialVCS, limiter *CloneLimiter) *Cloner {
+	return &Cloner{
+		deployVCS: deployVCS,
+		limiter:   limiter,
+		vcs:       vcs,
+	}
+}
+
+// Clone waits for a free slot of the limiter, then clones with the deploy credential
+// if set, and with the token otherwise.
+func (c *Cloner) Clone(ctx context.Context, url string, credentials Credentials) (analysis.Source, error) {
+	if err := c.limiter.sem.Acquire(ctx, 1); err != nil {
+		return nil, err
+	}
+	defer c.limiter.sem.Release(1)
+
+	if credentials.Deploy != nil {
+		if c.deployVCS == nil {
+			return nil, ErrDeployVCSRequired
+		}
+		return c.deployVCS.CloneWithCredential(ctx, url, *credentials.Deploy)
+	}
+	return c.vcs.Clone(ctx, url, credentials.Token)
+}
+
+// GetHeadCommit reads the HEAD commit like Clone chooses credentials. It only talks to the
+// remote, so it does not take a slot of the limiter.
+func (c *Cloner) GetHeadCommit(ctx context.Context, url string, credentials Credentials) (analysis.CommitInfo, error) {
+	if credentials.Deploy != nil {
+		if c.deployVCS == nil {
+			return analysis.CommitInfo{}, ErrDeployVCSRequired
+		}
+		return c.deployVCS.GetHeadCommitWithCredential(ctx, url, *credentials.Deploy)
+	}
+	return c.vcs.GetHeadCommit(ctx, url, credentials.Token)
+}
+
+// CloseSource removes a clone, logging failures since cleanup never fails the operation.
+func CloseSource(src analysis.Source, owner, repo string) {
+	// Use background context for cleanup operations
+	if closeErr := src.Close(context.Background()); closeErr != nil {
+		slog.Error("failed to close source",
+			"error", closeErr,
+			"owner", owner,
+			"repo", repo,
+		)
+	}
+}
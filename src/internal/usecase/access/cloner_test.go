@@ -0,0 +1,99 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+type mockVCS struct {
+	cloneFn func(ctx context.Context, url string, token *string) (analysis.Source, error)
+}
+
+func (m *mockVCS) Clone(ctx context.Context, url string, token *string) (analysis.Source, error) {
+	return m.cloneFn(ctx, url, token)
+}
+
+func (m *mockVCS) GetHeadCommit(ctx context.Context, url string, token *string) (analysis.CommitInfo, error) {
+	return analysis.CommitInfo{SHA: "token-head"}, nil
+}
+
+type mockDeployVCS struct {
+	cloned []analysis.DeployCredential
+}
+
+func (m *mockDeployVCS) CloneWithCredential(ctx context.Context, url string, credential analysis.DeployCredential) (analysis.Source, error) {
+	m.cloned = append(m.cloned, credential)
+	return nil, nil
+}
+
+func (m *mockDeployVCS) GetHeadCommitWithCredential(ctx context.Context, url string, credential analysis.DeployCredential) (analysis.CommitInfo, error) {
+	return analysis.CommitInfo{SHA: "deploy-head"}, nil
+}
+
+func TestCloner_Clone(t *testing.T) {
+	sshKey := analysis.DeployCredential{Kind: analysis.DeployCredentialSSHKey, Secret: "key"}
+	token := "user-token"
+
+	t.Run("deploy credential is preferred", func(t *testing.T) {
+		deployVCS := &mockDeployVCS{}
+		vcs := &mockVCS{cloneFn: func(ctx context.Context, url string, token *string) (analysis.Source, error) {
+			t.Error("unexpected token clone")
+			return nil, nil
+		}}
+		cloner := NewCloner(vcs, deployVCS, NewCloneLimiter(1))
+
+		if _, err := cloner.Clone(context.Background(), "https://github.com/owner/repo", Credentials{Deploy: &sshKey, Token: &token}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(deployVCS.cloned) != 1 {
+			t.Errorf("expected one deploy clone, got %d", len(deployVCS.cloned))
+		}
+		info, err := cloner.GetHeadCommit(context.Background(), "https://github.com/owner/repo", Credentials{Deploy: &sshKey})
+		if err != nil || info.SHA != "deploy-head" {
+			t.Errorf("expected deploy head commit, got %+v, %v", info, err)
+		}
+	})
+
+	t.Run("deploy credential without deploy VCS", func(t *testing.T) {
+		cloner := NewCloner(&mockVCS{}, nil, NewCloneLimiter(1))
+
+		_, err := cloner.Clone(context.Background(), "https://github.com/owner/repo", Credentials{Deploy: &sshKey})
+		if !errors.Is(err, ErrDeployVCSRequired) {
+			t.Errorf("expected ErrDeployVCSRequired, got %v", err)
+		}
+	})
+
+	t.Run("clones sharing a limiter wait for each other", func(t *testing.T) {
+		limiter := NewCloneLimiter(1)
+		started := make(chan struct{})
+		release := make(chan struct{})
+		blocking := NewCloner(&mockVCS{cloneFn: func(ctx context.Context, url string, token *string) (analysis.Source, error) {
+			close(started)
+			<-release
+			return nil, nil
+		}}, nil, limiter)
+		other := NewCloner(&mockVCS{cloneFn: func(ctx context.Context, url string, token *string) (analysis.Source, error) {
+			return nil, nil
+		}}, nil, limiter)
+
+		go func() {
+			_, _ = blocking.Clone(context.Background(), "https://github.com/owner/a", Credentials{})
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if _, err := other.Clone(ctx, "https://github.com/owner/b", Credentials{}); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected clone to wait for the shared limit, got %v", err)
+		}
+
+		close(release)
+		if _, err := other.Clone(context.Background(), "https://github.com/owner/b", Credentials{}); err != nil {
+			t.Errorf("unexpected error after release: %v", err)
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+// CodebaseCredentials is how a tracked codebase is accessed when no user requested it.
+type CodebaseCredentials struct {
+	Credentials
+	// UserID is the viewer whose OAuth token Token is, so work enqueued for the codebase
+	// runs with the same token. Nil for deploy credentials and installation tokens.
+	UserID *string
+}
+
+// CodebaseResolver finds the credentials of codebases refreshed in the background: the
+// codebase's own deploy credential, then a GitHub App installation, then the OAuth token
+// of its most recent viewer.
+type CodebaseResolver struct {
+	deployLookup  analysis.DeployCredentialLookup
+	installTokens analysis.InstallationTokenProvider
+	tokenLookup   analysis.TokenLookup
+}
+
+// NewCodebaseResolver creates a new CodebaseResolver from the same options as NewResolver.
+// tokenLookup is optional - if nil, viewers' tokens are never used. WithTokenVerification
+// does not apply: viewer tokens are verified by the analyses that use them.
+func NewCodebaseResolver(tokenLookup analysis.TokenLookup, opts ...Option) *CodebaseResolver {
+	var cfg Config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &CodebaseResolver{
+		deployLookup:  cfg.DeployCredentials,
+		installTokens: cfg.InstallationTokens,
+		tokenLookup:   tokenLookup,
+	}
+}
+
+// Resolve returns ok=false when a private codebase has no usable credential.
+// Public codebases need none. The codebase's own deploy credential comes first. An SSH deploy
+// key only reaches git, so a token for API requests is still resolved next to it, best effort.
+func (r *CodebaseResolver) Resolve(ctx context.Context, codebase analysis.CodebaseRefreshInfo) (CodebaseCredentials, bool, error) {
+	if !codebase.IsPrivate {
+		return CodebaseCredentials{}, true, nil
+	}
+
+	deploy, err := r.lookupDeployCredential(ctx, codebase)
+	if err != nil {
+		return CodebaseCredentials{}, false, err
+	}
+	if deploy != nil && deploy.Kind == analysis.DeployCredentialToken {
+		return CodebaseCredentials{Credentials: Credentials{Deploy: deploy, Token: deploy.APIToken()}}, true, nil
+	}
+
+	credentials, ok, err := r.resolveToken(ctx, codebase)
+	if deploy == nil {
+		return credentials, ok, err
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "failed to resolve API token next to deploy key, ignoring",
+			"owner", codebase.Owner,
+			"repo", codebase.Name,
+			"error", err,
+		)
+		credentials = CodebaseCredentials{}
+	}
+	credentials.Deploy = deploy
+	return credentials, true, nil
+}
+
+// Candidates returns every credential a private codebase can be read with: its deploy
+// credential, a GitHub App installation token and the OAuth tokens of viewerIDs, in that
+// order. Viewers whose token is missing or marked invalid are left out.
+func (r *CodebaseResolver) Candidates(ctx context.Context, codebase analysis.CodebaseRefreshInfo, viewerIDs []string) ([]CodebaseCredentials, error) {
+	var candidates []CodebaseCredentials
+
+	deploy, err := r.lookupDeployCredential(ctx, codebase)
+	if err != nil {
+		return nil, err
+	}
+	if deploy != nil {
+		candidates = append(candidates, CodebaseCredentials{Credentials: Credentials{Deploy: deploy, Token: deploy.APIToken()}})
+	}
+
+	if r.installTokens != nil {
+		token, err := r.installTokens.GetInstallationToken(ctx, codebase.Owner, codebase.Name)
+		if err != nil && !errors.Is(err, analysis.ErrTokenNotFound) {
+			return nil, fmt.Errorf("get installation token: %w", err)
+		}
+		if err == nil && token != "" {
+			candidates = append(candidates, CodebaseCredentials{Credentials: Credentials{Token: &token}})
+		}
+	}
+
+	if r.tokenLookup == nil {
+		return candidates, nil
+	}
+	for _, userID := range viewerIDs {
+		token, err := r.tokenLookup.GetOAuthToken(ctx, userID, DefaultOAuthProvider)
+		if err != nil {
+			if errors.Is(err, analysis.ErrTokenNotFound) || errors.Is(err, analysis.ErrTokenInvalid) {
+				continue
+			}
+			return nil, fmt.Errorf("lookup OAuth token for user %s: %w", userID, err)
+		}
+		if token == "" {
+			continue
+		}
+		candidates = append(candidates, CodebaseCredentials{Credentials: Credentials{Token: &token}, UserID: &userID})
+	}
+	return candidates, nil
+}
+
+// lookupDeployCredential returns nil if the codebase has no deploy credential.
+// It errors only on infrastructure failures.
+func (r *CodebaseResolver) lookupDeployCredential(ctx context.Context, codebase analysis.CodebaseRefreshInfo) (*analysis.DeployCredential, error) {
+	if r.deployLookup == nil {
+		return nil, nil
+	}
+	credential, err := r.deployLookup.GetDeployCredential(ctx, codebase.Host, codebase.Owner, codebase.Name)
+	if err != nil {
+		if errors.Is(err, analysis.ErrDeployCredentialNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup deploy credential: %w", err)
+	}
+	return &credential, nil
+}
+
+// resolveToken prefers a GitHub App installation over the most recent viewer's OAuth token,
+// so background work does not depend on a single user's grant; the analysis itself then
+// falls back to the installation token as well.
+func (r *CodebaseResolver) resolveToken(ctx context.Context, codebase analysis.CodebaseRefreshInfo) (CodebaseCredentials, bool, error) {
+	if r.installTokens != nil {
+		token, err := r.installTokens.GetInstallationToken(ctx, codebase.Owner, codebase.Name)
+		if err != nil && !errors.Is(err, analysis.ErrTokenNotFound) {
+			return CodebaseCredentials{}, false, fmt.Errorf("get installation token: %w", err)
+		}
+		if err == nil && token != "" {
+			return CodebaseCredentials{Credentials: Credentials{Token: &token}}, true, nil
+		}
+	}
+
+	if r.tokenLookup == nil || codebase.LastViewerID == nil {
+		return CodebaseCredentials{}, false, nil
+	}
+	token, err := r.tokenLookup.GetOAuthToken(ctx, *codebase.LastViewerID, DefaultOAuthProvider)
+	if err != nil {
+		// A token marked invalid waits for the viewer to reauthorize, like a missing one,
+		// rather than counting as a failure.
+		if errors.Is(err, analysis.ErrTokenNotFound) || errors.Is(err, analysis.ErrTokenInvalid) {
+			return CodebaseCredentials{}, false, nil
+		}
+		return CodebaseCredentials{}, false, fmt.Errorf("lookup OAuth token for user %s: %w", *codebase.LastViewerID, err)
+	}
+	if token == "" {
+		return CodebaseCredentials{}, false, nil
+	}
+	return CodebaseCredentials{Credentials: Credentials{Token: &token}, UserID: codebase.LastViewerID}, true, nil
+}
@@ -0,0 +1,262 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+const (
+	// DefaultHost is the only VCS host credentials are resolved for.
+	DefaultHost = "github.com"
+	// DefaultOAuthProvider is the OAuth provider for VCS authentication.
+	DefaultOAuthProvider = "github"
+	// privateRepoScope is the OAuth scope GitHub requires for cloning private repositories.
+	privateRepoScope = "repo"
+)
+
+// Credentials is how a repository is accessed. Deploy, if set, is used for git. Token is used
+// for API requests and, without Deploy, for git. Both nil means public access.
+type Credentials struct {
+	Deploy *analysis.DeployCredential
+	Token  *string
+}
+
+// Resolver finds the credentials for a repository: the codebase's own deploy credential,
+// then the requesting user's OAuth token, then a GitHub App installation token.
+type Resolver struct {
+	deployLookup     analysis.DeployCredentialLookup
+	installTokens    analysis.InstallationTokenProvider
+	tokenInvalidator analysis.TokenInvalidator
+	tokenLookup      analysis.TokenLookup
+	tokenVerifier    analysis.TokenVerifier
+}
+
+// Config holds the optional credential sources of a Resolver.
+type Config struct {
+	DeployCredentials  analysis.DeployCredentialLookup
+	InstallationTokens analysis.InstallationTokenProvider
+	TokenInvalidator   analysis.TokenInvalidator
+	TokenVerifier      analysis.TokenVerifier
+}
+
+// Option is a functional option for configuring Resolver.
+type Option func(*Config)
+
+// WithDeployCredentials prefers codebases' own deploy credential over user and installation tokens.
+// An SSH key only reaches git, so a token for API requests is still resolved next to it.
+func WithDeployCredentials(l analysis.DeployCredentialLookup) Option {
+	return func(cfg *Config) {
+		cfg.DeployCredentials = l
+	}
+}
+
+// WithInstallationTokenProvider falls back to a GitHub App installation token
+// when there is no user or the user has no OAuth token.
+func WithInstallationTokenProvider(p analysis.InstallationTokenProvider) Option {
+	return func(cfg *Config) {
+		cfg.InstallationTokens = p
+	}
+}
+
+// WithTokenVerification checks users' OAuth tokens with the provider before they are used.
+// A rejected token is marked invalid so later lookups degrade to public access until the
+// user reauthorizes, instead of failing every clone.
+func WithTokenVerification(v analysis.TokenVerifier, i analysis.TokenInvalidator) Option {
+	return func(cfg *Config) {
+		cfg.TokenInvalidator = i
+		cfg.TokenVerifier = v
+	}
+}
+
+// NewResolver creates a new Resolver.
+// tokenLookup is optional - if nil, user tokens are never used.
+func NewResolver(tokenLookup analysis.TokenLookup, opts ...Option) *Resolver {
+	var cfg Config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &Resolver{
+		deployLookup:     cfg.DeployCredentials,
+		installTokens:    cfg.InstallationTokens,
+		tokenInvalidator: cfg.TokenInvalidator,
+		tokenLookup:      tokenLookup,
+		tokenVerifier:    cfg.TokenVerifier,
+	}
+}
+
+// Resolve returns empty Credentials when no source has one, which degrades to public access.
+// Errors are infrastructure failures that should fail the operation.
+func (r *Resolver) Resolve(ctx context.Context, owner, repo string, userID *string) (Credentials, error) {
+	deploy, err := r.lookupDeployCredential(ctx, owner, repo)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	credentials := Credentials{Deploy: deploy}
+	if deploy != nil {
+		credentials.Token = deploy.APIToken()
+	}
+	if credentials.Token == nil {
+		credentials.Token, err = r.lookupToken(ctx, owner, repo, userID)
+		if err != nil {
+			return Credentials{}, err
+		}
+	}
+	return credentials, nil
+}
+
+// lookupDeployCredential returns nil if the codebase named owner/repo has no deploy credential.
+// It errors only on infrastructure failures.
+func (r *Resolver) lookupDeployCredential(ctx context.Context, owner, repo string) (*analysis.DeployCredential, error) {
+	if r.deployLookup == nil {
+		return nil, nil
+	}
+
+	credential, err := r.deployLookup.GetDeployCredential(ctx, DefaultHost, owner, repo)
+	if err != nil {
+		if errors.Is(err, analysis.ErrDeployCredentialNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to lookup deploy credential for %s/%s: %w", owner, repo, err)
+	}
+
+	slog.InfoContext(ctx, "using deploy credential",
+		"owner", owner,
+		"repo", repo,
+		"kind", credential.Kind,
+	)
+	return &credential, nil
+}
+
+// lookupToken retrieves the requesting user's OAuth token, falling back to a
+// GitHub App installation token for the repository.
+//
+// Returns:
+//   - (nil, nil): no token available from either source (graceful degradation to public access)
+//   - (*token, nil): token found successfully
+//   - (nil, error): infrastructure error (should fail the operation)
+func (r *Resolver) lookupToken(ctx context.Context, owner, repo string, userID *string) (*string, error) {
+	token, err := r.lookupUserToken(ctx, userID)
+	if err != nil || token != nil {
+		return token, err
+	}
+	return r.lookupInstallationToken(ctx, owner, repo)
+}
+
+// lookupUserToken retrieves OAuth token for the given user.
+//
+// Returns:
+//   - (nil, nil): no userID provided, tokenLookup not configured, or token not found (graceful degradation)
+//   - (*token, nil): token found successfully
+//   - (nil, error): infrastructure error (should fail the operation)
+//
+// Token not found (analysis.ErrTokenNotFound) triggers graceful degradation and is logged at INFO level.
+// So does a token marked invalid or rejected during verification, which is logged as needing reauthorization.
+// Infrastructure errors are returned to fail the operation.
+func (r *Resolver) lookupUserToken(ctx context.Context, userID *string) (*string, error) {
+	if userID == nil || r.tokenLookup == nil {
+		return nil, nil
+	}
+
+	token, err := r.tokenLookup.GetOAuthToken(ctx, *userID, DefaultOAuthProvider)
+	if err != nil {
+		if errors.Is(err, analysis.ErrTokenInvalid) {
+			slog.InfoContext(ctx, "OAuth token marked invalid, using public access",
+				"user_id", *userID,
+				"reauthorize", true,
+			)
+			return nil, nil
+		}
+		if errors.Is(err, analysis.ErrTokenNotFound) {
+			slog.InfoContext(ctx, "no OAuth token found, using public access",
+				"user_id", *userID,
+			)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to lookup OAuth token for user %s: %w", *userID, err)
+	}
+
+	if token == "" {
+		slog.WarnContext(ctx, "empty token returned, using public access",
+			"user_id", *userID,
+		)
+		return nil, nil
+	}
+
+	if !r.verifyUserToken(ctx, *userID, token) {
+		return nil, nil
+	}
+
+	return &token, nil
+}
+
+// verifyUserToken reports whether the token may be used. Only a token the provider rejects is refused;
+// it is marked invalid so the user is asked to reauthorize. Verification failures keep the token,
+// since the clone itself reveals a bad token as well.
+func (r *Resolver) verifyUserToken(ctx context.Context, userID, token string) bool {
+	if r.tokenVerifier == nil {
+		return true
+	}
+
+	info, err := r.tokenVerifier.VerifyToken(ctx, token)
+	if errors.Is(err, analysis.ErrTokenInvalid) {
+		slog.WarnContext(ctx, "OAuth token rejected by provider, using public access",
+			"user_id", userID,
+			"reauthorize", true,
+		)
+		if r.tokenInvalidator != nil {
+			if err := r.tokenInvalidator.MarkOAuthTokenInvalid(ctx, userID, DefaultOAuthProvider, token); err != nil {
+				slog.WarnContext(ctx, "failed to mark OAuth token invalid, ignoring",
+					"user_id", userID,
+					"error", err,
+				)
+			}
+		}
+		return false
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "failed to verify OAuth token, ignoring",
+			"user_id", userID,
+			"error", err,
+		)
+		return true
+	}
+
+	if info.MissingScope(privateRepoScope) {
+		slog.WarnContext(ctx, "OAuth token lacks repo scope, private repositories are not accessible",
+			"user_id", userID,
+			"scopes", info.Scopes,
+			"reauthorize", true,
+		)
+	}
+	return true
+}
+
+// lookupInstallationToken follows the same contract as lookupUserToken for GitHub App installation tokens.
+func (r *Resolver) lookupInstallationToken(ctx context.Context, owner, repo string) (*string, error) {
+	if r.installTokens == nil {
+		return nil, nil
+	}
+
+	token, err := r.installTokens.GetInstallationToken(ctx, owner, repo)
+	if err != nil {
+		if errors.Is(err, analysis.ErrTokenNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get installation token for %s/%s: %w", owner, repo, err)
+	}
+	if token == "" {
+		return nil, nil
+	}
+
+	slog.InfoContext(ctx, "using GitHub App installation token",
+		"owner", owner,
+		"repo", repo,
+	)
+	return &token, nil
+}
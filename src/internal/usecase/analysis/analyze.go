@@ -5,12 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
-	"golang.org/x/sync/semaphore"
-
 	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/usecase/access"
 )
 
 const (
@@ -18,26 +18,51 @@ const (
 	DefaultAnalysisTimeout     = 15 * time.Minute
 	// DefaultOAuthProvider is the OAuth provider for VCS authentication.
 	// Currently only GitHub is supported as the VCS provider (see repoURL construction in Execute).
-	DefaultOAuthProvider = "github"
-	DefaultHost          = "github.com"
+	DefaultOAuthProvider = access.DefaultOAuthProvider
+	DefaultHost          = access.DefaultHost
 )
 
 // AnalyzeUseCase orchestrates repository analysis workflow.
 type AnalyzeUseCase struct {
-	cloneSem     *semaphore.Weighted
-	codebaseRepo analysis.CodebaseRepository
-	parser       analysis.Parser
-	repository   analysis.Repository
-	timeout      time.Duration
-	tokenLookup  analysis.TokenLookup
-	vcs          analysis.VCS
-	vcsAPIClient analysis.VCSAPIClient
+	blamer           analysis.Blamer
+	churnReader      analysis.ChurnReader
+	churnWindow      time.Duration
+	ciLoader         analysis.CIConfigLoader
+	cloner           *access.Cloner
+	codebaseRepo     analysis.CodebaseRepository
+	codeowners       analysis.CodeownersLoader
+	credentials      *access.Resolver
+	fileLister       analysis.FileLister
+	lineCounter      analysis.LineCounter
+	parser           analysis.Parser
+	policyRuleRepo   analysis.PolicyRuleRepository
+	repoConfigLoader analysis.RepoConfigLoader
+	repository       analysis.Repository
+	timeout          time.Duration
+	vcsAPIClient     analysis.VCSAPIClient
+	wsDetector       analysis.WorkspaceDetector
 }
 
 // Config holds configuration for AnalyzeUseCase.
 type Config struct {
-	AnalysisTimeout     time.Duration
-	MaxConcurrentClones int64
+	AnalysisTimeout      time.Duration
+	Blamer               analysis.Blamer
+	ChurnReader          analysis.ChurnReader
+	ChurnWindow          time.Duration
+	CIConfigLoader       analysis.CIConfigLoader
+	CloneLimiter         *access.CloneLimiter
+	CodeownersLoader     analysis.CodeownersLoader
+	DeployCredentials    analysis.DeployCredentialLookup
+	DeployVCS            analysis.DeployCredentialVCS
+	FileLister           analysis.FileLister
+	InstallationTokens   analysis.InstallationTokenProvider
+	LineCounter          analysis.LineCounter
+	MaxConcurrentClones  int64
+	PolicyRuleRepository analysis.PolicyRuleRepository
+	RepoConfigLoader     analysis.RepoConfigLoader
+	TokenInvalidator     analysis.TokenInvalidator
+	TokenVerifier        analysis.TokenVerifier
+	WorkspaceDetector    analysis.WorkspaceDetector
 }
 
 // Option is a functional option for configuring AnalyzeUseCase.
@@ -63,6 +88,124 @@ func WithMaxConcurrentClones(n int64) Option {
 	}
 }
 
+// WithCloneLimiter shares a limit on concurrent clones with other use cases of the worker.
+// It takes precedence over WithMaxConcurrentClones.
+func WithCloneLimiter(l *access.CloneLimiter) Option {
+	return func(cfg *Config) {
+		cfg.CloneLimiter = l
+	}
+}
+
+// WithBlamer enables recording the introducing commit and last modification of each test.
+// Shallow clones are unshallowed on demand, which adds a full history fetch to every
+// analysis with test files, and declarations changed since their file was added cost
+// one more history walk each.
+func WithBlamer(b analysis.Blamer) Option {
+	return func(cfg *Config) {
+		cfg.Blamer = b
+	}
+}
+
+// WithChurnReader enables measuring per-test-file churn over the churn window.
+// Shallow clones are only deepened to the start of the window.
+func WithChurnReader(r analysis.ChurnReader) Option {
+	return func(cfg *Config) {
+		cfg.ChurnReader = r
+	}
+}
+
+// WithChurnWindow sets how far back from the analyzed commit churn is measured.
+// Zero or negative values are ignored and the default window is used.
+func WithChurnWindow(d time.Duration) Option {
+	return func(cfg *Config) {
+		if d > 0 {
+			cfg.ChurnWindow = d
+		}
+	}
+}
+
+// WithCIConfigLoader enables recording CI test commands and flagging detected frameworks no CI job runs.
+// It requires a file lister.
+func WithCIConfigLoader(l analysis.CIConfigLoader) Option {
+	return func(cfg *Config) {
+		cfg.CIConfigLoader = l
+	}
+}
+
+// WithCodeownersLoader enables attributing test files to their CODEOWNERS owners.
+func WithCodeownersLoader(l analysis.CodeownersLoader) Option {
+	return func(cfg *Config) {
+		cfg.CodeownersLoader = l
+	}
+}
+
+// WithDeployCredentials clones codebases that have their own deploy credential with it,
+// in preference to the user's OAuth token and app installation tokens. An SSH key only
+// reaches git, so API requests still use those tokens.
+func WithDeployCredentials(l analysis.DeployCredentialLookup, v analysis.DeployCredentialVCS) Option {
+	return func(cfg *Config) {
+		cfg.DeployCredentials = l
+		cfg.DeployVCS = v
+	}
+}
+
+// WithFileLister sets the lister required by workspace detection, test-to-source mapping,
+// CI config loading and require_test_file policies.
+// Without it, no mapping is recorded and those policies are skipped.
+func WithFileLister(l analysis.FileLister) Option {
+	return func(cfg *Config) {
+		cfg.FileLister = l
+	}
+}
+
+// WithInstallationTokenProvider enables cloning with a GitHub App installation token
+// when the request has no user or the user has no OAuth token.
+func WithInstallationTokenProvider(p analysis.InstallationTokenProvider) Option {
+	return func(cfg *Config) {
+		cfg.InstallationTokens = p
+	}
+}
+
+// WithLineCounter enables the per-language source census and test-to-code ratios.
+// It requires a file lister.
+func WithLineCounter(c analysis.LineCounter) Option {
+	return func(cfg *Config) {
+		cfg.LineCounter = c
+	}
+}
+
+// WithPolicyRuleRepository enables codebase-level policy rules.
+func WithPolicyRuleRepository(r analysis.PolicyRuleRepository) Option {
+	return func(cfg *Config) {
+		cfg.PolicyRuleRepository = r
+	}
+}
+
+// WithRepoConfigLoader enables reading the repository config file from the clone.
+func WithRepoConfigLoader(l analysis.RepoConfigLoader) Option {
+	return func(cfg *Config) {
+		cfg.RepoConfigLoader = l
+	}
+}
+
+// WithTokenVerification checks users' OAuth tokens with the provider before cloning.
+// A rejected token is marked invalid so later lookups degrade to public access until the
+// user reauthorizes, instead of failing every clone.
+func WithTokenVerification(v analysis.TokenVerifier, i analysis.TokenInvalidator) Option {
+	return func(cfg *Config) {
+		cfg.TokenInvalidator = i
+		cfg.TokenVerifier = v
+	}
+}
+
+// WithWorkspaceDetector enables grouping test files by monorepo package.
+// It requires a file lister.
+func WithWorkspaceDetector(d analysis.WorkspaceDetector) Option {
+	return func(cfg *Config) {
+		cfg.WorkspaceDetector = d
+	}
+}
+
 // NewAnalyzeUseCase creates a new AnalyzeUseCase with given dependencies.
 // tokenLookup is optional - if nil, all clones use public access (token=nil).
 func NewAnalyzeUseCase(
@@ -76,6 +219,7 @@ func NewAnalyzeUseCase(
 ) *AnalyzeUseCase {
 	cfg := Config{
 		AnalysisTimeout:     DefaultAnalysisTimeout,
+		ChurnWindow:         analysis.DefaultChurnWindow,
 		MaxConcurrentClones: DefaultMaxConcurrentClones,
 	}
 
@@ -83,15 +227,36 @@ func NewAnalyzeUseCase(
 		opt(&cfg)
 	}
 
+	cloneLimiter := cfg.CloneLimiter
+	if cloneLimiter == nil {
+		cloneLimiter = access.NewCloneLimiter(cfg.MaxConcurrentClones)
+	}
+	credentialOpts := []access.Option{
+		access.WithInstallationTokenProvider(cfg.InstallationTokens),
+		access.WithTokenVerification(cfg.TokenVerifier, cfg.TokenInvalidator),
+	}
+	if cfg.DeployVCS != nil {
+		credentialOpts = append(credentialOpts, access.WithDeployCredentials(cfg.DeployCredentials))
+	}
+
 	return &AnalyzeUseCase{
-		cloneSem:     semaphore.NewWeighted(cfg.MaxConcurrentClones),
-		codebaseRepo: codebaseRepo,
-		parser:       parser,
-		repository:   repository,
-		timeout:      cfg.AnalysisTimeout,
-		tokenLookup:  tokenLookup,
-		vcs:          vcs,
-		vcsAPIClient: vcsAPIClient,
+		blamer:           cfg.Blamer,
+		churnReader:      cfg.ChurnReader,
+		churnWindow:      cfg.ChurnWindow,
+		ciLoader:         cfg.CIConfigLoader,
+		cloner:           access.NewCloner(vcs, cfg.DeployVCS, cloneLimiter),
+		codebaseRepo:     codebaseRepo,
+		codeowners:       cfg.CodeownersLoader,
+		credentials:      access.NewResolver(tokenLookup, credentialOpts...),
+		fileLister:       cfg.FileLister,
+		lineCounter:      cfg.LineCounter,
+		parser:           parser,
+		policyRuleRepo:   cfg.PolicyRuleRepository,
+		repoConfigLoader: cfg.RepoConfigLoader,
+		repository:       repository,
+		timeout:          cfg.AnalysisTimeout,
+		vcsAPIClient:     vcsAPIClient,
+		wsDetector:       cfg.WorkspaceDetector,
 	}
 }
 
@@ -105,32 +270,45 @@ func (uc *AnalyzeUseCase) Execute(ctx context.Context, req analysis.AnalyzeReque
 
 	repoURL := fmt.Sprintf("https://github.com/%s/%s", req.Owner, req.Repo)
 
-	token, err := uc.lookupToken(timeoutCtx, req.UserID)
+	credentials, err := uc.credentials.Resolve(timeoutCtx, req.Owner, req.Repo, req.UserID)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrTokenLookupFailed, err)
 	}
+	token := credentials.Token
 
-	commitInfo, err := uc.vcs.GetHeadCommit(timeoutCtx, repoURL, token)
+	commitInfo, err := uc.cloner.GetHeadCommit(timeoutCtx, repoURL, credentials)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrHeadCommitFailed, err)
 	}
 
-	src, err := uc.cloneWithSemaphore(timeoutCtx, repoURL, token)
+	src, err := uc.cloner.Clone(timeoutCtx, repoURL, credentials)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrCloneFailed, err)
 	}
-	defer uc.closeSource(src, req.Owner, req.Repo)
+	defer access.CloseSource(src, req.Owner, req.Repo)
+
+	if req.ExactCommit {
+		if err := src.Checkout(timeoutCtx, req.CommitSHA); err != nil {
+			return fmt.Errorf("%w: %w", ErrCheckoutFailed, err)
+		}
+	}
 
 	codebase, err := uc.resolveCodebase(timeoutCtx, req, src, token, commitInfo.IsPrivate)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrCodebaseResolutionFailed, err)
 	}
 
+	// An exact commit that is still HEAD is the codebase's latest. Recording it as historical
+	// would hide it from the latest view, while River's dedupe and uq_analyses_completed_commit
+	// keep the regular HEAD analysis of the same commit from ever replacing it.
+	isHistorical := req.ExactCommit && src.CommitSHA() != commitInfo.SHA
+
 	createParams := analysis.CreateAnalysisRecordParams{
 		Branch:         src.Branch(),
 		CodebaseID:     &codebase.ID,
 		CommitSHA:      src.CommitSHA(),
 		ExternalRepoID: codebase.ExternalRepoID,
+		IsHistorical:   isHistorical,
 		Owner:          codebase.Owner,
 		Repo:           codebase.Name,
 	}
@@ -155,7 +333,9 @@ func (uc *AnalyzeUseCase) Execute(ctx context.Context, req analysis.AnalyzeReque
 		}
 	}()
 
-	inventory, err := uc.parser.Scan(timeoutCtx, src)
+	repoConfig := uc.loadRepoConfig(timeoutCtx, src, req.Owner, req.Repo)
+
+	inventory, err := uc.parser.Scan(timeoutCtx, src, repoConfig.ScanOptions())
 	if err != nil {
 		err = fmt.Errorf("%w: %w", ErrScanFailed, err)
 		return err
@@ -170,11 +350,47 @@ func (uc *AnalyzeUseCase) Execute(ctx context.Context, req analysis.AnalyzeReque
 		inventory = &analysis.Inventory{Files: []analysis.TestFile{}}
 	}
 
+	files, err := uc.listSourceFiles(timeoutCtx, src)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrScanFailed, err)
+		return err
+	}
+
+	workspaces := uc.detectWorkspaces(timeoutCtx, src, files, repoConfig, req.Owner, req.Repo)
+	analysis.AssignPackages(inventory, workspaces)
+	analysis.AssignOwners(inventory, uc.loadCodeowners(timeoutCtx, src, req.Owner, req.Repo))
+	uc.blameTests(timeoutCtx, src, inventory, req.Owner, req.Repo)
+	churn := uc.measureChurn(timeoutCtx, src, inventory, req.Owner, req.Repo)
+
+	var sourceCensus *analysis.SourceCensus
+	var sourceMapping *analysis.SourceMapping
+	if files != nil {
+		sources := analysis.SourceFiles(inventory, files, repoConfig.Exclude)
+		mapping := analysis.MapTestsToSources(inventory, sources)
+		sourceMapping = &mapping
+		sourceCensus = uc.countSources(timeoutCtx, src, sources, req.Owner, req.Repo)
+	}
+
+	ciReport := uc.detectCI(timeoutCtx, src, files, inventory, req.Owner, req.Repo)
+
+	policyResults, err := uc.evaluatePolicies(timeoutCtx, codebase.ID, repoConfig, files, inventory)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrPolicyEvaluationFailed, err)
+		return err
+	}
+
 	saveParams := analysis.SaveAnalysisInventoryParams{
-		AnalysisID:  analysisID,
-		CommittedAt: src.CommittedAt(),
-		Inventory:   inventory,
-		UserID:      req.UserID,
+		AnalysisID:    analysisID,
+		CIReport:      ciReport,
+		Churn:         churn,
+		CommittedAt:   src.CommittedAt(),
+		ConfigHash:    repoConfig.Hash,
+		Inventory:     inventory,
+		PolicyResults: policyResults,
+		SourceCensus:  sourceCensus,
+		SourceMapping: sourceMapping,
+		UserID:        req.UserID,
+		Workspaces:    workspaces,
 	}
 	if err = saveParams.Validate(); err != nil {
 		err = fmt.Errorf("%w: %w", ErrSaveFailed, err)
@@ -272,6 +488,32 @@ func (uc *AnalyzeUseCase) resolveCodebaseWithAPI(
 		)
 	}
 
+	codebase, err := uc.matchCodebase(ctx, host, req, codebaseByName, repoInfo, isPrivate)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.codebaseRepo.UpdateMetadata(ctx, codebase.ID, repoInfo); err != nil {
+		slog.WarnContext(ctx, "failed to update repository metadata, ignoring",
+			"error", err,
+			"codebase_id", codebase.ID,
+			"owner", req.Owner,
+			"repo", req.Repo,
+		)
+	}
+	return codebase, nil
+}
+
+// matchCodebase maps the repository identified by the API to its codebase record,
+// handling restores, renames and recreations.
+func (uc *AnalyzeUseCase) matchCodebase(
+	ctx context.Context,
+	host string,
+	req analysis.AnalyzeRequest,
+	codebaseByName *analysis.Codebase,
+	repoInfo analysis.RepoInfo,
+	isPrivate bool,
+) (*analysis.Codebase, error) {
 	externalRepoID := repoInfo.ExternalRepoID
 	codebaseByID, err := uc.codebaseRepo.FindByExternalID(ctx, host, externalRepoID)
 	if err != nil && !errors.Is(err, analysis.ErrCodebaseNotFound) {
@@ -319,6 +561,7 @@ func (uc *AnalyzeUseCase) resolveCodebaseWithAPI(
 	}
 
 	upsertParams := analysis.UpsertCodebaseParams{
+		DefaultBranch:  repoInfo.DefaultBranch,
 		Host:           host,
 		Owner:          req.Owner,
 		Name:           req.Repo,
@@ -355,58 +598,220 @@ func (uc *AnalyzeUseCase) resolveCodebaseWithAPI(
 	return newCodebase, nil
 }
 
-func (uc *AnalyzeUseCase) cloneWithSemaphore(ctx context.Context, url string, token *string) (analysis.Source, error) {
-	if err := uc.cloneSem.Acquire(ctx, 1); err != nil {
-		return nil, err
+// loadRepoConfig reads the repository config file from the clone.
+// A missing loader or an unreadable config degrades to an empty config so that
+// a broken config file never blocks the analysis itself.
+func (uc *AnalyzeUseCase) loadRepoConfig(ctx context.Context, src analysis.Source, owner, repo string) *analysis.RepoConfig {
+	if uc.repoConfigLoader == nil {
+		return &analysis.RepoConfig{}
 	}
-	defer uc.cloneSem.Release(1)
 
-	return uc.vcs.Clone(ctx, url, token)
+	cfg, err := uc.repoConfigLoader.Load(ctx, src)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load repository config, ignoring",
+			"error", err,
+			"owner", owner,
+			"repo", repo,
+		)
+		return &analysis.RepoConfig{}
+	}
+	if cfg == nil {
+		return &analysis.RepoConfig{}
+	}
+	return cfg
 }
 
-// lookupToken retrieves OAuth token for the given user.
-//
-// Returns:
-//   - (nil, nil): no userID provided, tokenLookup not configured, or token not found (graceful degradation)
-//   - (*token, nil): token found successfully
-//   - (nil, error): infrastructure error (should fail the operation)
-//
-// Token not found (analysis.ErrTokenNotFound) triggers graceful degradation and is logged at INFO level.
-// Infrastructure errors are returned to fail the operation.
-func (uc *AnalyzeUseCase) lookupToken(ctx context.Context, userID *string) (*string, error) {
-	if userID == nil || uc.tokenLookup == nil {
-		return nil, nil
+// blameTests attributes tests to the commits that introduced them.
+// Failures are logged and the analysis is saved without authorship.
+func (uc *AnalyzeUseCase) blameTests(ctx context.Context, src analysis.Source, inventory *analysis.Inventory, owner, repo string) {
+	if uc.blamer == nil {
+		return
+	}
+
+	if err := uc.blamer.Blame(ctx, src, inventory); err != nil {
+		slog.WarnContext(ctx, "failed to blame tests, ignoring",
+			"error", err,
+			"owner", owner,
+			"repo", repo,
+		)
+	}
+}
+
+// loadCodeowners reads the CODEOWNERS file from the clone.
+// Read failures are logged and the analysis continues without ownership.
+func (uc *AnalyzeUseCase) loadCodeowners(ctx context.Context, src analysis.Source, owner, repo string) *analysis.Codeowners {
+	if uc.codeowners == nil {
+		return nil
 	}
 
-	token, err := uc.tokenLookup.GetOAuthToken(ctx, *userID, DefaultOAuthProvider)
+	codeowners, err := uc.codeowners.Load(ctx, src)
 	if err != nil {
-		if errors.Is(err, analysis.ErrTokenNotFound) {
-			slog.InfoContext(ctx, "no OAuth token found, using public access",
-				"user_id", *userID,
-			)
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to lookup OAuth token for user %s: %w", *userID, err)
+		slog.WarnContext(ctx, "failed to load CODEOWNERS, ignoring",
+			"error", err,
+			"owner", owner,
+			"repo", repo,
+		)
+		return nil
+	}
+	return codeowners
+}
+
+// detectWorkspaces discovers monorepo packages in the clone. files is nil without a file lister.
+// Detection failures are logged and the analysis continues without a package dimension.
+func (uc *AnalyzeUseCase) detectWorkspaces(ctx context.Context, src analysis.Source, files []string, cfg *analysis.RepoConfig, owner, repo string) []analysis.Workspace {
+	if uc.wsDetector == nil || files == nil {
+		return nil
+	}
+
+	workspaces, err := uc.wsDetector.Detect(ctx, src, files, cfg.WorkspaceRoots)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to detect workspaces, ignoring",
+			"error", err,
+			"owner", owner,
+			"repo", repo,
+		)
+		return nil
+	}
+	return workspaces
+}
+
+// measureChurn reads test file churn over the window ending at the analyzed commit.
+// It returns nil when no churn reader is configured or reading fails.
+func (uc *AnalyzeUseCase) measureChurn(ctx context.Context, src analysis.Source, inventory *analysis.Inventory, owner, repo string) *analysis.ChurnReport {
+	if uc.churnReader == nil {
+		return nil
 	}
 
-	if token == "" {
-		slog.WarnContext(ctx, "empty token returned, using public access",
-			"user_id", *userID,
+	end := src.CommittedAt()
+	if end.IsZero() {
+		end = time.Now()
+	}
+	since := end.Add(-uc.churnWindow)
+
+	paths := make([]string, 0, len(inventory.Files))
+	for _, f := range inventory.Files {
+		paths = append(paths, f.Path)
+	}
+
+	churn, err := uc.churnReader.Churn(ctx, src, paths, since)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to read test churn, ignoring",
+			"error", err,
+			"owner", owner,
+			"repo", repo,
 		)
+		return nil
+	}
+
+	report := analysis.NewChurnReport(inventory, churn, since)
+	return &report
+}
+
+// listSourceFiles lists every file of the source once for workspace detection, source mapping,
+// CI config loading and require_test_file policies. Returns nil without a file lister.
+func (uc *AnalyzeUseCase) listSourceFiles(ctx context.Context, src analysis.Source) ([]string, error) {
+	if uc.fileLister == nil {
 		return nil, nil
 	}
+	files, err := uc.fileLister.ListFiles(ctx, src)
+	if err != nil {
+		return nil, fmt.Errorf("list source files: %w", err)
+	}
+	if files == nil {
+		files = []string{}
+	}
+	return files, nil
+}
+
+// countSources returns nil when no line counter is configured or counting fails.
+func (uc *AnalyzeUseCase) countSources(ctx context.Context, src analysis.Source, sources []string, owner, repo string) *analysis.SourceCensus {
+	if uc.lineCounter == nil {
+		return nil
+	}
+
+	lines, err := uc.lineCounter.CountLines(ctx, src, sources)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to count source lines, ignoring",
+			"error", err,
+			"owner", owner,
+			"repo", repo,
+		)
+		return nil
+	}
 
-	return &token, nil
+	census := analysis.NewSourceCensus(sources, lines)
+	return &census
 }
 
-func (uc *AnalyzeUseCase) closeSource(src analysis.Source, owner, repo string) {
-	// Use background context for cleanup operations
-	ctx := context.Background()
-	if closeErr := src.Close(ctx); closeErr != nil {
-		slog.Error("failed to close source",
-			"error", closeErr,
+// detectCI returns nil when no CI config loader or file lister is configured, the repository
+// has no CI configuration or loading fails.
+func (uc *AnalyzeUseCase) detectCI(ctx context.Context, src analysis.Source, files []string, inventory *analysis.Inventory, owner, repo string) *analysis.CIReport {
+	if uc.ciLoader == nil || files == nil {
+		return nil
+	}
+
+	report, err := uc.ciLoader.Load(ctx, src, files)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load CI config, ignoring",
+			"error", err,
 			"owner", owner,
 			"repo", repo,
 		)
+		return nil
+	}
+	if report == nil {
+		return nil
+	}
+
+	report.Frameworks = analysis.EvaluateCIFrameworks(inventory, report.Commands)
+	return report
+}
+
+// evaluatePolicies merges repository and codebase policy rules and evaluates them against the inventory.
+// Codebase rules override repository rules with the same name, including invalid ones,
+// which are otherwise reported as failed.
+func (uc *AnalyzeUseCase) evaluatePolicies(
+	ctx context.Context,
+	codebaseID analysis.UUID,
+	repoConfig *analysis.RepoConfig,
+	files []string,
+	inventory *analysis.Inventory,
+) ([]analysis.PolicyResult, error) {
+	var codebaseRules []analysis.PolicyRule
+	if uc.policyRuleRepo != nil {
+		rules, err := uc.policyRuleRepo.GetCodebasePolicyRules(ctx, codebaseID)
+		if err != nil {
+			return nil, fmt.Errorf("get codebase policy rules: %w", err)
+		}
+		for _, rule := range rules {
+			if validateErr := rule.Validate(); validateErr != nil {
+				slog.WarnContext(ctx, "skipping invalid codebase policy rule",
+					"error", validateErr,
+					"codebase_id", codebaseID,
+					"policy", rule.Name,
+				)
+				continue
+			}
+			codebaseRules = append(codebaseRules, rule)
+		}
+	}
+
+	rules := analysis.MergePolicyRules(repoConfig.Policies, codebaseRules)
+	invalid := slices.DeleteFunc(slices.Clone(repoConfig.InvalidPolicies), func(r analysis.PolicyResult) bool {
+		return slices.ContainsFunc(codebaseRules, func(c analysis.PolicyRule) bool { return c.Name == r.Name })
+	})
+	if len(rules) == 0 && len(invalid) == 0 {
+		return nil, nil
+	}
+
+	if analysis.HasRequireTestFileRule(rules) && uc.fileLister == nil {
+		slog.WarnContext(ctx, "file lister not configured, skipping require_test_file policies",
+			"codebase_id", codebaseID,
+		)
+		rules = slices.DeleteFunc(rules, func(r analysis.PolicyRule) bool {
+			return r.Kind == analysis.PolicyRuleKindRequireTestFile
+		})
 	}
+
+	return append(analysis.EvaluatePolicies(rules, inventory, files), invalid...), nil
 }
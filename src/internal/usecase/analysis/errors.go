@@ -3,9 +3,11 @@ package analysis
 import "errors"
 
 var (
+	ErrCheckoutFailed           = errors.New("checkout failed")
 	ErrCloneFailed              = errors.New("clone failed")
 	ErrCodebaseResolutionFailed = errors.New("codebase resolution failed")
 	ErrHeadCommitFailed         = errors.New("head commit lookup failed")
+	ErrPolicyEvaluationFailed   = errors.New("policy evaluation failed")
 	ErrRaceConditionDetected    = errors.New("race condition detected: repository state changed during analysis")
 	ErrSaveFailed               = errors.New("save failed")
 	ErrScanFailed               = errors.New("scan failed")
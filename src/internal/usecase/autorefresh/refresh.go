@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/usecase/access"
 )
 
 // Tuned for 1h cron interval:
@@ -16,23 +17,115 @@ import (
 // - 3 failures: persistent problem, stop to prevent cascade
 const maxConsecutiveEnqueueFailures = 3
 
+// metadataRefreshInterval bounds how stale stored repository metadata may get. Conditional
+// requests make unchanged repositories cheap to revalidate.
+const metadataRefreshInterval = 24 * time.Hour
+
+// maxRevocationViewers bounds how many recent viewers' tokens are tried before a private
+// repository is considered unreadable.
+const maxRevocationViewers = 5
+
 var ErrCircuitBreakerOpen = errors.New("circuit breaker: too many consecutive enqueue failures")
 
 type AutoRefreshUseCase struct {
-	repository analysis.AutoRefreshRepository
-	taskQueue  analysis.TaskQueue
-	vcs        analysis.VCS
+	credentials   *access.CodebaseResolver
+	deployVCS     analysis.DeployCredentialVCS
+	metadataStore analysis.CodebaseMetadataRepository
+	repoAPI       analysis.VCSAPIClient
+	repository    analysis.AutoRefreshRepository
+	staleStore    analysis.CodebaseStaleRepository
+	taskQueue     analysis.TaskQueue
+	vcs           analysis.VCS
+	viewers       analysis.CodebaseViewerLookup
+}
+
+// Config holds optional credential sources for refreshing private codebases
+// and the dependencies for keeping repository metadata current.
+// Without credential sources, private codebases are skipped.
+type Config struct {
+	DeployCredentials  analysis.DeployCredentialLookup
+	DeployVCS          analysis.DeployCredentialVCS
+	InstallationTokens analysis.InstallationTokenProvider
+	MetadataStore      analysis.CodebaseMetadataRepository
+	RepoAPI            analysis.VCSAPIClient
+	StaleStore         analysis.CodebaseStaleRepository
+	TokenLookup        analysis.TokenLookup
+	Viewers            analysis.CodebaseViewerLookup
+}
+
+// Option is a functional option for configuring AutoRefreshUseCase.
+type Option func(*Config)
+
+// WithDeployCredentials refreshes private codebases with their own deploy credential.
+func WithDeployCredentials(l analysis.DeployCredentialLookup, v analysis.DeployCredentialVCS) Option {
+	return func(cfg *Config) {
+		cfg.DeployCredentials = l
+		cfg.DeployVCS = v
+	}
+}
+
+// WithInstallationTokenProvider refreshes private codebases the GitHub App is installed on.
+func WithInstallationTokenProvider(p analysis.InstallationTokenProvider) Option {
+	return func(cfg *Config) {
+		cfg.InstallationTokens = p
+	}
+}
+
+// WithMetadataRefresh re-fetches repository metadata of candidates once it is older than a day.
+func WithMetadataRefresh(api analysis.VCSAPIClient, store analysis.CodebaseMetadataRepository) Option {
+	return func(cfg *Config) {
+		cfg.MetadataStore = store
+		cfg.RepoAPI = api
+	}
+}
+
+// WithStaleDetection confirms through the API that a repository git cannot find is gone
+// and marks its codebase stale, so it leaves the candidate set instead of failing every run.
+// viewers supplies the other tokens a private repository is tried with before it counts as gone.
+func WithStaleDetection(api analysis.VCSAPIClient, store analysis.CodebaseStaleRepository, viewers analysis.CodebaseViewerLookup) Option {
+	return func(cfg *Config) {
+		cfg.RepoAPI = api
+		cfg.StaleStore = store
+		cfg.Viewers = viewers
+	}
+}
+
+// WithTokenLookup refreshes private codebases with the OAuth token of their most recent viewer.
+func WithTokenLookup(l analysis.TokenLookup) Option {
+	return func(cfg *Config) {
+		cfg.TokenLookup = l
+	}
 }
 
 func NewAutoRefreshUseCase(
 	repository analysis.AutoRefreshRepository,
 	taskQueue analysis.TaskQueue,
 	vcs analysis.VCS,
+	opts ...Option,
 ) *AutoRefreshUseCase {
+	var cfg Config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var credentialOpts []access.Option
+	if cfg.DeployCredentials != nil && cfg.DeployVCS != nil {
+		credentialOpts = append(credentialOpts, access.WithDeployCredentials(cfg.DeployCredentials))
+	}
+	if cfg.InstallationTokens != nil {
+		credentialOpts = append(credentialOpts, access.WithInstallationTokenProvider(cfg.InstallationTokens))
+	}
+
 	return &AutoRefreshUseCase{
-		repository: repository,
-		taskQueue:  taskQueue,
-		vcs:        vcs,
+		credentials:   access.NewCodebaseResolver(cfg.TokenLookup, credentialOpts...),
+		deployVCS:     cfg.DeployVCS,
+		metadataStore: cfg.MetadataStore,
+		repoAPI:       cfg.RepoAPI,
+		repository:    repository,
+		staleStore:    cfg.StaleStore,
+		taskQueue:     taskQueue,
+		vcs:           vcs,
+		viewers:       cfg.Viewers,
 	}
 }
 
@@ -61,17 +154,63 @@ func (uc *AutoRefreshUseCase) Execute(ctx context.Context) error {
 			return fmt.Errorf("%w: %d failures", ErrCircuitBreakerOpen, consecutiveFailures)
 		}
 
-		if !analysis.ShouldRefreshAt(
-			codebase.LastViewedAt,
-			codebase.LastCompletedAt,
-			codebase.ConsecutiveFailures,
-			now,
-		) {
+		metadataDue := uc.metadataDue(codebase, now)
+		if !metadataDue && !codebase.ShouldRefreshAt(now) {
+			continue
+		}
+
+		credential, ok, err := uc.credentials.Resolve(ctx, codebase)
+		if err != nil {
+			consecutiveFailures++
+			slog.ErrorContext(ctx, "failed to resolve credential for auto-refresh",
+				"owner", codebase.Owner,
+				"repo", codebase.Name,
+				"consecutive_failures", consecutiveFailures,
+				"error", err,
+			)
+			continue
+		}
+		if !ok {
+			slog.DebugContext(ctx, "skipping auto-refresh: no credential for private codebase",
+				"owner", codebase.Owner,
+				"repo", codebase.Name,
+			)
+			continue
+		}
+
+		// Fresh metadata decides whether an archived repository was un-archived in the meantime.
+		if metadataDue {
+			if info, ok := uc.refreshMetadata(ctx, codebase, credential.Token); ok {
+				codebase.IsArchived = info.IsArchived
+				codebase.IsDisabled = info.IsDisabled
+			}
+		}
+		if codebase.IsArchived || codebase.IsDisabled {
+			slog.DebugContext(ctx, "skipping auto-refresh: repository is archived or disabled",
+				"owner", codebase.Owner,
+				"repo", codebase.Name,
+				"archived", codebase.IsArchived,
+				"disabled", codebase.IsDisabled,
+			)
+			continue
+		}
+		if !codebase.ShouldRefreshAt(now) {
 			continue
 		}
 
 		repoURL := fmt.Sprintf("https://%s/%s/%s", codebase.Host, codebase.Owner, codebase.Name)
-		commitInfo, err := uc.vcs.GetHeadCommit(ctx, repoURL, nil)
+		commitInfo, err := uc.getHeadCommit(ctx, repoURL, credential)
+		if errors.Is(err, analysis.ErrEmptyRepository) {
+			uc.updateEmptyState(ctx, codebase, true)
+			slog.DebugContext(ctx, "skipping auto-refresh: repository has no commits",
+				"owner", codebase.Owner,
+				"repo", codebase.Name,
+			)
+			continue
+		}
+		if errors.Is(err, analysis.ErrRepoNotFound) && uc.retireMissing(ctx, codebase, credential) {
+			continue
+		}
 		if err != nil {
 			consecutiveFailures++
 			slog.ErrorContext(ctx, "failed to get head commit for auto-refresh",
@@ -82,6 +221,9 @@ func (uc *AutoRefreshUseCase) Execute(ctx context.Context) error {
 			)
 			continue
 		}
+		if codebase.EmptyCheckedAt != nil {
+			uc.updateEmptyState(ctx, codebase, false)
+		}
 
 		if codebase.LastCommitSHA == commitInfo.SHA {
 			slog.DebugContext(ctx, "skipping auto-refresh: no new commits",
@@ -92,7 +234,7 @@ func (uc *AutoRefreshUseCase) Execute(ctx context.Context) error {
 			continue
 		}
 
-		if err := uc.taskQueue.EnqueueAnalysis(ctx, codebase.Owner, codebase.Name, commitInfo.SHA); err != nil {
+		if err := uc.enqueue(ctx, codebase, commitInfo.SHA, credential.UserID); err != nil {
 			consecutiveFailures++
 			slog.ErrorContext(ctx, "failed to enqueue auto-refresh task",
 				"owner", codebase.Owner,
@@ -118,3 +260,214 @@ func (uc *AutoRefreshUseCase) Execute(ctx context.Context) error {
 
 	return nil
 }
+
+func (uc *AutoRefreshUseCase) getHeadCommit(ctx context.Context, repoURL string, credential access.CodebaseCredentials) (analysis.CommitInfo, error) {
+	if credential.Deploy != nil {
+		return uc.deployVCS.GetHeadCommitWithCredential(ctx, repoURL, *credential.Deploy)
+	}
+	return uc.vcs.GetHeadCommit(ctx, repoURL, credential.Token)
+}
+
+func (uc *AutoRefreshUseCase) metadataDue(codebase analysis.CodebaseRefreshInfo, now time.Time) bool {
+	if uc.repoAPI == nil || uc.metadataStore == nil {
+		return false
+	}
+	return codebase.MetadataRefreshedAt == nil || now.Sub(*codebase.MetadataRefreshedAt) >= metadataRefreshInterval
+}
+
+// refreshMetadata is best effort: stale metadata must not hold back the refresh itself.
+// ok reports whether info was fetched from the provider.
+func (uc *AutoRefreshUseCase) refreshMetadata(ctx context.Context, codebase analysis.CodebaseRefreshInfo, token *string) (analysis.RepoInfo, bool) {
+	info, err := uc.repoAPI.GetRepoInfo(ctx, codebase.Host, codebase.Owner, codebase.Name, token)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to fetch repository metadata, ignoring",
+			"owner", codebase.Owner,
+			"repo", codebase.Name,
+			"error", err,
+		)
+		return analysis.RepoInfo{}, false
+	}
+	if err := uc.metadataStore.UpdateMetadata(ctx, codebase.ID, info); err != nil {
+		slog.WarnContext(ctx, "failed to update repository metadata, ignoring",
+			"owner", codebase.Owner,
+			"repo", codebase.Name,
+			"error", err,
+		)
+	}
+	return info, true
+}
+
+// updateEmptyState is best effort: without it an empty repository is merely rechecked sooner.
+func (uc *AutoRefreshUseCase) updateEmptyState(ctx context.Context, codebase analysis.CodebaseRefreshInfo, isEmpty bool) {
+	if err := uc.repository.UpdateEmptyState(ctx, codebase.ID, isEmpty); err != nil {
+		slog.WarnContext(ctx, "failed to update empty repository state, ignoring",
+			"owner", codebase.Owner,
+			"repo", codebase.Name,
+			"is_empty", isEmpty,
+			"error", err,
+		)
+	}
+}
+
+// retireMissing marks the codebase stale once the API confirms git's "not found", and reports
+// whether it did. Any doubt leaves the codebase in place to be retried on the next run.
+func (uc *AutoRefreshUseCase) retireMissing(ctx context.Context, codebase analysis.CodebaseRefreshInfo, credential access.CodebaseCredentials) bool {
+	if uc.repoAPI == nil || uc.staleStore == nil {
+		return false
+	}
+
+	reason, gone := uc.confirmMissing(ctx, codebase, credential)
+	if !gone {
+		return false
+	}
+	if err := uc.staleStore.MarkStale(ctx, codebase.ID, reason); err != nil {
+		slog.WarnContext(ctx, "failed to mark missing codebase stale",
+			"owner", codebase.Owner,
+			"repo", codebase.Name,
+			"reason", reason,
+			"error", err,
+		)
+		return false
+	}
+
+	slog.InfoContext(ctx, "marked codebase stale: repository is no longer accessible",
+		"owner", codebase.Owner,
+		"repo", codebase.Name,
+		"codebase_id", codebase.ID,
+		"reason", reason,
+	)
+	return true
+}
+
+// confirmMissing asks the API whether the repository is gone and why. GitHub answers deletion and
+// lack of access alike, so a public repository that vanished is probed with the credentials of a
+// private one: if they still see it, it was made private. A private repository counts as access
+// revoked only once every stored credential fails to read it.
+func (uc *AutoRefreshUseCase) confirmMissing(ctx context.Context, codebase analysis.CodebaseRefreshInfo, credential access.CodebaseCredentials) (analysis.StaleReason, bool) {
+	if codebase.IsPrivate {
+		if !uc.unreadableWithAny(ctx, codebase) {
+			return "", false
+		}
+		return analysis.StaleReasonAccessRevoked, true
+	}
+	if !uc.repoNotFound(ctx, codebase, credential.Token) {
+		return "", false
+	}
+
+	asPrivate := codebase
+	asPrivate.IsPrivate = true
+	fallback, ok, err := uc.credentials.Resolve(ctx, asPrivate)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to resolve credential to check repository visibility",
+			"owner", codebase.Owner,
+			"repo", codebase.Name,
+			"error", err,
+		)
+		return "", false
+	}
+	if !ok || fallback.Token == nil {
+		return analysis.StaleReasonDeleted, true
+	}
+
+	_, err = uc.repoAPI.GetRepoInfo(ctx, codebase.Host, codebase.Owner, codebase.Name, fallback.Token)
+	switch {
+	case err == nil:
+		return analysis.StaleReasonMadePrivate, true
+	case errors.Is(err, analysis.ErrRepoNotFound):
+		return analysis.StaleReasonDeleted, true
+	default:
+		slog.WarnContext(ctx, "failed to check repository visibility",
+			"owner", codebase.Owner,
+			"repo", codebase.Name,
+			"error", err,
+		)
+		return "", false
+	}
+}
+
+// unreadableWithAny reports whether the deploy credential, the installation token and the
+// recent viewers' tokens all fail to see the private repository. Tokens are checked through the
+// API and an SSH key through git. A bare SSH key cannot tell a deleted repository from a revoked
+// key, so at least one token has to confirm.
+func (uc *AutoRefreshUseCase) unreadableWithAny(ctx context.Context, codebase analysis.CodebaseRefreshInfo) bool {
+	var viewerIDs []string
+	if uc.viewers != nil {
+		ids, err := uc.viewers.ListRecentViewers(ctx, codebase.ID, maxRevocationViewers)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to list recent viewers to confirm missing repository",
+				"owner", codebase.Owner,
+				"repo", codebase.Name,
+				"error", err,
+			)
+			return false
+		}
+		viewerIDs = ids
+	}
+
+	candidates, err := uc.credentials.Candidates(ctx, codebase, viewerIDs)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to resolve credentials to confirm missing repository",
+			"owner", codebase.Owner,
+			"repo", codebase.Name,
+			"error", err,
+		)
+		return false
+	}
+
+	confirmed := false
+	for _, candidate := range candidates {
+		if candidate.Token != nil {
+			if !uc.repoNotFound(ctx, codebase, candidate.Token) {
+				return false
+			}
+			confirmed = true
+			continue
+		}
+		if candidate.Deploy != nil && !uc.deployKeyNotFound(ctx, codebase, *candidate.Deploy) {
+			return false
+		}
+	}
+	return confirmed
+}
+
+// deployKeyNotFound reports whether git confirms that the SSH deploy key cannot see the repository.
+func (uc *AutoRefreshUseCase) deployKeyNotFound(ctx context.Context, codebase analysis.CodebaseRefreshInfo, credential analysis.DeployCredential) bool {
+	repoURL := fmt.Sprintf("https://%s/%s/%s", codebase.Host, codebase.Owner, codebase.Name)
+	_, err := uc.deployVCS.GetHeadCommitWithCredential(ctx, repoURL, credential)
+	if err == nil {
+		return false
+	}
+	if !errors.Is(err, analysis.ErrRepoNotFound) {
+		slog.WarnContext(ctx, "failed to confirm missing repository with deploy key",
+			"owner", codebase.Owner,
+			"repo", codebase.Name,
+			"error", err,
+		)
+		return false
+	}
+	return true
+}
+
+// repoNotFound reports whether the API confirms that token cannot see the repository.
+func (uc *AutoRefreshUseCase) repoNotFound(ctx context.Context, codebase analysis.CodebaseRefreshInfo, token *string) bool {
+	_, err := uc.repoAPI.GetRepoInfo(ctx, codebase.Host, codebase.Owner, codebase.Name, token)
+	if err == nil {
+		return false
+	}
+	if !errors.Is(err, analysis.ErrRepoNotFound) {
+		slog.WarnContext(ctx, "failed to confirm missing repository",
+			"owner", codebase.Owner,
+			"repo", codebase.Name,
+			"error", err,
+		)
+		return false
+	}
+	return true
+}
+
+func (uc *AutoRefreshUseCase) enqueue(ctx context.Context, codebase analysis.CodebaseRefreshInfo, commitSHA string, userID *string) error {
+	if userID != nil {
+		return uc.taskQueue.EnqueueAnalysisWithUser(ctx, codebase.Owner, codebase.Name, commitSHA, userID)
+	}
+	return uc.taskQueue.EnqueueAnalysis(ctx, codebase.Owner, codebase.Name, commitSHA)
+}
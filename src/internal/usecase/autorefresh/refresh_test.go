@@ -3,6 +3,8 @@ package autorefresh
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -11,20 +13,31 @@ import (
 
 type mockAutoRefreshRepository struct {
 	codebases []analysis.CodebaseRefreshInfo
-	err       error
+	// emptyStates records each UpdateEmptyState call by codebase ID.
+	emptyStates map[analysis.UUID]bool
+	err         error
 }
 
 func (m *mockAutoRefreshRepository) GetCodebasesForAutoRefresh(ctx context.Context) ([]analysis.CodebaseRefreshInfo, error) {
 	return m.codebases, m.err
 }
 
+func (m *mockAutoRefreshRepository) UpdateEmptyState(ctx context.Context, id analysis.UUID, isEmpty bool) error {
+	if m.emptyStates == nil {
+		m.emptyStates = map[analysis.UUID]bool{}
+	}
+	m.emptyStates[id] = isEmpty
+	return nil
+}
+
 type mockTaskQueue struct {
 	enqueuedTasks []struct {
 		owner     string
 		repo      string
 		commitSHA string
 	}
-	err error
+	enqueuedUserIDs []string
+	err             error
 }
 
 func (m *mockTaskQueue) EnqueueAnalysis(ctx context.Context, owner, repo, commitSHA string) error {
@@ -39,9 +52,19 @@ func (m *mockTaskQueue) EnqueueAnalysis(ctx context.Context, owner, repo, commit
 	return nil
 }
 
+func (m *mockTaskQueue) EnqueueAnalysisWithUser(ctx context.Context, owner, repo, commitSHA string, userID *string) error {
+	if err := m.EnqueueAnalysis(ctx, owner, repo, commitSHA); err != nil {
+		return err
+	}
+	m.enqueuedUserIDs = append(m.enqueuedUserIDs, *userID)
+	return nil
+}
+
 type mockVCS struct {
 	commitSHA string
 	err       error
+	// tokens records the token of each GetHeadCommit call by URL.
+	tokens map[string]*string
 }
 
 func (m *mockVCS) Clone(ctx context.Context, url string, token *string) (analysis.Source, error) {
@@ -49,6 +72,9 @@ func (m *mockVCS) Clone(ctx context.Context, url string, token *string) (analysi
 }
 
 func (m *mockVCS) GetHeadCommit(ctx context.Context, url string, token *string) (analysis.CommitInfo, error) {
+	if m.tokens != nil {
+		m.tokens[url] = token
+	}
 	if m.err != nil {
 		return analysis.CommitInfo{}, m.err
 	}
@@ -276,6 +302,10 @@ func (m *errorOnFirstTaskQueue) EnqueueAnalysis(ctx context.Context, owner, repo
 	return nil
 }
 
+func (m *errorOnFirstTaskQueue) EnqueueAnalysisWithUser(ctx context.Context, owner, repo, commitSHA string, userID *string) error {
+	return m.EnqueueAnalysis(ctx, owner, repo, commitSHA)
+}
+
 func TestAutoRefreshUseCase_Execute_ContinuesOnEnqueueError(t *testing.T) {
 	now := time.Now()
 	completedAt := now.Add(-7 * time.Hour)
@@ -325,6 +355,10 @@ func (m *alwaysFailingTaskQueue) EnqueueAnalysis(ctx context.Context, owner, rep
 	return errors.New("enqueue error")
 }
 
+func (m *alwaysFailingTaskQueue) EnqueueAnalysisWithUser(ctx context.Context, owner, repo, commitSHA string, userID *string) error {
+	return m.EnqueueAnalysis(ctx, owner, repo, commitSHA)
+}
+
 func TestAutoRefreshUseCase_Execute_CircuitBreakerTriggered(t *testing.T) {
 	now := time.Now()
 	completedAt := now.Add(-7 * time.Hour)
@@ -354,3 +388,529 @@ func TestAutoRefreshUseCase_Execute_CircuitBreakerTriggered(t *testing.T) {
 		t.Errorf("expected 3 attempts before circuit breaker, got %d", queue.callCount)
 	}
 }
+
+type mockTokenLookup struct {
+	tokens map[string]string
+	err    error
+}
+
+func (m *mockTokenLookup) GetOAuthToken(ctx context.Context, userID string, provider string) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	token, ok := m.tokens[userID]
+	if !ok {
+		return "", analysis.ErrTokenNotFound
+	}
+	return token, nil
+}
+
+type mockInstallationTokenProvider struct {
+	tokens map[string]string
+}
+
+func (m *mockInstallationTokenProvider) GetInstallationToken(ctx context.Context, owner, repo string) (string, error) {
+	token, ok := m.tokens[owner+"/"+repo]
+	if !ok {
+		return "", analysis.ErrTokenNotFound
+	}
+	return token, nil
+}
+
+func TestAutoRefreshUseCase_Execute_PrivateCodebases(t *testing.T) {
+	now := time.Now()
+	completedAt := now.Add(-7 * time.Hour)
+	viewer := "viewer-1"
+	viewerWithoutToken := "viewer-2"
+	codebase := func(owner, name string, lastViewerID *string) analysis.CodebaseRefreshInfo {
+		return analysis.CodebaseRefreshInfo{
+			Host:            "github.com",
+			IsPrivate:       true,
+			LastCompletedAt: &completedAt,
+			LastViewedAt:    now.Add(-24 * time.Hour),
+			LastViewerID:    lastViewerID,
+			Name:            name,
+			Owner:           owner,
+		}
+	}
+
+	repo := &mockAutoRefreshRepository{
+		codebases: []analysis.CodebaseRefreshInfo{
+			codebase("acme", "app-installed", &viewer),
+			codebase("octo", "viewed", &viewer),
+			codebase("octo", "revoked", &viewerWithoutToken),
+			codebase("octo", "never-viewed", nil),
+		},
+	}
+	queue := &mockTaskQueue{}
+	vcs := &mockVCS{commitSHA: "abc123", tokens: map[string]*string{}}
+	uc := NewAutoRefreshUseCase(repo, queue, vcs,
+		WithInstallationTokenProvider(&mockInstallationTokenProvider{tokens: map[string]string{"acme/app-installed": "ghs_app"}}),
+		WithTokenLookup(&mockTokenLookup{tokens: map[string]string{viewer: "gho_viewer"}}),
+	)
+
+	if err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(queue.enqueuedTasks) != 2 {
+		t.Fatalf("expected 2 tasks enqueued, got %d", len(queue.enqueuedTasks))
+	}
+	if queue.enqueuedTasks[0].repo != "app-installed" || queue.enqueuedTasks[1].repo != "viewed" {
+		t.Errorf("unexpected tasks: %+v", queue.enqueuedTasks)
+	}
+	if len(queue.enqueuedUserIDs) != 1 || queue.enqueuedUserIDs[0] != viewer {
+		t.Errorf("expected only the viewer-token refresh to carry user %s, got %v", viewer, queue.enqueuedUserIDs)
+	}
+
+	if token := vcs.tokens["https://github.com/acme/app-installed"]; token == nil || *token != "ghs_app" {
+		t.Errorf("expected installation token for app-installed, got %v", token)
+	}
+	if token := vcs.tokens["https://github.com/octo/viewed"]; token == nil || *token != "gho_viewer" {
+		t.Errorf("expected viewer token for viewed, got %v", token)
+	}
+	if _, called := vcs.tokens["https://github.com/octo/revoked"]; called {
+		t.Error("expected codebase without credential to be skipped")
+	}
+}
+
+func TestAutoRefreshUseCase_Execute_PrivateCodebaseWithoutCredentialSources(t *testing.T) {
+	now := time.Now()
+	completedAt := now.Add(-7 * time.Hour)
+	viewer := "viewer-1"
+
+	repo := &mockAutoRefreshRepository{
+		codebases: []analysis.CodebaseRefreshInfo{
+			{Host: "github.com", IsPrivate: true, LastCompletedAt: &completedAt, LastViewedAt: now.Add(-24 * time.Hour), LastViewerID: &viewer, Name: "repo", Owner: "owner"},
+		},
+	}
+	queue := &mockTaskQueue{}
+	uc := NewAutoRefreshUseCase(repo, queue, &mockVCS{commitSHA: "abc123"})
+
+	if err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(queue.enqueuedTasks) != 0 {
+		t.Errorf("expected private codebase to be skipped, got %d tasks", len(queue.enqueuedTasks))
+	}
+}
+
+func TestAutoRefreshUseCase_Execute_PrivateCodebaseWithInvalidToken(t *testing.T) {
+	now := time.Now()
+	completedAt := now.Add(-7 * time.Hour)
+	viewer := "viewer-1"
+
+	revoked := func(name string) analysis.CodebaseRefreshInfo {
+		return analysis.CodebaseRefreshInfo{Host: "github.com", IsPrivate: true, LastCompletedAt: &completedAt, LastViewedAt: now.Add(-24 * time.Hour), LastViewerID: &viewer, Name: name, Owner: "owner"}
+	}
+
+	// Enough revoked codebases to open the circuit breaker if they counted as failures.
+	repo := &mockAutoRefreshRepository{
+		codebases: []analysis.CodebaseRefreshInfo{
+			revoked("revoked-1"),
+			revoked("revoked-2"),
+			revoked("revoked-3"),
+			{Host: "github.com", LastCompletedAt: &completedAt, LastViewedAt: now.Add(-24 * time.Hour), Name: "public", Owner: "owner"},
+		},
+	}
+	queue := &mockTaskQueue{}
+	uc := NewAutoRefreshUseCase(repo, queue, &mockVCS{commitSHA: "abc123"},
+		WithTokenLookup(&mockTokenLookup{err: analysis.ErrTokenInvalid}),
+	)
+
+	if err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(queue.enqueuedTasks) != 1 || queue.enqueuedTasks[0].repo != "public" {
+		t.Errorf("expected only the public codebase enqueued, got %+v", queue.enqueuedTasks)
+	}
+}
+
+type mockRepoAPI struct {
+	archived map[string]bool
+	err      error
+	// calls records the token of each GetRepoInfo call by repository name.
+	calls map[string]*string
+	// visibleTo limits a repository to a single token; "" hides it from everyone.
+	visibleTo map[string]string
+}
+
+func (m *mockRepoAPI) GetRepoInfo(ctx context.Context, host, owner, repo string, token *string) (analysis.RepoInfo, error) {
+	m.calls[repo] = token
+	if m.err != nil {
+		return analysis.RepoInfo{}, m.err
+	}
+	if want, limited := m.visibleTo[repo]; limited && (want == "" || token == nil || *token != want) {
+		return analysis.RepoInfo{}, fmt.Errorf("%w: %s/%s", analysis.ErrRepoNotFound, owner, repo)
+	}
+	return analysis.RepoInfo{ExternalRepoID: "1", IsArchived: m.archived[repo], Name: repo, Owner: owner, Stars: 3}, nil
+}
+
+type mockMetadataStore struct {
+	updated map[analysis.UUID]analysis.RepoInfo
+}
+
+func (m *mockMetadataStore) UpdateMetadata(ctx context.Context, id analysis.UUID, info analysis.RepoInfo) error {
+	m.updated[id] = info
+	return nil
+}
+
+func TestAutoRefreshUseCase_Execute_RefreshesStaleMetadata(t *testing.T) {
+	now := time.Now()
+	completedAt := now.Add(-7 * time.Hour)
+	freshAt := now.Add(-time.Hour)
+	staleAt := now.Add(-2 * metadataRefreshInterval)
+	codebase := func(name string, refreshedAt *time.Time) analysis.CodebaseRefreshInfo {
+		return analysis.CodebaseRefreshInfo{
+			Host:                "github.com",
+			ID:                  analysis.NewUUID(),
+			LastCompletedAt:     &completedAt,
+			LastViewedAt:        now.Add(-24 * time.Hour),
+			MetadataRefreshedAt: refreshedAt,
+			Name:                name,
+			Owner:               "owner",
+		}
+	}
+
+	t.Run("refreshes missing and stale metadata only", func(t *testing.T) {
+		never, stale, fresh := codebase("never", nil), codebase("stale", &staleAt), codebase("fresh", &freshAt)
+		repo := &mockAutoRefreshRepository{codebases: []analysis.CodebaseRefreshInfo{never, stale, fresh}}
+		api := &mockRepoAPI{calls: map[string]*string{}}
+		store := &mockMetadataStore{updated: map[analysis.UUID]analysis.RepoInfo{}}
+		queue := &mockTaskQueue{}
+
+		uc := NewAutoRefreshUseCase(repo, queue, &mockVCS{commitSHA: "abc123"}, WithMetadataRefresh(api, store))
+		if err := uc.Execute(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(api.calls) != 2 {
+			t.Errorf("expected 2 metadata fetches, got %v", api.calls)
+		}
+		if _, ok := store.updated[never.ID]; !ok {
+			t.Error("expected metadata of never-refreshed codebase to be stored")
+		}
+		if _, ok := store.updated[stale.ID]; !ok {
+			t.Error("expected metadata of stale codebase to be stored")
+		}
+		if _, ok := store.updated[fresh.ID]; ok {
+			t.Error("expected fresh metadata to be left alone")
+		}
+		if len(queue.enqueuedTasks) != 3 {
+			t.Errorf("expected 3 tasks enqueued, got %d", len(queue.enqueuedTasks))
+		}
+	})
+
+	t.Run("metadata failures do not block the refresh", func(t *testing.T) {
+		repo := &mockAutoRefreshRepository{codebases: []analysis.CodebaseRefreshInfo{codebase("repo", nil)}}
+		api := &mockRepoAPI{calls: map[string]*string{}, err: &analysis.RateLimitError{ResetAt: now.Add(time.Hour)}}
+		store := &mockMetadataStore{updated: map[analysis.UUID]analysis.RepoInfo{}}
+		queue := &mockTaskQueue{}
+
+		uc := NewAutoRefreshUseCase(repo, queue, &mockVCS{commitSHA: "abc123"}, WithMetadataRefresh(api, store))
+		if err := uc.Execute(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(store.updated) != 0 {
+			t.Errorf("expected no metadata stored, got %d", len(store.updated))
+		}
+		if len(queue.enqueuedTasks) != 1 {
+			t.Errorf("expected 1 task enqueued, got %d", len(queue.enqueuedTasks))
+		}
+	})
+}
+
+func TestAutoRefreshUseCase_Execute_ArchivedCodebases(t *testing.T) {
+	now := time.Now()
+	completedAt := now.Add(-7 * time.Hour)
+	staleAt := now.Add(-2 * metadataRefreshInterval)
+	freshAt := now.Add(-time.Hour)
+	archived := func(name string, refreshedAt *time.Time) analysis.CodebaseRefreshInfo {
+		return analysis.CodebaseRefreshInfo{
+			Host:                "github.com",
+			ID:                  analysis.NewUUID(),
+			IsArchived:          true,
+			LastCompletedAt:     &completedAt,
+			LastViewedAt:        now.Add(-24 * time.Hour),
+			MetadataRefreshedAt: refreshedAt,
+			Name:                name,
+			Owner:               "owner",
+		}
+	}
+
+	repo := &mockAutoRefreshRepository{
+		codebases: []analysis.CodebaseRefreshInfo{
+			archived("still-archived", &staleAt),
+			archived("unarchived", &staleAt),
+			archived("recently-checked", &freshAt),
+		},
+	}
+	api := &mockRepoAPI{archived: map[string]bool{"still-archived": true}, calls: map[string]*string{}}
+	store := &mockMetadataStore{updated: map[analysis.UUID]analysis.RepoInfo{}}
+	queue := &mockTaskQueue{}
+	vcs := &mockVCS{commitSHA: "abc123", tokens: map[string]*string{}}
+
+	uc := NewAutoRefreshUseCase(repo, queue, vcs, WithMetadataRefresh(api, store))
+	if err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(queue.enqueuedTasks) != 1 || queue.enqueuedTasks[0].repo != "unarchived" {
+		t.Errorf("expected only the un-archived codebase to be refreshed, got %+v", queue.enqueuedTasks)
+	}
+	if _, called := vcs.tokens["https://github.com/owner/still-archived"]; called {
+		t.Error("expected archived codebase not to be contacted")
+	}
+	if _, called := api.calls["recently-checked"]; called {
+		t.Error("expected recently checked archived codebase not to be rechecked")
+	}
+}
+
+func TestAutoRefreshUseCase_Execute_EmptyCodebases(t *testing.T) {
+	now := time.Now()
+	codebase := func(name string, emptyCheckedAt *time.Time) analysis.CodebaseRefreshInfo {
+		return analysis.CodebaseRefreshInfo{
+			EmptyCheckedAt: emptyCheckedAt,
+			Host:           "github.com",
+			ID:             analysis.NewUUID(),
+			LastViewedAt:   now.Add(-24 * time.Hour),
+			Name:           name,
+			Owner:          "owner",
+		}
+	}
+
+	t.Run("empty repositories are marked without tripping the circuit breaker", func(t *testing.T) {
+		codebases := make([]analysis.CodebaseRefreshInfo, maxConsecutiveEnqueueFailures+1)
+		for i := range codebases {
+			codebases[i] = codebase(fmt.Sprintf("empty-%d", i), nil)
+		}
+		repo := &mockAutoRefreshRepository{codebases: codebases}
+		queue := &mockTaskQueue{}
+		vcs := &mockVCS{err: fmt.Errorf("git ls-remote: %w", analysis.ErrEmptyRepository)}
+
+		uc := NewAutoRefreshUseCase(repo, queue, vcs)
+		if err := uc.Execute(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(queue.enqueuedTasks) != 0 {
+			t.Errorf("expected no tasks enqueued, got %d", len(queue.enqueuedTasks))
+		}
+		for _, c := range codebases {
+			if isEmpty, ok := repo.emptyStates[c.ID]; !ok || !isEmpty {
+				t.Errorf("expected %s to be marked empty", c.Name)
+			}
+		}
+	})
+
+	t.Run("first push clears the empty mark", func(t *testing.T) {
+		recheck := codebase("pushed", timePtr(now.Add(-2*24*time.Hour)))
+		backedOff := codebase("backed-off", timePtr(now.Add(-time.Hour)))
+		repo := &mockAutoRefreshRepository{codebases: []analysis.CodebaseRefreshInfo{recheck, backedOff}}
+		queue := &mockTaskQueue{}
+		vcs := &mockVCS{commitSHA: "abc123", tokens: map[string]*string{}}
+
+		uc := NewAutoRefreshUseCase(repo, queue, vcs)
+		if err := uc.Execute(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if isEmpty, ok := repo.emptyStates[recheck.ID]; !ok || isEmpty {
+			t.Errorf("expected empty mark to be cleared, got %v (recorded: %v)", isEmpty, ok)
+		}
+		if len(queue.enqueuedTasks) != 1 || queue.enqueuedTasks[0].repo != "pushed" {
+			t.Errorf("expected only the rechecked codebase to be refreshed, got %+v", queue.enqueuedTasks)
+		}
+		if _, called := vcs.tokens["https://github.com/owner/backed-off"]; called {
+			t.Error("expected recently checked empty codebase to be backed off")
+		}
+	})
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+type mockStaleStore struct {
+	reasons map[string]analysis.StaleReason
+	names   map[analysis.UUID]string
+}
+
+func (m *mockStaleStore) MarkStale(ctx context.Context, id analysis.UUID, reason analysis.StaleReason) error {
+	m.reasons[m.names[id]] = reason
+	return nil
+}
+
+type mockViewerLookup struct {
+	viewers []string
+}
+
+func (m *mockViewerLookup) ListRecentViewers(ctx context.Context, codebaseID analysis.UUID, limit int) ([]string, error) {
+	return m.viewers, nil
+}
+
+func TestAutoRefreshUseCase_Execute_MissingRepositories(t *testing.T) {
+	now := time.Now()
+	completedAt := now.Add(-7 * time.Hour)
+	viewer := "viewer-1"
+	codebase := func(name string, isPrivate bool) analysis.CodebaseRefreshInfo {
+		return analysis.CodebaseRefreshInfo{
+			Host:            "github.com",
+			ID:              analysis.NewUUID(),
+			IsPrivate:       isPrivate,
+			LastCompletedAt: &completedAt,
+			LastViewedAt:    now.Add(-24 * time.Hour),
+			LastViewerID:    &viewer,
+			Name:            name,
+			Owner:           "owner",
+		}
+	}
+
+	codebases := []analysis.CodebaseRefreshInfo{
+		codebase("deleted", false),
+		codebase("made-private", false),
+		codebase("revoked", true),
+		codebase("viewer-readable", true),
+		codebase("flaky", false),
+	}
+	store := &mockStaleStore{reasons: map[string]analysis.StaleReason{}, names: map[analysis.UUID]string{}}
+	for _, c := range codebases {
+		store.names[c.ID] = c.Name
+	}
+	api := &mockRepoAPI{
+		calls: map[string]*string{},
+		visibleTo: map[string]string{
+			"deleted":         "",
+			"made-private":    "ghs_app",
+			"revoked":         "",
+			"viewer-readable": "gho_viewer2",
+		},
+	}
+	queue := &mockTaskQueue{}
+	vcs := &mockVCS{err: fmt.Errorf("git ls-remote: %w", analysis.ErrRepoNotFound)}
+
+	uc := NewAutoRefreshUseCase(&mockAutoRefreshRepository{codebases: codebases}, queue, vcs,
+		WithInstallationTokenProvider(&mockInstallationTokenProvider{tokens: map[string]string{
+			"owner/made-private":    "ghs_app",
+			"owner/revoked":         "ghs_revoked",
+			"owner/viewer-readable": "ghs_lost",
+		}}),
+		WithTokenLookup(&mockTokenLookup{tokens: map[string]string{"viewer-2": "gho_viewer2"}}),
+		WithStaleDetection(api, store, &mockViewerLookup{viewers: []string{"viewer-2"}}),
+	)
+	if err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := map[string]analysis.StaleReason{
+		"deleted":      analysis.StaleReasonDeleted,
+		"made-private": analysis.StaleReasonMadePrivate,
+		"revoked":      analysis.StaleReasonAccessRevoked,
+	}
+	if !reflect.DeepEqual(store.reasons, want) {
+		t.Errorf("expected stale reasons %v, got %v", want, store.reasons)
+	}
+	if token := api.calls["revoked"]; token == nil || *token != "gho_viewer2" {
+		t.Errorf("expected private repository to be confirmed with every credential, got %v", token)
+	}
+	if len(queue.enqueuedTasks) != 0 {
+		t.Errorf("expected no tasks enqueued, got %d", len(queue.enqueuedTasks))
+	}
+}
+
+type mockDeployCredentials struct {
+	credentials map[string]analysis.DeployCredential
+}
+
+func (m *mockDeployCredentials) GetDeployCredential(ctx context.Context, host, owner, repo string) (analysis.DeployCredential, error) {
+	credential, ok := m.credentials[owner+"/"+repo]
+	if !ok {
+		return analysis.DeployCredential{}, analysis.ErrDeployCredentialNotFound
+	}
+	return credential, nil
+}
+
+type mockDeployVCS struct {
+	commitSHA string
+	// credentials records the credential of each GetHeadCommitWithCredential call by URL.
+	credentials map[string]analysis.DeployCredential
+	errs        map[string]error
+}
+
+func (m *mockDeployVCS) CloneWithCredential(ctx context.Context, url string, credential analysis.DeployCredential) (analysis.Source, error) {
+	return nil, nil
+}
+
+func (m *mockDeployVCS) GetHeadCommitWithCredential(ctx context.Context, url string, credential analysis.DeployCredential) (analysis.CommitInfo, error) {
+	m.credentials[url] = credential
+	if err := m.errs[url]; err != nil {
+		return analysis.CommitInfo{}, err
+	}
+	return analysis.CommitInfo{SHA: m.commitSHA, IsPrivate: true}, nil
+}
+
+func TestAutoRefreshUseCase_Execute_DeployCredentials(t *testing.T) {
+	now := time.Now()
+	completedAt := now.Add(-7 * time.Hour)
+	codebase := func(name string) analysis.CodebaseRefreshInfo {
+		return analysis.CodebaseRefreshInfo{
+			Host:            "github.com",
+			ID:              analysis.NewUUID(),
+			IsPrivate:       true,
+			LastCompletedAt: &completedAt,
+			LastViewedAt:    now.Add(-24 * time.Hour),
+			Name:            name,
+			Owner:           "acme",
+		}
+	}
+
+	sshKey := analysis.DeployCredential{Kind: analysis.DeployCredentialSSHKey, Secret: "key"}
+	token := analysis.DeployCredential{Kind: analysis.DeployCredentialToken, Secret: "github_pat"}
+	repo := &mockAutoRefreshRepository{
+		codebases: []analysis.CodebaseRefreshInfo{
+			codebase("ssh-service"),
+			codebase("token-service"),
+			codebase("ssh-revoked"),
+			codebase("no-credential"),
+		},
+	}
+	deployVCS := &mockDeployVCS{
+		commitSHA:   "abc123",
+		credentials: map[string]analysis.DeployCredential{},
+		errs: map[string]error{
+			"https://github.com/acme/ssh-revoked": fmt.Errorf("git ls-remote: %w", analysis.ErrRepoNotFound),
+		},
+	}
+	queue := &mockTaskQueue{}
+	store := &mockStaleStore{reasons: map[string]analysis.StaleReason{}, names: map[analysis.UUID]string{}}
+	api := &mockRepoAPI{calls: map[string]*string{}, visibleTo: map[string]string{"ssh-revoked": ""}}
+
+	uc := NewAutoRefreshUseCase(repo, queue, &mockVCS{commitSHA: "abc123"},
+		WithDeployCredentials(&mockDeployCredentials{credentials: map[string]analysis.DeployCredential{
+			"acme/ssh-service":   sshKey,
+			"acme/token-service": token,
+			"acme/ssh-revoked":   sshKey,
+		}}, deployVCS),
+		WithStaleDetection(api, store, &mockViewerLookup{}),
+	)
+	if err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(queue.enqueuedTasks) != 2 || queue.enqueuedTasks[0].repo != "ssh-service" || queue.enqueuedTasks[1].repo != "token-service" {
+		t.Errorf("expected codebases with deploy credentials to be refreshed, got %+v", queue.enqueuedTasks)
+	}
+	if len(queue.enqueuedUserIDs) != 0 {
+		t.Errorf("expected refreshes without a user, got %v", queue.enqueuedUserIDs)
+	}
+	if got := deployVCS.credentials["https://github.com/acme/ssh-service"]; got != sshKey {
+		t.Errorf("expected SSH key for ssh-service, got %+v", got)
+	}
+	if got := deployVCS.credentials["https://github.com/acme/token-service"]; got != token {
+		t.Errorf("expected deploy token for token-service, got %+v", got)
+	}
+	if len(store.reasons) != 0 {
+		t.Errorf("expected a bare SSH key not to retire codebases, got %v", store.reasons)
+	}
+}
@@ -0,0 +1,82 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/usecase/access"
+)
+
+var (
+	ErrEnqueueFailed     = errors.New("enqueue failed")
+	ErrHistoryFailed     = errors.New("history lookup failed")
+	ErrTokenLookupFailed = errors.New("token lookup failed")
+)
+
+// BackfillUseCase samples past commits of a repository and enqueues an exact-commit
+// analysis for each, so that a new codebase starts with a trend line.
+type BackfillUseCase struct {
+	credentials *access.Resolver
+	history     analysis.HistoryReader
+	taskQueue   analysis.HistoricalTaskQueue
+}
+
+// NewBackfillUseCase creates a new BackfillUseCase.
+// History is read with the token the credentials resolve to. An SSH deploy key alone
+// cannot read it, so such codebases need a user or installation token to be backfilled.
+func NewBackfillUseCase(
+	history analysis.HistoryReader,
+	taskQueue analysis.HistoricalTaskQueue,
+	credentials *access.Resolver,
+) *BackfillUseCase {
+	return &BackfillUseCase{
+		credentials: credentials,
+		history:     history,
+		taskQueue:   taskQueue,
+	}
+}
+
+// Execute returns the number of enqueued analyses.
+// Enqueueing stops at the first failure; commits enqueued before it are kept.
+func (uc *BackfillUseCase) Execute(ctx context.Context, req analysis.BackfillRequest) (int, error) {
+	if err := req.Validate(); err != nil {
+		return 0, err
+	}
+
+	credentials, err := uc.credentials.Resolve(ctx, req.Owner, req.Repo, req.UserID)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrTokenLookupFailed, err)
+	}
+	token := credentials.Token
+
+	repoURL := fmt.Sprintf("https://github.com/%s/%s", req.Owner, req.Repo)
+
+	var commits []analysis.HistoryCommit
+	if req.Plan.Strategy == analysis.BackfillStrategyTags {
+		commits, err = uc.history.ListTags(ctx, repoURL, token, req.Plan.Since)
+	} else {
+		commits, err = uc.history.ListCommits(ctx, repoURL, token, req.Plan.Since)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrHistoryFailed, err)
+	}
+
+	sampled := analysis.SampleCommits(commits, req.Plan)
+	for i, commit := range sampled {
+		if err := uc.taskQueue.EnqueueHistoricalAnalysis(ctx, req.Owner, req.Repo, commit.SHA, req.UserID); err != nil {
+			return i, fmt.Errorf("%w: commit %s: %w", ErrEnqueueFailed, commit.SHA, err)
+		}
+	}
+
+	slog.InfoContext(ctx, "backfill enqueued",
+		"owner", req.Owner,
+		"repo", req.Repo,
+		"strategy", req.Plan.Strategy,
+		"history_commits", len(commits),
+		"enqueued", len(sampled),
+	)
+	return len(sampled), nil
+}
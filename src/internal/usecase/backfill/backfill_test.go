@@ -0,0 +1,217 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/usecase/access"
+)
+
+type mockHistoryReader struct {
+	listCommitsFn func(ctx context.Context, url string, token *string, since time.Time) ([]analysis.HistoryCommit, error)
+	listTagsFn    func(ctx context.Context, url string, token *string, since time.Time) ([]analysis.HistoryCommit, error)
+}
+
+func (m *mockHistoryReader) ListCommits(ctx context.Context, url string, token *string, since time.Time) ([]analysis.HistoryCommit, error) {
+	if m.listCommitsFn != nil {
+		return m.listCommitsFn(ctx, url, token, since)
+	}
+	return nil, nil
+}
+
+func (m *mockHistoryReader) ListTags(ctx context.Context, url string, token *string, since time.Time) ([]analysis.HistoryCommit, error) {
+	if m.listTagsFn != nil {
+		return m.listTagsFn(ctx, url, token, since)
+	}
+	return nil, nil
+}
+
+type mockHistoricalTaskQueue struct {
+	enqueueFn func(ctx context.Context, owner, repo, commitSHA string, userID *string) error
+}
+
+func (m *mockHistoricalTaskQueue) EnqueueHistoricalAnalysis(ctx context.Context, owner, repo, commitSHA string, userID *string) error {
+	if m.enqueueFn != nil {
+		return m.enqueueFn(ctx, owner, repo, commitSHA, userID)
+	}
+	return nil
+}
+
+type mockTokenLookup struct {
+	getOAuthTokenFn func(ctx context.Context, userID, provider string) (string, error)
+}
+
+func (m *mockTokenLookup) GetOAuthToken(ctx context.Context, userID, provider string) (string, error) {
+	if m.getOAuthTokenFn != nil {
+		return m.getOAuthTokenFn(ctx, userID, provider)
+	}
+	return "", analysis.ErrTokenNotFound
+}
+
+func newHistory(n int) []analysis.HistoryCommit {
+	commits := make([]analysis.HistoryCommit, n)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range commits {
+		commits[i] = analysis.HistoryCommit{
+			SHA:         string(rune('a' + n - 1 - i)),
+			CommittedAt: base.AddDate(0, 0, n-1-i),
+		}
+	}
+	return commits
+}
+
+func TestBackfillUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("enqueues sampled commits oldest first", func(t *testing.T) {
+		history := &mockHistoryReader{
+			listCommitsFn: func(ctx context.Context, url string, token *string, since time.Time) ([]analysis.HistoryCommit, error) {
+				if url != "https://github.com/owner/repo" {
+					t.Errorf("unexpected url %q", url)
+				}
+				return newHistory(5), nil
+			},
+		}
+		var enqueued []string
+		queue := &mockHistoricalTaskQueue{
+			enqueueFn: func(ctx context.Context, owner, repo, commitSHA string, userID *string) error {
+				enqueued = append(enqueued, commitSHA)
+				return nil
+			},
+		}
+
+		uc := NewBackfillUseCase(history, queue, access.NewResolver(nil))
+		count, err := uc.Execute(ctx, analysis.BackfillRequest{
+			Owner: "owner",
+			Repo:  "repo",
+			Plan:  analysis.BackfillPlan{Strategy: analysis.BackfillStrategyEveryN, Every: 2},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if count != 3 {
+			t.Errorf("expected 3 enqueued, got %d", count)
+		}
+		want := []string{"a", "c", "e"}
+		for i := range want {
+			if i >= len(enqueued) || enqueued[i] != want[i] {
+				t.Fatalf("expected %v, got %v", want, enqueued)
+			}
+		}
+	})
+
+	t.Run("tags strategy reads tags", func(t *testing.T) {
+		history := &mockHistoryReader{
+			listCommitsFn: func(ctx context.Context, url string, token *string, since time.Time) ([]analysis.HistoryCommit, error) {
+				t.Error("unexpected ListCommits call")
+				return nil, nil
+			},
+			listTagsFn: func(ctx context.Context, url string, token *string, since time.Time) ([]analysis.HistoryCommit, error) {
+				return []analysis.HistoryCommit{{SHA: "t1", Tag: "v1.0.0"}}, nil
+			},
+		}
+
+		uc := NewBackfillUseCase(history, &mockHistoricalTaskQueue{}, access.NewResolver(nil))
+		count, err := uc.Execute(ctx, analysis.BackfillRequest{
+			Owner: "owner",
+			Repo:  "repo",
+			Plan:  analysis.BackfillPlan{Strategy: analysis.BackfillStrategyTags},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if count != 1 {
+			t.Errorf("expected 1 enqueued, got %d", count)
+		}
+	})
+
+	t.Run("uses user token for history", func(t *testing.T) {
+		var gotToken *string
+		history := &mockHistoryReader{
+			listCommitsFn: func(ctx context.Context, url string, token *string, since time.Time) ([]analysis.HistoryCommit, error) {
+				gotToken = token
+				return nil, nil
+			},
+		}
+		tokens := &mockTokenLookup{
+			getOAuthTokenFn: func(ctx context.Context, userID, provider string) (string, error) {
+				return "user-token", nil
+			},
+		}
+		userID := "user-1"
+
+		uc := NewBackfillUseCase(history, &mockHistoricalTaskQueue{}, access.NewResolver(tokens))
+		_, err := uc.Execute(ctx, analysis.BackfillRequest{
+			Owner:  "owner",
+			Repo:   "repo",
+			Plan:   analysis.BackfillPlan{Strategy: analysis.BackfillStrategyWeekly},
+			UserID: &userID,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotToken == nil || *gotToken != "user-token" {
+			t.Errorf("expected user token, got %v", gotToken)
+		}
+	})
+
+	t.Run("invalid plan", func(t *testing.T) {
+		uc := NewBackfillUseCase(&mockHistoryReader{}, &mockHistoricalTaskQueue{}, access.NewResolver(nil))
+		_, err := uc.Execute(ctx, analysis.BackfillRequest{Owner: "owner", Repo: "repo"})
+		if !errors.Is(err, analysis.ErrInvalidInput) {
+			t.Errorf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	t.Run("history failure", func(t *testing.T) {
+		history := &mockHistoryReader{
+			listCommitsFn: func(ctx context.Context, url string, token *string, since time.Time) ([]analysis.HistoryCommit, error) {
+				return nil, errors.New("clone failed")
+			},
+		}
+
+		uc := NewBackfillUseCase(history, &mockHistoricalTaskQueue{}, access.NewResolver(nil))
+		_, err := uc.Execute(ctx, analysis.BackfillRequest{
+			Owner: "owner",
+			Repo:  "repo",
+			Plan:  analysis.BackfillPlan{Strategy: analysis.BackfillStrategyWeekly},
+		})
+		if !errors.Is(err, ErrHistoryFailed) {
+			t.Errorf("expected ErrHistoryFailed, got %v", err)
+		}
+	})
+
+	t.Run("enqueue failure returns partial count", func(t *testing.T) {
+		history := &mockHistoryReader{
+			listCommitsFn: func(ctx context.Context, url string, token *string, since time.Time) ([]analysis.HistoryCommit, error) {
+				return newHistory(3), nil
+			},
+		}
+		calls := 0
+		queue := &mockHistoricalTaskQueue{
+			enqueueFn: func(ctx context.Context, owner, repo, commitSHA string, userID *string) error {
+				calls++
+				if calls == 2 {
+					return errors.New("db down")
+				}
+				return nil
+			},
+		}
+
+		uc := NewBackfillUseCase(history, queue, access.NewResolver(nil))
+		count, err := uc.Execute(ctx, analysis.BackfillRequest{
+			Owner: "owner",
+			Repo:  "repo",
+			Plan:  analysis.BackfillPlan{Strategy: analysis.BackfillStrategyEveryN, Every: 1},
+		})
+		if !errors.Is(err, ErrEnqueueFailed) {
+			t.Errorf("expected ErrEnqueueFailed, got %v", err)
+		}
+		if count != 1 {
+			t.Errorf("expected 1 enqueued before failure, got %d", count)
+		}
+	})
+}
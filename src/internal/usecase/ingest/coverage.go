@@ -0,0 +1,109 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/usecase/access"
+)
+
+// CoverageSummary counts the files of an ingested coverage report.
+type CoverageSummary struct {
+	Files     int
+	Unmatched int
+}
+
+// IngestCoverageUseCase stores per-file line coverage next to the commit's completed analysis.
+// Reported paths are normalized against the repository tree checked out at the commit.
+type IngestCoverageUseCase struct {
+	cloner      *access.Cloner
+	credentials *access.Resolver
+	fileLister  analysis.FileLister
+	parser      analysis.CoverageParser
+	repository  analysis.CoverageRepository
+}
+
+// NewIngestCoverageUseCase creates a new IngestCoverageUseCase.
+// The repository is cloned with the same credentials, and within the same clone limit, as analyses.
+func NewIngestCoverageUseCase(
+	parser analysis.CoverageParser,
+	repository analysis.CoverageRepository,
+	cloner *access.Cloner,
+	fileLister analysis.FileLister,
+	credentials *access.Resolver,
+) *IngestCoverageUseCase {
+	return &IngestCoverageUseCase{
+		cloner:      cloner,
+		credentials: credentials,
+		fileLister:  fileLister,
+		parser:      parser,
+		repository:  repository,
+	}
+}
+
+// Execute returns analysis.ErrAnalysisNotFound if the commit has not been analyzed yet.
+// Files that do not exist in the tree, such as generated code, are dropped.
+func (uc *IngestCoverageUseCase) Execute(ctx context.Context, req analysis.IngestCoverageRequest) (CoverageSummary, error) {
+	if err := req.Validate(); err != nil {
+		return CoverageSummary{}, err
+	}
+
+	files, err := uc.parser.ParseCoverage(req.Format, req.Report)
+	if err != nil {
+		return CoverageSummary{}, fmt.Errorf("%w: %w", ErrParseFailed, err)
+	}
+
+	analysisID, err := uc.repository.FindCompletedAnalysis(ctx, req.Owner, req.Repo, req.CommitSHA)
+	if err != nil {
+		return CoverageSummary{}, err
+	}
+
+	repoFiles, err := uc.listRepoFiles(ctx, req)
+	if err != nil {
+		return CoverageSummary{}, err
+	}
+
+	normalized, unmatched := analysis.NormalizeCoveragePaths(files, repoFiles)
+	if err := uc.repository.SaveFileCoverage(ctx, analysisID, req.ReportName, normalized); err != nil {
+		return CoverageSummary{}, fmt.Errorf("%w: %w", ErrSaveFailed, err)
+	}
+
+	summary := CoverageSummary{Files: len(normalized), Unmatched: len(unmatched)}
+	slog.InfoContext(ctx, "coverage ingested",
+		"owner", req.Owner,
+		"repo", req.Repo,
+		"commit", req.CommitSHA,
+		"report", req.ReportName,
+		"files", summary.Files,
+		"unmatched", summary.Unmatched,
+	)
+	return summary, nil
+}
+
+func (uc *IngestCoverageUseCase) listRepoFiles(ctx context.Context, req analysis.IngestCoverageRequest) ([]string, error) {
+	credentials, err := uc.credentials.Resolve(ctx, req.Owner, req.Repo, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenLookupFailed, err)
+	}
+
+	repoURL := fmt.Sprintf("https://github.com/%s/%s", req.Owner, req.Repo)
+	src, err := uc.cloner.Clone(ctx, repoURL, credentials)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCloneFailed, err)
+	}
+	defer access.CloseSource(src, req.Owner, req.Repo)
+
+	if src.CommitSHA() != req.CommitSHA {
+		if err := src.Checkout(ctx, req.CommitSHA); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCloneFailed, err)
+		}
+	}
+
+	repoFiles, err := uc.fileLister.ListFiles(ctx, src)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCloneFailed, err)
+	}
+	return repoFiles, nil
+}
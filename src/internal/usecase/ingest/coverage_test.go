@@ -0,0 +1,182 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/usecase/access"
+)
+
+type mockSource struct {
+	checkedOut string
+	closed     bool
+	commitSHA  string
+}
+
+func (m *mockSource) Branch() string { return "main" }
+
+func (m *mockSource) Checkout(ctx context.Context, sha string) error {
+	m.checkedOut = sha
+	m.commitSHA = sha
+	return nil
+}
+
+func (m *mockSource) CommitSHA() string { return m.commitSHA }
+
+func (m *mockSource) CommittedAt() time.Time { return time.Time{} }
+
+func (m *mockSource) Close(ctx context.Context) error {
+	m.closed = true
+	return nil
+}
+
+func (m *mockSource) VerifyCommitExists(ctx context.Context, sha string) (bool, error) {
+	return true, nil
+}
+
+type mockVCS struct {
+	cloneFn func(ctx context.Context, url string, token *string) (analysis.Source, error)
+}
+
+func (m *mockVCS) Clone(ctx context.Context, url string, token *string) (analysis.Source, error) {
+	return m.cloneFn(ctx, url, token)
+}
+
+func (m *mockVCS) GetHeadCommit(ctx context.Context, url string, token *string) (analysis.CommitInfo, error) {
+	return analysis.CommitInfo{}, nil
+}
+
+func newCloner(vcs analysis.VCS) *access.Cloner {
+	return access.NewCloner(vcs, nil, access.NewCloneLimiter(1))
+}
+
+type mockFileLister struct {
+	files []string
+}
+
+func (m *mockFileLister) ListFiles(ctx context.Context, src analysis.Source) ([]string, error) {
+	return m.files, nil
+}
+
+type mockCoverageParser struct {
+	files []analysis.FileCoverage
+	err   error
+}
+
+func (m *mockCoverageParser) ParseCoverage(format analysis.ReportFormat, data []byte) ([]analysis.FileCoverage, error) {
+	return m.files, m.err
+}
+
+type mockCoverageRepository struct {
+	findErr error
+	saved   []analysis.FileCoverage
+}
+
+func (m *mockCoverageRepository) FindCompletedAnalysis(ctx context.Context, owner, repo, commitSHA string) (analysis.UUID, error) {
+	if m.findErr != nil {
+		return analysis.NilUUID, m.findErr
+	}
+	return analysis.NewUUID(), nil
+}
+
+func (m *mockCoverageRepository) SaveFileCoverage(ctx context.Context, analysisID analysis.UUID, reportName string, files []analysis.FileCoverage) error {
+	m.saved = files
+	return nil
+}
+
+type mockTokenLookup struct {
+	token string
+	err   error
+}
+
+func (m *mockTokenLookup) GetOAuthToken(ctx context.Context, userID, provider string) (string, error) {
+	return m.token, m.err
+}
+
+func validCoverageRequest() analysis.IngestCoverageRequest {
+	return analysis.IngestCoverageRequest{
+		CommitSHA:  "abc123",
+		Format:     analysis.ReportFormatLcov,
+		Owner:      "owner",
+		Repo:       "repo",
+		Report:     []byte("SF:a.ts\nend_of_record\n"),
+		ReportName: "frontend",
+	}
+}
+
+func TestIngestCoverageUseCase_Execute(t *testing.T) {
+	t.Run("normalizes paths against the tree at the commit", func(t *testing.T) {
+		src := &mockSource{commitSHA: "head"}
+		var cloneToken *string
+		vcs := &mockVCS{cloneFn: func(ctx context.Context, url string, token *string) (analysis.Source, error) {
+			if url != "https://github.com/owner/repo" {
+				t.Errorf("unexpected clone URL %s", url)
+			}
+			cloneToken = token
+			return src, nil
+		}}
+		parser := &mockCoverageParser{files: []analysis.FileCoverage{
+			{Path: "/home/runner/work/repo/repo/src/cart.ts", CoveredLines: 1, TotalLines: 2, UncoveredLines: []int{2}},
+			{Path: "/home/runner/work/repo/repo/dist/bundle.js", TotalLines: 10},
+		}}
+		repo := &mockCoverageRepository{}
+		req := validCoverageRequest()
+		userID := "user-1"
+		req.UserID = &userID
+
+		uc := NewIngestCoverageUseCase(parser, repo, newCloner(vcs), &mockFileLister{files: []string{"src/cart.ts", "src/order.ts"}}, access.NewResolver(&mockTokenLookup{token: "secret"}))
+		summary, err := uc.Execute(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if summary.Files != 1 || summary.Unmatched != 1 {
+			t.Errorf("expected 1 file and 1 unmatched, got %+v", summary)
+		}
+		if len(repo.saved) != 1 || repo.saved[0].Path != "src/cart.ts" {
+			t.Errorf("expected src/cart.ts saved, got %+v", repo.saved)
+		}
+		if src.checkedOut != "abc123" || !src.closed {
+			t.Errorf("expected checkout of abc123 and close, got %q closed=%v", src.checkedOut, src.closed)
+		}
+		if cloneToken == nil || *cloneToken != "secret" {
+			t.Errorf("expected clone with the user's token")
+		}
+	})
+
+	t.Run("commit not analyzed", func(t *testing.T) {
+		vcs := &mockVCS{cloneFn: func(ctx context.Context, url string, token *string) (analysis.Source, error) {
+			t.Error("clone should not be called")
+			return nil, errors.New("unexpected")
+		}}
+		repo := &mockCoverageRepository{findErr: analysis.ErrAnalysisNotFound}
+
+		uc := NewIngestCoverageUseCase(&mockCoverageParser{}, repo, newCloner(vcs), &mockFileLister{}, access.NewResolver(nil))
+		if _, err := uc.Execute(context.Background(), validCoverageRequest()); !errors.Is(err, analysis.ErrAnalysisNotFound) {
+			t.Errorf("expected ErrAnalysisNotFound, got %v", err)
+		}
+	})
+
+	t.Run("clone failure", func(t *testing.T) {
+		vcs := &mockVCS{cloneFn: func(ctx context.Context, url string, token *string) (analysis.Source, error) {
+			return nil, errors.New("auth required")
+		}}
+
+		uc := NewIngestCoverageUseCase(&mockCoverageParser{}, &mockCoverageRepository{}, newCloner(vcs), &mockFileLister{}, access.NewResolver(nil))
+		if _, err := uc.Execute(context.Background(), validCoverageRequest()); !errors.Is(err, ErrCloneFailed) {
+			t.Errorf("expected ErrCloneFailed, got %v", err)
+		}
+	})
+
+	t.Run("malformed report", func(t *testing.T) {
+		parser := &mockCoverageParser{err: analysis.ErrInvalidReport}
+
+		uc := NewIngestCoverageUseCase(parser, &mockCoverageRepository{}, newCloner(&mockVCS{}), &mockFileLister{}, access.NewResolver(nil))
+		if _, err := uc.Execute(context.Background(), validCoverageRequest()); !errors.Is(err, analysis.ErrInvalidReport) {
+			t.Errorf("expected ErrInvalidReport, got %v", err)
+		}
+	})
+}
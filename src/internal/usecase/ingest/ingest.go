@@ -0,0 +1,84 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+var (
+	ErrCloneFailed       = errors.New("clone failed")
+	ErrLoadFailed        = errors.New("static tests lookup failed")
+	ErrParseFailed       = errors.New("report parsing failed")
+	ErrSaveFailed        = errors.New("save failed")
+	ErrTokenLookupFailed = errors.New("token lookup failed")
+)
+
+// Summary counts the executions of an ingested report.
+type Summary struct {
+	Executions int
+	Matched    int
+}
+
+// IngestResultsUseCase links the executions of a CI test report to the statically
+// discovered tests of the commit's completed analysis.
+type IngestResultsUseCase struct {
+	parser     analysis.ReportParser
+	repository analysis.ExecutionRepository
+}
+
+// NewIngestResultsUseCase creates a new IngestResultsUseCase.
+func NewIngestResultsUseCase(parser analysis.ReportParser, repository analysis.ExecutionRepository) *IngestResultsUseCase {
+	return &IngestResultsUseCase{
+		parser:     parser,
+		repository: repository,
+	}
+}
+
+// Execute returns analysis.ErrAnalysisNotFound if the commit has not been analyzed yet.
+// Executions that match no static test are stored unlinked.
+func (uc *IngestResultsUseCase) Execute(ctx context.Context, req analysis.IngestResultsRequest) (Summary, error) {
+	if err := req.Validate(); err != nil {
+		return Summary{}, err
+	}
+
+	executions, err := uc.parser.Parse(req.Format, req.Report)
+	if err != nil {
+		return Summary{}, fmt.Errorf("%w: %w", ErrParseFailed, err)
+	}
+
+	analysisID, err := uc.repository.FindCompletedAnalysis(ctx, req.Owner, req.Repo, req.CommitSHA)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	static, err := uc.repository.GetStaticTests(ctx, analysisID)
+	if err != nil {
+		return Summary{}, fmt.Errorf("%w: %w", ErrLoadFailed, err)
+	}
+
+	matches := analysis.MatchExecutions(static, executions)
+	if err := uc.repository.SaveTestExecutions(ctx, analysisID, req.ReportName, matches); err != nil {
+		return Summary{}, fmt.Errorf("%w: %w", ErrSaveFailed, err)
+	}
+
+	summary := Summary{Executions: len(matches)}
+	for _, match := range matches {
+		if match.TestCaseID != nil {
+			summary.Matched++
+		}
+	}
+
+	slog.InfoContext(ctx, "test results ingested",
+		"owner", req.Owner,
+		"repo", req.Repo,
+		"commit", req.CommitSHA,
+		"report", req.ReportName,
+		"executions", summary.Executions,
+		"matched", summary.Matched,
+	)
+	return summary, nil
+}
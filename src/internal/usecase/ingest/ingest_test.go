@@ -0,0 +1,162 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+)
+
+type mockReportParser struct {
+	parseFn func(format analysis.ReportFormat, data []byte) ([]analysis.TestExecution, error)
+}
+
+func (m *mockReportParser) Parse(format analysis.ReportFormat, data []byte) ([]analysis.TestExecution, error) {
+	if m.parseFn != nil {
+		return m.parseFn(format, data)
+	}
+	return nil, nil
+}
+
+type mockExecutionRepository struct {
+	findCompletedAnalysisFn func(ctx context.Context, owner, repo, commitSHA string) (analysis.UUID, error)
+	getStaticTestsFn        func(ctx context.Context, analysisID analysis.UUID) ([]analysis.StaticTest, error)
+	saveTestExecutionsFn    func(ctx context.Context, analysisID analysis.UUID, reportName string, matches []analysis.ExecutionMatch) error
+}
+
+func (m *mockExecutionRepository) FindCompletedAnalysis(ctx context.Context, owner, repo, commitSHA string) (analysis.UUID, error) {
+	if m.findCompletedAnalysisFn != nil {
+		return m.findCompletedAnalysisFn(ctx, owner, repo, commitSHA)
+	}
+	return analysis.NewUUID(), nil
+}
+
+func (m *mockExecutionRepository) GetStaticTests(ctx context.Context, analysisID analysis.UUID) ([]analysis.StaticTest, error) {
+	if m.getStaticTestsFn != nil {
+		return m.getStaticTestsFn(ctx, analysisID)
+	}
+	return nil, nil
+}
+
+func (m *mockExecutionRepository) SaveTestExecutions(ctx context.Context, analysisID analysis.UUID, reportName string, matches []analysis.ExecutionMatch) error {
+	if m.saveTestExecutionsFn != nil {
+		return m.saveTestExecutionsFn(ctx, analysisID, reportName, matches)
+	}
+	return nil
+}
+
+func validRequest() analysis.IngestResultsRequest {
+	return analysis.IngestResultsRequest{
+		CommitSHA:  "abc123",
+		Format:     analysis.ReportFormatJUnit,
+		Owner:      "owner",
+		Repo:       "repo",
+		Report:     []byte("<testsuites/>"),
+		ReportName: "unit",
+	}
+}
+
+func TestIngestResultsUseCase_Execute(t *testing.T) {
+	t.Run("links executions and saves them under the report name", func(t *testing.T) {
+		analysisID := analysis.NewUUID()
+		testID := analysis.NewUUID()
+
+		parser := &mockReportParser{
+			parseFn: func(format analysis.ReportFormat, data []byte) ([]analysis.TestExecution, error) {
+				return []analysis.TestExecution{
+					{Name: "adds item", Outcome: analysis.ExecutionOutcomePassed},
+					{Name: "unknown", Outcome: analysis.ExecutionOutcomeFailed},
+				}, nil
+			},
+		}
+		var savedReport string
+		var saved []analysis.ExecutionMatch
+		repo := &mockExecutionRepository{
+			findCompletedAnalysisFn: func(ctx context.Context, owner, repo, commitSHA string) (analysis.UUID, error) {
+				if owner != "owner" || repo != "repo" || commitSHA != "abc123" {
+					t.Errorf("unexpected lookup %s/%s@%s", owner, repo, commitSHA)
+				}
+				return analysisID, nil
+			},
+			getStaticTestsFn: func(ctx context.Context, id analysis.UUID) ([]analysis.StaticTest, error) {
+				return []analysis.StaticTest{{FilePath: "cart.test.ts", ID: testID, Name: "adds item"}}, nil
+			},
+			saveTestExecutionsFn: func(ctx context.Context, id analysis.UUID, reportName string, matches []analysis.ExecutionMatch) error {
+				if id != analysisID {
+					t.Errorf("expected analysis %v, got %v", analysisID, id)
+				}
+				savedReport = reportName
+				saved = matches
+				return nil
+			},
+		}
+
+		summary, err := NewIngestResultsUseCase(parser, repo).Execute(context.Background(), validRequest())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if summary.Executions != 2 || summary.Matched != 1 {
+			t.Errorf("expected 2 executions with 1 matched, got %+v", summary)
+		}
+		if savedReport != "unit" || len(saved) != 2 {
+			t.Fatalf("expected 2 executions saved as unit, got %d as %q", len(saved), savedReport)
+		}
+		if saved[0].TestCaseID == nil || *saved[0].TestCaseID != testID {
+			t.Errorf("expected first execution linked to %v", testID)
+		}
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		req := validRequest()
+		req.ReportName = ""
+
+		_, err := NewIngestResultsUseCase(&mockReportParser{}, &mockExecutionRepository{}).Execute(context.Background(), req)
+		if !errors.Is(err, analysis.ErrInvalidInput) {
+			t.Errorf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	t.Run("malformed report", func(t *testing.T) {
+		parser := &mockReportParser{
+			parseFn: func(format analysis.ReportFormat, data []byte) ([]analysis.TestExecution, error) {
+				return nil, analysis.ErrInvalidReport
+			},
+		}
+
+		_, err := NewIngestResultsUseCase(parser, &mockExecutionRepository{}).Execute(context.Background(), validRequest())
+		if !errors.Is(err, ErrParseFailed) || !errors.Is(err, analysis.ErrInvalidReport) {
+			t.Errorf("expected ErrParseFailed wrapping ErrInvalidReport, got %v", err)
+		}
+	})
+
+	t.Run("commit not analyzed", func(t *testing.T) {
+		repo := &mockExecutionRepository{
+			findCompletedAnalysisFn: func(ctx context.Context, owner, repo, commitSHA string) (analysis.UUID, error) {
+				return analysis.NilUUID, analysis.ErrAnalysisNotFound
+			},
+			saveTestExecutionsFn: func(ctx context.Context, id analysis.UUID, reportName string, matches []analysis.ExecutionMatch) error {
+				t.Error("save should not be called")
+				return nil
+			},
+		}
+
+		_, err := NewIngestResultsUseCase(&mockReportParser{}, repo).Execute(context.Background(), validRequest())
+		if !errors.Is(err, analysis.ErrAnalysisNotFound) {
+			t.Errorf("expected ErrAnalysisNotFound, got %v", err)
+		}
+	})
+
+	t.Run("save failure", func(t *testing.T) {
+		repo := &mockExecutionRepository{
+			saveTestExecutionsFn: func(ctx context.Context, id analysis.UUID, reportName string, matches []analysis.ExecutionMatch) error {
+				return errors.New("db down")
+			},
+		}
+
+		_, err := NewIngestResultsUseCase(&mockReportParser{}, repo).Execute(context.Background(), validRequest())
+		if !errors.Is(err, ErrSaveFailed) {
+			t.Errorf("expected ErrSaveFailed, got %v", err)
+		}
+	})
+}
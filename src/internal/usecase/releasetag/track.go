@@ -0,0 +1,195 @@
+package releasetag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/usecase/access"
+)
+
+// Same threshold as auto-refresh: a third consecutive enqueue failure
+// points at the queue rather than a single repository.
+const maxConsecutiveEnqueueFailures = 3
+
+var ErrCircuitBreakerOpen = errors.New("circuit breaker: too many consecutive enqueue failures")
+
+// ReleaseTagUseCase enqueues an exact-commit analysis for every new tag of the
+// codebases eligible for auto-refresh.
+//
+// The first scan of a codebase records its existing tags as a baseline without
+// analyzing them; older releases can be covered with a tags backfill.
+type ReleaseTagUseCase struct {
+	credentials   *access.CodebaseResolver
+	deployTags    analysis.DeployCredentialTagLister
+	repository    analysis.AutoRefreshRepository
+	tagLister     analysis.TagLister
+	tagRepository analysis.ReleaseTagRepository
+	taskQueue     analysis.HistoricalTaskQueue
+}
+
+// Config holds the optional credential sources for tracking private codebases.
+// Without them, private codebases are skipped.
+type Config struct {
+	Credentials *access.CodebaseResolver
+	DeployTags  analysis.DeployCredentialTagLister
+}
+
+// Option is a functional option for configuring ReleaseTagUseCase.
+type Option func(*Config)
+
+// WithCredentials tracks private codebases with the credentials auto-refresh would use.
+// Tags of codebases with a deploy credential are listed through tags.
+func WithCredentials(r *access.CodebaseResolver, tags analysis.DeployCredentialTagLister) Option {
+	return func(cfg *Config) {
+		cfg.Credentials = r
+		cfg.DeployTags = tags
+	}
+}
+
+func NewReleaseTagUseCase(
+	repository analysis.AutoRefreshRepository,
+	tagRepository analysis.ReleaseTagRepository,
+	tagLister analysis.TagLister,
+	taskQueue analysis.HistoricalTaskQueue,
+	opts ...Option,
+) *ReleaseTagUseCase {
+	var cfg Config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.Credentials == nil {
+		cfg.Credentials = access.NewCodebaseResolver(nil)
+	}
+
+	return &ReleaseTagUseCase{
+		credentials:   cfg.Credentials,
+		deployTags:    cfg.DeployTags,
+		repository:    repository,
+		tagLister:     tagLister,
+		tagRepository: tagRepository,
+		taskQueue:     taskQueue,
+	}
+}
+
+// Returns ErrCircuitBreakerOpen if too many consecutive enqueue failures occur.
+func (uc *ReleaseTagUseCase) Execute(ctx context.Context) error {
+	codebases, err := uc.repository.GetCodebasesForAutoRefresh(ctx)
+	if err != nil {
+		return err
+	}
+
+	var enqueued int
+	var consecutiveFailures int
+
+	for _, codebase := range codebases {
+		if consecutiveFailures >= maxConsecutiveEnqueueFailures {
+			slog.ErrorContext(ctx, "circuit breaker open, aborting release tag tracking",
+				"consecutive_failures", consecutiveFailures,
+				"enqueued_before_abort", enqueued,
+			)
+			return fmt.Errorf("%w: %d failures", ErrCircuitBreakerOpen, consecutiveFailures)
+		}
+
+		credentials, ok, err := uc.credentials.Resolve(ctx, codebase)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to resolve credential for release tags, skipping",
+				"owner", codebase.Owner,
+				"repo", codebase.Name,
+				"error", err,
+			)
+			continue
+		}
+		if !ok {
+			slog.DebugContext(ctx, "skipping release tags: no credential for private codebase",
+				"owner", codebase.Owner,
+				"repo", codebase.Name,
+			)
+			continue
+		}
+
+		repoURL := fmt.Sprintf("https://%s/%s/%s", codebase.Host, codebase.Owner, codebase.Name)
+		remote, err := uc.listTags(ctx, repoURL, credentials)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to list remote tags, skipping",
+				"owner", codebase.Owner,
+				"repo", codebase.Name,
+				"error", err,
+			)
+			continue
+		}
+
+		known, err := uc.tagRepository.GetCodebaseTags(ctx, codebase.ID)
+		if err != nil {
+			return fmt.Errorf("get tags for %s/%s: %w", codebase.Owner, codebase.Name, err)
+		}
+
+		fresh := analysis.NewReleaseTags(known, remote)
+		if len(fresh) == 0 {
+			continue
+		}
+
+		if len(known) == 0 {
+			if err := uc.tagRepository.SaveCodebaseTags(ctx, codebase.ID, fresh); err != nil {
+				return fmt.Errorf("save baseline tags for %s/%s: %w", codebase.Owner, codebase.Name, err)
+			}
+			slog.InfoContext(ctx, "recorded baseline release tags",
+				"owner", codebase.Owner,
+				"repo", codebase.Name,
+				"tags", len(fresh),
+			)
+			continue
+		}
+
+		// Only tags whose analysis was enqueued are saved, so failed ones are retried next run.
+		// A release is usually tagged on the latest commit, which the regular analysis already
+		// covers; the tag is linked to it once that analysis completes.
+		var tracked []analysis.ReleaseTag
+		for _, tag := range fresh {
+			if tag.SHA == codebase.LastCommitSHA {
+				tracked = append(tracked, tag)
+				continue
+			}
+			if err := uc.taskQueue.EnqueueHistoricalAnalysis(ctx, codebase.Owner, codebase.Name, tag.SHA, credentials.UserID); err != nil {
+				consecutiveFailures++
+				slog.ErrorContext(ctx, "failed to enqueue release tag analysis",
+					"owner", codebase.Owner,
+					"repo", codebase.Name,
+					"tag", tag.Name,
+					"consecutive_failures", consecutiveFailures,
+					"error", err,
+				)
+				break
+			}
+			consecutiveFailures = 0
+			tracked = append(tracked, tag)
+		}
+
+		if err := uc.tagRepository.SaveCodebaseTags(ctx, codebase.ID, tracked); err != nil {
+			return fmt.Errorf("save tags for %s/%s: %w", codebase.Owner, codebase.Name, err)
+		}
+
+		enqueued += len(tracked)
+		slog.InfoContext(ctx, "enqueued release tag analyses",
+			"owner", codebase.Owner,
+			"repo", codebase.Name,
+			"tags", len(tracked),
+		)
+	}
+
+	slog.InfoContext(ctx, "release tag tracking completed",
+		"total_candidates", len(codebases),
+		"enqueued", enqueued,
+	)
+
+	return nil
+}
+
+func (uc *ReleaseTagUseCase) listTags(ctx context.Context, repoURL string, credentials access.CodebaseCredentials) ([]analysis.ReleaseTag, error) {
+	if credentials.Deploy != nil && uc.deployTags != nil {
+		return uc.deployTags.ListRemoteTagsWithCredential(ctx, repoURL, *credentials.Deploy)
+	}
+	return uc.tagLister.ListRemoteTags(ctx, repoURL, credentials.Token)
+}
@@ -0,0 +1,290 @@
+package releasetag
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/specvital/collector/internal/domain/analysis"
+	"github.com/specvital/collector/internal/usecase/access"
+)
+
+type mockAutoRefreshRepository struct {
+	codebases []analysis.CodebaseRefreshInfo
+	err       error
+}
+
+func (m *mockAutoRefreshRepository) GetCodebasesForAutoRefresh(ctx context.Context) ([]analysis.CodebaseRefreshInfo, error) {
+	return m.codebases, m.err
+}
+
+func (m *mockAutoRefreshRepository) UpdateEmptyState(ctx context.Context, id analysis.UUID, isEmpty bool) error {
+	return nil
+}
+
+type mockTagLister struct {
+	tags   []analysis.ReleaseTag
+	err    error
+	tokens []*string
+}
+
+func (m *mockTagLister) ListRemoteTags(ctx context.Context, url string, token *string) ([]analysis.ReleaseTag, error) {
+	m.tokens = append(m.tokens, token)
+	return m.tags, m.err
+}
+
+type mockDeployTagLister struct {
+	tags        []analysis.ReleaseTag
+	credentials []analysis.DeployCredential
+}
+
+func (m *mockDeployTagLister) ListRemoteTagsWithCredential(ctx context.Context, url string, credential analysis.DeployCredential) ([]analysis.ReleaseTag, error) {
+	m.credentials = append(m.credentials, credential)
+	return m.tags, nil
+}
+
+type mockTokenLookup struct {
+	token string
+}
+
+func (m *mockTokenLookup) GetOAuthToken(ctx context.Context, userID, provider string) (string, error) {
+	return m.token, nil
+}
+
+type mockDeployCredentials struct {
+	credential analysis.DeployCredential
+}
+
+func (m *mockDeployCredentials) GetDeployCredential(ctx context.Context, host, owner, repo string) (analysis.DeployCredential, error) {
+	return m.credential, nil
+}
+
+type mockTagRepository struct {
+	known map[analysis.UUID][]analysis.ReleaseTag
+	saved []analysis.ReleaseTag
+}
+
+func (m *mockTagRepository) GetCodebaseTags(ctx context.Context, codebaseID analysis.UUID) ([]analysis.ReleaseTag, error) {
+	return m.known[codebaseID], nil
+}
+
+func (m *mockTagRepository) SaveCodebaseTags(ctx context.Context, codebaseID analysis.UUID, tags []analysis.ReleaseTag) error {
+	m.saved = append(m.saved, tags...)
+	return nil
+}
+
+type mockTaskQueue struct {
+	enqueued []string
+	err      error
+	userIDs  []*string
+}
+
+func (m *mockTaskQueue) EnqueueHistoricalAnalysis(ctx context.Context, owner, repo, commitSHA string, userID *string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.enqueued = append(m.enqueued, commitSHA)
+	m.userIDs = append(m.userIDs, userID)
+	return nil
+}
+
+var codebaseID = analysis.NewUUID()
+
+func newCodebases(n int) []analysis.CodebaseRefreshInfo {
+	codebases := make([]analysis.CodebaseRefreshInfo, n)
+	for i := range codebases {
+		codebases[i] = analysis.CodebaseRefreshInfo{ID: codebaseID, Host: "github.com", Owner: "owner", Name: "repo"}
+	}
+	return codebases
+}
+
+func TestReleaseTagUseCase_Execute_EnqueuesNewTags(t *testing.T) {
+	repo := &mockAutoRefreshRepository{codebases: newCodebases(1)}
+	lister := &mockTagLister{tags: []analysis.ReleaseTag{{Name: "v1.0.0", SHA: "a"}, {Name: "v1.1.0", SHA: "b"}}}
+	tagRepo := &mockTagRepository{known: map[analysis.UUID][]analysis.ReleaseTag{
+		codebaseID: {{Name: "v1.0.0", SHA: "a"}},
+	}}
+	queue := &mockTaskQueue{}
+
+	err := NewReleaseTagUseCase(repo, tagRepo, lister, queue).Execute(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queue.enqueued) != 1 || queue.enqueued[0] != "b" {
+		t.Errorf("expected only v1.1.0 enqueued, got %v", queue.enqueued)
+	}
+	if len(tagRepo.saved) != 1 || tagRepo.saved[0].Name != "v1.1.0" {
+		t.Errorf("expected v1.1.0 saved, got %v", tagRepo.saved)
+	}
+}
+
+func TestReleaseTagUseCase_Execute_TagOnLatestCommit(t *testing.T) {
+	codebases := newCodebases(1)
+	codebases[0].LastCommitSHA = "b"
+	repo := &mockAutoRefreshRepository{codebases: codebases}
+	lister := &mockTagLister{tags: []analysis.ReleaseTag{{Name: "v1.0.0", SHA: "a"}, {Name: "v1.1.0", SHA: "b"}}}
+	tagRepo := &mockTagRepository{known: map[analysis.UUID][]analysis.ReleaseTag{
+		codebaseID: {{Name: "v1.0.0", SHA: "a"}},
+	}}
+	queue := &mockTaskQueue{}
+
+	err := NewReleaseTagUseCase(repo, tagRepo, lister, queue).Execute(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queue.enqueued) != 0 {
+		t.Errorf("expected no historical analysis of the latest commit, got %v", queue.enqueued)
+	}
+	if len(tagRepo.saved) != 1 || tagRepo.saved[0].Name != "v1.1.0" {
+		t.Errorf("expected v1.1.0 saved for linking, got %v", tagRepo.saved)
+	}
+}
+
+func TestReleaseTagUseCase_Execute_RecordsBaseline(t *testing.T) {
+	repo := &mockAutoRefreshRepository{codebases: newCodebases(1)}
+	lister := &mockTagLister{tags: []analysis.ReleaseTag{{Name: "v1.0.0", SHA: "a"}, {Name: "v1.1.0", SHA: "b"}}}
+	tagRepo := &mockTagRepository{}
+	queue := &mockTaskQueue{}
+
+	err := NewReleaseTagUseCase(repo, tagRepo, lister, queue).Execute(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queue.enqueued) != 0 {
+		t.Errorf("expected no analyses for baseline, got %v", queue.enqueued)
+	}
+	if len(tagRepo.saved) != 2 {
+		t.Errorf("expected baseline of 2 tags, got %v", tagRepo.saved)
+	}
+}
+
+func TestReleaseTagUseCase_Execute_ListFailureSkipsCodebase(t *testing.T) {
+	repo := &mockAutoRefreshRepository{codebases: newCodebases(2)}
+	lister := &mockTagLister{err: errors.New("network")}
+	tagRepo := &mockTagRepository{}
+	queue := &mockTaskQueue{}
+
+	err := NewReleaseTagUseCase(repo, tagRepo, lister, queue).Execute(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tagRepo.saved) != 0 {
+		t.Errorf("expected nothing saved, got %v", tagRepo.saved)
+	}
+}
+
+func TestReleaseTagUseCase_Execute_EnqueueFailureNotSaved(t *testing.T) {
+	repo := &mockAutoRefreshRepository{codebases: newCodebases(1)}
+	lister := &mockTagLister{tags: []analysis.ReleaseTag{{Name: "v2.0.0", SHA: "c"}}}
+	tagRepo := &mockTagRepository{known: map[analysis.UUID][]analysis.ReleaseTag{
+		codebaseID: {{Name: "v1.0.0", SHA: "a"}},
+	}}
+	queue := &mockTaskQueue{err: errors.New("queue down")}
+
+	err := NewReleaseTagUseCase(repo, tagRepo, lister, queue).Execute(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tagRepo.saved) != 0 {
+		t.Errorf("expected failed tag to stay unsaved for retry, got %v", tagRepo.saved)
+	}
+}
+
+func TestReleaseTagUseCase_Execute_CircuitBreaker(t *testing.T) {
+	repo := &mockAutoRefreshRepository{codebases: newCodebases(5)}
+	lister := &mockTagLister{tags: []analysis.ReleaseTag{{Name: "v2.0.0", SHA: "c"}}}
+	tagRepo := &mockTagRepository{known: map[analysis.UUID][]analysis.ReleaseTag{
+		codebaseID: {{Name: "v1.0.0", SHA: "a"}},
+	}}
+	queue := &mockTaskQueue{err: errors.New("queue down")}
+
+	err := NewReleaseTagUseCase(repo, tagRepo, lister, queue).Execute(context.Background())
+
+	if !errors.Is(err, ErrCircuitBreakerOpen) {
+		t.Errorf("expected ErrCircuitBreakerOpen, got %v", err)
+	}
+}
+
+func TestReleaseTagUseCase_Execute_RepositoryError(t *testing.T) {
+	expectedErr := errors.New("database error")
+	repo := &mockAutoRefreshRepository{err: expectedErr}
+
+	err := NewReleaseTagUseCase(repo, &mockTagRepository{}, &mockTagLister{}, &mockTaskQueue{}).Execute(context.Background())
+
+	if !errors.Is(err, expectedErr) {
+		t.Errorf("expected %v, got %v", expectedErr, err)
+	}
+}
+
+func TestReleaseTagUseCase_Execute_PrivateCodebase(t *testing.T) {
+	viewerID := "viewer-1"
+	newPrivate := func() *mockAutoRefreshRepository {
+		codebases := newCodebases(1)
+		codebases[0].IsPrivate = true
+		codebases[0].LastViewerID = &viewerID
+		return &mockAutoRefreshRepository{codebases: codebases}
+	}
+	newKnown := func() *mockTagRepository {
+		return &mockTagRepository{known: map[analysis.UUID][]analysis.ReleaseTag{
+			codebaseID: {{Name: "v1.0.0", SHA: "a"}},
+		}}
+	}
+	tags := []analysis.ReleaseTag{{Name: "v1.1.0", SHA: "b"}}
+
+	t.Run("skipped without credentials", func(t *testing.T) {
+		lister := &mockTagLister{tags: tags}
+		queue := &mockTaskQueue{}
+
+		err := NewReleaseTagUseCase(newPrivate(), newKnown(), lister, queue).Execute(context.Background())
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(lister.tokens) != 0 || len(queue.enqueued) != 0 {
+			t.Errorf("expected private codebase to be skipped, listed %d, enqueued %v", len(lister.tokens), queue.enqueued)
+		}
+	})
+
+	t.Run("viewer token lists tags and runs the analysis", func(t *testing.T) {
+		lister := &mockTagLister{tags: tags}
+		queue := &mockTaskQueue{}
+		resolver := access.NewCodebaseResolver(&mockTokenLookup{token: "viewer-token"})
+
+		err := NewReleaseTagUseCase(newPrivate(), newKnown(), lister, queue, WithCredentials(resolver, nil)).Execute(context.Background())
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(lister.tokens) != 1 || lister.tokens[0] == nil || *lister.tokens[0] != "viewer-token" {
+			t.Errorf("expected tags listed with the viewer token, got %v", lister.tokens)
+		}
+		if len(queue.userIDs) != 1 || queue.userIDs[0] == nil || *queue.userIDs[0] != viewerID {
+			t.Errorf("expected analysis enqueued for the viewer, got %v", queue.userIDs)
+		}
+	})
+
+	t.Run("deploy key lists tags", func(t *testing.T) {
+		sshKey := analysis.DeployCredential{Kind: analysis.DeployCredentialSSHKey, Secret: "key"}
+		lister := &mockTagLister{}
+		deployTags := &mockDeployTagLister{tags: tags}
+		queue := &mockTaskQueue{}
+		resolver := access.NewCodebaseResolver(nil, access.WithDeployCredentials(&mockDeployCredentials{credential: sshKey}))
+
+		err := NewReleaseTagUseCase(newPrivate(), newKnown(), lister, queue, WithCredentials(resolver, deployTags)).Execute(context.Background())
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(deployTags.credentials) != 1 || len(lister.tokens) != 0 {
+			t.Errorf("expected tags listed with the deploy key, got %d deploy and %d token listings", len(deployTags.credentials), len(lister.tokens))
+		}
+		if len(queue.enqueued) != 1 || queue.userIDs[0] != nil {
+			t.Errorf("expected one analysis without a user, got %v", queue.userIDs)
+		}
+	})
+}